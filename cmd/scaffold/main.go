@@ -0,0 +1,458 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const modulePath = "github.com/faisd405/go-restapi-gin"
+
+// Anchors the generator looks for when wiring a new module into the tree.
+const (
+	routerFile   = "src/router/router.go"
+	mainFile     = "main.go"
+	routesAnchor = "// Scaffolded routes"
+	modelsAnchor = "// Add other models here as you create them"
+)
+
+var (
+	identPattern     = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	migrationPattern = regexp.MustCompile(`^(\d+)_.*\.(up|down)\.sql$`)
+)
+
+// Field describes a single column of the generated resource
+type Field struct {
+	Name     string
+	GoName   string
+	JSONName string
+	Type     string
+	GoType   string
+	SQLType  string
+	Required bool
+}
+
+// Resource holds everything the templates need to render a module
+type Resource struct {
+	Module   string
+	Name     string
+	Package  string
+	Pascal   string
+	Camel    string
+	Table    string
+	Route    string
+	Fields   []Field
+	Sequence string
+}
+
+type typeMapping struct {
+	goType  string
+	sqlType string
+}
+
+var fieldTypes = map[string]typeMapping{
+	"string":  {"string", "VARCHAR(255)"},
+	"text":    {"string", "TEXT"},
+	"int":     {"int", "INTEGER"},
+	"int64":   {"int64", "BIGINT"},
+	"uint":    {"uint", "INTEGER"},
+	"bool":    {"bool", "BOOLEAN"},
+	"float64": {"float64", "DOUBLE PRECISION"},
+	"time":    {"time.Time", "TIMESTAMP WITH TIME ZONE"},
+}
+
+func main() {
+	var (
+		name   = flag.String("name", "", "Resource name in snake_case, e.g. menu_item")
+		fields = flag.String("fields", "", "Comma separated fields as name:type[:required], e.g. name:string:required,price:int64")
+		dryRun = flag.Bool("dry-run", false, "Print the files that would be generated without writing them")
+	)
+	flag.Parse()
+
+	if *name == "" || *fields == "" {
+		fmt.Println("Usage:")
+		fmt.Println("  go run ./cmd/scaffold -name=menu_item -fields=name:string:required,price:int64:required,description:text")
+		fmt.Println()
+		fmt.Println("Supported field types: " + strings.Join(supportedTypes(), ", "))
+		os.Exit(1)
+	}
+
+	res, err := newResource(*name, *fields)
+	if err != nil {
+		log.Fatal("Invalid resource definition: ", err)
+	}
+
+	seq, err := nextMigrationSequence("migrations")
+	if err != nil {
+		log.Fatal("Failed to read migrations: ", err)
+	}
+	res.Sequence = fmt.Sprintf("%06d", seq)
+
+	files, err := renderFiles(res)
+	if err != nil {
+		log.Fatal("Failed to render templates: ", err)
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	if *dryRun {
+		for _, path := range paths {
+			fmt.Printf("==> %s\n%s\n", path, files[path])
+		}
+		return
+	}
+
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			log.Fatalf("Refusing to overwrite existing file %s", path)
+		}
+	}
+
+	for _, path := range paths {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			log.Fatal("Failed to create directory: ", err)
+		}
+		if err := os.WriteFile(path, files[path], 0o644); err != nil {
+			log.Fatal("Failed to write file: ", err)
+		}
+		fmt.Println("created", path)
+	}
+
+	if err := registerRoutes(res); err != nil {
+		log.Fatal("Failed to register routes: ", err)
+	}
+	fmt.Println("updated", routerFile)
+
+	if err := registerModel(res); err != nil {
+		log.Fatal("Failed to register model: ", err)
+	}
+	fmt.Println("updated", mainFile)
+}
+
+func supportedTypes() []string {
+	types := make([]string, 0, len(fieldTypes))
+	for t := range fieldTypes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func newResource(name, fieldSpec string) (*Resource, error) {
+	if !identPattern.MatchString(name) {
+		return nil, fmt.Errorf("name %q must be snake_case", name)
+	}
+
+	res := &Resource{
+		Module:  modulePath,
+		Name:    name,
+		Package: strings.ReplaceAll(name, "_", ""),
+		Pascal:  pascalCase(name),
+		Table:   pluralize(name),
+		Route:   strings.ReplaceAll(pluralize(name), "_", "-"),
+	}
+	res.Camel = strings.ToLower(res.Pascal[:1]) + res.Pascal[1:]
+
+	seen := map[string]bool{}
+	for _, raw := range strings.Split(fieldSpec, ",") {
+		parts := strings.Split(strings.TrimSpace(raw), ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("field %q must be name:type[:required]", raw)
+		}
+
+		fieldName, fieldType := parts[0], parts[1]
+		if !identPattern.MatchString(fieldName) {
+			return nil, fmt.Errorf("field name %q must be snake_case", fieldName)
+		}
+		switch fieldName {
+		case "id", "created_at", "updated_at", "deleted_at":
+			return nil, fmt.Errorf("field %q is generated automatically", fieldName)
+		}
+		if seen[fieldName] {
+			return nil, fmt.Errorf("duplicate field %q", fieldName)
+		}
+		seen[fieldName] = true
+
+		mapping, ok := fieldTypes[fieldType]
+		if !ok {
+			return nil, fmt.Errorf("unsupported type %q for field %q", fieldType, fieldName)
+		}
+
+		required := false
+		if len(parts) == 3 {
+			if parts[2] != "required" {
+				return nil, fmt.Errorf("unknown field option %q", parts[2])
+			}
+			required = true
+		}
+
+		res.Fields = append(res.Fields, Field{
+			Name:     fieldName,
+			GoName:   pascalCase(fieldName),
+			JSONName: fieldName,
+			Type:     fieldType,
+			GoType:   mapping.goType,
+			SQLType:  mapping.sqlType,
+			Required: required,
+		})
+	}
+
+	return res, nil
+}
+
+// HasTime reports whether the model needs the time import beyond timestamps
+func (r *Resource) HasTime() bool {
+	for _, f := range r.Fields {
+		if f.GoType == "time.Time" {
+			return true
+		}
+	}
+	return false
+}
+
+// GormTag returns the gorm struct tag for a field
+func (f Field) GormTag() string {
+	tag := "type:" + strings.ToLower(f.SQLType)
+	if f.Required {
+		tag += ";not null"
+	}
+	return tag
+}
+
+// BindingTag returns the binding struct tag for create requests
+func (f Field) BindingTag() string {
+	if f.Required && f.GoType != "bool" {
+		return ` binding:"required"`
+	}
+	return ""
+}
+
+// SampleValue returns a Go literal used by the generated tests
+func (f Field) SampleValue() string {
+	switch f.GoType {
+	case "string":
+		return strconv.Quote("sample " + f.Name)
+	case "bool":
+		return "true"
+	case "time.Time":
+		return "time.Now()"
+	default:
+		return "1"
+	}
+}
+
+func renderFiles(res *Resource) (map[string][]byte, error) {
+	base := filepath.Join("src", "app", res.Package)
+	outputs := map[string]string{
+		filepath.Join(base, "model", res.Name+".go"):                                     modelTemplate,
+		filepath.Join(base, "repository", res.Name+"_repository.go"):                     repositoryTemplate,
+		filepath.Join(base, "service", res.Name+"_service.go"):                           serviceTemplate,
+		filepath.Join(base, "service", res.Name+"_service_test.go"):                      serviceTestTemplate,
+		filepath.Join(base, "controller", res.Name+"_controller.go"):                     controllerTemplate,
+		filepath.Join("migrations", res.Sequence+"_create_"+res.Table+"_table.up.sql"):   upMigrationTemplate,
+		filepath.Join("migrations", res.Sequence+"_create_"+res.Table+"_table.down.sql"): downMigrationTemplate,
+	}
+
+	files := make(map[string][]byte, len(outputs))
+	for path, text := range outputs {
+		tmpl, err := template.New(filepath.Base(path)).Parse(text)
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, res); err != nil {
+			return nil, err
+		}
+
+		content := buf.Bytes()
+		if strings.HasSuffix(path, ".go") {
+			formatted, err := format.Source(content)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			content = formatted
+		}
+		files[path] = content
+	}
+
+	return files, nil
+}
+
+func nextMigrationSequence(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 1, nil
+		}
+		return 0, err
+	}
+
+	highest := 0
+	for _, entry := range entries {
+		match := migrationPattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		if n > highest {
+			highest = n
+		}
+	}
+
+	return highest + 1, nil
+}
+
+func registerRoutes(res *Resource) error {
+	imports := []string{
+		fmt.Sprintf("%scontroller \"%s/src/app/%s/controller\"", res.Package, res.Module, res.Package),
+		fmt.Sprintf("%srepository \"%s/src/app/%s/repository\"", res.Package, res.Module, res.Package),
+		fmt.Sprintf("%sservice \"%s/src/app/%s/service\"", res.Package, res.Module, res.Package),
+	}
+
+	var routes bytes.Buffer
+	if err := template.Must(template.New("routes").Parse(routesSnippet)).Execute(&routes, res); err != nil {
+		return err
+	}
+
+	return patchFile(routerFile, imports, routesAnchor, routes.String())
+}
+
+func registerModel(res *Resource) error {
+	imports := []string{
+		fmt.Sprintf("%smodel \"%s/src/app/%s/model\"", res.Package, res.Module, res.Package),
+	}
+	line := fmt.Sprintf("&%smodel.%s{},\n", res.Package, res.Pascal)
+
+	return patchFile(mainFile, imports, modelsAnchor, line)
+}
+
+// patchFile adds imports to the import block and inserts snippet
+// right before the line containing anchor, keeping the anchor's indentation.
+// Only the new lines are written; the rest of the file is left as it is.
+func patchFile(path string, imports []string, anchor, snippet string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	src := string(content)
+
+	for _, spec := range imports {
+		if src, err = insertImport(src, spec); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	anchorIdx := strings.Index(src, anchor)
+	if anchorIdx < 0 {
+		return fmt.Errorf("%s: anchor %q not found", path, anchor)
+	}
+	lineStart := strings.LastIndex(src[:anchorIdx], "\n") + 1
+	indent := src[lineStart:anchorIdx]
+
+	var indented strings.Builder
+	for _, line := range strings.SplitAfter(snippet, "\n") {
+		if strings.TrimSpace(line) == "" {
+			indented.WriteString(line)
+			continue
+		}
+		indented.WriteString(indent + line)
+	}
+	src = src[:lineStart] + indented.String() + src[lineStart:]
+
+	if _, err := parser.ParseFile(token.NewFileSet(), path, src, parser.AllErrors); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return os.WriteFile(path, []byte(src), 0o644)
+}
+
+// insertImport adds spec to the module imports of the import block, where
+// gofmt would sort it by path
+func insertImport(src, spec string) (string, error) {
+	start := strings.Index(src, "import (\n")
+	if start < 0 {
+		return "", errors.New("import block not found")
+	}
+	start += len("import (\n")
+	end := strings.Index(src[start:], "\n)")
+	if end < 0 {
+		return "", errors.New("import block not closed")
+	}
+	end += start + 1
+
+	path := importPath(spec)
+	at := -1
+	for offset := start; offset < end; {
+		next := strings.Index(src[offset:end], "\n") + offset + 1
+		line := src[offset:next]
+		if strings.Contains(line, "\""+modulePath+"/") {
+			if importPath(line) > path {
+				at = offset
+				break
+			}
+			at = next
+		}
+		offset = next
+	}
+	if at < 0 {
+		at = end
+	}
+
+	return src[:at] + "\t" + spec + "\n" + src[at:], nil
+}
+
+// importPath returns the quoted path of an import spec
+func importPath(spec string) string {
+	first := strings.Index(spec, "\"")
+	last := strings.LastIndex(spec, "\"")
+	if first < 0 || last <= first {
+		return ""
+	}
+	return spec[first+1 : last]
+}
+
+func pascalCase(s string) string {
+	parts := strings.Split(s, "_")
+	for i, p := range parts {
+		if p == "" {
+			continue
+		}
+		if p == "id" {
+			parts[i] = "ID"
+			continue
+		}
+		parts[i] = strings.ToUpper(p[:1]) + p[1:]
+	}
+	return strings.Join(parts, "")
+}
+
+func pluralize(s string) string {
+	switch {
+	case strings.HasSuffix(s, "y") && !strings.HasSuffix(s, "ay") && !strings.HasSuffix(s, "ey") && !strings.HasSuffix(s, "oy"):
+		return s[:len(s)-1] + "ies"
+	case strings.HasSuffix(s, "s"), strings.HasSuffix(s, "x"), strings.HasSuffix(s, "ch"), strings.HasSuffix(s, "sh"):
+		return s + "es"
+	default:
+		return s + "s"
+	}
+}
@@ -0,0 +1,571 @@
+package main
+
+const modelTemplate = `package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type {{.Pascal}} struct {
+	ID        uint           ` + "`" + `json:"id" gorm:"primaryKey"` + "`" + `
+{{- range .Fields}}
+	{{.GoName}} {{.GoType}} ` + "`" + `json:"{{.JSONName}}" gorm:"{{.GormTag}}"` + "`" + `
+{{- end}}
+	CreatedAt time.Time      ` + "`" + `json:"created_at"` + "`" + `
+	UpdatedAt time.Time      ` + "`" + `json:"updated_at"` + "`" + `
+	DeletedAt gorm.DeletedAt ` + "`" + `json:"-" gorm:"index"` + "`" + `
+}
+
+func ({{.Pascal}}) TableName() string {
+	return "{{.Table}}"
+}
+
+type Create{{.Pascal}}Request struct {
+{{- range .Fields}}
+	{{.GoName}} {{.GoType}} ` + "`" + `json:"{{.JSONName}}"{{.BindingTag}}` + "`" + `
+{{- end}}
+}
+
+type Update{{.Pascal}}Request struct {
+{{- range .Fields}}
+	{{.GoName}} {{.GoType}} ` + "`" + `json:"{{.JSONName}}"{{.BindingTag}}` + "`" + `
+{{- end}}
+}
+
+type {{.Pascal}}Response struct {
+	ID uint ` + "`" + `json:"id"` + "`" + `
+{{- range .Fields}}
+	{{.GoName}} {{.GoType}} ` + "`" + `json:"{{.JSONName}}"` + "`" + `
+{{- end}}
+	CreatedAt time.Time ` + "`" + `json:"created_at"` + "`" + `
+	UpdatedAt time.Time ` + "`" + `json:"updated_at"` + "`" + `
+}
+
+// ToResponse converts {{.Pascal}} to {{.Pascal}}Response
+func (m *{{.Pascal}}) ToResponse() {{.Pascal}}Response {
+	return {{.Pascal}}Response{
+		ID: m.ID,
+{{- range .Fields}}
+		{{.GoName}}: m.{{.GoName}},
+{{- end}}
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+`
+
+const repositoryTemplate = `package repository
+
+import (
+	"{{.Module}}/src/app/{{.Package}}/model"
+	"{{.Module}}/src/database"
+	"gorm.io/gorm"
+)
+
+type {{.Pascal}}Repository interface {
+	Create({{.Camel}} *model.{{.Pascal}}) error
+	GetByID(id uint) (*model.{{.Pascal}}, error)
+	Update({{.Camel}} *model.{{.Pascal}}) error
+	Delete(id uint) error
+	GetAll(offset, limit int) ([]model.{{.Pascal}}, int64, error)
+	WithTx(tx *gorm.DB) {{.Pascal}}Repository
+}
+
+type {{.Camel}}Repository struct {
+	database.Repository[model.{{.Pascal}}]
+}
+
+func New{{.Pascal}}Repository(db *gorm.DB) {{.Pascal}}Repository {
+	return &{{.Camel}}Repository{Repository: database.NewRepository[model.{{.Pascal}}](db)}
+}
+
+func (r *{{.Camel}}Repository) WithTx(tx *gorm.DB) {{.Pascal}}Repository {
+	return &{{.Camel}}Repository{Repository: r.Repository.WithTx(tx)}
+}
+`
+
+const serviceTemplate = `package service
+
+import (
+	"{{.Module}}/src/app/{{.Package}}/model"
+	"{{.Module}}/src/app/{{.Package}}/repository"
+)
+
+type {{.Pascal}}Service interface {
+	Create(req model.Create{{.Pascal}}Request) (*model.{{.Pascal}}Response, error)
+	GetByID(id uint) (*model.{{.Pascal}}Response, error)
+	Update(id uint, req model.Update{{.Pascal}}Request) (*model.{{.Pascal}}Response, error)
+	Delete(id uint) error
+	GetAll(page, limit int) ([]model.{{.Pascal}}Response, int64, error)
+}
+
+type {{.Camel}}Service struct {
+	{{.Camel}}Repo repository.{{.Pascal}}Repository
+}
+
+func New{{.Pascal}}Service({{.Camel}}Repo repository.{{.Pascal}}Repository) {{.Pascal}}Service {
+	return &{{.Camel}}Service{ {{- .Camel}}Repo: {{.Camel}}Repo}
+}
+
+func (s *{{.Camel}}Service) Create(req model.Create{{.Pascal}}Request) (*model.{{.Pascal}}Response, error) {
+	{{.Camel}} := &model.{{.Pascal}}{
+{{- range .Fields}}
+		{{.GoName}}: req.{{.GoName}},
+{{- end}}
+	}
+
+	if err := s.{{.Camel}}Repo.Create({{.Camel}}); err != nil {
+		return nil, err
+	}
+
+	response := {{.Camel}}.ToResponse()
+	return &response, nil
+}
+
+func (s *{{.Camel}}Service) GetByID(id uint) (*model.{{.Pascal}}Response, error) {
+	{{.Camel}}, err := s.{{.Camel}}Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := {{.Camel}}.ToResponse()
+	return &response, nil
+}
+
+func (s *{{.Camel}}Service) Update(id uint, req model.Update{{.Pascal}}Request) (*model.{{.Pascal}}Response, error) {
+	{{.Camel}}, err := s.{{.Camel}}Repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+{{- range .Fields}}
+	{{$.Camel}}.{{.GoName}} = req.{{.GoName}}
+{{- end}}
+
+	if err := s.{{.Camel}}Repo.Update({{.Camel}}); err != nil {
+		return nil, err
+	}
+
+	response := {{.Camel}}.ToResponse()
+	return &response, nil
+}
+
+func (s *{{.Camel}}Service) Delete(id uint) error {
+	_, err := s.{{.Camel}}Repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	return s.{{.Camel}}Repo.Delete(id)
+}
+
+func (s *{{.Camel}}Service) GetAll(page, limit int) ([]model.{{.Pascal}}Response, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+	items, total, err := s.{{.Camel}}Repo.GetAll(offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]model.{{.Pascal}}Response, len(items))
+	for i, item := range items {
+		responses[i] = item.ToResponse()
+	}
+
+	return responses, total, nil
+}
+`
+
+const serviceTestTemplate = `package service
+
+import (
+	"errors"
+	"testing"
+{{- if .HasTime}}
+	"time"
+{{- end}}
+
+	"{{.Module}}/src/app/{{.Package}}/model"
+	"{{.Module}}/src/app/{{.Package}}/repository"
+	"gorm.io/gorm"
+)
+
+type fake{{.Pascal}}Repository struct {
+	items  map[uint]*model.{{.Pascal}}
+	nextID uint
+}
+
+func newFake{{.Pascal}}Repository() *fake{{.Pascal}}Repository {
+	return &fake{{.Pascal}}Repository{items: map[uint]*model.{{.Pascal}}{}, nextID: 1}
+}
+
+func (r *fake{{.Pascal}}Repository) Create({{.Camel}} *model.{{.Pascal}}) error {
+	{{.Camel}}.ID = r.nextID
+	r.nextID++
+	stored := *{{.Camel}}
+	r.items[{{.Camel}}.ID] = &stored
+	return nil
+}
+
+func (r *fake{{.Pascal}}Repository) GetByID(id uint) (*model.{{.Pascal}}, error) {
+	item, ok := r.items[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *item
+	return &found, nil
+}
+
+func (r *fake{{.Pascal}}Repository) Update({{.Camel}} *model.{{.Pascal}}) error {
+	stored := *{{.Camel}}
+	r.items[{{.Camel}}.ID] = &stored
+	return nil
+}
+
+func (r *fake{{.Pascal}}Repository) Delete(id uint) error {
+	delete(r.items, id)
+	return nil
+}
+
+func (r *fake{{.Pascal}}Repository) WithTx(tx *gorm.DB) repository.{{.Pascal}}Repository {
+	return r
+}
+
+func (r *fake{{.Pascal}}Repository) GetAll(offset, limit int) ([]model.{{.Pascal}}, int64, error) {
+	var items []model.{{.Pascal}}
+	for id := uint(1); id < r.nextID; id++ {
+		if item, ok := r.items[id]; ok {
+			items = append(items, *item)
+		}
+	}
+	total := int64(len(items))
+	if offset >= len(items) {
+		return nil, total, nil
+	}
+	end := offset + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[offset:end], total, nil
+}
+
+func sampleCreate{{.Pascal}}Request() model.Create{{.Pascal}}Request {
+	return model.Create{{.Pascal}}Request{
+{{- range .Fields}}
+		{{.GoName}}: {{.SampleValue}},
+{{- end}}
+	}
+}
+
+func Test{{.Pascal}}Service_GetByID(t *testing.T) {
+	repo := newFake{{.Pascal}}Repository()
+	svc := New{{.Pascal}}Service(repo)
+
+	created, err := svc.Create(sampleCreate{{.Pascal}}Request())
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		id      uint
+		wantErr error
+	}{
+		{name: "existing", id: created.ID},
+		{name: "missing", id: created.ID + 100, wantErr: gorm.ErrRecordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.GetByID(tt.id)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetByID() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got.ID != tt.id {
+				t.Errorf("GetByID() id = %d, want %d", got.ID, tt.id)
+			}
+		})
+	}
+}
+
+func Test{{.Pascal}}Service_GetAll(t *testing.T) {
+	repo := newFake{{.Pascal}}Repository()
+	svc := New{{.Pascal}}Service(repo)
+
+	for i := 0; i < 3; i++ {
+		if _, err := svc.Create(sampleCreate{{.Pascal}}Request()); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		page      int
+		limit     int
+		wantCount int
+	}{
+		{name: "first page", page: 1, limit: 2, wantCount: 2},
+		{name: "last page", page: 2, limit: 2, wantCount: 1},
+		{name: "out of range", page: 5, limit: 2, wantCount: 0},
+		{name: "defaults", page: 0, limit: 0, wantCount: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, total, err := svc.GetAll(tt.page, tt.limit)
+			if err != nil {
+				t.Fatalf("GetAll() error = %v", err)
+			}
+			if total != 3 {
+				t.Errorf("GetAll() total = %d, want 3", total)
+			}
+			if len(items) != tt.wantCount {
+				t.Errorf("GetAll() returned %d items, want %d", len(items), tt.wantCount)
+			}
+		})
+	}
+}
+
+func Test{{.Pascal}}Service_Delete(t *testing.T) {
+	repo := newFake{{.Pascal}}Repository()
+	svc := New{{.Pascal}}Service(repo)
+
+	created, err := svc.Create(sampleCreate{{.Pascal}}Request())
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		id      uint
+		wantErr error
+	}{
+		{name: "existing", id: created.ID},
+		{name: "already deleted", id: created.ID, wantErr: gorm.ErrRecordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := svc.Delete(tt.id); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Delete() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+`
+
+const controllerTemplate = `package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"{{.Module}}/src/app/{{.Package}}/model"
+	"{{.Module}}/src/app/{{.Package}}/service"
+	"{{.Module}}/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type {{.Pascal}}Controller struct {
+	{{.Camel}}Service service.{{.Pascal}}Service
+}
+
+func New{{.Pascal}}Controller({{.Camel}}Service service.{{.Pascal}}Service) *{{.Pascal}}Controller {
+	return &{{.Pascal}}Controller{ {{- .Camel}}Service: {{.Camel}}Service}
+}
+
+// Index godoc
+// @Summary List {{.Table}}
+// @Description Get paginated list of {{.Table}}
+// @Tags {{.Table}}
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response
+// @Router /{{.Route}} [get]
+func (ctrl *{{.Pascal}}Controller) Index(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		limit = 10
+	}
+
+	items, total, err := ctrl.{{.Camel}}Service.GetAll(page, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get {{.Table}}", err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"{{.Table}}": items,
+		"pagination": map[string]interface{}{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "{{.Pascal}} list retrieved successfully", response)
+}
+
+// Show godoc
+// @Summary Get {{.Name}}
+// @Description Get a {{.Name}} by ID
+// @Tags {{.Table}}
+// @Produce json
+// @Param id path int true "{{.Pascal}} ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /{{.Route}}/{id} [get]
+func (ctrl *{{.Pascal}}Controller) Show(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid {{.Name}} ID", err.Error())
+		return
+	}
+
+	item, err := ctrl.{{.Camel}}Service.GetByID(uint(id))
+	if err != nil {
+		utils.DatabaseErrorResponse(c, "{{.Pascal}} not found", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "{{.Pascal}} retrieved successfully", item)
+}
+
+// Create godoc
+// @Summary Create {{.Name}}
+// @Description Create a new {{.Name}}
+// @Tags {{.Table}}
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param {{.Name}} body model.Create{{.Pascal}}Request true "{{.Pascal}} data"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /{{.Route}} [post]
+func (ctrl *{{.Pascal}}Controller) Create(c *gin.Context) {
+	var req model.Create{{.Pascal}}Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	item, err := ctrl.{{.Camel}}Service.Create(req)
+	if err != nil {
+		utils.DatabaseErrorResponse(c, "{{.Pascal}} creation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "{{.Pascal}} created successfully", item)
+}
+
+// Update godoc
+// @Summary Update {{.Name}}
+// @Description Update a {{.Name}} by ID
+// @Tags {{.Table}}
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "{{.Pascal}} ID"
+// @Param {{.Name}} body model.Update{{.Pascal}}Request true "{{.Pascal}} data"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /{{.Route}}/{id} [put]
+func (ctrl *{{.Pascal}}Controller) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid {{.Name}} ID", err.Error())
+		return
+	}
+
+	var req model.Update{{.Pascal}}Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	item, err := ctrl.{{.Camel}}Service.Update(uint(id), req)
+	if err != nil {
+		utils.DatabaseErrorResponse(c, "{{.Pascal}} update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "{{.Pascal}} updated successfully", item)
+}
+
+// Delete godoc
+// @Summary Delete {{.Name}}
+// @Description Delete a {{.Name}} by ID
+// @Tags {{.Table}}
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "{{.Pascal}} ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /{{.Route}}/{id} [delete]
+func (ctrl *{{.Pascal}}Controller) Delete(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid {{.Name}} ID", err.Error())
+		return
+	}
+
+	if err := ctrl.{{.Camel}}Service.Delete(uint(id)); err != nil {
+		utils.DatabaseErrorResponse(c, "{{.Pascal}} deletion failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "{{.Pascal}} deleted successfully", nil)
+}
+`
+
+const upMigrationTemplate = `CREATE TABLE IF NOT EXISTS {{.Table}} (
+    id SERIAL PRIMARY KEY,
+{{- range .Fields}}
+    {{.Name}} {{.SQLType}}{{if .Required}} NOT NULL{{end}},
+{{- end}}
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_{{.Table}}_deleted_at ON {{.Table}}(deleted_at);
+`
+
+const downMigrationTemplate = `DROP TABLE IF EXISTS {{.Table}};
+`
+
+const routesSnippet = `// {{.Pascal}} routes
+{{.Camel}}Repo := {{.Package}}repository.New{{.Pascal}}Repository(config.GetDB())
+{{.Camel}}Svc := {{.Package}}service.New{{.Pascal}}Service({{.Camel}}Repo)
+{{.Camel}}Ctrl := {{.Package}}controller.New{{.Pascal}}Controller({{.Camel}}Svc)
+
+{{.Camel}}Routes := v1.Group("/{{.Route}}")
+{
+	{{.Camel}}Routes.GET("", {{.Camel}}Ctrl.Index)
+	{{.Camel}}Routes.GET("/:id", {{.Camel}}Ctrl.Show)
+}
+
+{{.Camel}}Admin := v1.Group("/{{.Route}}")
+{{.Camel}}Admin.Use(middleware.AuthMiddleware())
+{{.Camel}}Admin.Use(middleware.AdminMiddleware())
+{
+	{{.Camel}}Admin.POST("", {{.Camel}}Ctrl.Create)
+	{{.Camel}}Admin.PUT("/:id", {{.Camel}}Ctrl.Update)
+	{{.Camel}}Admin.DELETE("/:id", {{.Camel}}Ctrl.Delete)
+}
+
+`
@@ -5,8 +5,23 @@ import (
 	"log"
 	"os"
 
+	inventorymodel "github.com/faisd405/go-restapi-gin/src/app/inventory/model"
+	kitchenmodel "github.com/faisd405/go-restapi-gin/src/app/kitchen/model"
+	loyaltymodel "github.com/faisd405/go-restapi-gin/src/app/loyalty/model"
+	menumodel "github.com/faisd405/go-restapi-gin/src/app/menu/model"
+	ordermodel "github.com/faisd405/go-restapi-gin/src/app/order/model"
+	paymentmodel "github.com/faisd405/go-restapi-gin/src/app/payment/model"
+	pricingmodel "github.com/faisd405/go-restapi-gin/src/app/pricing/model"
+	printingmodel "github.com/faisd405/go-restapi-gin/src/app/printing/model"
+	purchasingmodel "github.com/faisd405/go-restapi-gin/src/app/purchasing/model"
+	receiptmodel "github.com/faisd405/go-restapi-gin/src/app/receipt/model"
+	reservationmodel "github.com/faisd405/go-restapi-gin/src/app/reservation/model"
+	restaurantmodel "github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	shiftmodel "github.com/faisd405/go-restapi-gin/src/app/shift/model"
+	tablemodel "github.com/faisd405/go-restapi-gin/src/app/table/model"
 	"github.com/faisd405/go-restapi-gin/src/app/user/model"
 	"github.com/faisd405/go-restapi-gin/src/config"
+	"github.com/faisd405/go-restapi-gin/src/idempotency"
 	"github.com/faisd405/go-restapi-gin/src/router"
 	"github.com/joho/godotenv"
 )
@@ -55,6 +70,57 @@ func runMigrations() {
 	// Auto migrate models
 	err := db.AutoMigrate(
 		&model.User{},
+		&idempotency.Record{},
+		&restaurantmodel.Restaurant{},
+		&restaurantmodel.Branch{},
+		&restaurantmodel.BranchStaff{},
+		&restaurantmodel.OpeningHours{},
+		&restaurantmodel.HoursException{},
+		&menumodel.Category{},
+		&menumodel.Item{},
+		&menumodel.ModifierGroup{},
+		&menumodel.ModifierOption{},
+		&menumodel.ItemModifierGroup{},
+		&menumodel.Ingredient{},
+		&menumodel.ItemIngredient{},
+		&pricingmodel.BranchPricing{},
+		&pricingmodel.TaxRate{},
+		&pricingmodel.Discount{},
+		&tablemodel.Table{},
+		&tablemodel.Session{},
+		&reservationmodel.Reservation{},
+		&reservationmodel.Settings{},
+		&ordermodel.Order{},
+		&ordermodel.OrderLine{},
+		&ordermodel.StatusChange{},
+		&kitchenmodel.Station{},
+		&kitchenmodel.StationItem{},
+		&kitchenmodel.Ticket{},
+		&paymentmodel.Payment{},
+		&paymentmodel.WebhookEvent{},
+		&paymentmodel.RefundGroup{},
+		&paymentmodel.Refund{},
+		&paymentmodel.Bill{},
+		&receiptmodel.Template{},
+		&receiptmodel.Invoice{},
+		&receiptmodel.InvoiceCounter{},
+		&printingmodel.Printer{},
+		&printingmodel.Job{},
+		&inventorymodel.Stock{},
+		&inventorymodel.Movement{},
+		&purchasingmodel.Supplier{},
+		&purchasingmodel.SupplierIngredient{},
+		&purchasingmodel.PurchaseOrder{},
+		&purchasingmodel.PurchaseOrderLine{},
+		&shiftmodel.Settings{},
+		&shiftmodel.Shift{},
+		&shiftmodel.TimeEntry{},
+		&shiftmodel.ClockPIN{},
+		&loyaltymodel.Settings{},
+		&loyaltymodel.Tier{},
+		&loyaltymodel.Account{},
+		&loyaltymodel.Entry{},
+		&loyaltymodel.LotUse{},
 		// Add other models here as you create them
 	)
 	
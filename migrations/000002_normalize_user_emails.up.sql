@@ -0,0 +1,5 @@
+-- Emails are stored lowercased and trimmed; this fails if two existing
+-- accounts only differ by case and must be resolved manually first.
+UPDATE users SET email = LOWER(TRIM(email)) WHERE email <> LOWER(TRIM(email));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users (LOWER(email));
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS branch_staff;
+DROP TABLE IF EXISTS branches;
+DROP TABLE IF EXISTS restaurants;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS menu_categories (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_menu_categories_branch_id ON menu_categories(branch_id);
+CREATE INDEX idx_menu_categories_deleted_at ON menu_categories(deleted_at);
+
+CREATE TABLE IF NOT EXISTS menu_items (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    category_id INTEGER NOT NULL REFERENCES menu_categories(id),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    price BIGINT NOT NULL CHECK (price >= 0),
+    image_url VARCHAR(500),
+    tags JSONB NOT NULL DEFAULT '[]',
+    is_available BOOLEAN NOT NULL DEFAULT true,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_menu_items_branch_id ON menu_items(branch_id);
+CREATE INDEX idx_menu_items_category_id ON menu_items(category_id);
+CREATE INDEX idx_menu_items_deleted_at ON menu_items(deleted_at);
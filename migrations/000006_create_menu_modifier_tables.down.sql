@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS menu_item_modifier_groups;
+DROP TABLE IF EXISTS menu_modifier_options;
+DROP TABLE IF EXISTS menu_modifier_groups;
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS menu_modifier_groups (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    min_select INTEGER NOT NULL DEFAULT 0 CHECK (min_select >= 0),
+    max_select INTEGER NOT NULL DEFAULT 0 CHECK (max_select >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_menu_modifier_groups_branch_id ON menu_modifier_groups(branch_id);
+CREATE INDEX idx_menu_modifier_groups_deleted_at ON menu_modifier_groups(deleted_at);
+
+CREATE TABLE IF NOT EXISTS menu_modifier_options (
+    id SERIAL PRIMARY KEY,
+    group_id INTEGER NOT NULL REFERENCES menu_modifier_groups(id),
+    name VARCHAR(255) NOT NULL,
+    price_delta BIGINT NOT NULL DEFAULT 0,
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    is_available BOOLEAN NOT NULL DEFAULT true,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_menu_modifier_options_group_id ON menu_modifier_options(group_id);
+
+CREATE TABLE IF NOT EXISTS menu_item_modifier_groups (
+    item_id INTEGER NOT NULL REFERENCES menu_items(id),
+    group_id INTEGER NOT NULL REFERENCES menu_modifier_groups(id),
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (item_id, group_id)
+);
+
+CREATE INDEX idx_menu_item_modifier_groups_group_id ON menu_item_modifier_groups(group_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS menu_item_ingredients;
+DROP TABLE IF EXISTS menu_ingredients;
+
+ALTER TABLE menu_items DROP COLUMN IF EXISTS diets;
+ALTER TABLE menu_items DROP COLUMN IF EXISTS allergens;
@@ -0,0 +1,24 @@
+ALTER TABLE menu_items ADD COLUMN IF NOT EXISTS allergens JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE menu_items ADD COLUMN IF NOT EXISTS diets JSONB NOT NULL DEFAULT '[]';
+
+CREATE TABLE IF NOT EXISTS menu_ingredients (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    name VARCHAR(255) NOT NULL,
+    allergens JSONB NOT NULL DEFAULT '[]',
+    diets JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_menu_ingredients_branch_id ON menu_ingredients(branch_id);
+CREATE INDEX idx_menu_ingredients_deleted_at ON menu_ingredients(deleted_at);
+
+CREATE TABLE IF NOT EXISTS menu_item_ingredients (
+    item_id INTEGER NOT NULL REFERENCES menu_items(id),
+    ingredient_id INTEGER NOT NULL REFERENCES menu_ingredients(id),
+    PRIMARY KEY (item_id, ingredient_id)
+);
+
+CREATE INDEX idx_menu_item_ingredients_ingredient_id ON menu_item_ingredients(ingredient_id);
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS order_status_history_immutable ON order_status_history;
+DROP FUNCTION IF EXISTS prevent_order_status_history_change();
+DROP TABLE IF EXISTS order_status_history;
+DROP TABLE IF EXISTS order_lines;
+DROP TABLE IF EXISTS orders;
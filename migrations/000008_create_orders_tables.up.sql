@@ -0,0 +1,61 @@
+CREATE TABLE IF NOT EXISTS orders (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    user_id INTEGER REFERENCES users(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('dine_in', 'takeaway')),
+    status VARCHAR(20) NOT NULL,
+    currency CHAR(3) NOT NULL,
+    subtotal BIGINT NOT NULL DEFAULT 0,
+    total BIGINT NOT NULL DEFAULT 0,
+    notes TEXT,
+    placed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_orders_branch_id ON orders(branch_id);
+CREATE INDEX idx_orders_user_id ON orders(user_id);
+CREATE INDEX idx_orders_status ON orders(status);
+CREATE INDEX idx_orders_deleted_at ON orders(deleted_at);
+
+CREATE TABLE IF NOT EXISTS order_lines (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    menu_item_id INTEGER NOT NULL REFERENCES menu_items(id),
+    name VARCHAR(255) NOT NULL,
+    base_price BIGINT NOT NULL,
+    modifiers JSONB NOT NULL DEFAULT '[]',
+    unit_price BIGINT NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    line_total BIGINT NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_lines_order_id ON order_lines(order_id);
+CREATE INDEX idx_order_lines_menu_item_id ON order_lines(menu_item_id);
+
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id),
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    actor_id INTEGER REFERENCES users(id),
+    actor_role VARCHAR(20) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_status_history_order_id ON order_status_history(order_id);
+
+-- The status history is append-only
+CREATE OR REPLACE FUNCTION prevent_order_status_history_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'order_status_history is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER order_status_history_immutable
+    BEFORE UPDATE OR DELETE ON order_status_history
+    FOR EACH ROW EXECUTE FUNCTION prevent_order_status_history_change();
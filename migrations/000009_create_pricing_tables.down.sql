@@ -0,0 +1,16 @@
+ALTER TABLE order_lines DROP COLUMN IF EXISTS tags;
+ALTER TABLE order_lines DROP COLUMN IF EXISTS tax;
+ALTER TABLE order_lines DROP COLUMN IF EXISTS discount;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS rounding;
+ALTER TABLE orders DROP COLUMN IF EXISTS inclusive_tax_total;
+ALTER TABLE orders DROP COLUMN IF EXISTS tax_total;
+ALTER TABLE orders DROP COLUMN IF EXISTS taxes;
+ALTER TABLE orders DROP COLUMN IF EXISTS service_charge;
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_total;
+ALTER TABLE orders DROP COLUMN IF EXISTS discounts;
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_code;
+
+DROP TABLE IF EXISTS discounts;
+DROP TABLE IF EXISTS tax_rates;
+DROP TABLE IF EXISTS branch_pricing;
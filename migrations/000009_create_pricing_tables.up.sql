@@ -0,0 +1,55 @@
+CREATE TABLE IF NOT EXISTS branch_pricing (
+    branch_id INTEGER PRIMARY KEY REFERENCES branches(id),
+    service_charge_bps INTEGER NOT NULL DEFAULT 0 CHECK (service_charge_bps BETWEEN 0 AND 10000),
+    service_charge_taxable BOOLEAN NOT NULL DEFAULT false,
+    rounding_increment BIGINT NOT NULL DEFAULT 0 CHECK (rounding_increment >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS tax_rates (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    name VARCHAR(255) NOT NULL,
+    rate_bps INTEGER NOT NULL CHECK (rate_bps BETWEEN 0 AND 10000),
+    inclusive BOOLEAN NOT NULL DEFAULT false,
+    tags JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_tax_rates_branch_id ON tax_rates(branch_id);
+CREATE INDEX idx_tax_rates_deleted_at ON tax_rates(deleted_at);
+
+CREATE TABLE IF NOT EXISTS discounts (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('percent', 'fixed')),
+    value BIGINT NOT NULL CHECK (value > 0),
+    min_subtotal BIGINT NOT NULL DEFAULT 0,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_discounts_branch_code ON discounts(branch_id, code) WHERE deleted_at IS NULL;
+CREATE INDEX idx_discounts_deleted_at ON discounts(deleted_at);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS discount_code VARCHAR(50);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS discounts JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS discount_total BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS service_charge BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS taxes JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tax_total BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS inclusive_tax_total BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS rounding BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE order_lines ADD COLUMN IF NOT EXISTS discount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE order_lines ADD COLUMN IF NOT EXISTS tax BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE order_lines ADD COLUMN IF NOT EXISTS tags JSONB NOT NULL DEFAULT '[]';
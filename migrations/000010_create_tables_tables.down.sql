@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_orders_table_session_id;
+DROP INDEX IF EXISTS idx_orders_table_id;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS table_session_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS table_id;
+
+DROP TABLE IF EXISTS table_sessions;
+DROP TABLE IF EXISTS tables;
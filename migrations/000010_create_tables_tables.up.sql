@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS tables (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    name VARCHAR(50) NOT NULL,
+    capacity INTEGER NOT NULL CHECK (capacity > 0),
+    area VARCHAR(50),
+    status VARCHAR(20) NOT NULL DEFAULT 'free' CHECK (status IN ('free', 'occupied', 'reserved', 'cleaning')),
+    qr_version INTEGER NOT NULL DEFAULT 1,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_tables_branch_name ON tables(branch_id, name) WHERE deleted_at IS NULL;
+CREATE INDEX idx_tables_status ON tables(status);
+CREATE INDEX idx_tables_deleted_at ON tables(deleted_at);
+
+CREATE TABLE IF NOT EXISTS table_sessions (
+    id SERIAL PRIMARY KEY,
+    table_id INTEGER NOT NULL REFERENCES tables(id),
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_table_sessions_table_id ON table_sessions(table_id);
+CREATE INDEX idx_table_sessions_branch_id ON table_sessions(branch_id);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS table_id INTEGER REFERENCES tables(id);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS table_session_id INTEGER REFERENCES table_sessions(id);
+
+CREATE INDEX IF NOT EXISTS idx_orders_table_id ON orders(table_id);
+CREATE INDEX IF NOT EXISTS idx_orders_table_session_id ON orders(table_session_id);
@@ -0,0 +1,51 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE IF NOT EXISTS reservation_settings (
+    branch_id INTEGER PRIMARY KEY REFERENCES branches(id),
+    slot_minutes INTEGER NOT NULL DEFAULT 15 CHECK (slot_minutes > 0),
+    turn_minutes INTEGER NOT NULL DEFAULT 90 CHECK (turn_minutes > 0),
+    buffer_minutes INTEGER NOT NULL DEFAULT 15 CHECK (buffer_minutes >= 0),
+    first_seating VARCHAR(5) NOT NULL DEFAULT '11:00',
+    last_seating VARCHAR(5) NOT NULL DEFAULT '21:00',
+    min_notice_minutes INTEGER NOT NULL DEFAULT 60 CHECK (min_notice_minutes >= 0),
+    max_advance_days INTEGER NOT NULL DEFAULT 60 CHECK (max_advance_days > 0),
+    max_party_size INTEGER NOT NULL DEFAULT 12 CHECK (max_party_size > 0),
+    auto_confirm BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS reservations (
+    id SERIAL PRIMARY KEY,
+    reference VARCHAR(12) NOT NULL,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    table_id INTEGER NOT NULL REFERENCES tables(id),
+    user_id INTEGER REFERENCES users(id),
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    phone VARCHAR(30),
+    party_size INTEGER NOT NULL CHECK (party_size > 0),
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    blocked_until TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'confirmed', 'seated', 'completed', 'cancelled', 'no_show')),
+    status_reason TEXT,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CHECK (ends_at > starts_at AND blocked_until >= ends_at),
+    -- A table can only be held by one active reservation at a time
+    CONSTRAINT reservations_no_double_booking EXCLUDE USING gist (
+        table_id WITH =,
+        tstzrange(starts_at, blocked_until) WITH &&
+    ) WHERE (status IN ('pending', 'confirmed', 'seated') AND deleted_at IS NULL)
+);
+
+CREATE UNIQUE INDEX idx_reservations_reference ON reservations(reference);
+CREATE INDEX idx_reservations_branch_id ON reservations(branch_id);
+CREATE INDEX idx_reservations_table_id ON reservations(table_id);
+CREATE INDEX idx_reservations_user_id ON reservations(user_id);
+CREATE INDEX idx_reservations_starts_at ON reservations(starts_at);
+CREATE INDEX idx_reservations_status ON reservations(status);
+CREATE INDEX idx_reservations_deleted_at ON reservations(deleted_at);
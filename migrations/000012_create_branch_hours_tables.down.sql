@@ -0,0 +1,4 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS scheduled_for;
+
+DROP TABLE IF EXISTS branch_hours_exceptions;
+DROP TABLE IF EXISTS branch_opening_hours;
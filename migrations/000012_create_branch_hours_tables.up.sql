@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS branch_opening_hours (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    weekday INTEGER NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    opens VARCHAR(5) NOT NULL,
+    closes VARCHAR(5) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_branch_opening_hours_branch_id ON branch_opening_hours(branch_id);
+
+CREATE TABLE IF NOT EXISTS branch_hours_exceptions (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    date VARCHAR(10) NOT NULL,
+    name VARCHAR(255),
+    closed BOOLEAN NOT NULL DEFAULT false,
+    shifts JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_hours_exceptions_branch_date ON branch_hours_exceptions(branch_id, date);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS scheduled_for TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS kitchen_tickets;
+DROP TABLE IF EXISTS kitchen_station_items;
+DROP TABLE IF EXISTS kitchen_stations;
@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS kitchen_stations (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    name VARCHAR(50) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT false,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_kitchen_stations_branch_name ON kitchen_stations(branch_id, name) WHERE deleted_at IS NULL;
+CREATE INDEX idx_kitchen_stations_deleted_at ON kitchen_stations(deleted_at);
+
+CREATE TABLE IF NOT EXISTS kitchen_station_items (
+    menu_item_id INTEGER PRIMARY KEY REFERENCES menu_items(id) ON DELETE CASCADE,
+    station_id INTEGER NOT NULL REFERENCES kitchen_stations(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_kitchen_station_items_station_id ON kitchen_station_items(station_id);
+
+CREATE TABLE IF NOT EXISTS kitchen_tickets (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id),
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    station_id INTEGER NOT NULL REFERENCES kitchen_stations(id),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'ready', 'bumped', 'cancelled')),
+    order_type VARCHAR(20) NOT NULL,
+    table_id INTEGER REFERENCES tables(id),
+    notes TEXT,
+    lines JSONB NOT NULL DEFAULT '[]',
+    ready_at TIMESTAMP WITH TIME ZONE,
+    bumped_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_kitchen_tickets_order_id ON kitchen_tickets(order_id);
+CREATE INDEX idx_kitchen_tickets_branch_id ON kitchen_tickets(branch_id);
+CREATE INDEX idx_kitchen_tickets_station_id ON kitchen_tickets(station_id);
+CREATE INDEX idx_kitchen_tickets_status ON kitchen_tickets(status);
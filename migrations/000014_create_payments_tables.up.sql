@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS payments (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id),
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    method VARCHAR(20) NOT NULL CHECK (method IN ('card', 'cash', 'terminal')),
+    provider VARCHAR(30) NOT NULL,
+    provider_ref VARCHAR(100),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'authorized', 'captured', 'voided', 'failed')),
+    currency CHAR(3) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    captured_amount BIGINT NOT NULL DEFAULT 0 CHECK (captured_amount >= 0),
+    tendered BIGINT NOT NULL DEFAULT 0,
+    change BIGINT NOT NULL DEFAULT 0,
+    reference VARCHAR(100),
+    failure_reason TEXT,
+    created_by_id INTEGER REFERENCES users(id),
+    authorized_at TIMESTAMP WITH TIME ZONE,
+    captured_at TIMESTAMP WITH TIME ZONE,
+    voided_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_payments_provider_ref ON payments(provider, provider_ref);
+CREATE INDEX idx_payments_order_id ON payments(order_id);
+CREATE INDEX idx_payments_branch_id ON payments(branch_id);
+CREATE INDEX idx_payments_status ON payments(status);
+
+CREATE TABLE IF NOT EXISTS payment_webhook_events (
+    id SERIAL PRIMARY KEY,
+    provider VARCHAR(30) NOT NULL,
+    event_id VARCHAR(100) NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    reference VARCHAR(100) NOT NULL,
+    amount BIGINT NOT NULL DEFAULT 0,
+    payment_id INTEGER REFERENCES payments(id),
+    outcome VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_payment_webhook_events_event ON payment_webhook_events(provider, event_id);
+CREATE INDEX idx_payment_webhook_events_payment_id ON payment_webhook_events(payment_id);
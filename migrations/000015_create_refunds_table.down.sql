@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS refunds;
+ALTER TABLE payments DROP CONSTRAINT IF EXISTS chk_payments_refunded_amount;
+ALTER TABLE payments DROP COLUMN IF EXISTS refunded_amount;
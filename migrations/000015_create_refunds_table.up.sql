@@ -0,0 +1,27 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS refunded_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE payments ADD CONSTRAINT chk_payments_refunded_amount CHECK (refunded_amount >= 0 AND refunded_amount <= captured_amount);
+
+CREATE TABLE IF NOT EXISTS refunds (
+    id SERIAL PRIMARY KEY,
+    payment_id INTEGER NOT NULL REFERENCES payments(id),
+    order_id INTEGER NOT NULL REFERENCES orders(id),
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    method VARCHAR(20) NOT NULL,
+    provider VARCHAR(30) NOT NULL,
+    provider_ref VARCHAR(100),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'succeeded', 'failed')),
+    currency CHAR(3) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    reason TEXT NOT NULL CHECK (reason <> ''),
+    lines JSONB NOT NULL DEFAULT '[]',
+    failure_reason TEXT,
+    created_by_id INTEGER REFERENCES users(id),
+    refunded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_refunds_payment_id ON refunds(payment_id);
+CREATE INDEX idx_refunds_order_id ON refunds(order_id);
+CREATE INDEX idx_refunds_branch_id ON refunds(branch_id);
+CREATE INDEX idx_refunds_status ON refunds(status);
@@ -0,0 +1,4 @@
+ALTER TABLE payments DROP COLUMN IF EXISTS tip_staff_id;
+ALTER TABLE payments DROP COLUMN IF EXISTS tip;
+ALTER TABLE payments DROP COLUMN IF EXISTS bill_id;
+DROP TABLE IF EXISTS order_bills;
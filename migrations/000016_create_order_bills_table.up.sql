@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS order_bills (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id),
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    position INTEGER NOT NULL,
+    label VARCHAR(100) NOT NULL,
+    mode VARCHAR(20) NOT NULL CHECK (mode IN ('items', 'equal', 'custom')),
+    currency CHAR(3) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount >= 0),
+    lines JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_bills_order_id ON order_bills(order_id);
+CREATE INDEX idx_order_bills_branch_id ON order_bills(branch_id);
+
+-- Failed and voided payments of replaced bills keep their row
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS bill_id INTEGER REFERENCES order_bills(id) ON DELETE SET NULL;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS tip BIGINT NOT NULL DEFAULT 0 CHECK (tip >= 0);
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS tip_staff_id INTEGER REFERENCES users(id);
+
+CREATE INDEX idx_payments_bill_id ON payments(bill_id);
+CREATE INDEX idx_payments_tip_staff_id ON payments(tip_staff_id);
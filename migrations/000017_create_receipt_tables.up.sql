@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS receipt_templates (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INTEGER NOT NULL UNIQUE REFERENCES restaurants(id),
+    header JSONB NOT NULL DEFAULT '[]',
+    footer JSONB NOT NULL DEFAULT '[]',
+    legal_name VARCHAR(200),
+    tax_id VARCHAR(50),
+    invoice_prefix VARCHAR(20) NOT NULL,
+    width INTEGER NOT NULL CHECK (width BETWEEN 32 AND 64),
+    show_taxes BOOLEAN NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Invoice numbers are taken from the counter row of the branch, which is
+-- locked until the invoice is written, so they run without gaps
+CREATE TABLE IF NOT EXISTS invoice_counters (
+    branch_id INTEGER PRIMARY KEY REFERENCES branches(id),
+    last BIGINT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL UNIQUE REFERENCES orders(id),
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    sequence BIGINT NOT NULL,
+    number VARCHAR(50) NOT NULL,
+    customer_name VARCHAR(200) NOT NULL,
+    customer_tax_id VARCHAR(50) NOT NULL,
+    customer_address TEXT,
+    created_by_id INTEGER REFERENCES users(id),
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT idx_invoices_branch_sequence UNIQUE (branch_id, sequence)
+);
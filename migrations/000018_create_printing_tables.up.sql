@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS printers (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    name VARCHAR(50) NOT NULL,
+    transport VARCHAR(10) NOT NULL CHECK (transport IN ('tcp', 'file')),
+    address VARCHAR(255) NOT NULL,
+    width INTEGER NOT NULL CHECK (width BETWEEN 32 AND 64),
+    station_id INTEGER REFERENCES kitchen_stations(id),
+    prints_receipts BOOLEAN NOT NULL DEFAULT FALSE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_printers_branch_name ON printers(branch_id, name) WHERE deleted_at IS NULL;
+CREATE INDEX idx_printers_station_id ON printers(station_id);
+CREATE INDEX idx_printers_deleted_at ON printers(deleted_at);
+
+-- Ticket jobs are rendered when they are sent and have no data
+CREATE TABLE IF NOT EXISTS print_jobs (
+    id SERIAL PRIMARY KEY,
+    printer_id INTEGER NOT NULL REFERENCES printers(id),
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('ticket', 'cancellation', 'receipt', 'test')),
+    source_id INTEGER,
+    data BYTEA,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('queued', 'printed', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    printed_at TIMESTAMP WITH TIME ZONE,
+    created_by_id INTEGER REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_print_jobs_printer_id ON print_jobs(printer_id);
+CREATE INDEX idx_print_jobs_branch_id ON print_jobs(branch_id);
+CREATE INDEX idx_print_jobs_status ON print_jobs(status);
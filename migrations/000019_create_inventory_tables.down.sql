@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS inventory_movements;
+DROP TABLE IF EXISTS inventory_stock;
+
+ALTER TABLE menu_item_ingredients DROP COLUMN IF EXISTS quantity;
+ALTER TABLE menu_ingredients DROP COLUMN IF EXISTS unit;
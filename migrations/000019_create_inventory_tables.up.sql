@@ -0,0 +1,36 @@
+-- Recipes: how much of each ingredient one serving of a menu item uses
+ALTER TABLE menu_ingredients
+    ADD COLUMN IF NOT EXISTS unit VARCHAR(10) NOT NULL DEFAULT 'pc'
+    CHECK (unit IN ('g', 'kg', 'ml', 'l', 'pc'));
+
+ALTER TABLE menu_item_ingredients
+    ADD COLUMN IF NOT EXISTS quantity NUMERIC(14,3) NOT NULL DEFAULT 0
+    CHECK (quantity >= 0);
+
+-- Stock may go below zero when more was sold than was bought
+CREATE TABLE IF NOT EXISTS inventory_stock (
+    ingredient_id INTEGER PRIMARY KEY REFERENCES menu_ingredients(id),
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    quantity NUMERIC(14,3) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_inventory_stock_branch_id ON inventory_stock(branch_id);
+
+CREATE TABLE IF NOT EXISTS inventory_movements (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    ingredient_id INTEGER NOT NULL REFERENCES menu_ingredients(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('purchase', 'waste', 'adjustment', 'sale')),
+    quantity NUMERIC(14,3) NOT NULL,
+    balance NUMERIC(14,3) NOT NULL,
+    order_id INTEGER REFERENCES orders(id),
+    note TEXT,
+    created_by_id INTEGER REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_inventory_movements_branch_id ON inventory_movements(branch_id);
+CREATE INDEX idx_inventory_movements_ingredient_id ON inventory_movements(ingredient_id);
+CREATE INDEX idx_inventory_movements_type ON inventory_movements(type);
+CREATE INDEX idx_inventory_movements_order_id ON inventory_movements(order_id);
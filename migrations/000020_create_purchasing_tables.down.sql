@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_inventory_movements_purchase_order_id;
+ALTER TABLE inventory_movements DROP COLUMN IF EXISTS purchase_order_id;
+ALTER TABLE inventory_stock DROP COLUMN IF EXISTS reorder_quantity;
+ALTER TABLE inventory_stock DROP COLUMN IF EXISTS reorder_level;
+
+DROP TABLE IF EXISTS purchase_order_lines;
+DROP TABLE IF EXISTS purchase_orders;
+DROP TABLE IF EXISTS supplier_ingredients;
+DROP TABLE IF EXISTS suppliers;
@@ -0,0 +1,67 @@
+CREATE TABLE IF NOT EXISTS suppliers (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    name VARCHAR(100) NOT NULL,
+    contact_name VARCHAR(100),
+    email VARCHAR(255),
+    phone VARCHAR(30),
+    notes TEXT,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_suppliers_branch_id ON suppliers(branch_id);
+CREATE INDEX idx_suppliers_deleted_at ON suppliers(deleted_at);
+
+-- The catalog of a supplier: the ingredients it sells and their unit cost
+CREATE TABLE IF NOT EXISTS supplier_ingredients (
+    supplier_id INTEGER NOT NULL REFERENCES suppliers(id),
+    ingredient_id INTEGER NOT NULL REFERENCES menu_ingredients(id),
+    unit_cost BIGINT NOT NULL DEFAULT 0 CHECK (unit_cost >= 0),
+    PRIMARY KEY (supplier_id, ingredient_id)
+);
+
+CREATE INDEX idx_supplier_ingredients_ingredient_id ON supplier_ingredients(ingredient_id);
+
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    supplier_id INTEGER NOT NULL REFERENCES suppliers(id),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('draft', 'sent', 'partially_received', 'received')),
+    currency CHAR(3),
+    note TEXT,
+    total BIGINT NOT NULL DEFAULT 0,
+    created_by_id INTEGER REFERENCES users(id),
+    sent_at TIMESTAMP WITH TIME ZONE,
+    received_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_purchase_orders_branch_id ON purchase_orders(branch_id);
+CREATE INDEX idx_purchase_orders_supplier_id ON purchase_orders(supplier_id);
+CREATE INDEX idx_purchase_orders_status ON purchase_orders(status);
+
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+    id SERIAL PRIMARY KEY,
+    purchase_order_id INTEGER NOT NULL REFERENCES purchase_orders(id),
+    ingredient_id INTEGER NOT NULL REFERENCES menu_ingredients(id),
+    name VARCHAR(100) NOT NULL,
+    unit VARCHAR(10) NOT NULL,
+    quantity NUMERIC(14,3) NOT NULL CHECK (quantity > 0),
+    received_quantity NUMERIC(14,3) NOT NULL DEFAULT 0 CHECK (received_quantity >= 0 AND received_quantity <= quantity),
+    unit_cost BIGINT NOT NULL DEFAULT 0 CHECK (unit_cost >= 0),
+    total BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_purchase_order_lines_purchase_order_id ON purchase_order_lines(purchase_order_id);
+CREATE INDEX idx_purchase_order_lines_ingredient_id ON purchase_order_lines(ingredient_id);
+
+-- Low stock thresholds; purchase orders are suggested at or below the level
+ALTER TABLE inventory_stock ADD COLUMN IF NOT EXISTS reorder_level NUMERIC(14,3) CHECK (reorder_level >= 0);
+ALTER TABLE inventory_stock ADD COLUMN IF NOT EXISTS reorder_quantity NUMERIC(14,3) NOT NULL DEFAULT 0 CHECK (reorder_quantity >= 0);
+
+ALTER TABLE inventory_movements ADD COLUMN IF NOT EXISTS purchase_order_id INTEGER REFERENCES purchase_orders(id);
+CREATE INDEX idx_inventory_movements_purchase_order_id ON inventory_movements(purchase_order_id);
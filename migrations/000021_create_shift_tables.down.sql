@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS staff_clock_pins;
+DROP TABLE IF EXISTS time_entries;
+DROP TABLE IF EXISTS shifts;
+DROP TABLE IF EXISTS labor_settings;
@@ -0,0 +1,71 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE IF NOT EXISTS labor_settings (
+    branch_id INTEGER PRIMARY KEY REFERENCES branches(id),
+    pay_period VARCHAR(20) NOT NULL DEFAULT 'weekly' CHECK (pay_period IN ('weekly', 'biweekly', 'semimonthly', 'monthly')),
+    period_anchor VARCHAR(10) NOT NULL DEFAULT '2024-01-01',
+    daily_overtime_minutes INTEGER NOT NULL DEFAULT 480 CHECK (daily_overtime_minutes >= 0),
+    weekly_overtime_minutes INTEGER NOT NULL DEFAULT 2400 CHECK (weekly_overtime_minutes >= 0),
+    break_after_minutes INTEGER NOT NULL DEFAULT 360 CHECK (break_after_minutes >= 0),
+    break_minutes INTEGER NOT NULL DEFAULT 30 CHECK (break_minutes >= 0),
+    clock_in_window_minutes INTEGER NOT NULL DEFAULT 30 CHECK (clock_in_window_minutes >= 0),
+    require_pin BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS shifts (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    position VARCHAR(50),
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    note TEXT,
+    created_by_id INTEGER REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CHECK (ends_at > starts_at AND ends_at <= starts_at + INTERVAL '24 hours'),
+    -- A staff member works one shift at a time, across all branches
+    CONSTRAINT shifts_no_overlap EXCLUDE USING gist (
+        user_id WITH =,
+        tstzrange(starts_at, ends_at) WITH &&
+    ) WHERE (deleted_at IS NULL)
+);
+
+CREATE INDEX idx_shifts_branch_id ON shifts(branch_id);
+CREATE INDEX idx_shifts_user_id ON shifts(user_id);
+CREATE INDEX idx_shifts_starts_at ON shifts(starts_at);
+CREATE INDEX idx_shifts_deleted_at ON shifts(deleted_at);
+
+CREATE TABLE IF NOT EXISTS time_entries (
+    id SERIAL PRIMARY KEY,
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    shift_id INTEGER REFERENCES shifts(id),
+    clock_in_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    clock_out_at TIMESTAMP WITH TIME ZONE,
+    break_minutes INTEGER NOT NULL DEFAULT 0 CHECK (break_minutes >= 0),
+    worked_minutes INTEGER NOT NULL DEFAULT 0 CHECK (worked_minutes >= 0),
+    note TEXT,
+    edited_by_id INTEGER REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (clock_out_at IS NULL OR clock_out_at > clock_in_at)
+);
+
+CREATE INDEX idx_time_entries_branch_id ON time_entries(branch_id);
+CREATE INDEX idx_time_entries_user_id ON time_entries(user_id);
+CREATE INDEX idx_time_entries_shift_id ON time_entries(shift_id);
+CREATE INDEX idx_time_entries_clock_in_at ON time_entries(clock_in_at);
+-- A staff member is clocked in at most once at a time
+CREATE UNIQUE INDEX idx_time_entries_open ON time_entries(user_id) WHERE clock_out_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS staff_clock_pins (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id),
+    pin_hash VARCHAR(255) NOT NULL,
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
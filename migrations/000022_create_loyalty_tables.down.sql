@@ -0,0 +1,6 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS redeem_points;
+
+DROP TABLE IF EXISTS loyalty_entries;
+DROP TABLE IF EXISTS loyalty_accounts;
+DROP TABLE IF EXISTS loyalty_tiers;
+DROP TABLE IF EXISTS loyalty_settings;
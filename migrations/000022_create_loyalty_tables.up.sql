@@ -0,0 +1,55 @@
+CREATE TABLE IF NOT EXISTS loyalty_settings (
+    branch_id INTEGER PRIMARY KEY REFERENCES branches(id),
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    earn_unit BIGINT NOT NULL DEFAULT 100 CHECK (earn_unit > 0),
+    points_per_unit BIGINT NOT NULL DEFAULT 1 CHECK (points_per_unit >= 0),
+    point_value BIGINT NOT NULL DEFAULT 1 CHECK (point_value > 0),
+    min_redeem_points BIGINT NOT NULL DEFAULT 0 CHECK (min_redeem_points >= 0),
+    expiry_days INTEGER NOT NULL DEFAULT 365 CHECK (expiry_days >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS loyalty_tiers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    min_points BIGINT NOT NULL CHECK (min_points >= 0),
+    multiplier_bps INTEGER NOT NULL DEFAULT 10000 CHECK (multiplier_bps > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_loyalty_tiers_min_points ON loyalty_tiers(min_points) WHERE deleted_at IS NULL;
+CREATE INDEX idx_loyalty_tiers_deleted_at ON loyalty_tiers(deleted_at);
+
+CREATE TABLE IF NOT EXISTS loyalty_accounts (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id),
+    balance BIGINT NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    lifetime_points BIGINT NOT NULL DEFAULT 0 CHECK (lifetime_points >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS loyalty_entries (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('earn', 'redeem', 'expire', 'adjust')),
+    points BIGINT NOT NULL,
+    balance BIGINT NOT NULL CHECK (balance >= 0),
+    remaining BIGINT NOT NULL DEFAULT 0 CHECK (remaining >= 0),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    branch_id INTEGER REFERENCES branches(id),
+    order_id INTEGER REFERENCES orders(id),
+    actor_id INTEGER REFERENCES users(id),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_loyalty_entries_user_id ON loyalty_entries(user_id);
+CREATE INDEX idx_loyalty_entries_order_id ON loyalty_entries(order_id);
+CREATE INDEX idx_loyalty_entries_created_at ON loyalty_entries(created_at);
+-- Lots with points left, in the order they are used up and expire
+CREATE INDEX idx_loyalty_entries_lots ON loyalty_entries(user_id, expires_at) WHERE remaining > 0;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS redeem_points BIGINT NOT NULL DEFAULT 0 CHECK (redeem_points >= 0);
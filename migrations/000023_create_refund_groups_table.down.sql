@@ -0,0 +1,12 @@
+ALTER TABLE refunds ADD COLUMN IF NOT EXISTS lines JSONB NOT NULL DEFAULT '[]';
+
+-- Lines go back to the first refund of their group
+UPDATE refunds r SET lines = g.lines
+FROM refund_groups g
+WHERE g.id = r.group_id
+  AND r.id = (SELECT MIN(id) FROM refunds WHERE group_id = g.id);
+
+DROP INDEX IF EXISTS idx_refunds_group_id;
+ALTER TABLE refunds DROP COLUMN IF EXISTS group_id;
+
+DROP TABLE IF EXISTS refund_groups;
@@ -0,0 +1,33 @@
+-- A refund request split over several payments is one group keeping the
+-- refunded lines; its status follows its refunds
+CREATE TABLE IF NOT EXISTS refund_groups (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id),
+    branch_id INTEGER NOT NULL REFERENCES branches(id),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'succeeded', 'failed', 'partial')),
+    currency CHAR(3) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    reason TEXT NOT NULL CHECK (reason <> ''),
+    lines JSONB NOT NULL DEFAULT '[]',
+    created_by_id INTEGER REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_refund_groups_order_id ON refund_groups(order_id);
+CREATE INDEX idx_refund_groups_branch_id ON refund_groups(branch_id);
+CREATE INDEX idx_refund_groups_status ON refund_groups(status);
+
+-- Existing refunds each become a group of their own
+INSERT INTO refund_groups (id, order_id, branch_id, status, currency, amount, reason, lines, created_by_id, created_at, updated_at)
+SELECT id, order_id, branch_id, status, currency, amount, reason, lines, created_by_id, created_at, updated_at
+FROM refunds;
+
+SELECT setval(pg_get_serial_sequence('refund_groups', 'id'), COALESCE((SELECT MAX(id) FROM refund_groups), 0) + 1, false);
+
+ALTER TABLE refunds ADD COLUMN IF NOT EXISTS group_id INTEGER REFERENCES refund_groups(id);
+UPDATE refunds SET group_id = id;
+ALTER TABLE refunds ALTER COLUMN group_id SET NOT NULL;
+ALTER TABLE refunds DROP COLUMN IF EXISTS lines;
+
+CREATE INDEX idx_refunds_group_id ON refunds(group_id);
@@ -0,0 +1,10 @@
+-- Which lots each redemption or negative adjustment used up, so cancelled
+-- redemptions go back to their lots and keep their expiry
+CREATE TABLE IF NOT EXISTS loyalty_lot_uses (
+    id SERIAL PRIMARY KEY,
+    entry_id INTEGER NOT NULL REFERENCES loyalty_entries(id),
+    lot_id INTEGER NOT NULL REFERENCES loyalty_entries(id),
+    points BIGINT NOT NULL CHECK (points > 0)
+);
+
+CREATE INDEX idx_loyalty_lot_uses_entry_id ON loyalty_lot_uses(entry_id);
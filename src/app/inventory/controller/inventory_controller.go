@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/faisd405/go-restapi-gin/src/app/inventory/model"
+	"github.com/faisd405/go-restapi-gin/src/app/inventory/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type InventoryController struct {
+	inventoryService service.InventoryService
+}
+
+func NewInventoryController(inventoryService service.InventoryService) *InventoryController {
+	return &InventoryController{inventoryService: inventoryService}
+}
+
+// GetStock godoc
+// @Summary Get stock levels (Admin/Manager)
+// @Description Get the quantity on hand of every ingredient of a branch
+// @Tags inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /branches/{id}/stock [get]
+func (ctrl *InventoryController) GetStock(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	stock, err := ctrl.inventoryService.GetStock(actor, id)
+	if err != nil {
+		inventoryErrorResponse(c, "Failed to retrieve stock", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Stock retrieved successfully", stock)
+}
+
+// GetMovements godoc
+// @Summary Get stock movements (Admin/Manager)
+// @Description Get the stock ledger of a branch, newest first
+// @Tags inventory
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param ingredient_id query int false "Ingredient ID"
+// @Param type query string false "purchase, waste, adjustment or sale"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /branches/{id}/stock/movements [get]
+func (ctrl *InventoryController) GetMovements(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	filter := model.MovementFilter{Type: c.Query("type")}
+	if value := c.Query("ingredient_id"); value != "" {
+		ingredientID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid ingredient ID", err.Error())
+			return
+		}
+		filter.IngredientID = uint(ingredientID)
+	}
+
+	page, limit := utils.GetPagination(c)
+
+	movements, total, err := ctrl.inventoryService.GetMovements(actor, id, page, limit, filter)
+	if err != nil {
+		inventoryErrorResponse(c, "Failed to retrieve stock movements", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Stock movements retrieved successfully",
+		utils.PaginatedData("movements", movements, page, limit, total))
+}
+
+// RecordMovement godoc
+// @Summary Record stock movement (Admin/Manager)
+// @Description Book a purchase, waste or adjustment. An adjustment takes either a signed quantity or the counted stock. Menu items of an ingredient that runs out are marked unavailable.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param movement body model.MovementRequest true "Movement"
+// @Success 201 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /branches/{id}/stock/movements [post]
+func (ctrl *InventoryController) RecordMovement(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.MovementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := ctrl.inventoryService.RecordMovement(actor, id, req)
+	if err != nil {
+		inventoryErrorResponse(c, "Failed to record stock movement", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Stock movement recorded successfully", result)
+}
+
+// SetReorderLevel godoc
+// @Summary Set reorder level (Admin/Manager)
+// @Description Set the stock level at which an ingredient is low and the quantity suggested to buy then. A null reorder_level stops the suggestions.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param reorder body model.ReorderRequest true "Reorder level"
+// @Success 200 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /branches/{id}/stock/reorder-levels [put]
+func (ctrl *InventoryController) SetReorderLevel(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	level, err := ctrl.inventoryService.SetReorderLevel(actor, id, req)
+	if err != nil {
+		inventoryErrorResponse(c, "Failed to set reorder level", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Reorder level set successfully", level)
+}
+
+func inventoryErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrIngredientNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, message, err.Error())
+	case errors.Is(err, service.ErrInvalidQuantity),
+		errors.Is(err, service.ErrInvalidReorder),
+		errors.Is(err, model.ErrInvalidMovementType):
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, message, err.Error())
+	default:
+		utils.DatabaseErrorResponse(c, message, err)
+	}
+}
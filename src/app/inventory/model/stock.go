@@ -0,0 +1,121 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	menumodel "github.com/faisd405/go-restapi-gin/src/app/menu/model"
+)
+
+// Movement types. Purchases add stock, waste and sales take it away and
+// adjustments correct it after a stock count.
+const (
+	MovementPurchase   = "purchase"
+	MovementWaste      = "waste"
+	MovementAdjustment = "adjustment"
+	MovementSale       = "sale"
+)
+
+var ErrInvalidMovementType = errors.New("unknown stock movement type")
+
+// Stock is the quantity of an ingredient a branch has on hand, in the unit
+// of the ingredient. It goes below zero when more was sold than was
+// recorded as bought. Once it falls to ReorderLevel, ReorderQuantity is
+// suggested to be bought.
+type Stock struct {
+	IngredientID    uint      `json:"ingredient_id" gorm:"primaryKey;autoIncrement:false"`
+	BranchID        uint      `json:"branch_id" gorm:"not null;index"`
+	Quantity        float64   `json:"quantity" gorm:"type:numeric(14,3);not null;default:0"`
+	ReorderLevel    *float64  `json:"reorder_level" gorm:"type:numeric(14,3)"`
+	ReorderQuantity float64   `json:"reorder_quantity" gorm:"type:numeric(14,3);not null;default:0"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// IsLow reports whether the stock fell to its reorder level
+func (s *Stock) IsLow() bool {
+	return s.ReorderLevel != nil && s.Quantity <= *s.ReorderLevel
+}
+
+func (Stock) TableName() string {
+	return "inventory_stock"
+}
+
+// Movement is an entry of the stock ledger of a branch. Quantity is signed:
+// positive when stock was added. Balance is the stock after the movement.
+type Movement struct {
+	ID              uint                  `json:"id" gorm:"primaryKey"`
+	BranchID        uint                  `json:"branch_id" gorm:"not null;index"`
+	IngredientID    uint                  `json:"ingredient_id" gorm:"not null;index"`
+	Ingredient      *menumodel.Ingredient `json:"ingredient,omitempty" gorm:"foreignKey:IngredientID"`
+	Type            string                `json:"type" gorm:"type:varchar(20);not null;index"`
+	Quantity        float64               `json:"quantity" gorm:"type:numeric(14,3);not null"`
+	Balance         float64               `json:"balance" gorm:"type:numeric(14,3);not null"`
+	OrderID         *uint                 `json:"order_id" gorm:"index"`
+	PurchaseOrderID *uint                 `json:"purchase_order_id" gorm:"index"`
+	Note            string                `json:"note" gorm:"type:text"`
+	CreatedByID     *uint                 `json:"created_by_id"`
+	CreatedAt       time.Time             `json:"created_at"`
+}
+
+func (Movement) TableName() string {
+	return "inventory_movements"
+}
+
+// IsValidMovementType reports whether t is one of the movement types
+func IsValidMovementType(t string) bool {
+	switch t {
+	case MovementPurchase, MovementWaste, MovementAdjustment, MovementSale:
+		return true
+	}
+	return false
+}
+
+// StockLevel is an ingredient of a branch with the quantity on hand
+type StockLevel struct {
+	IngredientID    uint       `json:"ingredient_id"`
+	Name            string     `json:"name"`
+	Unit            string     `json:"unit"`
+	Quantity        float64    `json:"quantity"`
+	ReorderLevel    *float64   `json:"reorder_level"`
+	ReorderQuantity float64    `json:"reorder_quantity"`
+	Low             bool       `json:"low"`
+	UpdatedAt       *time.Time `json:"updated_at"`
+}
+
+// MovementRequest records a purchase, waste or adjustment. Purchases and
+// waste give the quantity added or thrown away; adjustments give either the
+// quantity to add or take away, or the counted quantity on hand.
+type MovementRequest struct {
+	IngredientID uint     `json:"ingredient_id" binding:"required"`
+	Type         string   `json:"type" binding:"required,oneof=purchase waste adjustment"`
+	Quantity     float64  `json:"quantity" binding:"gte=-1000000,lte=1000000"`
+	Counted      *float64 `json:"counted" binding:"omitempty,gte=0,lte=1000000"`
+	Note         string   `json:"note" binding:"max=500"`
+}
+
+// ReorderRequest sets when an ingredient is low on stock and how much of
+// it to buy then. A nil ReorderLevel stops suggesting purchases.
+type ReorderRequest struct {
+	IngredientID    uint     `json:"ingredient_id" binding:"required"`
+	ReorderLevel    *float64 `json:"reorder_level" binding:"omitempty,gte=0,lte=1000000"`
+	ReorderQuantity float64  `json:"reorder_quantity" binding:"gte=0,lte=1000000"`
+}
+
+// MovementResult is a recorded movement with the menu items it took off
+// the menu because an ingredient ran out
+type MovementResult struct {
+	Movement           *Movement `json:"movement"`
+	UnavailableItemIDs []uint    `json:"unavailable_item_ids"`
+}
+
+// MovementFilter narrows down the stock ledger
+type MovementFilter struct {
+	IngredientID uint
+	Type         string
+}
+
+// RoundQuantity rounds a quantity to the three decimals stock is kept in
+func RoundQuantity(q float64) float64 {
+	return math.Round(q*1000) / 1000
+}
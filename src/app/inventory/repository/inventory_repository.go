@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/inventory/model"
+	menumodel "github.com/faisd405/go-restapi-gin/src/app/menu/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StockRepository interface {
+	First(q *database.Query) (*model.Stock, error)
+	Find(q *database.Query) ([]model.Stock, error)
+	Upsert(stock *model.Stock, conflictColumns []string, updateColumns ...string) error
+	// GetForUpdate loads the stock of an ingredient and locks it until the
+	// surrounding transaction ends. Ingredients never stocked have none.
+	GetForUpdate(ingredientID, branchID uint) (*model.Stock, error)
+	// Apply adds a movement to the ledger and its quantity to the stock of
+	// its ingredient, and sets the balance of the movement
+	Apply(movement *model.Movement) error
+	// Consume takes the recipe quantities of sold menu items, counted by
+	// item ID, off stock and records them as sales of an order
+	Consume(branchID, orderID uint, items map[uint]int) ([]model.Movement, error)
+	// Release puts back on stock what the sales of an order took off, by
+	// recording reversing sales, and returns the reversing movements
+	Release(branchID, orderID uint) ([]model.Movement, error)
+	// FlagUnavailable takes the menu items that use any of ingredientIDs
+	// in their recipe off the menu and returns their IDs
+	FlagUnavailable(ingredientIDs []uint) ([]uint, error)
+	WithTx(tx *gorm.DB) StockRepository
+}
+
+type stockRepository struct {
+	database.Repository[model.Stock]
+}
+
+func NewStockRepository(db *gorm.DB) StockRepository {
+	return &stockRepository{Repository: database.NewRepository[model.Stock](db)}
+}
+
+func (r *stockRepository) GetForUpdate(ingredientID, branchID uint) (*model.Stock, error) {
+	var stock model.Stock
+	err := r.DB().Clauses(clause.Locking{Strength: "UPDATE"}).First(&stock, "ingredient_id = ?", ingredientID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &model.Stock{IngredientID: ingredientID, BranchID: branchID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stock, nil
+}
+
+func (r *stockRepository) Apply(movement *model.Movement) error {
+	movement.Quantity = model.RoundQuantity(movement.Quantity)
+
+	var balance float64
+	err := r.DB().Raw(`INSERT INTO inventory_stock (ingredient_id, branch_id, quantity, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (ingredient_id) DO UPDATE
+		SET quantity = inventory_stock.quantity + EXCLUDED.quantity, updated_at = EXCLUDED.updated_at
+		RETURNING quantity`,
+		movement.IngredientID, movement.BranchID, movement.Quantity, time.Now()).
+		Scan(&balance).Error
+	if err != nil {
+		return database.TranslateError(err)
+	}
+
+	movement.Balance = balance
+	return database.TranslateError(r.DB().Omit("Ingredient").Create(movement).Error)
+}
+
+func (r *stockRepository) Consume(branchID, orderID uint, items map[uint]int) ([]model.Movement, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+	itemIDs := make([]uint, 0, len(items))
+	for itemID := range items {
+		itemIDs = append(itemIDs, itemID)
+	}
+
+	var links []menumodel.ItemIngredient
+	if err := r.DB().Where("item_id IN ? AND quantity > 0", itemIDs).Find(&links).Error; err != nil {
+		return nil, database.TranslateError(err)
+	}
+
+	usage := make(map[uint]float64)
+	for _, link := range links {
+		usage[link.IngredientID] += link.Quantity * float64(items[link.ItemID])
+	}
+
+	// Stock rows are taken in ID order so concurrent orders cannot deadlock
+	ingredientIDs := make([]uint, 0, len(usage))
+	for ingredientID := range usage {
+		ingredientIDs = append(ingredientIDs, ingredientID)
+	}
+	sort.Slice(ingredientIDs, func(i, j int) bool { return ingredientIDs[i] < ingredientIDs[j] })
+
+	movements := make([]model.Movement, 0, len(ingredientIDs))
+	for _, ingredientID := range ingredientIDs {
+		movement := model.Movement{
+			BranchID:     branchID,
+			IngredientID: ingredientID,
+			Type:         model.MovementSale,
+			Quantity:     -usage[ingredientID],
+			OrderID:      &orderID,
+		}
+		if err := r.Apply(&movement); err != nil {
+			return nil, err
+		}
+		movements = append(movements, movement)
+	}
+	return movements, nil
+}
+
+func (r *stockRepository) Release(branchID, orderID uint) ([]model.Movement, error) {
+	// Sales are summed so an order is never put back twice
+	var sold []struct {
+		IngredientID uint
+		Quantity     float64
+	}
+	err := r.DB().Model(&model.Movement{}).
+		Select("ingredient_id, SUM(quantity) AS quantity").
+		Where("order_id = ? AND type = ?", orderID, model.MovementSale).
+		Group("ingredient_id").
+		Having("SUM(quantity) < 0").
+		Order("ingredient_id").
+		Scan(&sold).Error
+	if err != nil {
+		return nil, database.TranslateError(err)
+	}
+
+	movements := make([]model.Movement, 0, len(sold))
+	for _, sale := range sold {
+		movement := model.Movement{
+			BranchID:     branchID,
+			IngredientID: sale.IngredientID,
+			Type:         model.MovementSale,
+			Quantity:     -sale.Quantity,
+			OrderID:      &orderID,
+			Note:         fmt.Sprintf("Order #%d cancelled", orderID),
+		}
+		if err := r.Apply(&movement); err != nil {
+			return nil, err
+		}
+		movements = append(movements, movement)
+	}
+	return movements, nil
+}
+
+func (r *stockRepository) FlagUnavailable(ingredientIDs []uint) ([]uint, error) {
+	if len(ingredientIDs) == 0 {
+		return nil, nil
+	}
+
+	var items []menumodel.Item
+	err := r.DB().Model(&items).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Where("is_available AND id IN (?)", r.DB().Model(&menumodel.ItemIngredient{}).
+			Select("item_id").
+			Where("ingredient_id IN ? AND quantity > 0", ingredientIDs)).
+		Update("is_available", false).Error
+	if err != nil {
+		return nil, database.TranslateError(err)
+	}
+
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids, nil
+}
+
+func (r *stockRepository) WithTx(tx *gorm.DB) StockRepository {
+	return &stockRepository{Repository: r.Repository.WithTx(tx)}
+}
+
+type MovementRepository interface {
+	FindPage(q *database.Query) ([]model.Movement, int64, error)
+	WithTx(tx *gorm.DB) MovementRepository
+}
+
+type movementRepository struct {
+	database.Repository[model.Movement]
+}
+
+func NewMovementRepository(db *gorm.DB) MovementRepository {
+	return &movementRepository{Repository: database.NewRepository[model.Movement](db)}
+}
+
+func (r *movementRepository) WithTx(tx *gorm.DB) MovementRepository {
+	return &movementRepository{Repository: r.Repository.WithTx(tx)}
+}
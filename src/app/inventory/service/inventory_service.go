@@ -0,0 +1,233 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/inventory/model"
+	"github.com/faisd405/go-restapi-gin/src/app/inventory/repository"
+	menumodel "github.com/faisd405/go-restapi-gin/src/app/menu/model"
+	menuservice "github.com/faisd405/go-restapi-gin/src/app/menu/service"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrIngredientNotFound = errors.New("ingredient not found in this branch")
+	ErrInvalidQuantity    = errors.New("purchases and waste need a positive quantity; adjustments a quantity or a count, not both")
+	ErrInvalidReorder     = errors.New("a reorder level needs a reorder quantity above zero")
+)
+
+type InventoryService interface {
+	// GetStock lists every ingredient of a branch with the quantity on hand
+	GetStock(actor utils.Actor, branchID uint) ([]model.StockLevel, error)
+	GetMovements(actor utils.Actor, branchID uint, page, limit int, filter model.MovementFilter) ([]model.Movement, int64, error)
+	// RecordMovement books a purchase, waste or adjustment and takes the
+	// menu items of an ingredient that ran out off the menu
+	RecordMovement(actor utils.Actor, branchID uint, req model.MovementRequest) (*model.MovementResult, error)
+	// SetReorderLevel sets the stock level at which an ingredient is
+	// suggested to be bought again
+	SetReorderLevel(actor utils.Actor, branchID uint, req model.ReorderRequest) (*model.StockLevel, error)
+}
+
+type inventoryService struct {
+	stockRepo     repository.StockRepository
+	movementRepo  repository.MovementRepository
+	menuSvc       menuservice.MenuService
+	restaurantSvc restaurantservice.RestaurantService
+	txManager     database.TxManager
+}
+
+func NewInventoryService(
+	stockRepo repository.StockRepository,
+	movementRepo repository.MovementRepository,
+	menuSvc menuservice.MenuService,
+	restaurantSvc restaurantservice.RestaurantService,
+	txManager database.TxManager,
+) InventoryService {
+	return &inventoryService{
+		stockRepo:     stockRepo,
+		movementRepo:  movementRepo,
+		menuSvc:       menuSvc,
+		restaurantSvc: restaurantSvc,
+		txManager:     txManager,
+	}
+}
+
+func (s *inventoryService) GetStock(actor utils.Actor, branchID uint) ([]model.StockLevel, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	ingredients, err := s.menuSvc.GetIngredients(actor, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	stocks, err := s.stockRepo.Find(database.NewQuery().Eq("branch_id", branchID))
+	if err != nil {
+		return nil, err
+	}
+	byIngredient := make(map[uint]model.Stock, len(stocks))
+	for _, stock := range stocks {
+		byIngredient[stock.IngredientID] = stock
+	}
+
+	levels := make([]model.StockLevel, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		stock, ok := byIngredient[ingredient.ID]
+		if !ok {
+			stock = model.Stock{IngredientID: ingredient.ID}
+		}
+		levels = append(levels, stockLevel(ingredient, stock, ok))
+	}
+	return levels, nil
+}
+
+func (s *inventoryService) GetMovements(actor utils.Actor, branchID uint, page, limit int, filter model.MovementFilter) ([]model.Movement, int64, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, 0, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, 0, err
+	}
+
+	q := database.NewQuery().Eq("branch_id", branchID)
+	if filter.IngredientID != 0 {
+		q.Eq("ingredient_id", filter.IngredientID)
+	}
+	if filter.Type != "" {
+		if !model.IsValidMovementType(filter.Type) {
+			return nil, 0, model.ErrInvalidMovementType
+		}
+		q.Eq("type", filter.Type)
+	}
+
+	return s.movementRepo.FindPage(q.Preload("Ingredient").OrderByDesc("id").Paginate(utils.Offset(page, limit), limit))
+}
+
+func (s *inventoryService) RecordMovement(actor utils.Actor, branchID uint, req model.MovementRequest) (*model.MovementResult, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if _, err := s.branchIngredient(actor, branchID, req.IngredientID); err != nil {
+		return nil, err
+	}
+
+	switch req.Type {
+	case model.MovementPurchase, model.MovementWaste:
+		if req.Quantity <= 0 || req.Counted != nil {
+			return nil, ErrInvalidQuantity
+		}
+	case model.MovementAdjustment:
+		if (req.Quantity == 0) == (req.Counted == nil) {
+			return nil, ErrInvalidQuantity
+		}
+	}
+
+	result := &model.MovementResult{UnavailableItemIDs: []uint{}}
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		stockRepo := s.stockRepo.WithTx(tx)
+
+		movement := &model.Movement{
+			BranchID:     branchID,
+			IngredientID: req.IngredientID,
+			Type:         req.Type,
+			Quantity:     req.Quantity,
+			Note:         req.Note,
+			CreatedByID:  utils.ActorID(actor),
+		}
+		switch {
+		case req.Type == model.MovementWaste:
+			movement.Quantity = -req.Quantity
+		case req.Counted != nil:
+			stock, err := stockRepo.GetForUpdate(req.IngredientID, branchID)
+			if err != nil {
+				return err
+			}
+			movement.Quantity = *req.Counted - stock.Quantity
+		}
+
+		if err := stockRepo.Apply(movement); err != nil {
+			return err
+		}
+		result.Movement = movement
+
+		if movement.Balance > 0 {
+			return nil
+		}
+		flagged, err := stockRepo.FlagUnavailable([]uint{movement.IngredientID})
+		if err != nil {
+			return err
+		}
+		result.UnavailableItemIDs = append(result.UnavailableItemIDs, flagged...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *inventoryService) SetReorderLevel(actor utils.Actor, branchID uint, req model.ReorderRequest) (*model.StockLevel, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	ingredient, err := s.branchIngredient(actor, branchID, req.IngredientID)
+	if err != nil {
+		return nil, err
+	}
+
+	stock := &model.Stock{IngredientID: ingredient.ID, BranchID: branchID, UpdatedAt: time.Now()}
+	if req.ReorderLevel != nil {
+		if req.ReorderQuantity <= 0 {
+			return nil, ErrInvalidReorder
+		}
+		level := model.RoundQuantity(*req.ReorderLevel)
+		stock.ReorderLevel = &level
+		stock.ReorderQuantity = model.RoundQuantity(req.ReorderQuantity)
+	}
+	if err := s.stockRepo.Upsert(stock, []string{"ingredient_id"}, "reorder_level", "reorder_quantity"); err != nil {
+		return nil, err
+	}
+
+	stock, err = s.stockRepo.First(database.NewQuery().Eq("ingredient_id", ingredient.ID))
+	if err != nil {
+		return nil, err
+	}
+	level := stockLevel(*ingredient, *stock, true)
+	return &level, nil
+}
+
+// branchIngredient loads an ingredient of a branch the actor manages
+func (s *inventoryService) branchIngredient(actor utils.Actor, branchID, ingredientID uint) (*menumodel.Ingredient, error) {
+	ingredients, err := s.menuSvc.GetIngredients(actor, branchID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ingredients {
+		if ingredients[i].ID == ingredientID {
+			return &ingredients[i], nil
+		}
+	}
+	return nil, ErrIngredientNotFound
+}
+
+// stockLevel combines an ingredient with its stock; stocked is false for
+// ingredients that have no stock row yet
+func stockLevel(ingredient menumodel.Ingredient, stock model.Stock, stocked bool) model.StockLevel {
+	level := model.StockLevel{
+		IngredientID:    ingredient.ID,
+		Name:            ingredient.Name,
+		Unit:            ingredient.Unit,
+		Quantity:        stock.Quantity,
+		ReorderLevel:    stock.ReorderLevel,
+		ReorderQuantity: stock.ReorderQuantity,
+		Low:             stock.IsLow(),
+	}
+	if stocked {
+		level.UpdatedAt = &stock.UpdatedAt
+	}
+	return level
+}
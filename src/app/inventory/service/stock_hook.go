@@ -0,0 +1,56 @@
+package service
+
+import (
+	"github.com/faisd405/go-restapi-gin/src/app/inventory/repository"
+	ordermodel "github.com/faisd405/go-restapi-gin/src/app/order/model"
+	"gorm.io/gorm"
+)
+
+type stockHook struct {
+	stockRepo repository.StockRepository
+}
+
+// NewStockHook returns the order status hook that takes the ingredients of
+// an order the branch accepted off stock, and puts them back when the
+// order is cancelled
+func NewStockHook(stockRepo repository.StockRepository) ordermodel.StatusHook {
+	return &stockHook{stockRepo: stockRepo}
+}
+
+// OnStatusChange consumes the recipes of an accepted order and takes the
+// menu items of ingredients that ran out off the menu. Stock may go below
+// zero; the order is accepted all the same. Cancelling an order the
+// kitchen had accepted puts its ingredients back. Like any restock, that
+// leaves menu items off the menu until a manager puts them back.
+func (h *stockHook) OnStatusChange(tx *gorm.DB, event *ordermodel.StatusEvent) error {
+	order := event.Order
+	switch {
+	case order.Status == ordermodel.StatusAccepted:
+		return h.consume(h.stockRepo.WithTx(tx), order)
+	case order.Status == ordermodel.StatusCancelled &&
+		(event.From == ordermodel.StatusAccepted || event.From == ordermodel.StatusPreparing):
+		_, err := h.stockRepo.WithTx(tx).Release(order.BranchID, order.ID)
+		return err
+	}
+	return nil
+}
+
+func (h *stockHook) consume(stockRepo repository.StockRepository, order *ordermodel.Order) error {
+	items := make(map[uint]int, len(order.Lines))
+	for _, line := range order.Lines {
+		items[line.MenuItemID] += line.Quantity
+	}
+	movements, err := stockRepo.Consume(order.BranchID, order.ID, items)
+	if err != nil {
+		return err
+	}
+
+	var depleted []uint
+	for _, movement := range movements {
+		if movement.Balance <= 0 {
+			depleted = append(depleted, movement.IngredientID)
+		}
+	}
+	_, err = stockRepo.FlagUnavailable(depleted)
+	return err
+}
@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/kitchen/service"
+	"github.com/faisd405/go-restapi-gin/src/config"
+	"github.com/faisd405/go-restapi-gin/src/realtime"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Stream events sent besides the published events
+const (
+	EventHeartbeat = "heartbeat"
+	EventReset     = "reset"
+)
+
+type FeedController struct {
+	feedService service.FeedService
+	cfg         config.RealtimeConfig
+}
+
+func NewFeedController(feedService service.FeedService, cfg config.RealtimeConfig) *FeedController {
+	return &FeedController{feedService: feedService, cfg: cfg}
+}
+
+// StreamBranch godoc
+// @Summary Stream branch order events (Staff)
+// @Description Server-sent event stream of the orders of a branch for kitchen screens. Each event carries its ID; reconnecting clients send it back in the Last-Event-ID header (or last_event_id query) to receive the events they missed. A "reset" event tells the client that missed events are no longer available and it should reload the orders. Browsers that cannot set headers pass a stream ticket in the ticket query.
+// @Tags kitchen
+// @Produce text/event-stream
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Param last_event_id query string false "ID of the last event received"
+// @Param ticket query string false "Stream ticket"
+// @Success 200 {string} string "event stream"
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/kitchen/stream [get]
+func (ctrl *FeedController) StreamBranch(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	lastEventID, err := parseLastEventID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid last event ID", err.Error())
+		return
+	}
+
+	sub, err := ctrl.feedService.SubscribeBranch(c.Request.Context(), actor, id, lastEventID)
+	if err != nil {
+		kitchenErrorResponse(c, "Failed to subscribe to kitchen feed", err)
+		return
+	}
+
+	ctrl.stream(c, sub)
+}
+
+// StreamStation godoc
+// @Summary Stream station ticket events (Staff/Station)
+// @Description Server-sent event stream of the tickets of a kitchen station: "ticket.created" when an accepted order reaches the station and "ticket.updated" when a ticket is marked ready, bumped or cancelled. Reconnects, replay and heartbeats work as on the branch stream.
+// @Tags kitchen
+// @Produce text/event-stream
+// @Security ApiKeyAuth
+// @Param id path int true "Station ID"
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Param last_event_id query string false "ID of the last event received"
+// @Param ticket query string false "Stream ticket"
+// @Success 200 {string} string "event stream"
+// @Failure 403 {object} utils.Response
+// @Router /stations/{id}/stream [get]
+func (ctrl *FeedController) StreamStation(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid station ID", err.Error())
+		return
+	}
+
+	lastEventID, err := parseLastEventID(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid last event ID", err.Error())
+		return
+	}
+
+	sub, err := ctrl.feedService.SubscribeStation(c.Request.Context(), actor, id, lastEventID)
+	if err != nil {
+		kitchenErrorResponse(c, "Failed to subscribe to station feed", err)
+		return
+	}
+
+	ctrl.stream(c, sub)
+}
+
+// IssueBranchTicket godoc
+// @Summary Issue branch stream ticket (Staff)
+// @Description Issue a short-lived ticket that opens the order event stream of a branch as ?ticket=, for browsers whose EventSource cannot send the Authorization header. Tickets are only checked when connecting; fetch a new one before reconnecting.
+// @Tags kitchen
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 201 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/kitchen/stream/tickets [post]
+func (ctrl *FeedController) IssueBranchTicket(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	ticket, err := ctrl.feedService.IssueBranchTicket(actor, id)
+	if err != nil {
+		kitchenErrorResponse(c, "Failed to issue stream ticket", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Stream ticket issued successfully", ticket)
+}
+
+// IssueStationTicket godoc
+// @Summary Issue station stream ticket (Staff/Station)
+// @Description Issue a short-lived ticket that opens the ticket event stream of a station as ?ticket=.
+// @Tags kitchen
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Station ID"
+// @Success 201 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /stations/{id}/stream/tickets [post]
+func (ctrl *FeedController) IssueStationTicket(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid station ID", err.Error())
+		return
+	}
+
+	ticket, err := ctrl.feedService.IssueStationTicket(actor, id)
+	if err != nil {
+		kitchenErrorResponse(c, "Failed to issue stream ticket", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Stream ticket issued successfully", ticket)
+}
+
+// stream writes sub to the client as server-sent events until the client
+// disconnects or the hub ends the subscription
+func (ctrl *FeedController) stream(c *gin.Context, sub *realtime.Subscription) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", ctrl.cfg.Retry.Milliseconds())
+	if sub.Missed {
+		writeEvent(w, 0, EventReset, []byte("{}"))
+	}
+	for _, event := range sub.Replay {
+		writeEvent(w, event.ID, event.Type, event.Data)
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(ctrl.cfg.Heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, open := <-sub.Events:
+			if !open {
+				return
+			}
+			writeEvent(w, event.ID, event.Type, event.Data)
+		case now := <-heartbeat.C:
+			writeEvent(w, 0, EventHeartbeat, []byte(fmt.Sprintf(`{"time":%q}`, now.UTC().Format(time.RFC3339))))
+		}
+		w.Flush()
+	}
+}
+
+// writeEvent writes one server-sent event. Events without ID do not move
+// the client's last event ID.
+func writeEvent(w io.Writer, id uint64, eventType string, data []byte) {
+	if id != 0 {
+		fmt.Fprintf(w, "id: %d\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, data)
+}
+
+func parseLastEventID(c *gin.Context) (uint64, error) {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
@@ -0,0 +1,282 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/faisd405/go-restapi-gin/src/app/kitchen/model"
+	"github.com/faisd405/go-restapi-gin/src/app/kitchen/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Error codes of the kitchen API
+const (
+	ErrCodeStationAccessDenied     = "STATION_ACCESS_DENIED"
+	ErrCodeStationInactive         = "STATION_INACTIVE"
+	ErrCodeInvalidTicketTransition = "INVALID_TICKET_TRANSITION"
+)
+
+type StationController struct {
+	stationService service.StationService
+}
+
+func NewStationController(stationService service.StationService) *StationController {
+	return &StationController{stationService: stationService}
+}
+
+// GetStations godoc
+// @Summary Get kitchen stations (Staff)
+// @Description Get the kitchen stations of a branch with the menu items routed to them
+// @Tags kitchen
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/stations [get]
+func (ctrl *StationController) GetStations(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	stations, err := ctrl.stationService.GetStations(actor, id)
+	if err != nil {
+		kitchenErrorResponse(c, "Failed to retrieve stations", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Stations retrieved successfully", stations)
+}
+
+// GetStation godoc
+// @Summary Get kitchen station (Staff/Station)
+// @Description Get a kitchen station with the menu items routed to it
+// @Tags kitchen
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Station ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /stations/{id} [get]
+func (ctrl *StationController) GetStation(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid station ID", err.Error())
+		return
+	}
+
+	station, err := ctrl.stationService.GetStation(actor, id)
+	if err != nil {
+		kitchenErrorResponse(c, "Failed to retrieve station", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Station retrieved successfully", station)
+}
+
+// CreateStation godoc
+// @Summary Create kitchen station (Admin/Manager)
+// @Description Add a kitchen station to a branch. Marking it default routes all unmapped menu items to it.
+// @Tags kitchen
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param station body model.StationRequest true "Station data"
+// @Success 201 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /branches/{id}/stations [post]
+func (ctrl *StationController) CreateStation(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.StationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	station, err := ctrl.stationService.CreateStation(actor, id, req)
+	if err != nil {
+		kitchenErrorResponse(c, "Station creation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Station created successfully", station)
+}
+
+// UpdateStation godoc
+// @Summary Update kitchen station (Admin/Manager)
+// @Description Update the name, order, default or active flag of a station. Items of inactive stations go to the default station.
+// @Tags kitchen
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Station ID"
+// @Param station body model.StationRequest true "Station data"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /stations/{id} [put]
+func (ctrl *StationController) UpdateStation(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid station ID", err.Error())
+		return
+	}
+
+	var req model.StationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	station, err := ctrl.stationService.UpdateStation(actor, id, req)
+	if err != nil {
+		kitchenErrorResponse(c, "Station update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Station updated successfully", station)
+}
+
+// DeleteStation godoc
+// @Summary Delete kitchen station (Admin/Manager)
+// @Description Delete a station and its menu item routes
+// @Tags kitchen
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Station ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /stations/{id} [delete]
+func (ctrl *StationController) DeleteStation(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid station ID", err.Error())
+		return
+	}
+
+	if err := ctrl.stationService.DeleteStation(actor, id); err != nil {
+		kitchenErrorResponse(c, "Station deletion failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Station deleted successfully", nil)
+}
+
+// SetStationItems godoc
+// @Summary Set station menu items (Admin/Manager)
+// @Description Replace the menu items prepared at a station. Items routed to another station are moved.
+// @Tags kitchen
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Station ID"
+// @Param items body model.StationItemsRequest true "Menu item IDs"
+// @Success 200 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /stations/{id}/items [put]
+func (ctrl *StationController) SetStationItems(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid station ID", err.Error())
+		return
+	}
+
+	var req model.StationItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	station, err := ctrl.stationService.SetStationItems(actor, id, req)
+	if err != nil {
+		kitchenErrorResponse(c, "Failed to update station items", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Station items updated successfully", station)
+}
+
+// StartSession godoc
+// @Summary Open station screen (Staff)
+// @Description Issue the bearer token of a kitchen station screen. The token only grants access to the tickets and feed of the station.
+// @Tags kitchen
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Station ID"
+// @Success 201 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /stations/{id}/sessions [post]
+func (ctrl *StationController) StartSession(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid station ID", err.Error())
+		return
+	}
+
+	token, err := ctrl.stationService.StartSession(actor, id)
+	if err != nil {
+		kitchenErrorResponse(c, "Failed to open station screen", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Station screen opened successfully", token)
+}
+
+func kitchenErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrStationAccessDenied):
+		utils.ErrorResponseWithCode(c, http.StatusForbidden, ErrCodeStationAccessDenied, message, err.Error())
+	case errors.Is(err, service.ErrStationInactive):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeStationInactive, message, err.Error())
+	case errors.Is(err, model.ErrInvalidTicketTransition):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeInvalidTicketTransition, message, err.Error())
+	case errors.Is(err, service.ErrItemBranchMismatch),
+		errors.Is(err, model.ErrInvalidTicketStatus):
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, message, err.Error())
+	default:
+		utils.DatabaseErrorResponse(c, message, err)
+	}
+}
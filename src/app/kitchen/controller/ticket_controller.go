@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/faisd405/go-restapi-gin/src/app/kitchen/model"
+	"github.com/faisd405/go-restapi-gin/src/app/kitchen/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type TicketController struct {
+	ticketService service.TicketService
+}
+
+func NewTicketController(ticketService service.TicketService) *TicketController {
+	return &TicketController{ticketService: ticketService}
+}
+
+// GetTickets godoc
+// @Summary Get station tickets (Staff/Station)
+// @Description Get the tickets of a kitchen station, oldest first. Without a status the pending and ready tickets still on the screen are listed.
+// @Tags kitchen
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Station ID"
+// @Param status query string false "Filter by status"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /stations/{id}/tickets [get]
+func (ctrl *TicketController) GetTickets(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid station ID", err.Error())
+		return
+	}
+
+	tickets, err := ctrl.ticketService.GetTickets(actor, id, model.TicketFilter{Status: c.Query("status")})
+	if err != nil {
+		kitchenErrorResponse(c, "Failed to retrieve tickets", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tickets retrieved successfully", tickets)
+}
+
+// GetTicket godoc
+// @Summary Get ticket (Staff/Station)
+// @Description Get a kitchen ticket by ID
+// @Tags kitchen
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Ticket ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/{id} [get]
+func (ctrl *TicketController) GetTicket(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid ticket ID", err.Error())
+		return
+	}
+
+	ticket, err := ctrl.ticketService.GetTicket(actor, id)
+	if err != nil {
+		kitchenErrorResponse(c, "Failed to retrieve ticket", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ticket retrieved successfully", ticket)
+}
+
+// Transition godoc
+// @Summary Change ticket status (Staff/Station)
+// @Description Mark a ticket ready or bump it off the screen. The order moves to preparing with its first finished ticket and to ready once all its tickets are.
+// @Tags kitchen
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Ticket ID"
+// @Param status body model.TicketStatusRequest true "New status"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /tickets/{id}/status [post]
+func (ctrl *TicketController) Transition(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid ticket ID", err.Error())
+		return
+	}
+
+	var req model.TicketStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	ticket, err := ctrl.ticketService.Transition(actor, id, req)
+	if err != nil {
+		kitchenErrorResponse(c, "Ticket status change failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ticket status changed successfully", ticket)
+}
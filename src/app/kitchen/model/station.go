@@ -0,0 +1,82 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Station is a kitchen station of a branch, such as the grill or the bar.
+// Accepted orders are split into one ticket per station. Items not mapped
+// to any station go to the default station of the branch.
+type Station struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	BranchID  uint           `json:"branch_id" gorm:"not null;uniqueIndex:idx_kitchen_stations_branch_name,where:deleted_at IS NULL"`
+	Name      string         `json:"name" gorm:"type:varchar(50);not null;uniqueIndex:idx_kitchen_stations_branch_name,where:deleted_at IS NULL"`
+	IsDefault bool           `json:"is_default" gorm:"not null;default:false"`
+	IsActive  bool           `json:"is_active" gorm:"not null;default:true"`
+	SortOrder int            `json:"sort_order" gorm:"not null;default:0"`
+	Items     []StationItem  `json:"items,omitempty" gorm:"foreignKey:StationID"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+func (Station) TableName() string {
+	return "kitchen_stations"
+}
+
+// StationItem routes a menu item to a station. An item is prepared at one
+// station only.
+type StationItem struct {
+	MenuItemID uint      `json:"menu_item_id" gorm:"primaryKey;autoIncrement:false"`
+	StationID  uint      `json:"station_id" gorm:"not null;index"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (StationItem) TableName() string {
+	return "kitchen_station_items"
+}
+
+type StationRequest struct {
+	Name      string `json:"name" binding:"required,max=50"`
+	IsDefault bool   `json:"is_default"`
+	IsActive  *bool  `json:"is_active"`
+	SortOrder int    `json:"sort_order"`
+}
+
+// StationItemsRequest sets the menu items prepared at a station. Items
+// mapped to another station before are moved.
+type StationItemsRequest struct {
+	MenuItemIDs []uint `json:"menu_item_ids" binding:"required"`
+}
+
+// Routing maps the menu items of a branch to the active stations that
+// prepare them
+type Routing struct {
+	Items            map[uint]uint
+	DefaultStationID uint
+}
+
+// StationFor returns the station preparing a menu item, or zero when no
+// station does
+func (r *Routing) StationFor(menuItemID uint) uint {
+	if stationID, ok := r.Items[menuItemID]; ok {
+		return stationID
+	}
+	return r.DefaultStationID
+}
+
+// StationToken is the bearer token of a station screen
+type StationToken struct {
+	Station   Station   `json:"station"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// StreamTicket opens one event stream from a browser, which cannot send the
+// Authorization header, without putting the login token in the URL
+type StreamTicket struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
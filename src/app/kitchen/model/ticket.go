@@ -0,0 +1,105 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	menumodel "github.com/faisd405/go-restapi-gin/src/app/menu/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+)
+
+// Ticket statuses. Cooks mark a ticket ready when its items are done and
+// bump it off the screen once it has been picked up; bumping a pending
+// ticket marks it ready as well. Tickets of cancelled orders are cancelled.
+const (
+	TicketPending   = "pending"
+	TicketReady     = "ready"
+	TicketBumped    = "bumped"
+	TicketCancelled = "cancelled"
+)
+
+// Ticket event types published to the feed of the station
+const (
+	EventTicketCreated = "ticket.created"
+	EventTicketUpdated = "ticket.updated"
+)
+
+var (
+	ErrInvalidTicketTransition = errors.New("invalid ticket status transition")
+	ErrInvalidTicketStatus     = errors.New("unknown ticket status")
+)
+
+// Ticket is the part of an accepted order prepared at one station
+type Ticket struct {
+	ID        uint                          `json:"id" gorm:"primaryKey"`
+	OrderID   uint                          `json:"order_id" gorm:"not null;index"`
+	BranchID  uint                          `json:"branch_id" gorm:"not null;index"`
+	StationID uint                          `json:"station_id" gorm:"not null;index"`
+	Status    string                        `json:"status" gorm:"type:varchar(20);not null;index"`
+	OrderType string                        `json:"order_type" gorm:"type:varchar(20);not null"`
+	TableID   *uint                         `json:"table_id"`
+	Notes     string                        `json:"notes" gorm:"type:text"`
+	Lines     database.JSONList[TicketLine] `json:"lines" gorm:"type:jsonb;not null;default:'[]'"`
+	ReadyAt   *time.Time                    `json:"ready_at"`
+	BumpedAt  *time.Time                    `json:"bumped_at"`
+	CreatedAt time.Time                     `json:"created_at"`
+	UpdatedAt time.Time                     `json:"updated_at"`
+}
+
+func (Ticket) TableName() string {
+	return "kitchen_tickets"
+}
+
+// TicketLine is an order line as the cooks need it
+type TicketLine struct {
+	OrderLineID uint                       `json:"order_line_id"`
+	MenuItemID  uint                       `json:"menu_item_id"`
+	Name        string                     `json:"name"`
+	Quantity    int                        `json:"quantity"`
+	Modifiers   []menumodel.SelectedOption `json:"modifiers"`
+	Notes       string                     `json:"notes,omitempty"`
+}
+
+// IsValidTicketStatus reports whether status is one of the ticket statuses
+func IsValidTicketStatus(status string) bool {
+	switch status {
+	case TicketPending, TicketReady, TicketBumped, TicketCancelled:
+		return true
+	}
+	return false
+}
+
+// Advance moves the ticket to status at now
+func (t *Ticket) Advance(status string, now time.Time) error {
+	switch {
+	case status == TicketReady && t.Status == TicketPending:
+		t.ReadyAt = &now
+	case status == TicketBumped && t.Status == TicketPending:
+		t.ReadyAt = &now
+		t.BumpedAt = &now
+	case status == TicketBumped && t.Status == TicketReady:
+		t.BumpedAt = &now
+	default:
+		return fmt.Errorf("%w: %s → %s", ErrInvalidTicketTransition, t.Status, status)
+	}
+	t.Status = status
+	return nil
+}
+
+type TicketStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=ready bumped"`
+}
+
+// TicketFilter narrows down the tickets of a station. Without a status the
+// tickets still on the screen, pending and ready, are listed.
+type TicketFilter struct {
+	Status string
+}
+
+// TicketProgress counts the tickets of an order that are still being
+// prepared and those that are done
+type TicketProgress struct {
+	Open int64
+	Done int64
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"github.com/faisd405/go-restapi-gin/src/app/kitchen/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+)
+
+type StationRepository interface {
+	Create(station *model.Station) error
+	GetByID(id uint) (*model.Station, error)
+	Update(station *model.Station) error
+	Delete(id uint) error
+	Find(q *database.Query) ([]model.Station, error)
+	// ClearDefault unsets the default flag of the other stations of a
+	// branch
+	ClearDefault(branchID, keepID uint) error
+	// ReplaceItems routes menuItemIDs to a station, taking them off the
+	// stations they were routed to before
+	ReplaceItems(stationID uint, menuItemIDs []uint) error
+	// GetRouting returns the item routes of the active stations of a
+	// branch
+	GetRouting(branchID uint) (*model.Routing, error)
+	WithTx(tx *gorm.DB) StationRepository
+}
+
+type stationRepository struct {
+	database.Repository[model.Station]
+}
+
+func NewStationRepository(db *gorm.DB) StationRepository {
+	return &stationRepository{Repository: database.NewRepository[model.Station](db)}
+}
+
+// GetByID loads a station with its item routes
+func (r *stationRepository) GetByID(id uint) (*model.Station, error) {
+	return r.FindByID(id, database.NewQuery().Preload("Items"))
+}
+
+// Update saves the station columns only; items are managed by ReplaceItems
+func (r *stationRepository) Update(station *model.Station) error {
+	return database.TranslateError(r.DB().Omit("Items").Save(station).Error)
+}
+
+// Delete soft deletes a station and drops its item routes
+func (r *stationRepository) Delete(id uint) error {
+	if err := r.DB().Where("station_id = ?", id).Delete(&model.StationItem{}).Error; err != nil {
+		return database.TranslateError(err)
+	}
+	return r.Repository.Delete(id)
+}
+
+func (r *stationRepository) ClearDefault(branchID, keepID uint) error {
+	err := r.DB().Model(&model.Station{}).
+		Where("branch_id = ? AND id <> ? AND is_default", branchID, keepID).
+		Update("is_default", false).Error
+	return database.TranslateError(err)
+}
+
+func (r *stationRepository) ReplaceItems(stationID uint, menuItemIDs []uint) error {
+	db := r.DB()
+	if err := db.Where("station_id = ?", stationID).Delete(&model.StationItem{}).Error; err != nil {
+		return database.TranslateError(err)
+	}
+	if len(menuItemIDs) == 0 {
+		return nil
+	}
+	if err := db.Where("menu_item_id IN ?", menuItemIDs).Delete(&model.StationItem{}).Error; err != nil {
+		return database.TranslateError(err)
+	}
+
+	items := make([]model.StationItem, 0, len(menuItemIDs))
+	for _, id := range menuItemIDs {
+		items = append(items, model.StationItem{MenuItemID: id, StationID: stationID})
+	}
+	return database.TranslateError(db.Create(&items).Error)
+}
+
+func (r *stationRepository) GetRouting(branchID uint) (*model.Routing, error) {
+	stations, err := r.Find(database.NewQuery().
+		Eq("branch_id", branchID).
+		Eq("is_active", true).
+		Preload("Items"))
+	if err != nil {
+		return nil, err
+	}
+
+	routing := &model.Routing{Items: map[uint]uint{}}
+	for _, station := range stations {
+		if station.IsDefault {
+			routing.DefaultStationID = station.ID
+		}
+		for _, item := range station.Items {
+			routing.Items[item.MenuItemID] = station.ID
+		}
+	}
+	return routing, nil
+}
+
+func (r *stationRepository) WithTx(tx *gorm.DB) StationRepository {
+	return &stationRepository{Repository: r.Repository.WithTx(tx)}
+}
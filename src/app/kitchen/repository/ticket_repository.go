@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"github.com/faisd405/go-restapi-gin/src/app/kitchen/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TicketRepository interface {
+	Create(ticket *model.Ticket) error
+	GetByID(id uint) (*model.Ticket, error)
+	GetForUpdate(id uint) (*model.Ticket, error)
+	Update(ticket *model.Ticket) error
+	Find(q *database.Query) ([]model.Ticket, error)
+	// CancelOpen cancels the pending and ready tickets of an order and
+	// returns them
+	CancelOpen(orderID uint) ([]model.Ticket, error)
+	// GetProgress counts the open and done tickets of an order
+	GetProgress(orderID uint) (*model.TicketProgress, error)
+	WithTx(tx *gorm.DB) TicketRepository
+}
+
+type ticketRepository struct {
+	database.Repository[model.Ticket]
+}
+
+func NewTicketRepository(db *gorm.DB) TicketRepository {
+	return &ticketRepository{Repository: database.NewRepository[model.Ticket](db)}
+}
+
+// GetForUpdate loads a ticket and locks its row until the surrounding
+// transaction ends
+func (r *ticketRepository) GetForUpdate(id uint) (*model.Ticket, error) {
+	var ticket model.Ticket
+	if err := r.DB().Clauses(clause.Locking{Strength: "UPDATE"}).First(&ticket, id).Error; err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+func (r *ticketRepository) CancelOpen(orderID uint) ([]model.Ticket, error) {
+	var tickets []model.Ticket
+	err := r.DB().Model(&tickets).
+		Clauses(clause.Returning{}).
+		Where("order_id = ? AND status IN ?", orderID, []string{model.TicketPending, model.TicketReady}).
+		Update("status", model.TicketCancelled).Error
+	if err != nil {
+		return nil, database.TranslateError(err)
+	}
+	return tickets, nil
+}
+
+func (r *ticketRepository) GetProgress(orderID uint) (*model.TicketProgress, error) {
+	progress := &model.TicketProgress{}
+	err := r.DB().Model(&model.Ticket{}).
+		Select("COUNT(*) FILTER (WHERE status = ?) AS open, COUNT(*) FILTER (WHERE status IN ?) AS done",
+			model.TicketPending, []string{model.TicketReady, model.TicketBumped}).
+		Where("order_id = ?", orderID).
+		Scan(progress).Error
+	if err != nil {
+		return nil, database.TranslateError(err)
+	}
+	return progress, nil
+}
+
+func (r *ticketRepository) WithTx(tx *gorm.DB) TicketRepository {
+	return &ticketRepository{Repository: r.Repository.WithTx(tx)}
+}
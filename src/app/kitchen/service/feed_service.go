@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/kitchen/model"
+	"github.com/faisd405/go-restapi-gin/src/app/kitchen/repository"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/config"
+	"github.com/faisd405/go-restapi-gin/src/realtime"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+)
+
+// FeedService streams the live order and ticket events kitchen screens
+// display
+type FeedService interface {
+	SubscribeBranch(ctx context.Context, actor utils.Actor, branchID uint, lastEventID uint64) (*realtime.Subscription, error)
+	SubscribeStation(ctx context.Context, actor utils.Actor, stationID uint, lastEventID uint64) (*realtime.Subscription, error)
+	IssueBranchTicket(actor utils.Actor, branchID uint) (*model.StreamTicket, error)
+	IssueStationTicket(actor utils.Actor, stationID uint) (*model.StreamTicket, error)
+}
+
+type feedService struct {
+	hub           realtime.Hub
+	stationRepo   repository.StationRepository
+	restaurantSvc restaurantservice.RestaurantService
+	cfg           config.KitchenConfig
+}
+
+func NewFeedService(hub realtime.Hub, stationRepo repository.StationRepository, restaurantSvc restaurantservice.RestaurantService, cfg config.KitchenConfig) FeedService {
+	return &feedService{hub: hub, stationRepo: stationRepo, restaurantSvc: restaurantSvc, cfg: cfg}
+}
+
+// SubscribeBranch subscribes to the order events of a branch the actor
+// works at. Station screens only follow their own tickets. The
+// subscription ends with ctx.
+func (s *feedService) SubscribeBranch(ctx context.Context, actor utils.Actor, branchID uint, lastEventID uint64) (*realtime.Subscription, error) {
+	if actor.IsStation() {
+		return nil, ErrStationAccessDenied
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	return s.hub.Subscribe(ctx, lastEventID, realtime.BranchTopic(branchID))
+}
+
+// IssueBranchTicket issues a stream ticket for the order events of a branch
+// the actor may subscribe to
+func (s *feedService) IssueBranchTicket(actor utils.Actor, branchID uint) (*model.StreamTicket, error) {
+	if actor.IsStation() {
+		return nil, ErrStationAccessDenied
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	return s.issueTicket(actor, utils.StreamScope(utils.StreamBranch, branchID))
+}
+
+// IssueStationTicket issues a stream ticket for the ticket events of a
+// station the actor may subscribe to
+func (s *feedService) IssueStationTicket(actor utils.Actor, stationID uint) (*model.StreamTicket, error) {
+	station, err := s.stationRepo.GetByID(stationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStationAccess(s.restaurantSvc, actor, station.ID, station.BranchID); err != nil {
+		return nil, err
+	}
+
+	return s.issueTicket(actor, utils.StreamScope(utils.StreamStation, station.ID))
+}
+
+func (s *feedService) issueTicket(actor utils.Actor, stream string) (*model.StreamTicket, error) {
+	expiresAt := time.Now().Add(s.cfg.StreamTicketTTL)
+	ticket, err := utils.GenerateStreamTicket(actor, stream, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.StreamTicket{Ticket: ticket, ExpiresAt: expiresAt}, nil
+}
+
+// SubscribeStation subscribes to the ticket events of a station. The
+// subscription ends with ctx.
+func (s *feedService) SubscribeStation(ctx context.Context, actor utils.Actor, stationID uint, lastEventID uint64) (*realtime.Subscription, error) {
+	station, err := s.stationRepo.GetByID(stationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStationAccess(s.restaurantSvc, actor, station.ID, station.BranchID); err != nil {
+		return nil, err
+	}
+
+	return s.hub.Subscribe(ctx, lastEventID, realtime.StationTopic(station.ID))
+}
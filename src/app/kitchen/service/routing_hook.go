@@ -0,0 +1,125 @@
+package service
+
+import (
+	"log"
+
+	"github.com/faisd405/go-restapi-gin/src/app/kitchen/model"
+	"github.com/faisd405/go-restapi-gin/src/app/kitchen/repository"
+	ordermodel "github.com/faisd405/go-restapi-gin/src/app/order/model"
+	"github.com/faisd405/go-restapi-gin/src/realtime"
+	"gorm.io/gorm"
+)
+
+// TicketHook lets other modules react to the tickets routing created or
+// cancelled for an order, e.g. to print them, within the same transaction
+type TicketHook interface {
+	OnTickets(tx *gorm.DB, tickets []model.Ticket) error
+}
+
+type routingHook struct {
+	stationRepo repository.StationRepository
+	ticketRepo  repository.TicketRepository
+	ticketHooks []TicketHook
+	events      realtime.Publisher
+}
+
+// NewRoutingHook returns the order status hook that splits an order the
+// branch accepted into one kitchen ticket per station and cancels the open
+// tickets of an order that is cancelled
+func NewRoutingHook(
+	stationRepo repository.StationRepository,
+	ticketRepo repository.TicketRepository,
+	events realtime.Publisher,
+	ticketHooks ...TicketHook,
+) ordermodel.StatusHook {
+	return &routingHook{
+		stationRepo: stationRepo,
+		ticketRepo:  ticketRepo,
+		ticketHooks: ticketHooks,
+		events:      events,
+	}
+}
+
+func (h *routingHook) OnStatusChange(tx *gorm.DB, event *ordermodel.StatusEvent) error {
+	tickets, err := h.routeTickets(tx, event.Order)
+	if err != nil || len(tickets) == 0 {
+		return err
+	}
+	for _, hook := range h.ticketHooks {
+		if err := hook.OnTickets(tx, tickets); err != nil {
+			return err
+		}
+	}
+	event.AfterCommit(func() { h.publish(tickets) })
+	return nil
+}
+
+// routeTickets creates the tickets of an accepted order or cancels those
+// of a cancelled one. Items no active station prepares get no ticket.
+func (h *routingHook) routeTickets(tx *gorm.DB, order *ordermodel.Order) ([]model.Ticket, error) {
+	ticketRepo := h.ticketRepo.WithTx(tx)
+
+	if order.Status == ordermodel.StatusCancelled {
+		return ticketRepo.CancelOpen(order.ID)
+	}
+	if order.Status != ordermodel.StatusAccepted {
+		return nil, nil
+	}
+
+	routing, err := h.stationRepo.WithTx(tx).GetRouting(order.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	tickets := []model.Ticket{}
+	index := map[uint]int{}
+	for _, line := range order.Lines {
+		stationID := routing.StationFor(line.MenuItemID)
+		if stationID == 0 {
+			continue
+		}
+		i, ok := index[stationID]
+		if !ok {
+			i = len(tickets)
+			index[stationID] = i
+			tickets = append(tickets, model.Ticket{
+				OrderID:   order.ID,
+				BranchID:  order.BranchID,
+				StationID: stationID,
+				Status:    model.TicketPending,
+				OrderType: order.Type,
+				TableID:   order.TableID,
+				Notes:     order.Notes,
+			})
+		}
+		tickets[i].Lines = append(tickets[i].Lines, model.TicketLine{
+			OrderLineID: line.ID,
+			MenuItemID:  line.MenuItemID,
+			Name:        line.Name,
+			Quantity:    line.Quantity,
+			Modifiers:   line.Modifiers,
+			Notes:       line.Notes,
+		})
+	}
+
+	for i := range tickets {
+		if err := ticketRepo.Create(&tickets[i]); err != nil {
+			return nil, err
+		}
+	}
+	return tickets, nil
+}
+
+// publish sends committed new and cancelled tickets to the feeds of their
+// stations
+func (h *routingHook) publish(tickets []model.Ticket) {
+	for i := range tickets {
+		eventType := model.EventTicketUpdated
+		if tickets[i].Status == model.TicketPending {
+			eventType = model.EventTicketCreated
+		}
+		if err := h.events.Publish(realtime.StationTopic(tickets[i].StationID), eventType, tickets[i]); err != nil {
+			log.Println("Failed to publish ticket event:", err)
+		}
+	}
+}
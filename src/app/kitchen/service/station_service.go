@@ -0,0 +1,222 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/kitchen/model"
+	"github.com/faisd405/go-restapi-gin/src/app/kitchen/repository"
+	menuservice "github.com/faisd405/go-restapi-gin/src/app/menu/service"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	usermodel "github.com/faisd405/go-restapi-gin/src/app/user/model"
+	"github.com/faisd405/go-restapi-gin/src/config"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrStationAccessDenied = errors.New("you do not have access to this station")
+	ErrStationInactive     = errors.New("station is not active")
+	ErrItemBranchMismatch  = errors.New("menu item belongs to a different branch")
+)
+
+type StationService interface {
+	GetStations(actor utils.Actor, branchID uint) ([]model.Station, error)
+	GetStation(actor utils.Actor, id uint) (*model.Station, error)
+	CreateStation(actor utils.Actor, branchID uint, req model.StationRequest) (*model.Station, error)
+	UpdateStation(actor utils.Actor, id uint, req model.StationRequest) (*model.Station, error)
+	DeleteStation(actor utils.Actor, id uint) error
+	SetStationItems(actor utils.Actor, id uint, req model.StationItemsRequest) (*model.Station, error)
+
+	// StartSession issues the token a station screen uses to see and work
+	// on the tickets of the station
+	StartSession(actor utils.Actor, id uint) (*model.StationToken, error)
+}
+
+type stationService struct {
+	stationRepo   repository.StationRepository
+	menuSvc       menuservice.MenuService
+	restaurantSvc restaurantservice.RestaurantService
+	txManager     database.TxManager
+	cfg           config.KitchenConfig
+}
+
+func NewStationService(
+	stationRepo repository.StationRepository,
+	menuSvc menuservice.MenuService,
+	restaurantSvc restaurantservice.RestaurantService,
+	txManager database.TxManager,
+	cfg config.KitchenConfig,
+) StationService {
+	return &stationService{
+		stationRepo:   stationRepo,
+		menuSvc:       menuSvc,
+		restaurantSvc: restaurantSvc,
+		txManager:     txManager,
+		cfg:           cfg,
+	}
+}
+
+func (s *stationService) GetStations(actor utils.Actor, branchID uint) ([]model.Station, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	return s.stationRepo.Find(database.NewQuery().
+		Eq("branch_id", branchID).
+		Preload("Items").
+		OrderBy("sort_order").
+		OrderBy("name"))
+}
+
+func (s *stationService) GetStation(actor utils.Actor, id uint) (*model.Station, error) {
+	return s.getAccessibleStation(actor, id)
+}
+
+func (s *stationService) CreateStation(actor utils.Actor, branchID uint, req model.StationRequest) (*model.Station, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	station := &model.Station{BranchID: branchID, IsActive: true}
+	applyStationRequest(station, req)
+
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		stationRepo := s.stationRepo.WithTx(tx)
+		if err := stationRepo.Create(station); err != nil {
+			return err
+		}
+		if station.IsDefault {
+			return stationRepo.ClearDefault(branchID, station.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return station, nil
+}
+
+func (s *stationService) UpdateStation(actor utils.Actor, id uint, req model.StationRequest) (*model.Station, error) {
+	station, err := s.getAccessibleStation(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyStationRequest(station, req)
+	err = s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		stationRepo := s.stationRepo.WithTx(tx)
+		if err := stationRepo.Update(station); err != nil {
+			return err
+		}
+		if station.IsDefault {
+			return stationRepo.ClearDefault(station.BranchID, station.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return station, nil
+}
+
+// DeleteStation deletes a station and its item routes. Tickets already on
+// the station stay there.
+func (s *stationService) DeleteStation(actor utils.Actor, id uint) error {
+	if _, err := s.getAccessibleStation(actor, id); err != nil {
+		return err
+	}
+
+	return s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		return s.stationRepo.WithTx(tx).Delete(id)
+	})
+}
+
+func (s *stationService) SetStationItems(actor utils.Actor, id uint, req model.StationItemsRequest) (*model.Station, error) {
+	station, err := s.getAccessibleStation(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[uint]bool{}
+	itemIDs := make([]uint, 0, len(req.MenuItemIDs))
+	for _, itemID := range req.MenuItemIDs {
+		if seen[itemID] {
+			continue
+		}
+		seen[itemID] = true
+
+		item, err := s.menuSvc.GetItem(itemID)
+		if err != nil {
+			return nil, err
+		}
+		if item.BranchID != station.BranchID {
+			return nil, ErrItemBranchMismatch
+		}
+		itemIDs = append(itemIDs, itemID)
+	}
+
+	err = s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		return s.stationRepo.WithTx(tx).ReplaceItems(station.ID, itemIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.stationRepo.GetByID(station.ID)
+}
+
+func (s *stationService) StartSession(actor utils.Actor, id uint) (*model.StationToken, error) {
+	station, err := s.getAccessibleStation(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if !station.IsActive {
+		return nil, ErrStationInactive
+	}
+
+	expiresAt := time.Now().Add(s.cfg.StationTokenTTL)
+	token, err := utils.GenerateStationJWT(actor.UserID, actor.Email, station.ID, station.BranchID, usermodel.RoleStation, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.StationToken{Station: *station, Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// getAccessibleStation loads a station the actor works at
+func (s *stationService) getAccessibleStation(actor utils.Actor, id uint) (*model.Station, error) {
+	station, err := s.stationRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStationAccess(s.restaurantSvc, actor, station.ID, station.BranchID); err != nil {
+		return nil, err
+	}
+	return station, nil
+}
+
+// checkStationAccess allows station screens on their own station only and
+// other users on the stations of the branches they work at
+func checkStationAccess(checker utils.BranchAccessChecker, actor utils.Actor, stationID, branchID uint) error {
+	if actor.IsStation() && actor.StationID != stationID {
+		return ErrStationAccessDenied
+	}
+	return utils.CheckBranchAccess(checker, actor, branchID)
+}
+
+func applyStationRequest(station *model.Station, req model.StationRequest) {
+	station.Name = req.Name
+	station.IsDefault = req.IsDefault
+	station.SortOrder = req.SortOrder
+	if req.IsActive != nil {
+		station.IsActive = *req.IsActive
+	}
+}
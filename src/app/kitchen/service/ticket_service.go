@@ -0,0 +1,129 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/kitchen/model"
+	"github.com/faisd405/go-restapi-gin/src/app/kitchen/repository"
+	ordermodel "github.com/faisd405/go-restapi-gin/src/app/order/model"
+	orderservice "github.com/faisd405/go-restapi-gin/src/app/order/service"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/realtime"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+type TicketService interface {
+	GetTickets(actor utils.Actor, stationID uint, filter model.TicketFilter) ([]model.Ticket, error)
+	GetTicket(actor utils.Actor, id uint) (*model.Ticket, error)
+	// Transition marks a ticket ready or bumps it off the screen and moves
+	// its order along
+	Transition(actor utils.Actor, id uint, req model.TicketStatusRequest) (*model.Ticket, error)
+}
+
+type ticketService struct {
+	ticketRepo    repository.TicketRepository
+	stationRepo   repository.StationRepository
+	orderSvc      orderservice.OrderService
+	restaurantSvc restaurantservice.RestaurantService
+	events        realtime.Publisher
+	txManager     database.TxManager
+}
+
+func NewTicketService(
+	ticketRepo repository.TicketRepository,
+	stationRepo repository.StationRepository,
+	orderSvc orderservice.OrderService,
+	restaurantSvc restaurantservice.RestaurantService,
+	events realtime.Publisher,
+	txManager database.TxManager,
+) TicketService {
+	return &ticketService{
+		ticketRepo:    ticketRepo,
+		stationRepo:   stationRepo,
+		orderSvc:      orderSvc,
+		restaurantSvc: restaurantSvc,
+		events:        events,
+		txManager:     txManager,
+	}
+}
+
+func (s *ticketService) GetTickets(actor utils.Actor, stationID uint, filter model.TicketFilter) ([]model.Ticket, error) {
+	station, err := s.stationRepo.GetByID(stationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStationAccess(s.restaurantSvc, actor, station.ID, station.BranchID); err != nil {
+		return nil, err
+	}
+
+	q := database.NewQuery().Eq("station_id", station.ID)
+	if filter.Status != "" {
+		if !model.IsValidTicketStatus(filter.Status) {
+			return nil, model.ErrInvalidTicketStatus
+		}
+		q.Eq("status", filter.Status)
+	} else {
+		q.In("status", []string{model.TicketPending, model.TicketReady})
+	}
+
+	return s.ticketRepo.Find(q.OrderBy("created_at"))
+}
+
+func (s *ticketService) GetTicket(actor utils.Actor, id uint) (*model.Ticket, error) {
+	ticket, err := s.ticketRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStationAccess(s.restaurantSvc, actor, ticket.StationID, ticket.BranchID); err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+func (s *ticketService) Transition(actor utils.Actor, id uint, req model.TicketStatusRequest) (*model.Ticket, error) {
+	var ticket *model.Ticket
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		ticketRepo := s.ticketRepo.WithTx(tx)
+
+		var err error
+		ticket, err = ticketRepo.GetForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if err := checkStationAccess(s.restaurantSvc, actor, ticket.StationID, ticket.BranchID); err != nil {
+			return err
+		}
+		if err := ticket.Advance(req.Status, time.Now()); err != nil {
+			return err
+		}
+		return ticketRepo.Update(ticket)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.events.Publish(realtime.StationTopic(ticket.StationID), model.EventTicketUpdated, ticket); err != nil {
+		log.Println("Failed to publish ticket event:", err)
+	}
+
+	// The ticket is saved; an order that fails to move along can still be
+	// moved by staff
+	if _, err := s.orderSvc.SyncKitchen(actor, ticket.OrderID, s.progress); err != nil {
+		log.Println("Failed to update order from kitchen ticket:", err)
+	}
+
+	return ticket, nil
+}
+
+// progress counts the open and done tickets of an order for the order
+// service
+func (s *ticketService) progress(tx *gorm.DB, orderID uint) (*ordermodel.KitchenProgress, error) {
+	progress, err := s.ticketRepo.WithTx(tx).GetProgress(orderID)
+	if err != nil {
+		return nil, err
+	}
+	return &ordermodel.KitchenProgress{Open: progress.Open, Done: progress.Done}, nil
+}
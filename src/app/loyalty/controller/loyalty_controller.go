@@ -0,0 +1,356 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/faisd405/go-restapi-gin/src/app/loyalty/model"
+	"github.com/faisd405/go-restapi-gin/src/app/loyalty/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ErrCodeInsufficientPoints = "INSUFFICIENT_POINTS"
+	ErrCodeLoyaltyDisabled    = "LOYALTY_DISABLED"
+)
+
+type LoyaltyController struct {
+	loyaltyService service.LoyaltyService
+}
+
+func NewLoyaltyController(loyaltyService service.LoyaltyService) *LoyaltyController {
+	return &LoyaltyController{loyaltyService: loyaltyService}
+}
+
+// GetMyLoyalty godoc
+// @Summary Get my loyalty points
+// @Description Get the point balance and tier of the authenticated user and the points left to the next tier
+// @Tags loyalty
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} utils.Response
+// @Router /users/loyalty [get]
+func (ctrl *LoyaltyController) GetMyLoyalty(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	summary, err := ctrl.loyaltyService.GetSummary(actor)
+	if err != nil {
+		loyaltyErrorResponse(c, "Failed to retrieve loyalty points", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Loyalty points retrieved successfully", summary)
+}
+
+// GetMyHistory godoc
+// @Summary Get my loyalty history
+// @Description List the points the authenticated user earned, redeemed and lost, newest first
+// @Tags loyalty
+// @Produce json
+// @Security ApiKeyAuth
+// @Param type query string false "earn, redeem, expire or adjust"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} utils.Response
+// @Router /users/loyalty/history [get]
+func (ctrl *LoyaltyController) GetMyHistory(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	page, limit := utils.GetPagination(c)
+	entries, total, err := ctrl.loyaltyService.GetHistory(actor, page, limit, model.EntryFilter{Type: c.Query("type")})
+	if err != nil {
+		loyaltyErrorResponse(c, "Failed to retrieve loyalty history", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Loyalty history retrieved successfully",
+		utils.PaginatedData("entries", entries, page, limit, total))
+}
+
+// GetUserLoyalty godoc
+// @Summary Get loyalty points of a user (Admin only)
+// @Description Get the point balance and tier of a user
+// @Tags loyalty
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/users/{id}/loyalty [get]
+func (ctrl *LoyaltyController) GetUserLoyalty(c *gin.Context) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	summary, err := ctrl.loyaltyService.GetUserSummary(id)
+	if err != nil {
+		loyaltyErrorResponse(c, "Failed to retrieve loyalty points", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Loyalty points retrieved successfully", summary)
+}
+
+// GetUserHistory godoc
+// @Summary Get loyalty history of a user (Admin only)
+// @Description List the loyalty ledger of a user, newest first
+// @Tags loyalty
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "User ID"
+// @Param type query string false "earn, redeem, expire or adjust"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/users/{id}/loyalty/history [get]
+func (ctrl *LoyaltyController) GetUserHistory(c *gin.Context) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	page, limit := utils.GetPagination(c)
+	entries, total, err := ctrl.loyaltyService.GetUserHistory(id, page, limit, model.EntryFilter{Type: c.Query("type")})
+	if err != nil {
+		loyaltyErrorResponse(c, "Failed to retrieve loyalty history", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Loyalty history retrieved successfully",
+		utils.PaginatedData("entries", entries, page, limit, total))
+}
+
+// Adjust godoc
+// @Summary Adjust loyalty points (Admin only)
+// @Description Add points to a user or, with negative points, take them off. The balance cannot go below zero.
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "User ID"
+// @Param adjustment body model.AdjustRequest true "Adjustment"
+// @Success 201 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /admin/users/{id}/loyalty/adjustments [post]
+func (ctrl *LoyaltyController) Adjust(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	var req model.AdjustRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	entry, err := ctrl.loyaltyService.Adjust(actor, id, req)
+	if err != nil {
+		loyaltyErrorResponse(c, "Failed to adjust loyalty points", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Loyalty points adjusted successfully", entry)
+}
+
+// GetSettings godoc
+// @Summary Get loyalty settings (Admin/Manager)
+// @Description Get the earn rate, point value and expiry of the loyalty program at a branch
+// @Tags loyalty
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/loyalty-settings [get]
+func (ctrl *LoyaltyController) GetSettings(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	settings, err := ctrl.loyaltyService.GetSettings(actor, id)
+	if err != nil {
+		loyaltyErrorResponse(c, "Failed to retrieve loyalty settings", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Loyalty settings retrieved successfully", settings)
+}
+
+// UpdateSettings godoc
+// @Summary Update loyalty settings (Admin/Manager)
+// @Description Turn the loyalty program of a branch on or off and set how many points an amount spent earns, what a point is worth at checkout and after how many days points expire. An expiry of 0 keeps points forever.
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param settings body model.SettingsRequest true "Loyalty settings"
+// @Success 200 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /branches/{id}/loyalty-settings [put]
+func (ctrl *LoyaltyController) UpdateSettings(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.SettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	settings, err := ctrl.loyaltyService.UpdateSettings(actor, id, req)
+	if err != nil {
+		loyaltyErrorResponse(c, "Loyalty settings update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Loyalty settings updated successfully", settings)
+}
+
+// GetTiers godoc
+// @Summary Get loyalty tiers (Admin only)
+// @Description List the loyalty tiers by the lifetime points they need
+// @Tags loyalty
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} utils.Response
+// @Router /admin/loyalty/tiers [get]
+func (ctrl *LoyaltyController) GetTiers(c *gin.Context) {
+	tiers, err := ctrl.loyaltyService.GetTiers()
+	if err != nil {
+		loyaltyErrorResponse(c, "Failed to retrieve loyalty tiers", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Loyalty tiers retrieved successfully", tiers)
+}
+
+// CreateTier godoc
+// @Summary Create loyalty tier (Admin only)
+// @Description Add a tier users reach with min_points lifetime points. Its multiplier, in basis points, scales the points they earn.
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param tier body model.TierRequest true "Tier"
+// @Success 201 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /admin/loyalty/tiers [post]
+func (ctrl *LoyaltyController) CreateTier(c *gin.Context) {
+	var req model.TierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	tier, err := ctrl.loyaltyService.CreateTier(req)
+	if err != nil {
+		loyaltyErrorResponse(c, "Loyalty tier creation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Loyalty tier created successfully", tier)
+}
+
+// UpdateTier godoc
+// @Summary Update loyalty tier (Admin only)
+// @Description Update the name, threshold and multiplier of a tier
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Tier ID"
+// @Param tier body model.TierRequest true "Tier"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/loyalty/tiers/{id} [put]
+func (ctrl *LoyaltyController) UpdateTier(c *gin.Context) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid tier ID", err.Error())
+		return
+	}
+
+	var req model.TierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	tier, err := ctrl.loyaltyService.UpdateTier(id, req)
+	if err != nil {
+		loyaltyErrorResponse(c, "Loyalty tier update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Loyalty tier updated successfully", tier)
+}
+
+// DeleteTier godoc
+// @Summary Delete loyalty tier (Admin only)
+// @Description Remove a tier; its members fall back to the tier below
+// @Tags loyalty
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Tier ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/loyalty/tiers/{id} [delete]
+func (ctrl *LoyaltyController) DeleteTier(c *gin.Context) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid tier ID", err.Error())
+		return
+	}
+
+	if err := ctrl.loyaltyService.DeleteTier(id); err != nil {
+		loyaltyErrorResponse(c, "Loyalty tier deletion failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Loyalty tier deleted successfully", nil)
+}
+
+func loyaltyErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrInsufficientPoints):
+		utils.ErrorResponseWithCode(c, http.StatusUnprocessableEntity, ErrCodeInsufficientPoints, message, err.Error())
+	case errors.Is(err, model.ErrInvalidEntryType):
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, message, err.Error())
+	default:
+		utils.DatabaseErrorResponse(c, message, err)
+	}
+}
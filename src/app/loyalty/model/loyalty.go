@@ -0,0 +1,191 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Ledger entry types. Earned points and positive adjustments are lots that
+// redemptions and negative adjustments use up oldest first; expiry takes
+// what is left of a lot once it lapses.
+const (
+	EntryEarn   = "earn"
+	EntryRedeem = "redeem"
+	EntryExpire = "expire"
+	EntryAdjust = "adjust"
+)
+
+// DiscountCode marks the order discount paid with loyalty points
+const DiscountCode = "LOYALTY"
+
+var ErrInvalidEntryType = errors.New("entry type must be earn, redeem, expire or adjust")
+
+// IsValidEntryType reports whether t is a known ledger entry type
+func IsValidEntryType(t string) bool {
+	switch t {
+	case EntryEarn, EntryRedeem, EntryExpire, EntryAdjust:
+		return true
+	}
+	return false
+}
+
+// Account is the loyalty balance of a user. LifetimePoints counts every
+// point earned and sets the tier.
+type Account struct {
+	UserID         uint      `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	Balance        int64     `json:"balance" gorm:"not null;default:0"`
+	LifetimePoints int64     `json:"lifetime_points" gorm:"not null;default:0"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (Account) TableName() string {
+	return "loyalty_accounts"
+}
+
+// Entry is a line of the append-only points ledger of a user. Points are
+// signed and Balance is the account balance after the entry. Remaining is
+// what is left of a lot of points, which expires at ExpiresAt.
+type Entry struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	Type      string     `json:"type" gorm:"type:varchar(20);not null"`
+	Points    int64      `json:"points" gorm:"not null"`
+	Balance   int64      `json:"balance" gorm:"not null"`
+	Remaining int64      `json:"-" gorm:"not null;default:0"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	BranchID  *uint      `json:"branch_id"`
+	OrderID   *uint      `json:"order_id" gorm:"index"`
+	ActorID   *uint      `json:"actor_id"`
+	Note      string     `json:"note" gorm:"type:text"`
+	CreatedAt time.Time  `json:"created_at" gorm:"index"`
+}
+
+func (Entry) TableName() string {
+	return "loyalty_entries"
+}
+
+// LotUse records the points of a lot an entry used up, so the points of a
+// redemption go back to the lots they came from and keep their expiry
+type LotUse struct {
+	ID      uint  `json:"id" gorm:"primaryKey"`
+	EntryID uint  `json:"entry_id" gorm:"not null;index"`
+	LotID   uint  `json:"lot_id" gorm:"not null"`
+	Points  int64 `json:"points" gorm:"not null"`
+}
+
+func (LotUse) TableName() string {
+	return "loyalty_lot_uses"
+}
+
+// Tier is a loyalty level users reach by the points they earned over time.
+// Members of a tier earn MultiplierBps of the base points, in basis points
+// (15000 earns one and a half times as much).
+type Tier struct {
+	ID            uint           `json:"id" gorm:"primaryKey"`
+	Name          string         `json:"name" gorm:"type:varchar(50);not null"`
+	MinPoints     int64          `json:"min_points" gorm:"not null;uniqueIndex:idx_loyalty_tiers_min_points,where:deleted_at IS NULL"`
+	MultiplierBps int            `json:"multiplier_bps" gorm:"not null;default:10000"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+func (Tier) TableName() string {
+	return "loyalty_tiers"
+}
+
+// Settings are the loyalty rules of a branch. Orders earn PointsPerUnit
+// points for every full EarnUnit of spend, in minor units, times the
+// multiplier of the customer's tier. A point redeemed at checkout is worth
+// PointValue minor units. Earned points expire after ExpiryDays; 0 keeps
+// them forever.
+type Settings struct {
+	BranchID        uint      `json:"branch_id" gorm:"primaryKey;autoIncrement:false"`
+	Enabled         bool      `json:"enabled" gorm:"not null;default:false"`
+	EarnUnit        int64     `json:"earn_unit" gorm:"not null;default:100"`
+	PointsPerUnit   int64     `json:"points_per_unit" gorm:"not null;default:1"`
+	PointValue      int64     `json:"point_value" gorm:"not null;default:1"`
+	MinRedeemPoints int64     `json:"min_redeem_points" gorm:"not null;default:0"`
+	ExpiryDays      int       `json:"expiry_days" gorm:"not null;default:365"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func (Settings) TableName() string {
+	return "loyalty_settings"
+}
+
+// DefaultSettings returns the rules of a branch that has not configured
+// loyalty yet; the program is off until a manager enables it
+func DefaultSettings(branchID uint) *Settings {
+	return &Settings{
+		BranchID:      branchID,
+		EarnUnit:      100,
+		PointsPerUnit: 1,
+		PointValue:    1,
+		ExpiryDays:    365,
+	}
+}
+
+// EarnedPoints returns the points spend earns for a member of tier, which
+// may be nil. Partial units earn nothing and fractions are dropped.
+func (s *Settings) EarnedPoints(spend int64, tier *Tier) int64 {
+	if spend <= 0 || s.EarnUnit <= 0 {
+		return 0
+	}
+	points := spend / s.EarnUnit * s.PointsPerUnit
+	if tier != nil {
+		points = points * int64(tier.MultiplierBps) / 10000
+	}
+	return points
+}
+
+// ExpiresAt returns when points earned at t expire, or nil when they never
+// do
+func (s *Settings) ExpiresAt(t time.Time) *time.Time {
+	if s.ExpiryDays <= 0 {
+		return nil
+	}
+	expires := t.AddDate(0, 0, s.ExpiryDays)
+	return &expires
+}
+
+// Summary is the loyalty account of a user with their tier and the tier
+// they reach next
+type Summary struct {
+	Account
+	Tier             *Tier `json:"tier"`
+	NextTier         *Tier `json:"next_tier"`
+	PointsToNextTier int64 `json:"points_to_next_tier"`
+}
+
+type SettingsRequest struct {
+	Enabled         *bool `json:"enabled"`
+	EarnUnit        int64 `json:"earn_unit" binding:"required,min=1"`
+	PointsPerUnit   int64 `json:"points_per_unit" binding:"min=0"`
+	PointValue      int64 `json:"point_value" binding:"required,min=1"`
+	MinRedeemPoints int64 `json:"min_redeem_points" binding:"min=0"`
+	ExpiryDays      int   `json:"expiry_days" binding:"min=0,max=3650"`
+}
+
+type TierRequest struct {
+	Name          string `json:"name" binding:"required,max=50"`
+	MinPoints     int64  `json:"min_points" binding:"min=0"`
+	MultiplierBps int    `json:"multiplier_bps" binding:"required,min=1,max=100000"`
+}
+
+// AdjustRequest corrects the balance of a user. Positive points are added
+// as a lot that does not expire; negative ones are taken off the oldest
+// lots.
+type AdjustRequest struct {
+	Points int64  `json:"points" binding:"required"`
+	Note   string `json:"note" binding:"required,max=500"`
+}
+
+// EntryFilter narrows down ledger listings
+type EntryFilter struct {
+	Type string
+}
@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/loyalty/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AccountRepository interface {
+	// GetForUpdate loads the account of a user, opening an empty one when
+	// they have none, and locks it until the surrounding transaction ends
+	GetForUpdate(userID uint) (*model.Account, error)
+	// Post adds an entry to the ledger of account and its points to the
+	// balance. Positive points become a lot; negative ones use up the
+	// oldest lots first. The balance must cover negative points.
+	Post(account *model.Account, entry *model.Entry) error
+	// Restore gives back the points the redemptions of an order took and
+	// books them as entry. They go back to the lots they were taken from,
+	// so they expire as they would have.
+	Restore(account *model.Account, orderID uint, entry *model.Entry) error
+	// Expire books what is left of the lots of account that lapsed by now
+	// as expired
+	Expire(account *model.Account, now time.Time) ([]model.Entry, error)
+	// Earned returns the points the user of account earned on an order,
+	// net of those taken back since
+	Earned(account *model.Account, orderID uint) (int64, error)
+	WithTx(tx *gorm.DB) AccountRepository
+}
+
+type accountRepository struct {
+	database.Repository[model.Account]
+}
+
+func NewAccountRepository(db *gorm.DB) AccountRepository {
+	return &accountRepository{Repository: database.NewRepository[model.Account](db)}
+}
+
+func (r *accountRepository) GetForUpdate(userID uint) (*model.Account, error) {
+	err := r.DB().Clauses(clause.OnConflict{DoNothing: true}).Create(&model.Account{UserID: userID}).Error
+	if err != nil {
+		return nil, database.TranslateError(err)
+	}
+
+	var account model.Account
+	err = r.DB().Clauses(clause.Locking{Strength: "UPDATE"}).First(&account, "user_id = ?", userID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *accountRepository) Post(account *model.Account, entry *model.Entry) error {
+	if entry.Points > 0 {
+		entry.Remaining = entry.Points
+	}
+	if err := r.book(account, entry); err != nil {
+		return err
+	}
+
+	if entry.Points < 0 && entry.Type != model.EntryExpire {
+		return r.useLots(account.UserID, entry.ID, -entry.Points)
+	}
+	return nil
+}
+
+func (r *accountRepository) Restore(account *model.Account, orderID uint, entry *model.Entry) error {
+	var redemptions []model.Entry
+	err := r.DB().
+		Where("user_id = ? AND order_id = ? AND type = ? AND points < 0", account.UserID, orderID, model.EntryRedeem).
+		Find(&redemptions).Error
+	if err != nil {
+		return database.TranslateError(err)
+	}
+	if len(redemptions) == 0 {
+		return nil
+	}
+
+	entryIDs := make([]uint, 0, len(redemptions))
+	entry.Points = 0
+	for _, redemption := range redemptions {
+		entryIDs = append(entryIDs, redemption.ID)
+		entry.Points -= redemption.Points
+	}
+
+	var uses []model.LotUse
+	if err := r.DB().Where("entry_id IN ?", entryIDs).Find(&uses).Error; err != nil {
+		return database.TranslateError(err)
+	}
+	// Points not traced to a lot become a lot of their own
+	entry.Remaining = entry.Points
+	for _, use := range uses {
+		err := r.DB().Model(&model.Entry{}).
+			Where("id = ?", use.LotID).
+			Update("remaining", gorm.Expr("remaining + ?", use.Points)).Error
+		if err != nil {
+			return database.TranslateError(err)
+		}
+		entry.Remaining -= use.Points
+	}
+
+	return r.book(account, entry)
+}
+
+// book adds entry to the ledger of account and its points to the balance
+func (r *accountRepository) book(account *model.Account, entry *model.Entry) error {
+	entry.UserID = account.UserID
+	account.Balance += entry.Points
+	if entry.Type == model.EntryEarn {
+		account.LifetimePoints += entry.Points
+	}
+	entry.Balance = account.Balance
+
+	if err := r.Update(account); err != nil {
+		return err
+	}
+	return database.TranslateError(r.DB().Create(entry).Error)
+}
+
+func (r *accountRepository) Earned(account *model.Account, orderID uint) (int64, error) {
+	var points int64
+	err := r.DB().Model(&model.Entry{}).
+		Where("user_id = ? AND order_id = ? AND type = ?", account.UserID, orderID, model.EntryEarn).
+		Select("COALESCE(SUM(points), 0)").
+		Scan(&points).Error
+	return points, err
+}
+
+func (r *accountRepository) Expire(account *model.Account, now time.Time) ([]model.Entry, error) {
+	var lots []model.Entry
+	err := r.DB().
+		Where("user_id = ? AND remaining > 0 AND expires_at <= ?", account.UserID, now).
+		Order("expires_at, id").
+		Find(&lots).Error
+	if err != nil {
+		return nil, database.TranslateError(err)
+	}
+
+	expired := make([]model.Entry, 0, len(lots))
+	for _, lot := range lots {
+		if err := r.DB().Model(&lot).Update("remaining", 0).Error; err != nil {
+			return nil, database.TranslateError(err)
+		}
+		entry := model.Entry{
+			Type:     model.EntryExpire,
+			Points:   -lot.Remaining,
+			BranchID: lot.BranchID,
+			OrderID:  lot.OrderID,
+			Note:     "Points earned on " + lot.CreatedAt.Format("2006-01-02") + " expired",
+		}
+		if err := r.Post(account, &entry); err != nil {
+			return nil, err
+		}
+		expired = append(expired, entry)
+	}
+	return expired, nil
+}
+
+// useLots takes points off the lots of a user, those expiring first before
+// the others, and records which lots entry used
+func (r *accountRepository) useLots(userID, entryID uint, points int64) error {
+	var lots []model.Entry
+	err := r.DB().
+		Where("user_id = ? AND remaining > 0", userID).
+		Order("expires_at NULLS LAST, id").
+		Find(&lots).Error
+	if err != nil {
+		return database.TranslateError(err)
+	}
+
+	for _, lot := range lots {
+		if points <= 0 {
+			break
+		}
+		used := min(points, lot.Remaining)
+		if err := r.DB().Model(&lot).Update("remaining", lot.Remaining-used).Error; err != nil {
+			return database.TranslateError(err)
+		}
+		use := model.LotUse{EntryID: entryID, LotID: lot.ID, Points: used}
+		if err := r.DB().Create(&use).Error; err != nil {
+			return database.TranslateError(err)
+		}
+		points -= used
+	}
+	return nil
+}
+
+func (r *accountRepository) WithTx(tx *gorm.DB) AccountRepository {
+	return &accountRepository{Repository: r.Repository.WithTx(tx)}
+}
+
+type EntryRepository interface {
+	First(q *database.Query) (*model.Entry, error)
+	FindPage(q *database.Query) ([]model.Entry, int64, error)
+}
+
+type entryRepository struct {
+	database.Repository[model.Entry]
+}
+
+func NewEntryRepository(db *gorm.DB) EntryRepository {
+	return &entryRepository{Repository: database.NewRepository[model.Entry](db)}
+}
+
+type TierRepository interface {
+	Create(tier *model.Tier) error
+	GetByID(id uint) (*model.Tier, error)
+	Update(tier *model.Tier) error
+	Delete(id uint) error
+	Find(q *database.Query) ([]model.Tier, error)
+	// ForPoints returns the highest tier reached with points and the one
+	// after it; either is nil when there is none
+	ForPoints(points int64) (*model.Tier, *model.Tier, error)
+}
+
+type tierRepository struct {
+	database.Repository[model.Tier]
+}
+
+func NewTierRepository(db *gorm.DB) TierRepository {
+	return &tierRepository{Repository: database.NewRepository[model.Tier](db)}
+}
+
+func (r *tierRepository) ForPoints(points int64) (*model.Tier, *model.Tier, error) {
+	tier, err := r.First(database.NewQuery().Where("min_points", database.OpLte, points).OrderByDesc("min_points"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		tier = nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	next, err := r.First(database.NewQuery().Where("min_points", database.OpGt, points).OrderBy("min_points"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		next = nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	return tier, next, nil
+}
+
+type SettingsRepository interface {
+	// GetByBranch returns the settings of a branch, or defaults when the
+	// branch has none yet
+	GetByBranch(branchID uint) (*model.Settings, error)
+	Save(settings *model.Settings) error
+}
+
+type settingsRepository struct {
+	database.Repository[model.Settings]
+}
+
+func NewSettingsRepository(db *gorm.DB) SettingsRepository {
+	return &settingsRepository{Repository: database.NewRepository[model.Settings](db)}
+}
+
+func (r *settingsRepository) GetByBranch(branchID uint) (*model.Settings, error) {
+	settings, err := r.First(database.NewQuery().Eq("branch_id", branchID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.DefaultSettings(branchID), nil
+	}
+	return settings, err
+}
+
+func (r *settingsRepository) Save(settings *model.Settings) error {
+	return r.Upsert(settings, []string{"branch_id"},
+		"enabled", "earn_unit", "points_per_unit", "point_value", "min_redeem_points", "expiry_days", "updated_at")
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/loyalty/model"
+	"github.com/faisd405/go-restapi-gin/src/app/loyalty/repository"
+	pricingmodel "github.com/faisd405/go-restapi-gin/src/app/pricing/model"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	userservice "github.com/faisd405/go-restapi-gin/src/app/user/service"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrLoyaltyDisabled        = errors.New("branch does not run a loyalty program")
+	ErrInsufficientPoints     = errors.New("not enough loyalty points")
+	ErrBelowMinRedeem         = errors.New("fewer points than the minimum that can be redeemed")
+	ErrRedemptionExceedsOrder = errors.New("redeemed points are worth more than the order")
+	ErrAccountRequired        = errors.New("loyalty points can only be redeemed with an account")
+)
+
+type LoyaltyService interface {
+	// GetSummary returns the balance and tier of the actor
+	GetSummary(actor utils.Actor) (*model.Summary, error)
+	// GetHistory lists the ledger of the actor, latest first
+	GetHistory(actor utils.Actor, page, limit int, filter model.EntryFilter) ([]model.Entry, int64, error)
+	GetUserSummary(userID uint) (*model.Summary, error)
+	GetUserHistory(userID uint, page, limit int, filter model.EntryFilter) ([]model.Entry, int64, error)
+	// Adjust corrects the balance of a user, e.g. for a complaint or a
+	// refunded order
+	Adjust(actor utils.Actor, userID uint, req model.AdjustRequest) (*model.Entry, error)
+	// Reclaim takes back the share of the points earned on an order that a
+	// refund gave back, out of what was still paid before it. It takes no
+	// more than the balance, as spent points cannot be returned.
+	Reclaim(orderID uint, refunded, paid int64) error
+
+	GetSettings(actor utils.Actor, branchID uint) (*model.Settings, error)
+	UpdateSettings(actor utils.Actor, branchID uint, req model.SettingsRequest) (*model.Settings, error)
+
+	GetTiers() ([]model.Tier, error)
+	CreateTier(req model.TierRequest) (*model.Tier, error)
+	UpdateTier(id uint, req model.TierRequest) (*model.Tier, error)
+	DeleteTier(id uint) error
+
+	// Redemption prices points redeemed at a branch as an order discount
+	// for the pricing engine
+	Redemption(branchID uint, points int64) (*pricingmodel.DiscountInput, error)
+}
+
+type loyaltyService struct {
+	accountRepo   repository.AccountRepository
+	entryRepo     repository.EntryRepository
+	tierRepo      repository.TierRepository
+	settingsRepo  repository.SettingsRepository
+	restaurantSvc restaurantservice.RestaurantService
+	userSvc       userservice.UserService
+	txManager     database.TxManager
+	now           func() time.Time
+}
+
+func NewLoyaltyService(
+	accountRepo repository.AccountRepository,
+	entryRepo repository.EntryRepository,
+	tierRepo repository.TierRepository,
+	settingsRepo repository.SettingsRepository,
+	restaurantSvc restaurantservice.RestaurantService,
+	userSvc userservice.UserService,
+	txManager database.TxManager,
+) LoyaltyService {
+	return &loyaltyService{
+		accountRepo:   accountRepo,
+		entryRepo:     entryRepo,
+		tierRepo:      tierRepo,
+		settingsRepo:  settingsRepo,
+		restaurantSvc: restaurantSvc,
+		userSvc:       userSvc,
+		txManager:     txManager,
+		now:           time.Now,
+	}
+}
+
+func (s *loyaltyService) GetSummary(actor utils.Actor) (*model.Summary, error) {
+	return s.summary(actor.UserID)
+}
+
+func (s *loyaltyService) GetHistory(actor utils.Actor, page, limit int, filter model.EntryFilter) ([]model.Entry, int64, error) {
+	return s.history(actor.UserID, page, limit, filter)
+}
+
+func (s *loyaltyService) GetUserSummary(userID uint) (*model.Summary, error) {
+	if _, err := s.userSvc.GetProfile(userID); err != nil {
+		return nil, err
+	}
+	return s.summary(userID)
+}
+
+func (s *loyaltyService) GetUserHistory(userID uint, page, limit int, filter model.EntryFilter) ([]model.Entry, int64, error) {
+	if _, err := s.userSvc.GetProfile(userID); err != nil {
+		return nil, 0, err
+	}
+	return s.history(userID, page, limit, filter)
+}
+
+func (s *loyaltyService) Adjust(actor utils.Actor, userID uint, req model.AdjustRequest) (*model.Entry, error) {
+	if _, err := s.userSvc.GetProfile(userID); err != nil {
+		return nil, err
+	}
+
+	entry := &model.Entry{Type: model.EntryAdjust, Points: req.Points, ActorID: utils.ActorID(actor), Note: req.Note}
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		accountRepo := s.accountRepo.WithTx(tx)
+		account, err := accountRepo.GetForUpdate(userID)
+		if err != nil {
+			return err
+		}
+		if _, err := accountRepo.Expire(account, s.now()); err != nil {
+			return err
+		}
+		if account.Balance+entry.Points < 0 {
+			return ErrInsufficientPoints
+		}
+		return accountRepo.Post(account, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *loyaltyService) Reclaim(orderID uint, refunded, paid int64) error {
+	if refunded <= 0 || paid <= 0 {
+		return nil
+	}
+
+	// Orders of guests and orders not completed yet earned nothing
+	earn, err := s.entryRepo.First(database.NewQuery().Eq("order_id", orderID).Eq("type", model.EntryEarn))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		accountRepo := s.accountRepo.WithTx(tx)
+		account, err := accountRepo.GetForUpdate(earn.UserID)
+		if err != nil {
+			return err
+		}
+		if _, err := accountRepo.Expire(account, s.now()); err != nil {
+			return err
+		}
+
+		earned, err := accountRepo.Earned(account, orderID)
+		if err != nil {
+			return err
+		}
+		points := min(earned*min(refunded, paid)/paid, account.Balance)
+		if points <= 0 {
+			return nil
+		}
+		return accountRepo.Post(account, &model.Entry{
+			Type:     model.EntryEarn,
+			Points:   -points,
+			BranchID: earn.BranchID,
+			OrderID:  &orderID,
+			Note:     fmt.Sprintf("Order #%d refunded", orderID),
+		})
+	})
+}
+
+func (s *loyaltyService) GetSettings(actor utils.Actor, branchID uint) (*model.Settings, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+	return s.settingsRepo.GetByBranch(branchID)
+}
+
+func (s *loyaltyService) UpdateSettings(actor utils.Actor, branchID uint, req model.SettingsRequest) (*model.Settings, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.settingsRepo.GetByBranch(branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Enabled != nil {
+		settings.Enabled = *req.Enabled
+	}
+	settings.EarnUnit = req.EarnUnit
+	settings.PointsPerUnit = req.PointsPerUnit
+	settings.PointValue = req.PointValue
+	settings.MinRedeemPoints = req.MinRedeemPoints
+	settings.ExpiryDays = req.ExpiryDays
+
+	if err := s.settingsRepo.Save(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (s *loyaltyService) GetTiers() ([]model.Tier, error) {
+	return s.tierRepo.Find(database.NewQuery().OrderBy("min_points"))
+}
+
+func (s *loyaltyService) CreateTier(req model.TierRequest) (*model.Tier, error) {
+	tier := &model.Tier{Name: req.Name, MinPoints: req.MinPoints, MultiplierBps: req.MultiplierBps}
+	if err := s.tierRepo.Create(tier); err != nil {
+		return nil, err
+	}
+	return tier, nil
+}
+
+func (s *loyaltyService) UpdateTier(id uint, req model.TierRequest) (*model.Tier, error) {
+	tier, err := s.tierRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	tier.Name = req.Name
+	tier.MinPoints = req.MinPoints
+	tier.MultiplierBps = req.MultiplierBps
+	if err := s.tierRepo.Update(tier); err != nil {
+		return nil, err
+	}
+	return tier, nil
+}
+
+func (s *loyaltyService) DeleteTier(id uint) error {
+	if _, err := s.tierRepo.GetByID(id); err != nil {
+		return err
+	}
+	return s.tierRepo.Delete(id)
+}
+
+func (s *loyaltyService) Redemption(branchID uint, points int64) (*pricingmodel.DiscountInput, error) {
+	settings, err := s.settingsRepo.GetByBranch(branchID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.Enabled {
+		return nil, ErrLoyaltyDisabled
+	}
+	if points < settings.MinRedeemPoints {
+		return nil, ErrBelowMinRedeem
+	}
+
+	return &pricingmodel.DiscountInput{
+		Code:   model.DiscountCode,
+		Name:   fmt.Sprintf("%d loyalty points", points),
+		Amount: points * settings.PointValue,
+	}, nil
+}
+
+// summary expires the lapsed points of a user and returns their account
+// with its tiers
+func (s *loyaltyService) summary(userID uint) (*model.Summary, error) {
+	var account *model.Account
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		accountRepo := s.accountRepo.WithTx(tx)
+		var err error
+		account, err = accountRepo.GetForUpdate(userID)
+		if err != nil {
+			return err
+		}
+		_, err = accountRepo.Expire(account, s.now())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tier, next, err := s.tierRepo.ForPoints(account.LifetimePoints)
+	if err != nil {
+		return nil, err
+	}
+	summary := &model.Summary{Account: *account, Tier: tier, NextTier: next}
+	if next != nil {
+		summary.PointsToNextTier = next.MinPoints - account.LifetimePoints
+	}
+	return summary, nil
+}
+
+func (s *loyaltyService) history(userID uint, page, limit int, filter model.EntryFilter) ([]model.Entry, int64, error) {
+	q := database.NewQuery().Eq("user_id", userID)
+	if filter.Type != "" {
+		if !model.IsValidEntryType(filter.Type) {
+			return nil, 0, model.ErrInvalidEntryType
+		}
+		q.Eq("type", filter.Type)
+	}
+	return s.entryRepo.FindPage(q.OrderByDesc("created_at").OrderByDesc("id").Paginate(utils.Offset(page, limit), limit))
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/loyalty/model"
+	"github.com/faisd405/go-restapi-gin/src/app/loyalty/repository"
+	ordermodel "github.com/faisd405/go-restapi-gin/src/app/order/model"
+	"gorm.io/gorm"
+)
+
+// RefundedFunc reads within tx how much of an order was refunded
+type RefundedFunc func(tx *gorm.DB, orderID uint) (int64, error)
+
+type orderHook struct {
+	accountRepo  repository.AccountRepository
+	tierRepo     repository.TierRepository
+	settingsRepo repository.SettingsRepository
+	refunded     RefundedFunc
+	now          func() time.Time
+}
+
+// NewOrderHook returns the order status hook that books the points of
+// orders in the ledger of their user
+func NewOrderHook(
+	accountRepo repository.AccountRepository,
+	tierRepo repository.TierRepository,
+	settingsRepo repository.SettingsRepository,
+	refunded RefundedFunc,
+) ordermodel.StatusHook {
+	return &orderHook{
+		accountRepo:  accountRepo,
+		tierRepo:     tierRepo,
+		settingsRepo: settingsRepo,
+		refunded:     refunded,
+		now:          time.Now,
+	}
+}
+
+// OnStatusChange takes redeemed points off the balance when an order is
+// placed, gives them back to the lots they came from when it is cancelled
+// or rejected after that and books the points earned on what the user paid
+// when it is completed. Guest orders have no account.
+func (h *orderHook) OnStatusChange(tx *gorm.DB, event *ordermodel.StatusEvent) error {
+	order := event.Order
+	if order.UserID == nil {
+		return nil
+	}
+	switch order.Status {
+	case ordermodel.StatusPlaced:
+		if order.RedeemPoints == 0 {
+			return nil
+		}
+	case ordermodel.StatusCancelled, ordermodel.StatusRejected:
+		// Drafts never took any points
+		if order.RedeemPoints == 0 || event.From == ordermodel.StatusDraft {
+			return nil
+		}
+	case ordermodel.StatusCompleted:
+	default:
+		return nil
+	}
+
+	accountRepo := h.accountRepo.WithTx(tx)
+	account, err := accountRepo.GetForUpdate(*order.UserID)
+	if err != nil {
+		return err
+	}
+	if _, err := accountRepo.Expire(account, h.now()); err != nil {
+		return err
+	}
+
+	var entry *model.Entry
+	switch order.Status {
+	case ordermodel.StatusPlaced:
+		if account.Balance < order.RedeemPoints {
+			return ErrInsufficientPoints
+		}
+		entry = &model.Entry{Type: model.EntryRedeem, Points: -order.RedeemPoints}
+	case ordermodel.StatusCancelled, ordermodel.StatusRejected:
+		entry = &model.Entry{
+			Type:     model.EntryRedeem,
+			BranchID: &order.BranchID,
+			OrderID:  &order.ID,
+			Note:     fmt.Sprintf("Order #%d %s", order.ID, order.Status),
+		}
+		if err := accountRepo.Restore(account, order.ID, entry); err != nil {
+			return err
+		}
+		// Lots that lapsed while the order was open expire at once
+		_, err := accountRepo.Expire(account, h.now())
+		return err
+	case ordermodel.StatusCompleted:
+		spend, err := h.spend(tx, order)
+		if err != nil {
+			return err
+		}
+		entry, err = h.earned(order.BranchID, account, spend)
+		if err != nil || entry == nil {
+			return err
+		}
+	}
+
+	entry.BranchID = &order.BranchID
+	entry.OrderID = &order.ID
+	return accountRepo.Post(account, entry)
+}
+
+// spend is what an order earns points on: its subtotal after discounts,
+// less the share of it refunded before the order completed. Later refunds
+// take their share back through LoyaltyService.Reclaim.
+func (h *orderHook) spend(tx *gorm.DB, order *ordermodel.Order) (int64, error) {
+	spend := order.Subtotal - order.DiscountTotal
+	if order.Total <= 0 {
+		return spend, nil
+	}
+
+	refunded, err := h.refunded(tx, order.ID)
+	if err != nil {
+		return 0, err
+	}
+	refunded = min(refunded, order.Total)
+	return spend - spend*refunded/order.Total, nil
+}
+
+// earned returns the entry for what the holder of account spent at a
+// branch, or nil when it earns nothing
+func (h *orderHook) earned(branchID uint, account *model.Account, spend int64) (*model.Entry, error) {
+	settings, err := h.settingsRepo.GetByBranch(branchID)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.Enabled {
+		return nil, nil
+	}
+
+	tier, _, err := h.tierRepo.ForPoints(account.LifetimePoints)
+	if err != nil {
+		return nil, err
+	}
+
+	points := settings.EarnedPoints(spend, tier)
+	if points <= 0 {
+		return nil, nil
+	}
+	return &model.Entry{
+		Type:      model.EntryEarn,
+		Points:    points,
+		ExpiresAt: settings.ExpiresAt(h.now()),
+	}, nil
+}
@@ -0,0 +1,268 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/faisd405/go-restapi-gin/src/app/menu/model"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetDietaryLabels godoc
+// @Summary Get dietary vocabulary
+// @Description List the standard allergens, diets and accepted allergen aliases
+// @Tags menu
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /menu/dietary-labels [get]
+func (ctrl *MenuController) GetDietaryLabels(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Dietary labels retrieved successfully", ctrl.menuService.GetDietaryLabels())
+}
+
+// GetIngredients godoc
+// @Summary List ingredients (Admin/Manager)
+// @Description List the ingredients of a branch with their allergens and diets
+// @Tags menu
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/menu/ingredients [get]
+func (ctrl *MenuController) GetIngredients(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	branchID, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	ingredients, err := ctrl.menuService.GetIngredients(actor, branchID)
+	if err != nil {
+		menuErrorResponse(c, "Failed to retrieve ingredients", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ingredients retrieved successfully", ingredients)
+}
+
+// CreateIngredient godoc
+// @Summary Create ingredient (Admin/Manager)
+// @Description Add an ingredient with its allergens and diets to a branch
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param ingredient body model.IngredientRequest true "Ingredient data"
+// @Success 201 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /branches/{id}/menu/ingredients [post]
+func (ctrl *MenuController) CreateIngredient(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	branchID, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.IngredientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	ingredient, err := ctrl.menuService.CreateIngredient(actor, branchID, req)
+	if err != nil {
+		menuErrorResponse(c, "Ingredient creation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Ingredient created successfully", ingredient)
+}
+
+// UpdateIngredient godoc
+// @Summary Update ingredient (Admin/Manager)
+// @Description Update an ingredient by ID
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Ingredient ID"
+// @Param ingredient body model.IngredientRequest true "Ingredient data"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /menu/ingredients/{id} [put]
+func (ctrl *MenuController) UpdateIngredient(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid ingredient ID", err.Error())
+		return
+	}
+
+	var req model.IngredientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	ingredient, err := ctrl.menuService.UpdateIngredient(actor, id, req)
+	if err != nil {
+		menuErrorResponse(c, "Ingredient update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ingredient updated successfully", ingredient)
+}
+
+// DeleteIngredient godoc
+// @Summary Delete ingredient (Admin/Manager)
+// @Description Delete an ingredient and remove it from every item
+// @Tags menu
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Ingredient ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /menu/ingredients/{id} [delete]
+func (ctrl *MenuController) DeleteIngredient(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid ingredient ID", err.Error())
+		return
+	}
+
+	if err := ctrl.menuService.DeleteIngredient(actor, id); err != nil {
+		menuErrorResponse(c, "Ingredient deletion failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ingredient deleted successfully", nil)
+}
+
+// SetItemIngredients godoc
+// @Summary Set item ingredients (Admin/Manager)
+// @Description Replace the ingredient list of a menu item
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Menu item ID"
+// @Param ingredients body model.ItemIngredientsRequest true "Ingredient IDs"
+// @Success 200 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /menu/items/{id}/ingredients [put]
+func (ctrl *MenuController) SetItemIngredients(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid menu item ID", err.Error())
+		return
+	}
+
+	var req model.ItemIngredientsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	item, err := ctrl.menuService.SetItemIngredients(actor, id, req)
+	if err != nil {
+		menuErrorResponse(c, "Ingredient assignment failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ingredients assigned successfully", item)
+}
+
+// GetRecipe godoc
+// @Summary Get item recipe (Admin/Manager)
+// @Description List the ingredients of a menu item with the amount, in the unit of the ingredient, one serving uses up
+// @Tags menu
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Menu item ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /menu/items/{id}/recipe [get]
+func (ctrl *MenuController) GetRecipe(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid menu item ID", err.Error())
+		return
+	}
+
+	recipe, err := ctrl.menuService.GetRecipe(actor, id)
+	if err != nil {
+		menuErrorResponse(c, "Failed to retrieve recipe", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recipe retrieved successfully", recipe)
+}
+
+// SetRecipe godoc
+// @Summary Set item recipe (Admin/Manager)
+// @Description Replace the ingredients of a menu item together with the amounts one serving uses up. Stock of these ingredients is deducted when orders are accepted.
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Menu item ID"
+// @Param recipe body model.RecipeRequest true "Recipe lines"
+// @Success 200 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /menu/items/{id}/recipe [put]
+func (ctrl *MenuController) SetRecipe(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid menu item ID", err.Error())
+		return
+	}
+
+	var req model.RecipeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	recipe, err := ctrl.menuService.SetRecipe(actor, id, req)
+	if err != nil {
+		menuErrorResponse(c, "Recipe update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recipe updated successfully", recipe)
+}
@@ -0,0 +1,408 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/faisd405/go-restapi-gin/src/app/menu/model"
+	"github.com/faisd405/go-restapi-gin/src/app/menu/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type MenuController struct {
+	menuService service.MenuService
+}
+
+func NewMenuController(menuService service.MenuService) *MenuController {
+	return &MenuController{menuService: menuService}
+}
+
+// GetMenu godoc
+// @Summary Get branch menu
+// @Description Get the full menu of a branch with categories and their items
+// @Tags menu
+// @Produce json
+// @Param id path int true "Branch ID"
+// @Param available_only query bool false "Only include available items"
+// @Param exclude_allergens query string false "Comma-separated allergens to exclude, e.g. nuts,dairy"
+// @Param diet query string false "Comma-separated diets every item must satisfy, e.g. vegan"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /branches/{id}/menu [get]
+func (ctrl *MenuController) GetMenu(c *gin.Context) {
+	branchID, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	availableOnly, _ := strconv.ParseBool(c.DefaultQuery("available_only", "false"))
+
+	dietary, err := parseDietaryFilter(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid dietary filter", err.Error())
+		return
+	}
+
+	menu, err := ctrl.menuService.GetMenu(branchID, model.MenuFilter{AvailableOnly: availableOnly, Dietary: dietary})
+	if err != nil {
+		utils.DatabaseErrorResponse(c, "Menu not found", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Menu retrieved successfully", menu)
+}
+
+// GetItem godoc
+// @Summary Get menu item
+// @Description Get a single menu item
+// @Tags menu
+// @Produce json
+// @Param id path int true "Menu item ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /menu/items/{id} [get]
+func (ctrl *MenuController) GetItem(c *gin.Context) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid menu item ID", err.Error())
+		return
+	}
+
+	item, err := ctrl.menuService.GetItem(id)
+	if err != nil {
+		utils.DatabaseErrorResponse(c, "Menu item not found", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Menu item retrieved successfully", item)
+}
+
+// CreateCategory godoc
+// @Summary Create menu category (Admin/Manager)
+// @Description Add a menu category to a branch
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param category body model.CategoryRequest true "Category data"
+// @Success 201 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/menu/categories [post]
+func (ctrl *MenuController) CreateCategory(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	branchID, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	category, err := ctrl.menuService.CreateCategory(actor, branchID, req)
+	if err != nil {
+		menuErrorResponse(c, "Category creation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Category created successfully", category)
+}
+
+// ReorderCategories godoc
+// @Summary Reorder menu categories (Admin/Manager)
+// @Description Set the display order of every category of a branch
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param order body model.ReorderCategoriesRequest true "Category IDs in display order"
+// @Success 200 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /branches/{id}/menu/categories/order [put]
+func (ctrl *MenuController) ReorderCategories(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	branchID, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.ReorderCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	categories, err := ctrl.menuService.ReorderCategories(actor, branchID, req)
+	if err != nil {
+		menuErrorResponse(c, "Category reorder failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Categories reordered successfully", categories)
+}
+
+// UpdateCategory godoc
+// @Summary Update menu category (Admin/Manager)
+// @Description Update a menu category by ID
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Category ID"
+// @Param category body model.CategoryRequest true "Category data"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /menu/categories/{id} [put]
+func (ctrl *MenuController) UpdateCategory(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID", err.Error())
+		return
+	}
+
+	var req model.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	category, err := ctrl.menuService.UpdateCategory(actor, id, req)
+	if err != nil {
+		menuErrorResponse(c, "Category update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Category updated successfully", category)
+}
+
+// DeleteCategory godoc
+// @Summary Delete menu category (Admin/Manager)
+// @Description Delete a menu category and its items
+// @Tags menu
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Category ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /menu/categories/{id} [delete]
+func (ctrl *MenuController) DeleteCategory(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID", err.Error())
+		return
+	}
+
+	if err := ctrl.menuService.DeleteCategory(actor, id); err != nil {
+		menuErrorResponse(c, "Category deletion failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Category deleted successfully", nil)
+}
+
+// CreateItem godoc
+// @Summary Create menu item (Admin/Manager)
+// @Description Add an item to a menu category
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param item body model.ItemRequest true "Menu item data"
+// @Success 201 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /menu/items [post]
+func (ctrl *MenuController) CreateItem(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	var req model.ItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	item, err := ctrl.menuService.CreateItem(actor, req)
+	if err != nil {
+		menuErrorResponse(c, "Menu item creation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Menu item created successfully", item)
+}
+
+// UpdateItem godoc
+// @Summary Update menu item (Admin/Manager)
+// @Description Update a menu item by ID
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Menu item ID"
+// @Param item body model.ItemRequest true "Menu item data"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /menu/items/{id} [put]
+func (ctrl *MenuController) UpdateItem(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid menu item ID", err.Error())
+		return
+	}
+
+	var req model.ItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	item, err := ctrl.menuService.UpdateItem(actor, id, req)
+	if err != nil {
+		menuErrorResponse(c, "Menu item update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Menu item updated successfully", item)
+}
+
+// SetAvailability godoc
+// @Summary Toggle menu item availability (Admin/Manager)
+// @Description Mark a menu item as available or sold out
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Menu item ID"
+// @Param availability body model.AvailabilityRequest true "Availability"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /menu/items/{id}/availability [put]
+func (ctrl *MenuController) SetAvailability(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid menu item ID", err.Error())
+		return
+	}
+
+	var req model.AvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	item, err := ctrl.menuService.SetAvailability(actor, id, *req.IsAvailable)
+	if err != nil {
+		menuErrorResponse(c, "Availability update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Availability updated successfully", item)
+}
+
+// DeleteItem godoc
+// @Summary Delete menu item (Admin/Manager)
+// @Description Delete a menu item by ID
+// @Tags menu
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Menu item ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /menu/items/{id} [delete]
+func (ctrl *MenuController) DeleteItem(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid menu item ID", err.Error())
+		return
+	}
+
+	if err := ctrl.menuService.DeleteItem(actor, id); err != nil {
+		menuErrorResponse(c, "Menu item deletion failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Menu item deleted successfully", nil)
+}
+
+// parseDietaryFilter reads the exclude_allergens and diet query parameters
+func parseDietaryFilter(c *gin.Context) (model.DietaryFilter, error) {
+	allergens, err := model.NormalizeAllergens(splitQuery(c.Query("exclude_allergens")))
+	if err != nil {
+		return model.DietaryFilter{}, err
+	}
+	diets, err := model.NormalizeDiets(splitQuery(c.Query("diet")))
+	if err != nil {
+		return model.DietaryFilter{}, err
+	}
+	return model.DietaryFilter{ExcludeAllergens: allergens, Diets: diets}, nil
+}
+
+func splitQuery(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func menuErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrCategoryBranchMismatch),
+		errors.Is(err, service.ErrIncompleteReorder),
+		errors.Is(err, service.ErrModifierGroupBranchMismatch),
+		errors.Is(err, service.ErrDuplicateModifierGroup),
+		errors.Is(err, service.ErrOptionGroupMismatch),
+		errors.Is(err, service.ErrItemUnavailable),
+		errors.Is(err, service.ErrIngredientBranchMismatch),
+		errors.Is(err, service.ErrDuplicateIngredient),
+		errors.Is(err, model.ErrInvalidSelection),
+		errors.Is(err, model.ErrUnknownLabel):
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, message, err.Error())
+	default:
+		utils.DatabaseErrorResponse(c, message, err)
+	}
+}
@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/faisd405/go-restapi-gin/src/app/menu/model"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetModifierGroups godoc
+// @Summary List modifier groups (Admin/Manager)
+// @Description List the reusable modifier groups of a branch with their options
+// @Tags menu
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/menu/modifier-groups [get]
+func (ctrl *MenuController) GetModifierGroups(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	branchID, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	groups, err := ctrl.menuService.GetModifierGroups(actor, branchID)
+	if err != nil {
+		menuErrorResponse(c, "Failed to retrieve modifier groups", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Modifier groups retrieved successfully", groups)
+}
+
+// CreateModifierGroup godoc
+// @Summary Create modifier group (Admin/Manager)
+// @Description Add a modifier group with its options to a branch
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param group body model.ModifierGroupRequest true "Modifier group data"
+// @Success 201 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /branches/{id}/menu/modifier-groups [post]
+func (ctrl *MenuController) CreateModifierGroup(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	branchID, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.ModifierGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	group, err := ctrl.menuService.CreateModifierGroup(actor, branchID, req)
+	if err != nil {
+		menuErrorResponse(c, "Modifier group creation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Modifier group created successfully", group)
+}
+
+// UpdateModifierGroup godoc
+// @Summary Update modifier group (Admin/Manager)
+// @Description Update a modifier group and replace its option list. Options without an ID are created, omitted options are removed.
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Modifier group ID"
+// @Param group body model.ModifierGroupRequest true "Modifier group data"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /menu/modifier-groups/{id} [put]
+func (ctrl *MenuController) UpdateModifierGroup(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid modifier group ID", err.Error())
+		return
+	}
+
+	var req model.ModifierGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	group, err := ctrl.menuService.UpdateModifierGroup(actor, id, req)
+	if err != nil {
+		menuErrorResponse(c, "Modifier group update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Modifier group updated successfully", group)
+}
+
+// DeleteModifierGroup godoc
+// @Summary Delete modifier group (Admin/Manager)
+// @Description Delete a modifier group and detach it from every item
+// @Tags menu
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Modifier group ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /menu/modifier-groups/{id} [delete]
+func (ctrl *MenuController) DeleteModifierGroup(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid modifier group ID", err.Error())
+		return
+	}
+
+	if err := ctrl.menuService.DeleteModifierGroup(actor, id); err != nil {
+		menuErrorResponse(c, "Modifier group deletion failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Modifier group deleted successfully", nil)
+}
+
+// SetItemModifierGroups godoc
+// @Summary Attach modifier groups to item (Admin/Manager)
+// @Description Replace the modifier groups offered for a menu item, in display order
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Menu item ID"
+// @Param groups body model.ItemModifierGroupsRequest true "Modifier group IDs"
+// @Success 200 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /menu/items/{id}/modifier-groups [put]
+func (ctrl *MenuController) SetItemModifierGroups(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid menu item ID", err.Error())
+		return
+	}
+
+	var req model.ItemModifierGroupsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	item, err := ctrl.menuService.SetItemModifierGroups(actor, id, req)
+	if err != nil {
+		menuErrorResponse(c, "Modifier group assignment failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Modifier groups assigned successfully", item)
+}
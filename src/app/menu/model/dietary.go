@@ -0,0 +1,212 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/faisd405/go-restapi-gin/src/database"
+)
+
+// ErrUnknownLabel is wrapped when an allergen or diet is not part of the
+// standard vocabulary
+var ErrUnknownLabel = errors.New("unknown dietary label")
+
+// Allergens is the standard allergen vocabulary, based on the 14 allergens
+// that must be declared under EU FIC 1169/2011
+var Allergens = []string{
+	"gluten",
+	"crustaceans",
+	"eggs",
+	"fish",
+	"peanuts",
+	"soy",
+	"dairy",
+	"nuts",
+	"celery",
+	"mustard",
+	"sesame",
+	"sulphites",
+	"lupin",
+	"molluscs",
+}
+
+// Diets is the standard dietary tag vocabulary
+var Diets = []string{
+	"vegan",
+	"vegetarian",
+	"pescatarian",
+	"halal",
+	"kosher",
+	"gluten_free",
+	"dairy_free",
+}
+
+// allergenAliases maps common names onto the vocabulary. An alias may stand
+// for several allergens.
+var allergenAliases = map[string][]string{
+	"milk":      {"dairy"},
+	"lactose":   {"dairy"},
+	"egg":       {"eggs"},
+	"peanut":    {"peanuts"},
+	"soya":      {"soy"},
+	"tree_nuts": {"nuts"},
+	"sulfites":  {"sulphites"},
+	"shellfish": {"crustaceans", "molluscs"},
+	"wheat":     {"gluten"},
+}
+
+// impliedDiets lists the diets every item of a diet also satisfies
+var impliedDiets = map[string][]string{
+	"vegan":      {"vegetarian", "pescatarian", "dairy_free"},
+	"vegetarian": {"pescatarian"},
+}
+
+// dietConflicts lists the allergens that rule out a diet
+var dietConflicts = map[string][]string{
+	"vegan":       {"dairy", "eggs", "fish", "crustaceans", "molluscs"},
+	"vegetarian":  {"fish", "crustaceans", "molluscs"},
+	"gluten_free": {"gluten"},
+	"dairy_free":  {"dairy"},
+}
+
+// DietaryLabels is the vocabulary as returned by the API
+type DietaryLabels struct {
+	Allergens []string            `json:"allergens"`
+	Diets     []string            `json:"diets"`
+	Aliases   map[string][]string `json:"aliases"`
+}
+
+// Vocabulary returns the allergen and diet vocabulary
+func Vocabulary() DietaryLabels {
+	return DietaryLabels{Allergens: Allergens, Diets: Diets, Aliases: allergenAliases}
+}
+
+// NormalizeAllergens lowercases values, resolves aliases and returns them in
+// vocabulary order without duplicates
+func NormalizeAllergens(values []string) (database.StringList, error) {
+	found := make(map[string]bool)
+	for _, value := range values {
+		key := normalizeLabel(value)
+		if key == "" {
+			continue
+		}
+		if aliases, ok := allergenAliases[key]; ok {
+			for _, allergen := range aliases {
+				found[allergen] = true
+			}
+			continue
+		}
+		if !contains(Allergens, key) {
+			return nil, fmt.Errorf("%w: allergen %q", ErrUnknownLabel, value)
+		}
+		found[key] = true
+	}
+	return ordered(Allergens, found), nil
+}
+
+// NormalizeDiets lowercases values, adds the diets they imply and returns
+// them in vocabulary order without duplicates
+func NormalizeDiets(values []string) (database.StringList, error) {
+	found := make(map[string]bool)
+	for _, value := range values {
+		key := normalizeLabel(value)
+		if key == "" {
+			continue
+		}
+		if !contains(Diets, key) {
+			return nil, fmt.Errorf("%w: diet %q", ErrUnknownLabel, value)
+		}
+		found[key] = true
+		for _, implied := range impliedDiets[key] {
+			found[implied] = true
+		}
+	}
+	return ordered(Diets, found), nil
+}
+
+// EffectiveAllergens is the union of the item's own allergens and those of
+// its ingredients
+func (i *Item) EffectiveAllergens() database.StringList {
+	found := make(map[string]bool)
+	for _, allergen := range i.Allergens {
+		found[allergen] = true
+	}
+	for _, ingredient := range i.Ingredients {
+		for _, allergen := range ingredient.Allergens {
+			found[allergen] = true
+		}
+	}
+	return ordered(Allergens, found)
+}
+
+// EffectiveDiets keeps the diets declared for the item that every
+// ingredient also satisfies and that none of its allergens rule out
+func (i *Item) EffectiveDiets() database.StringList {
+	allergens := i.EffectiveAllergens()
+
+	found := make(map[string]bool)
+	for _, diet := range i.Diets {
+		found[diet] = true
+		for _, ingredient := range i.Ingredients {
+			if !ingredient.Diets.Contains(diet) {
+				found[diet] = false
+				break
+			}
+		}
+		for _, allergen := range dietConflicts[diet] {
+			if allergens.Contains(allergen) {
+				found[diet] = false
+				break
+			}
+		}
+	}
+	return ordered(Diets, found)
+}
+
+// DietaryFilter restricts a menu to items without the excluded allergens
+// that satisfy every listed diet
+type DietaryFilter struct {
+	ExcludeAllergens database.StringList
+	Diets            database.StringList
+}
+
+// Matches reports whether an item with the given effective labels passes
+// the filter
+func (f DietaryFilter) Matches(allergens, diets database.StringList) bool {
+	for _, allergen := range f.ExcludeAllergens {
+		if allergens.Contains(allergen) {
+			return false
+		}
+	}
+	for _, diet := range f.Diets {
+		if !diets.Contains(diet) {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeLabel(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return strings.NewReplacer("-", "_", " ", "_").Replace(value)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func ordered(vocabulary []string, found map[string]bool) database.StringList {
+	list := database.StringList{}
+	for _, label := range vocabulary {
+		if found[label] {
+			list = append(list, label)
+		}
+	}
+	return list
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+)
+
+// Units ingredients are measured and stocked in
+const (
+	UnitGram       = "g"
+	UnitKilogram   = "kg"
+	UnitMilliliter = "ml"
+	UnitLiter      = "l"
+	UnitPiece      = "pc"
+)
+
+// Ingredient is a component of the items of a branch. Its allergens and
+// diets are merged into those of every item that uses it.
+type Ingredient struct {
+	ID        uint                `json:"id" gorm:"primaryKey"`
+	BranchID  uint                `json:"branch_id" gorm:"not null;index"`
+	Name      string              `json:"name" gorm:"not null"`
+	Unit      string              `json:"unit" gorm:"type:varchar(10);not null;default:'pc'"`
+	Allergens database.StringList `json:"allergens" gorm:"type:jsonb;not null;default:'[]'"`
+	Diets     database.StringList `json:"diets" gorm:"type:jsonb;not null;default:'[]'"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	DeletedAt gorm.DeletedAt      `json:"-" gorm:"index"`
+}
+
+func (Ingredient) TableName() string {
+	return "menu_ingredients"
+}
+
+// ItemIngredient links a menu item to one of its ingredients. Quantity is
+// the recipe: the amount of the ingredient, in its unit, one serving of the
+// item uses up. Ingredients without a quantity are not stocked.
+type ItemIngredient struct {
+	ItemID       uint    `json:"item_id" gorm:"primaryKey"`
+	IngredientID uint    `json:"ingredient_id" gorm:"primaryKey;index"`
+	Quantity     float64 `json:"quantity" gorm:"type:numeric(14,3);not null;default:0"`
+}
+
+func (ItemIngredient) TableName() string {
+	return "menu_item_ingredients"
+}
+
+type IngredientRequest struct {
+	Name      string   `json:"name" binding:"required"`
+	Unit      string   `json:"unit" binding:"omitempty,oneof=g kg ml l pc"`
+	Allergens []string `json:"allergens"`
+	Diets     []string `json:"diets"`
+}
+
+type ItemIngredientsRequest struct {
+	IngredientIDs []uint `json:"ingredient_ids" binding:"required"`
+}
+
+// RecipeLine is an ingredient of a menu item with the amount one serving
+// uses up
+type RecipeLine struct {
+	IngredientID uint    `json:"ingredient_id"`
+	Name         string  `json:"name"`
+	Unit         string  `json:"unit"`
+	Quantity     float64 `json:"quantity"`
+}
+
+// RecipeRequest replaces the ingredients of a menu item together with their
+// quantities. A quantity of zero lists the ingredient without stocking it.
+type RecipeRequest struct {
+	Lines []RecipeLineRequest `json:"lines" binding:"required,dive"`
+}
+
+type RecipeLineRequest struct {
+	IngredientID uint    `json:"ingredient_id" binding:"required"`
+	Quantity     float64 `json:"quantity" binding:"gte=0,lte=100000"`
+}
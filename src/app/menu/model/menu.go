@@ -0,0 +1,104 @@
+package model
+
+import (
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+)
+
+// Category groups the menu items of a branch
+type Category struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	BranchID    uint           `json:"branch_id" gorm:"not null;index"`
+	Name        string         `json:"name" gorm:"not null"`
+	Description string         `json:"description" gorm:"type:text"`
+	SortOrder   int            `json:"sort_order" gorm:"not null;default:0"`
+	IsActive    bool           `json:"is_active" gorm:"not null;default:true"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+func (Category) TableName() string {
+	return "menu_categories"
+}
+
+// Item is a dish or drink on a branch menu. Price is in the minor unit of
+// the branch currency, e.g. cents. Allergens and Diets are declared for the
+// item itself; public menu responses replace them with the effective labels
+// that include its ingredients.
+type Item struct {
+	ID          uint                `json:"id" gorm:"primaryKey"`
+	BranchID    uint                `json:"branch_id" gorm:"not null;index"`
+	CategoryID  uint                `json:"category_id" gorm:"not null;index"`
+	Name        string              `json:"name" gorm:"not null"`
+	Description string              `json:"description" gorm:"type:text"`
+	Price       int64               `json:"price" gorm:"not null"`
+	ImageURL    string              `json:"image_url"`
+	Tags        database.StringList `json:"tags" gorm:"type:jsonb;not null;default:'[]'"`
+	Allergens   database.StringList `json:"allergens" gorm:"type:jsonb;not null;default:'[]'"`
+	Diets       database.StringList `json:"diets" gorm:"type:jsonb;not null;default:'[]'"`
+	IsAvailable bool                `json:"is_available" gorm:"not null;default:true"`
+	SortOrder   int                 `json:"sort_order" gorm:"not null;default:0"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt      `json:"-" gorm:"index"`
+
+	// ModifierGroups and Ingredients are filled in by the service for menu
+	// responses
+	ModifierGroups []ModifierGroup `json:"modifier_groups,omitempty" gorm:"-"`
+	Ingredients    []Ingredient    `json:"ingredients,omitempty" gorm:"-"`
+}
+
+func (Item) TableName() string {
+	return "menu_items"
+}
+
+type CategoryRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	SortOrder   int    `json:"sort_order"`
+	IsActive    *bool  `json:"is_active"`
+}
+
+type ReorderCategoriesRequest struct {
+	CategoryIDs []uint `json:"category_ids" binding:"required,min=1"`
+}
+
+type ItemRequest struct {
+	CategoryID  uint     `json:"category_id" binding:"required"`
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	Price       int64    `json:"price" binding:"min=0"`
+	ImageURL    string   `json:"image_url" binding:"omitempty,url"`
+	Tags        []string `json:"tags" binding:"omitempty,dive,required,max=50"`
+	Allergens   []string `json:"allergens"`
+	Diets       []string `json:"diets"`
+	IsAvailable *bool    `json:"is_available"`
+	SortOrder   int      `json:"sort_order"`
+}
+
+// MenuFilter narrows down the items of a public menu
+type MenuFilter struct {
+	AvailableOnly bool
+	Dietary       DietaryFilter
+}
+
+type AvailabilityRequest struct {
+	IsAvailable *bool `json:"is_available" binding:"required"`
+}
+
+// MenuCategory is a category together with its items in a branch menu
+type MenuCategory struct {
+	Category
+	Items []Item `json:"items"`
+}
+
+// Menu is the full nested menu of a branch
+type Menu struct {
+	BranchID   uint           `json:"branch_id"`
+	BranchName string         `json:"branch_name"`
+	Currency   string         `json:"currency"`
+	Categories []MenuCategory `json:"categories"`
+}
@@ -0,0 +1,168 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidSelection is wrapped by every modifier selection error
+var ErrInvalidSelection = errors.New("invalid modifier selection")
+
+// ModifierGroup is a reusable set of choices, such as size or extra
+// toppings, that can be attached to several items of a branch. MaxSelect 0
+// means there is no upper limit.
+type ModifierGroup struct {
+	ID          uint             `json:"id" gorm:"primaryKey"`
+	BranchID    uint             `json:"branch_id" gorm:"not null;index"`
+	Name        string           `json:"name" gorm:"not null"`
+	Description string           `json:"description" gorm:"type:text"`
+	MinSelect   int              `json:"min_select" gorm:"not null;default:0"`
+	MaxSelect   int              `json:"max_select" gorm:"not null;default:0"`
+	Options     []ModifierOption `json:"options" gorm:"foreignKey:GroupID"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt   `json:"-" gorm:"index"`
+}
+
+func (ModifierGroup) TableName() string {
+	return "menu_modifier_groups"
+}
+
+// ModifierOption is one choice of a group. PriceDelta is added to the item
+// price in minor units and may be negative, e.g. for a smaller size.
+type ModifierOption struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	GroupID     uint      `json:"group_id" gorm:"not null;index"`
+	Name        string    `json:"name" gorm:"not null"`
+	PriceDelta  int64     `json:"price_delta" gorm:"not null;default:0"`
+	IsDefault   bool      `json:"is_default" gorm:"not null;default:false"`
+	IsAvailable bool      `json:"is_available" gorm:"not null;default:true"`
+	SortOrder   int       `json:"sort_order" gorm:"not null;default:0"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (ModifierOption) TableName() string {
+	return "menu_modifier_options"
+}
+
+// ItemModifierGroup attaches a modifier group to a menu item
+type ItemModifierGroup struct {
+	ItemID    uint `json:"item_id" gorm:"primaryKey"`
+	GroupID   uint `json:"group_id" gorm:"primaryKey;index"`
+	SortOrder int  `json:"sort_order" gorm:"not null;default:0"`
+}
+
+func (ItemModifierGroup) TableName() string {
+	return "menu_item_modifier_groups"
+}
+
+type ModifierGroupRequest struct {
+	Name        string                  `json:"name" binding:"required"`
+	Description string                  `json:"description"`
+	MinSelect   int                     `json:"min_select" binding:"min=0"`
+	MaxSelect   int                     `json:"max_select" binding:"min=0"`
+	Options     []ModifierOptionRequest `json:"options" binding:"required,min=1,dive"`
+}
+
+// ModifierOptionRequest updates the option with ID or creates a new one
+// when ID is empty. Options left out of an update are removed.
+type ModifierOptionRequest struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name" binding:"required"`
+	PriceDelta  int64  `json:"price_delta"`
+	IsDefault   bool   `json:"is_default"`
+	IsAvailable *bool  `json:"is_available"`
+	SortOrder   int    `json:"sort_order"`
+}
+
+type ItemModifierGroupsRequest struct {
+	GroupIDs []uint `json:"group_ids" binding:"required"`
+}
+
+// SelectedOption is a validated modifier choice of an order line
+type SelectedOption struct {
+	GroupID    uint   `json:"group_id"`
+	GroupName  string `json:"group_name"`
+	OptionID   uint   `json:"option_id"`
+	OptionName string `json:"option_name"`
+	PriceDelta int64  `json:"price_delta"`
+}
+
+// PricedItem is a menu item with a validated modifier selection and the
+// resulting unit price
+type PricedItem struct {
+	Item      Item             `json:"item"`
+	Options   []SelectedOption `json:"options"`
+	UnitPrice int64            `json:"unit_price"`
+}
+
+// ValidateRules checks that the selection limits of a group are consistent
+func (g *ModifierGroup) ValidateRules() error {
+	if g.MaxSelect > 0 && g.MinSelect > g.MaxSelect {
+		return fmt.Errorf("%w: min_select cannot exceed max_select", ErrInvalidSelection)
+	}
+	if g.MinSelect > len(g.Options) {
+		return fmt.Errorf("%w: min_select cannot exceed the number of options", ErrInvalidSelection)
+	}
+	return nil
+}
+
+// ValidateSelection checks optionIDs against the modifier groups attached to
+// an item: every option must belong to one of the groups and be available,
+// no option may be picked twice and each group's min/max limits must hold.
+func ValidateSelection(groups []ModifierGroup, optionIDs []uint) ([]SelectedOption, error) {
+	type optionRef struct {
+		group  *ModifierGroup
+		option *ModifierOption
+	}
+
+	byID := make(map[uint]optionRef)
+	for gi := range groups {
+		for oi := range groups[gi].Options {
+			option := &groups[gi].Options[oi]
+			byID[option.ID] = optionRef{group: &groups[gi], option: option}
+		}
+	}
+
+	seen := make(map[uint]bool, len(optionIDs))
+	counts := make(map[uint]int, len(groups))
+	selected := make([]SelectedOption, 0, len(optionIDs))
+	for _, id := range optionIDs {
+		ref, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("%w: option %d is not offered for this item", ErrInvalidSelection, id)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("%w: option %q selected more than once", ErrInvalidSelection, ref.option.Name)
+		}
+		if !ref.option.IsAvailable {
+			return nil, fmt.Errorf("%w: option %q is not available", ErrInvalidSelection, ref.option.Name)
+		}
+		seen[id] = true
+		counts[ref.group.ID]++
+
+		selected = append(selected, SelectedOption{
+			GroupID:    ref.group.ID,
+			GroupName:  ref.group.Name,
+			OptionID:   ref.option.ID,
+			OptionName: ref.option.Name,
+			PriceDelta: ref.option.PriceDelta,
+		})
+	}
+
+	for _, group := range groups {
+		count := counts[group.ID]
+		if count < group.MinSelect {
+			return nil, fmt.Errorf("%w: %q requires at least %d selection(s)", ErrInvalidSelection, group.Name, group.MinSelect)
+		}
+		if group.MaxSelect > 0 && count > group.MaxSelect {
+			return nil, fmt.Errorf("%w: %q allows at most %d selection(s)", ErrInvalidSelection, group.Name, group.MaxSelect)
+		}
+	}
+
+	return selected, nil
+}
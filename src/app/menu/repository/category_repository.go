@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"github.com/faisd405/go-restapi-gin/src/app/menu/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+)
+
+type CategoryRepository interface {
+	Create(category *model.Category) error
+	GetByID(id uint) (*model.Category, error)
+	Update(category *model.Category) error
+	Delete(id uint) error
+	Find(q *database.Query) ([]model.Category, error)
+	UpdateSortOrder(id uint, sortOrder int) error
+	WithTx(tx *gorm.DB) CategoryRepository
+}
+
+type categoryRepository struct {
+	database.Repository[model.Category]
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{Repository: database.NewRepository[model.Category](db)}
+}
+
+func (r *categoryRepository) UpdateSortOrder(id uint, sortOrder int) error {
+	return r.DB().Model(&model.Category{}).Where("id = ?", id).Update("sort_order", sortOrder).Error
+}
+
+func (r *categoryRepository) WithTx(tx *gorm.DB) CategoryRepository {
+	return &categoryRepository{Repository: r.Repository.WithTx(tx)}
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"github.com/faisd405/go-restapi-gin/src/app/menu/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IngredientRepository interface {
+	Create(ingredient *model.Ingredient) error
+	GetByID(id uint) (*model.Ingredient, error)
+	Update(ingredient *model.Ingredient) error
+	Delete(id uint) error
+	Find(q *database.Query) ([]model.Ingredient, error)
+	GetItemIngredients(itemIDs []uint) (map[uint][]model.Ingredient, error)
+	SetItemIngredients(itemID uint, ingredientIDs []uint) error
+	// GetRecipe returns the ingredient links of an item
+	GetRecipe(itemID uint) ([]model.ItemIngredient, error)
+	// SetRecipe replaces the ingredient links of an item
+	SetRecipe(itemID uint, links []model.ItemIngredient) error
+	DetachIngredient(ingredientID uint) error
+	WithTx(tx *gorm.DB) IngredientRepository
+}
+
+type ingredientRepository struct {
+	database.Repository[model.Ingredient]
+}
+
+func NewIngredientRepository(db *gorm.DB) IngredientRepository {
+	return &ingredientRepository{Repository: database.NewRepository[model.Ingredient](db)}
+}
+
+// GetItemIngredients returns the ingredients of each of itemIDs, keyed by
+// item ID and sorted by name
+func (r *ingredientRepository) GetItemIngredients(itemIDs []uint) (map[uint][]model.Ingredient, error) {
+	result := make(map[uint][]model.Ingredient)
+	if len(itemIDs) == 0 {
+		return result, nil
+	}
+
+	var links []model.ItemIngredient
+	if err := r.DB().Where("item_id IN ?", itemIDs).Find(&links).Error; err != nil || len(links) == 0 {
+		return result, err
+	}
+
+	ingredientIDs := make([]uint, 0, len(links))
+	for _, link := range links {
+		ingredientIDs = append(ingredientIDs, link.IngredientID)
+	}
+
+	ingredients, err := r.Find(database.NewQuery().In("id", ingredientIDs).OrderBy("name"))
+	if err != nil {
+		return nil, err
+	}
+
+	itemsByIngredient := make(map[uint][]uint)
+	for _, link := range links {
+		itemsByIngredient[link.IngredientID] = append(itemsByIngredient[link.IngredientID], link.ItemID)
+	}
+	for _, ingredient := range ingredients {
+		for _, itemID := range itemsByIngredient[ingredient.ID] {
+			result[itemID] = append(result[itemID], ingredient)
+		}
+	}
+	return result, nil
+}
+
+// SetItemIngredients replaces the ingredient list of an item. Ingredients
+// the item keeps keep their recipe quantity.
+func (r *ingredientRepository) SetItemIngredients(itemID uint, ingredientIDs []uint) error {
+	remove := r.DB().Where("item_id = ?", itemID)
+	if len(ingredientIDs) > 0 {
+		remove = remove.Where("ingredient_id NOT IN ?", ingredientIDs)
+	}
+	if err := remove.Delete(&model.ItemIngredient{}).Error; err != nil {
+		return database.TranslateError(err)
+	}
+	if len(ingredientIDs) == 0 {
+		return nil
+	}
+
+	links := make([]model.ItemIngredient, 0, len(ingredientIDs))
+	for _, ingredientID := range ingredientIDs {
+		links = append(links, model.ItemIngredient{ItemID: itemID, IngredientID: ingredientID})
+	}
+	return database.TranslateError(r.DB().Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error)
+}
+
+func (r *ingredientRepository) GetRecipe(itemID uint) ([]model.ItemIngredient, error) {
+	var links []model.ItemIngredient
+	if err := r.DB().Where("item_id = ?", itemID).Find(&links).Error; err != nil {
+		return nil, database.TranslateError(err)
+	}
+	return links, nil
+}
+
+func (r *ingredientRepository) SetRecipe(itemID uint, links []model.ItemIngredient) error {
+	if err := r.DB().Where("item_id = ?", itemID).Delete(&model.ItemIngredient{}).Error; err != nil {
+		return database.TranslateError(err)
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	return database.TranslateError(r.DB().Create(&links).Error)
+}
+
+// DetachIngredient removes an ingredient from every item that uses it
+func (r *ingredientRepository) DetachIngredient(ingredientID uint) error {
+	return database.TranslateError(r.DB().Where("ingredient_id = ?", ingredientID).Delete(&model.ItemIngredient{}).Error)
+}
+
+func (r *ingredientRepository) WithTx(tx *gorm.DB) IngredientRepository {
+	return &ingredientRepository{Repository: r.Repository.WithTx(tx)}
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"github.com/faisd405/go-restapi-gin/src/app/menu/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+)
+
+type ItemRepository interface {
+	Create(item *model.Item) error
+	GetByID(id uint) (*model.Item, error)
+	Update(item *model.Item) error
+	Delete(id uint) error
+	Find(q *database.Query) ([]model.Item, error)
+	DeleteByCategory(categoryID uint) error
+	WithTx(tx *gorm.DB) ItemRepository
+}
+
+type itemRepository struct {
+	database.Repository[model.Item]
+}
+
+func NewItemRepository(db *gorm.DB) ItemRepository {
+	return &itemRepository{Repository: database.NewRepository[model.Item](db)}
+}
+
+func (r *itemRepository) DeleteByCategory(categoryID uint) error {
+	return r.DB().Where("category_id = ?", categoryID).Delete(&model.Item{}).Error
+}
+
+func (r *itemRepository) WithTx(tx *gorm.DB) ItemRepository {
+	return &itemRepository{Repository: r.Repository.WithTx(tx)}
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"sort"
+
+	"github.com/faisd405/go-restapi-gin/src/app/menu/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+)
+
+type ModifierGroupRepository interface {
+	Create(group *model.ModifierGroup) error
+	GetByID(id uint) (*model.ModifierGroup, error)
+	Update(group *model.ModifierGroup) error
+	Delete(id uint) error
+	Find(q *database.Query) ([]model.ModifierGroup, error)
+	ReplaceOptions(groupID uint, options []model.ModifierOption) ([]model.ModifierOption, error)
+	GetItemGroups(itemIDs []uint) (map[uint][]model.ModifierGroup, error)
+	SetItemGroups(itemID uint, groupIDs []uint) error
+	DetachGroup(groupID uint) error
+	WithTx(tx *gorm.DB) ModifierGroupRepository
+}
+
+type modifierGroupRepository struct {
+	database.Repository[model.ModifierGroup]
+}
+
+func NewModifierGroupRepository(db *gorm.DB) ModifierGroupRepository {
+	return &modifierGroupRepository{Repository: database.NewRepository[model.ModifierGroup](db)}
+}
+
+// GetByID loads a group with its options in display order
+func (r *modifierGroupRepository) GetByID(id uint) (*model.ModifierGroup, error) {
+	var group model.ModifierGroup
+	if err := r.withOptions().First(&group, id).Error; err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// Update saves the group columns only; options are managed by ReplaceOptions
+func (r *modifierGroupRepository) Update(group *model.ModifierGroup) error {
+	return database.TranslateError(r.DB().Omit("Options").Save(group).Error)
+}
+
+// Find returns the groups matching q with their options in display order
+func (r *modifierGroupRepository) Find(q *database.Query) ([]model.ModifierGroup, error) {
+	groups, err := r.Repository.Find(database.NewQuery().Merge(q).Preload("Options"))
+	if err != nil {
+		return nil, err
+	}
+	for i := range groups {
+		options := groups[i].Options
+		sort.SliceStable(options, func(a, b int) bool {
+			if options[a].SortOrder != options[b].SortOrder {
+				return options[a].SortOrder < options[b].SortOrder
+			}
+			return options[a].ID < options[b].ID
+		})
+	}
+	return groups, nil
+}
+
+// ReplaceOptions makes options the complete option list of the group:
+// options with an ID are updated, new ones are inserted and every other
+// option of the group is removed
+func (r *modifierGroupRepository) ReplaceOptions(groupID uint, options []model.ModifierOption) ([]model.ModifierOption, error) {
+	keep := make([]uint, 0, len(options))
+	for _, option := range options {
+		if option.ID != 0 {
+			keep = append(keep, option.ID)
+		}
+	}
+
+	remove := r.DB().Where("group_id = ?", groupID)
+	if len(keep) > 0 {
+		remove = remove.Where("id NOT IN ?", keep)
+	}
+	if err := remove.Delete(&model.ModifierOption{}).Error; err != nil {
+		return nil, database.TranslateError(err)
+	}
+
+	for i := range options {
+		options[i].GroupID = groupID
+		if err := r.DB().Save(&options[i]).Error; err != nil {
+			return nil, database.TranslateError(err)
+		}
+	}
+
+	return options, nil
+}
+
+// GetItemGroups returns the modifier groups attached to each of itemIDs,
+// keyed by item ID and in attachment order
+func (r *modifierGroupRepository) GetItemGroups(itemIDs []uint) (map[uint][]model.ModifierGroup, error) {
+	result := make(map[uint][]model.ModifierGroup)
+	if len(itemIDs) == 0 {
+		return result, nil
+	}
+
+	var links []model.ItemModifierGroup
+	err := r.DB().Where("item_id IN ?", itemIDs).Order("sort_order").Order("group_id").Find(&links).Error
+	if err != nil || len(links) == 0 {
+		return result, err
+	}
+
+	groupIDs := make([]uint, 0, len(links))
+	for _, link := range links {
+		groupIDs = append(groupIDs, link.GroupID)
+	}
+
+	var groups []model.ModifierGroup
+	if err := r.withOptions().Where("id IN ?", groupIDs).Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]model.ModifierGroup, len(groups))
+	for _, group := range groups {
+		byID[group.ID] = group
+	}
+
+	for _, link := range links {
+		// Soft deleted groups are skipped
+		if group, ok := byID[link.GroupID]; ok {
+			result[link.ItemID] = append(result[link.ItemID], group)
+		}
+	}
+	return result, nil
+}
+
+// SetItemGroups replaces the groups attached to an item, keeping the order
+// of groupIDs
+func (r *modifierGroupRepository) SetItemGroups(itemID uint, groupIDs []uint) error {
+	if err := r.DB().Where("item_id = ?", itemID).Delete(&model.ItemModifierGroup{}).Error; err != nil {
+		return database.TranslateError(err)
+	}
+	if len(groupIDs) == 0 {
+		return nil
+	}
+
+	links := make([]model.ItemModifierGroup, 0, len(groupIDs))
+	for position, groupID := range groupIDs {
+		links = append(links, model.ItemModifierGroup{ItemID: itemID, GroupID: groupID, SortOrder: position})
+	}
+	return database.TranslateError(r.DB().Create(&links).Error)
+}
+
+// DetachGroup removes a group from every item it is attached to
+func (r *modifierGroupRepository) DetachGroup(groupID uint) error {
+	return database.TranslateError(r.DB().Where("group_id = ?", groupID).Delete(&model.ItemModifierGroup{}).Error)
+}
+
+func (r *modifierGroupRepository) WithTx(tx *gorm.DB) ModifierGroupRepository {
+	return &modifierGroupRepository{Repository: r.Repository.WithTx(tx)}
+}
+
+func (r *modifierGroupRepository) withOptions() *gorm.DB {
+	return r.DB().Preload("Options", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order").Order("id")
+	})
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"errors"
+	"math"
+
+	"github.com/faisd405/go-restapi-gin/src/app/menu/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrIngredientBranchMismatch = errors.New("ingredient belongs to a different branch")
+	ErrDuplicateIngredient      = errors.New("an ingredient must not be listed twice")
+)
+
+func (s *menuService) GetIngredients(actor utils.Actor, branchID uint) ([]model.Ingredient, error) {
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	return s.ingredientRepo.Find(database.NewQuery().Eq("branch_id", branchID).OrderBy("name"))
+}
+
+func (s *menuService) CreateIngredient(actor utils.Actor, branchID uint, req model.IngredientRequest) (*model.Ingredient, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	ingredient := &model.Ingredient{BranchID: branchID, Unit: model.UnitPiece}
+	if err := applyIngredientRequest(ingredient, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.ingredientRepo.Create(ingredient); err != nil {
+		return nil, err
+	}
+
+	return ingredient, nil
+}
+
+func (s *menuService) UpdateIngredient(actor utils.Actor, id uint, req model.IngredientRequest) (*model.Ingredient, error) {
+	ingredient, err := s.getManagedIngredient(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyIngredientRequest(ingredient, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.ingredientRepo.Update(ingredient); err != nil {
+		return nil, err
+	}
+
+	return ingredient, nil
+}
+
+func (s *menuService) DeleteIngredient(actor utils.Actor, id uint) error {
+	if _, err := s.getManagedIngredient(actor, id); err != nil {
+		return err
+	}
+
+	return s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		ingredientRepo := s.ingredientRepo.WithTx(tx)
+		if err := ingredientRepo.DetachIngredient(id); err != nil {
+			return err
+		}
+		return ingredientRepo.Delete(id)
+	})
+}
+
+func (s *menuService) SetItemIngredients(actor utils.Actor, itemID uint, req model.ItemIngredientsRequest) (*model.Item, error) {
+	item, err := s.getManagedItem(actor, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool, len(req.IngredientIDs))
+	for _, ingredientID := range req.IngredientIDs {
+		if seen[ingredientID] {
+			return nil, ErrDuplicateIngredient
+		}
+		seen[ingredientID] = true
+
+		ingredient, err := s.ingredientRepo.GetByID(ingredientID)
+		if err != nil {
+			return nil, err
+		}
+		if ingredient.BranchID != item.BranchID {
+			return nil, ErrIngredientBranchMismatch
+		}
+	}
+
+	if err := s.ingredientRepo.SetItemIngredients(item.ID, req.IngredientIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.attachIngredients([]*model.Item{item}); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (s *menuService) GetRecipe(actor utils.Actor, itemID uint) ([]model.RecipeLine, error) {
+	item, err := s.getManagedItem(actor, itemID)
+	if err != nil {
+		return nil, err
+	}
+	return s.recipe(item.ID)
+}
+
+func (s *menuService) SetRecipe(actor utils.Actor, itemID uint, req model.RecipeRequest) ([]model.RecipeLine, error) {
+	item, err := s.getManagedItem(actor, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]model.ItemIngredient, 0, len(req.Lines))
+	seen := make(map[uint]bool, len(req.Lines))
+	for _, line := range req.Lines {
+		if seen[line.IngredientID] {
+			return nil, ErrDuplicateIngredient
+		}
+		seen[line.IngredientID] = true
+
+		ingredient, err := s.ingredientRepo.GetByID(line.IngredientID)
+		if err != nil {
+			return nil, err
+		}
+		if ingredient.BranchID != item.BranchID {
+			return nil, ErrIngredientBranchMismatch
+		}
+		links = append(links, model.ItemIngredient{
+			ItemID:       item.ID,
+			IngredientID: ingredient.ID,
+			Quantity:     math.Round(line.Quantity*1000) / 1000,
+		})
+	}
+
+	err = s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		return s.ingredientRepo.WithTx(tx).SetRecipe(item.ID, links)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.recipe(item.ID)
+}
+
+// recipe lists the ingredients of an item with their quantities, sorted
+// by name
+func (s *menuService) recipe(itemID uint) ([]model.RecipeLine, error) {
+	links, err := s.ingredientRepo.GetRecipe(itemID)
+	if err != nil {
+		return nil, err
+	}
+	quantities := make(map[uint]float64, len(links))
+	for _, link := range links {
+		quantities[link.IngredientID] = link.Quantity
+	}
+
+	ingredients, err := s.ingredientRepo.GetItemIngredients([]uint{itemID})
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]model.RecipeLine, 0, len(ingredients[itemID]))
+	for _, ingredient := range ingredients[itemID] {
+		lines = append(lines, model.RecipeLine{
+			IngredientID: ingredient.ID,
+			Name:         ingredient.Name,
+			Unit:         ingredient.Unit,
+			Quantity:     quantities[ingredient.ID],
+		})
+	}
+	return lines, nil
+}
+
+// GetDietaryLabels returns the allergen and diet vocabulary
+func (s *menuService) GetDietaryLabels() model.DietaryLabels {
+	return model.Vocabulary()
+}
+
+// attachIngredients fills in the Ingredients of items with one query
+func (s *menuService) attachIngredients(items []*model.Item) error {
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+
+	ingredients, err := s.ingredientRepo.GetItemIngredients(ids)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		item.Ingredients = ingredients[item.ID]
+	}
+	return nil
+}
+
+// applyEffectiveLabels replaces the declared allergens and diets of items
+// with the effective ones for public responses
+func applyEffectiveLabels(items []*model.Item) {
+	for _, item := range items {
+		allergens, diets := item.EffectiveAllergens(), item.EffectiveDiets()
+		item.Allergens = allergens
+		item.Diets = diets
+	}
+}
+
+func (s *menuService) getManagedIngredient(actor utils.Actor, id uint) (*model.Ingredient, error) {
+	ingredient, err := s.ingredientRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, ingredient.BranchID); err != nil {
+		return nil, err
+	}
+	return ingredient, nil
+}
+
+func applyIngredientRequest(ingredient *model.Ingredient, req model.IngredientRequest) error {
+	allergens, err := model.NormalizeAllergens(req.Allergens)
+	if err != nil {
+		return err
+	}
+	diets, err := model.NormalizeDiets(req.Diets)
+	if err != nil {
+		return err
+	}
+
+	ingredient.Name = req.Name
+	if req.Unit != "" {
+		ingredient.Unit = req.Unit
+	}
+	ingredient.Allergens = allergens
+	ingredient.Diets = diets
+	return nil
+}
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/faisd405/go-restapi-gin/src/app/menu/model"
+	"github.com/faisd405/go-restapi-gin/src/app/menu/repository"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCategoryBranchMismatch = errors.New("category belongs to a different branch")
+	ErrIncompleteReorder      = errors.New("category_ids must list every category of the branch exactly once")
+)
+
+type MenuService interface {
+	GetMenu(branchID uint, filter model.MenuFilter) (*model.Menu, error)
+	GetItem(id uint) (*model.Item, error)
+
+	CreateCategory(actor utils.Actor, branchID uint, req model.CategoryRequest) (*model.Category, error)
+	UpdateCategory(actor utils.Actor, id uint, req model.CategoryRequest) (*model.Category, error)
+	DeleteCategory(actor utils.Actor, id uint) error
+	ReorderCategories(actor utils.Actor, branchID uint, req model.ReorderCategoriesRequest) ([]model.Category, error)
+
+	CreateItem(actor utils.Actor, req model.ItemRequest) (*model.Item, error)
+	UpdateItem(actor utils.Actor, id uint, req model.ItemRequest) (*model.Item, error)
+	DeleteItem(actor utils.Actor, id uint) error
+	SetAvailability(actor utils.Actor, id uint, available bool) (*model.Item, error)
+
+	GetModifierGroups(actor utils.Actor, branchID uint) ([]model.ModifierGroup, error)
+	CreateModifierGroup(actor utils.Actor, branchID uint, req model.ModifierGroupRequest) (*model.ModifierGroup, error)
+	UpdateModifierGroup(actor utils.Actor, id uint, req model.ModifierGroupRequest) (*model.ModifierGroup, error)
+	DeleteModifierGroup(actor utils.Actor, id uint) error
+	SetItemModifierGroups(actor utils.Actor, itemID uint, req model.ItemModifierGroupsRequest) (*model.Item, error)
+	PriceItem(itemID uint, optionIDs []uint) (*model.PricedItem, error)
+
+	GetIngredients(actor utils.Actor, branchID uint) ([]model.Ingredient, error)
+	CreateIngredient(actor utils.Actor, branchID uint, req model.IngredientRequest) (*model.Ingredient, error)
+	UpdateIngredient(actor utils.Actor, id uint, req model.IngredientRequest) (*model.Ingredient, error)
+	DeleteIngredient(actor utils.Actor, id uint) error
+	SetItemIngredients(actor utils.Actor, itemID uint, req model.ItemIngredientsRequest) (*model.Item, error)
+	GetRecipe(actor utils.Actor, itemID uint) ([]model.RecipeLine, error)
+	// SetRecipe replaces the ingredients of an item together with the
+	// amounts one serving uses up
+	SetRecipe(actor utils.Actor, itemID uint, req model.RecipeRequest) ([]model.RecipeLine, error)
+	GetDietaryLabels() model.DietaryLabels
+}
+
+type menuService struct {
+	categoryRepo   repository.CategoryRepository
+	itemRepo       repository.ItemRepository
+	modifierRepo   repository.ModifierGroupRepository
+	ingredientRepo repository.IngredientRepository
+	restaurantSvc  restaurantservice.RestaurantService
+	txManager      database.TxManager
+}
+
+func NewMenuService(
+	categoryRepo repository.CategoryRepository,
+	itemRepo repository.ItemRepository,
+	modifierRepo repository.ModifierGroupRepository,
+	ingredientRepo repository.IngredientRepository,
+	restaurantSvc restaurantservice.RestaurantService,
+	txManager database.TxManager,
+) MenuService {
+	return &menuService{
+		categoryRepo:   categoryRepo,
+		itemRepo:       itemRepo,
+		modifierRepo:   modifierRepo,
+		ingredientRepo: ingredientRepo,
+		restaurantSvc:  restaurantSvc,
+		txManager:      txManager,
+	}
+}
+
+func (s *menuService) GetMenu(branchID uint, filter model.MenuFilter) (*model.Menu, error) {
+	branch, err := s.restaurantSvc.GetBranch(branchID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryRepo.Find(database.NewQuery().
+		Eq("branch_id", branchID).
+		Eq("is_active", true).
+		OrderBy("sort_order").OrderBy("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	itemQuery := database.NewQuery().Eq("branch_id", branchID).OrderBy("sort_order").OrderBy("id")
+	if filter.AvailableOnly {
+		itemQuery.Eq("is_available", true)
+	}
+	items, err := s.itemRepo.Find(itemQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	itemPtrs := make([]*model.Item, len(items))
+	for i := range items {
+		itemPtrs[i] = &items[i]
+	}
+	if err := s.attachModifierGroups(itemPtrs); err != nil {
+		return nil, err
+	}
+	if err := s.attachIngredients(itemPtrs); err != nil {
+		return nil, err
+	}
+	applyEffectiveLabels(itemPtrs)
+
+	itemsByCategory := make(map[uint][]model.Item)
+	for _, item := range items {
+		if !filter.Dietary.Matches(item.Allergens, item.Diets) {
+			continue
+		}
+		itemsByCategory[item.CategoryID] = append(itemsByCategory[item.CategoryID], item)
+	}
+
+	menu := &model.Menu{
+		BranchID:   branch.ID,
+		BranchName: branch.Name,
+		Currency:   branch.EffectiveCurrency(),
+		Categories: make([]model.MenuCategory, 0, len(categories)),
+	}
+	for _, category := range categories {
+		categoryItems := itemsByCategory[category.ID]
+		if categoryItems == nil {
+			categoryItems = []model.Item{}
+		}
+		menu.Categories = append(menu.Categories, model.MenuCategory{Category: category, Items: categoryItems})
+	}
+
+	return menu, nil
+}
+
+func (s *menuService) GetItem(id uint) (*model.Item, error) {
+	item, err := s.itemRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Items of hidden branches or inactive categories are not public
+	if _, err := s.restaurantSvc.GetBranch(item.BranchID, true); err != nil {
+		return nil, err
+	}
+	category, err := s.categoryRepo.GetByID(item.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if !category.IsActive {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	items := []*model.Item{item}
+	if err := s.attachModifierGroups(items); err != nil {
+		return nil, err
+	}
+	if err := s.attachIngredients(items); err != nil {
+		return nil, err
+	}
+	applyEffectiveLabels(items)
+
+	return item, nil
+}
+
+func (s *menuService) CreateCategory(actor utils.Actor, branchID uint, req model.CategoryRequest) (*model.Category, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	category := &model.Category{BranchID: branchID, IsActive: true}
+	applyCategoryRequest(category, req)
+
+	if err := s.categoryRepo.Create(category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *menuService) UpdateCategory(actor utils.Actor, id uint, req model.CategoryRequest) (*model.Category, error) {
+	category, err := s.getManagedCategory(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyCategoryRequest(category, req)
+
+	if err := s.categoryRepo.Update(category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *menuService) DeleteCategory(actor utils.Actor, id uint) error {
+	if _, err := s.getManagedCategory(actor, id); err != nil {
+		return err
+	}
+
+	// Items cannot outlive their category
+	return s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		if err := s.itemRepo.WithTx(tx).DeleteByCategory(id); err != nil {
+			return err
+		}
+		return s.categoryRepo.WithTx(tx).Delete(id)
+	})
+}
+
+func (s *menuService) ReorderCategories(actor utils.Actor, branchID uint, req model.ReorderCategoriesRequest) ([]model.Category, error) {
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryRepo.Find(database.NewQuery().Eq("branch_id", branchID))
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[uint]bool, len(categories))
+	for _, category := range categories {
+		known[category.ID] = true
+	}
+	if len(req.CategoryIDs) != len(categories) {
+		return nil, ErrIncompleteReorder
+	}
+	for _, id := range req.CategoryIDs {
+		if !known[id] {
+			return nil, ErrIncompleteReorder
+		}
+		delete(known, id)
+	}
+
+	err = s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		categoryRepo := s.categoryRepo.WithTx(tx)
+		for position, id := range req.CategoryIDs {
+			if err := categoryRepo.UpdateSortOrder(id, position); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.categoryRepo.Find(database.NewQuery().Eq("branch_id", branchID).OrderBy("sort_order"))
+}
+
+func (s *menuService) CreateItem(actor utils.Actor, req model.ItemRequest) (*model.Item, error) {
+	category, err := s.getManagedCategory(actor, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &model.Item{BranchID: category.BranchID, IsAvailable: true}
+	if err := applyItemRequest(item, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.itemRepo.Create(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *menuService) UpdateItem(actor utils.Actor, id uint, req model.ItemRequest) (*model.Item, error) {
+	item, err := s.getManagedItem(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.CategoryID != item.CategoryID {
+		category, err := s.categoryRepo.GetByID(req.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		if category.BranchID != item.BranchID {
+			return nil, ErrCategoryBranchMismatch
+		}
+	}
+
+	if err := applyItemRequest(item, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.itemRepo.Update(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *menuService) DeleteItem(actor utils.Actor, id uint) error {
+	if _, err := s.getManagedItem(actor, id); err != nil {
+		return err
+	}
+
+	return s.itemRepo.Delete(id)
+}
+
+func (s *menuService) SetAvailability(actor utils.Actor, id uint, available bool) (*model.Item, error) {
+	item, err := s.getManagedItem(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	item.IsAvailable = available
+	if err := s.itemRepo.Update(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *menuService) getManagedCategory(actor utils.Actor, id uint) (*model.Category, error) {
+	category, err := s.categoryRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, category.BranchID); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (s *menuService) getManagedItem(actor utils.Actor, id uint) (*model.Item, error) {
+	item, err := s.itemRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, item.BranchID); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func applyCategoryRequest(category *model.Category, req model.CategoryRequest) {
+	category.Name = req.Name
+	category.Description = req.Description
+	category.SortOrder = req.SortOrder
+	if req.IsActive != nil {
+		category.IsActive = *req.IsActive
+	}
+}
+
+func applyItemRequest(item *model.Item, req model.ItemRequest) error {
+	allergens, err := model.NormalizeAllergens(req.Allergens)
+	if err != nil {
+		return err
+	}
+	diets, err := model.NormalizeDiets(req.Diets)
+	if err != nil {
+		return err
+	}
+
+	item.CategoryID = req.CategoryID
+	item.Name = req.Name
+	item.Description = req.Description
+	item.Price = req.Price
+	item.ImageURL = req.ImageURL
+	item.Tags = normalizeTags(req.Tags)
+	item.Allergens = allergens
+	item.Diets = diets
+	item.SortOrder = req.SortOrder
+	if req.IsAvailable != nil {
+		item.IsAvailable = *req.IsAvailable
+	}
+	return nil
+}
+
+func normalizeTags(tags []string) database.StringList {
+	normalized := database.StringList{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !normalized.Contains(tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/faisd405/go-restapi-gin/src/app/menu/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrModifierGroupBranchMismatch = errors.New("modifier group belongs to a different branch")
+	ErrDuplicateModifierGroup      = errors.New("group_ids must not contain duplicates")
+	ErrOptionGroupMismatch         = errors.New("option does not belong to this modifier group")
+	ErrItemUnavailable             = errors.New("menu item is not available")
+)
+
+func (s *menuService) GetModifierGroups(actor utils.Actor, branchID uint) ([]model.ModifierGroup, error) {
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	return s.modifierRepo.Find(database.NewQuery().Eq("branch_id", branchID).OrderBy("name"))
+}
+
+func (s *menuService) CreateModifierGroup(actor utils.Actor, branchID uint, req model.ModifierGroupRequest) (*model.ModifierGroup, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	group := &model.ModifierGroup{BranchID: branchID}
+	applyModifierGroupRequest(group, req)
+	if err := group.ValidateRules(); err != nil {
+		return nil, err
+	}
+	for _, option := range req.Options {
+		if option.ID != 0 {
+			return nil, ErrOptionGroupMismatch
+		}
+	}
+
+	if err := s.modifierRepo.Create(group); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (s *menuService) UpdateModifierGroup(actor utils.Actor, id uint, req model.ModifierGroupRequest) (*model.ModifierGroup, error) {
+	group, err := s.getManagedModifierGroup(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[uint]bool, len(group.Options))
+	for _, option := range group.Options {
+		existing[option.ID] = true
+	}
+	for _, option := range req.Options {
+		if option.ID != 0 && !existing[option.ID] {
+			return nil, ErrOptionGroupMismatch
+		}
+	}
+
+	applyModifierGroupRequest(group, req)
+	if err := group.ValidateRules(); err != nil {
+		return nil, err
+	}
+
+	err = s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		modifierRepo := s.modifierRepo.WithTx(tx)
+		if err := modifierRepo.Update(group); err != nil {
+			return err
+		}
+		options, err := modifierRepo.ReplaceOptions(group.ID, group.Options)
+		if err != nil {
+			return err
+		}
+		group.Options = options
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (s *menuService) DeleteModifierGroup(actor utils.Actor, id uint) error {
+	if _, err := s.getManagedModifierGroup(actor, id); err != nil {
+		return err
+	}
+
+	// Detach the group so items stop offering it
+	return s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		modifierRepo := s.modifierRepo.WithTx(tx)
+		if err := modifierRepo.DetachGroup(id); err != nil {
+			return err
+		}
+		return modifierRepo.Delete(id)
+	})
+}
+
+func (s *menuService) SetItemModifierGroups(actor utils.Actor, itemID uint, req model.ItemModifierGroupsRequest) (*model.Item, error) {
+	item, err := s.getManagedItem(actor, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool, len(req.GroupIDs))
+	for _, groupID := range req.GroupIDs {
+		if seen[groupID] {
+			return nil, ErrDuplicateModifierGroup
+		}
+		seen[groupID] = true
+
+		group, err := s.modifierRepo.GetByID(groupID)
+		if err != nil {
+			return nil, err
+		}
+		if group.BranchID != item.BranchID {
+			return nil, ErrModifierGroupBranchMismatch
+		}
+	}
+
+	if err := s.modifierRepo.SetItemGroups(item.ID, req.GroupIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.attachModifierGroups([]*model.Item{item}); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// PriceItem validates a modifier selection for one unit of an item and
+// returns its unit price. It is the single place order lines are priced
+// from the live menu.
+func (s *menuService) PriceItem(itemID uint, optionIDs []uint) (*model.PricedItem, error) {
+	item, err := s.itemRepo.GetByID(itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := s.categoryRepo.GetByID(item.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if !item.IsAvailable || !category.IsActive {
+		return nil, ErrItemUnavailable
+	}
+
+	if err := s.attachModifierGroups([]*model.Item{item}); err != nil {
+		return nil, err
+	}
+
+	selected, err := model.ValidateSelection(item.ModifierGroups, optionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	unitPrice := item.Price
+	for _, option := range selected {
+		unitPrice += option.PriceDelta
+	}
+	if unitPrice < 0 {
+		unitPrice = 0
+	}
+
+	return &model.PricedItem{Item: *item, Options: selected, UnitPrice: unitPrice}, nil
+}
+
+// attachModifierGroups fills in the ModifierGroups of items with one query
+func (s *menuService) attachModifierGroups(items []*model.Item) error {
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+
+	groups, err := s.modifierRepo.GetItemGroups(ids)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		item.ModifierGroups = groups[item.ID]
+	}
+	return nil
+}
+
+func (s *menuService) getManagedModifierGroup(actor utils.Actor, id uint) (*model.ModifierGroup, error) {
+	group, err := s.modifierRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, group.BranchID); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func applyModifierGroupRequest(group *model.ModifierGroup, req model.ModifierGroupRequest) {
+	group.Name = req.Name
+	group.Description = req.Description
+	group.MinSelect = req.MinSelect
+	group.MaxSelect = req.MaxSelect
+
+	group.Options = make([]model.ModifierOption, 0, len(req.Options))
+	for _, option := range req.Options {
+		isAvailable := true
+		if option.IsAvailable != nil {
+			isAvailable = *option.IsAvailable
+		}
+		group.Options = append(group.Options, model.ModifierOption{
+			ID:          option.ID,
+			GroupID:     group.ID,
+			Name:        option.Name,
+			PriceDelta:  option.PriceDelta,
+			IsDefault:   option.IsDefault,
+			IsAvailable: isAvailable,
+			SortOrder:   option.SortOrder,
+		})
+	}
+}
@@ -0,0 +1,335 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	loyaltycontroller "github.com/faisd405/go-restapi-gin/src/app/loyalty/controller"
+	loyaltyservice "github.com/faisd405/go-restapi-gin/src/app/loyalty/service"
+	menumodel "github.com/faisd405/go-restapi-gin/src/app/menu/model"
+	menuservice "github.com/faisd405/go-restapi-gin/src/app/menu/service"
+	"github.com/faisd405/go-restapi-gin/src/app/order/model"
+	"github.com/faisd405/go-restapi-gin/src/app/order/service"
+	pricingservice "github.com/faisd405/go-restapi-gin/src/app/pricing/service"
+	tablecontroller "github.com/faisd405/go-restapi-gin/src/app/table/controller"
+	tableservice "github.com/faisd405/go-restapi-gin/src/app/table/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Error codes of the orders API
+const (
+	ErrCodeInvalidTransition    = "INVALID_STATUS_TRANSITION"
+	ErrCodeTransitionNotAllowed = "STATUS_TRANSITION_NOT_ALLOWED"
+	ErrCodeOrderNotEditable     = "ORDER_NOT_EDITABLE"
+)
+
+type OrderController struct {
+	orderService service.OrderService
+}
+
+func NewOrderController(orderService service.OrderService) *OrderController {
+	return &OrderController{orderService: orderService}
+}
+
+// Quote godoc
+// @Summary Quote order
+// @Description Price prospective order lines with the branch taxes, service charge and an optional discount code, without creating an order
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param quote body model.QuoteRequest true "Order lines"
+// @Success 200 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /orders/quote [post]
+func (ctrl *OrderController) Quote(c *gin.Context) {
+	var req model.QuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	quote, err := ctrl.orderService.Quote(req)
+	if err != nil {
+		orderErrorResponse(c, "Quote failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Quote calculated successfully", quote)
+}
+
+// CreateOrder godoc
+// @Summary Create order
+// @Description Create a draft order for the authenticated customer. Prices and modifiers are copied from the menu. Set place to submit it right away. Guests of a table session may omit branch_id and type; their orders are dine-in at the table. The branch must be open now, or at scheduled_for for orders scheduled later.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param order body model.CreateOrderRequest true "Order data"
+// @Success 201 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /orders [post]
+func (ctrl *OrderController) CreateOrder(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	var req model.CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	order, err := ctrl.orderService.CreateOrder(actor, req)
+	if err != nil {
+		orderErrorResponse(c, "Order creation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Order created successfully", order)
+}
+
+// UpdateLines godoc
+// @Summary Replace order lines
+// @Description Replace the lines of a draft order
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Param lines body model.UpdateLinesRequest true "Order lines"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /orders/{id}/lines [put]
+func (ctrl *OrderController) UpdateLines(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID", err.Error())
+		return
+	}
+
+	var req model.UpdateLinesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	order, err := ctrl.orderService.UpdateLines(actor, id, req)
+	if err != nil {
+		orderErrorResponse(c, "Order update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Order updated successfully", order)
+}
+
+// GetOrder godoc
+// @Summary Get order
+// @Description Get an order of the authenticated customer, or of a branch the staff member works at, with the statuses they may move it to
+// @Tags orders
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /orders/{id} [get]
+func (ctrl *OrderController) GetOrder(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID", err.Error())
+		return
+	}
+
+	order, err := ctrl.orderService.GetOrder(actor, id)
+	if err != nil {
+		orderErrorResponse(c, "Order not found", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Order retrieved successfully", order)
+}
+
+// GetMyOrders godoc
+// @Summary List my orders
+// @Description List the orders of the authenticated customer, newest first
+// @Tags orders
+// @Produce json
+// @Security ApiKeyAuth
+// @Param status query string false "Filter by status"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} utils.Response
+// @Router /orders [get]
+func (ctrl *OrderController) GetMyOrders(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	page, limit := utils.GetPagination(c)
+	filter := model.OrderFilter{Status: c.Query("status")}
+
+	orders, total, err := ctrl.orderService.GetMyOrders(actor, page, limit, filter)
+	if err != nil {
+		orderErrorResponse(c, "Failed to retrieve orders", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Orders retrieved successfully",
+		utils.PaginatedData("orders", orders, page, limit, total))
+}
+
+// GetBranchOrders godoc
+// @Summary List branch orders (Staff)
+// @Description List the submitted orders of a branch, oldest first
+// @Tags orders
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param status query string false "Filter by status"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/orders [get]
+func (ctrl *OrderController) GetBranchOrders(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	branchID, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	page, limit := utils.GetPagination(c)
+	filter := model.OrderFilter{Status: c.Query("status")}
+
+	orders, total, err := ctrl.orderService.GetBranchOrders(actor, branchID, page, limit, filter)
+	if err != nil {
+		orderErrorResponse(c, "Failed to retrieve orders", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Orders retrieved successfully",
+		utils.PaginatedData("orders", orders, page, limit, total))
+}
+
+// Transition godoc
+// @Summary Change order status
+// @Description Move an order to a new status. Customers place and cancel their orders, branch staff advance them.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Param transition body model.TransitionRequest true "Target status"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /orders/{id}/status [post]
+func (ctrl *OrderController) Transition(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID", err.Error())
+		return
+	}
+
+	var req model.TransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	order, err := ctrl.orderService.Transition(actor, id, req)
+	if err != nil {
+		orderErrorResponse(c, "Status change failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Order status updated successfully", order)
+}
+
+// GetHistory godoc
+// @Summary Get order status history
+// @Description List every status change of an order, oldest first
+// @Tags orders
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /orders/{id}/history [get]
+func (ctrl *OrderController) GetHistory(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID", err.Error())
+		return
+	}
+
+	history, err := ctrl.orderService.GetHistory(actor, id)
+	if err != nil {
+		orderErrorResponse(c, "Order not found", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Order history retrieved successfully", history)
+}
+
+func orderErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, model.ErrInvalidTransition):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeInvalidTransition, message, err.Error())
+	case errors.Is(err, model.ErrTransitionNotAllowed):
+		utils.ErrorResponseWithCode(c, http.StatusForbidden, ErrCodeTransitionNotAllowed, message, err.Error())
+	case errors.Is(err, service.ErrOrderNotEditable):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeOrderNotEditable, message, err.Error())
+	case errors.Is(err, tableservice.ErrSessionEnded):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, tablecontroller.ErrCodeSessionEnded, message, err.Error())
+	case errors.Is(err, loyaltyservice.ErrInsufficientPoints):
+		utils.ErrorResponseWithCode(c, http.StatusUnprocessableEntity, loyaltycontroller.ErrCodeInsufficientPoints, message, err.Error())
+	case errors.Is(err, loyaltyservice.ErrLoyaltyDisabled):
+		utils.ErrorResponseWithCode(c, http.StatusUnprocessableEntity, loyaltycontroller.ErrCodeLoyaltyDisabled, message, err.Error())
+	case errors.Is(err, service.ErrBranchNotAccepting),
+		errors.Is(err, service.ErrBranchRequired),
+		errors.Is(err, service.ErrTableOrderType),
+		errors.Is(err, service.ErrBranchClosed),
+		errors.Is(err, service.ErrScheduleInPast),
+		errors.Is(err, service.ErrItemBranchMismatch),
+		errors.Is(err, service.ErrInvalidStatus),
+		errors.Is(err, menuservice.ErrItemUnavailable),
+		errors.Is(err, pricingservice.ErrInvalidDiscountCode),
+		errors.Is(err, pricingservice.ErrDiscountMinSubtotal),
+		errors.Is(err, loyaltyservice.ErrBelowMinRedeem),
+		errors.Is(err, loyaltyservice.ErrRedemptionExceedsOrder),
+		errors.Is(err, loyaltyservice.ErrAccountRequired),
+		errors.Is(err, menumodel.ErrInvalidSelection):
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, message, err.Error())
+	default:
+		utils.DatabaseErrorResponse(c, message, err)
+	}
+}
@@ -0,0 +1,14 @@
+package model
+
+// Order event types published to the kitchen feed of the branch
+const (
+	EventOrderPlaced  = "order.placed"
+	EventOrderUpdated = "order.updated"
+)
+
+// OrderEvent is the payload of an order event. Drafts are never published;
+// the first event of an order is EventOrderPlaced.
+type OrderEvent struct {
+	FromStatus string `json:"from_status"`
+	Order      *Order `json:"order"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import "gorm.io/gorm"
+
+// StatusHook lets other modules react to orders changing status, e.g. to
+// route kitchen tickets or take stock, without the order service knowing
+// about them. Hooks run in the order they were registered.
+type StatusHook interface {
+	// OnStatusChange runs within the transaction that moved the order,
+	// after the order was saved; an error rolls the change back
+	OnStatusChange(tx *gorm.DB, event *StatusEvent) error
+}
+
+// StatusEvent is an order that moved from one status to another
+type StatusEvent struct {
+	Order *Order
+	From  string
+
+	afterCommit []func()
+}
+
+// AfterCommit queues fn to run once the transaction committed, e.g. to
+// publish what the hook changed to live feeds
+func (e *StatusEvent) AfterCommit(fn func()) {
+	e.afterCommit = append(e.afterCommit, fn)
+}
+
+// Committed runs what hooks queued with AfterCommit
+func (e *StatusEvent) Committed() {
+	for _, fn := range e.afterCommit {
+		fn()
+	}
+}
+
+// KitchenProgress counts the kitchen tickets of an order still open and
+// done
+type KitchenProgress struct {
+	Open int64
+	Done int64
+}
+
+// ProgressFunc reads the kitchen progress of an order within tx
+type ProgressFunc func(tx *gorm.DB, orderID uint) (*KitchenProgress, error)
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"time"
+
+	menumodel "github.com/faisd405/go-restapi-gin/src/app/menu/model"
+	pricingmodel "github.com/faisd405/go-restapi-gin/src/app/pricing/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+)
+
+// Fulfillment types
+const (
+	TypeDineIn   = "dine_in"
+	TypeTakeaway = "takeaway"
+)
+
+// Order is a customer order at a branch. Amounts are in the minor unit of
+// Currency and are computed by the pricing engine, never taken from the
+// client. Orders placed through a table session have no user but the table
+// and session they were placed from.
+type Order struct {
+	ID                uint                                         `json:"id" gorm:"primaryKey"`
+	BranchID          uint                                         `json:"branch_id" gorm:"not null;index"`
+	UserID            *uint                                        `json:"user_id" gorm:"index"`
+	TableID           *uint                                        `json:"table_id" gorm:"index"`
+	TableSessionID    *uint                                        `json:"table_session_id" gorm:"index"`
+	Type              string                                       `json:"type" gorm:"type:varchar(20);not null"`
+	Status            string                                       `json:"status" gorm:"type:varchar(20);not null;index"`
+	Currency          string                                       `json:"currency" gorm:"type:char(3);not null"`
+	DiscountCode      string                                       `json:"discount_code" gorm:"type:varchar(50)"`
+	RedeemPoints      int64                                        `json:"redeem_points" gorm:"not null;default:0"`
+	Subtotal          int64                                        `json:"subtotal" gorm:"not null;default:0"`
+	Discounts         database.JSONList[pricingmodel.DiscountLine] `json:"discounts" gorm:"type:jsonb;not null;default:'[]'"`
+	DiscountTotal     int64                                        `json:"discount_total" gorm:"not null;default:0"`
+	ServiceCharge     int64                                        `json:"service_charge" gorm:"not null;default:0"`
+	Taxes             database.JSONList[pricingmodel.TaxLine]      `json:"taxes" gorm:"type:jsonb;not null;default:'[]'"`
+	TaxTotal          int64                                        `json:"tax_total" gorm:"not null;default:0"`
+	InclusiveTaxTotal int64                                        `json:"inclusive_tax_total" gorm:"not null;default:0"`
+	Rounding          int64                                        `json:"rounding" gorm:"not null;default:0"`
+	Total             int64                                        `json:"total" gorm:"not null;default:0"`
+	Notes             string                                       `json:"notes" gorm:"type:text"`
+	ScheduledFor      *time.Time                                   `json:"scheduled_for"`
+	PlacedAt          *time.Time                                   `json:"placed_at"`
+	Lines             []OrderLine                                  `json:"lines,omitempty" gorm:"foreignKey:OrderID"`
+	CreatedAt         time.Time                                    `json:"created_at"`
+	UpdatedAt         time.Time                                    `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt                               `json:"-" gorm:"index"`
+}
+
+// OrderLine is a menu item on an order. Name, prices, modifiers and tags
+// are copied from the menu when the line is added so later menu changes do
+// not alter the order. LineTotal is the gross amount; Discount is the
+// line's share of the order discounts and Tax the exclusive tax on the rest.
+type OrderLine struct {
+	ID         uint                                        `json:"id" gorm:"primaryKey"`
+	OrderID    uint                                        `json:"order_id" gorm:"not null;index"`
+	MenuItemID uint                                        `json:"menu_item_id" gorm:"not null;index"`
+	Name       string                                      `json:"name" gorm:"not null"`
+	BasePrice  int64                                       `json:"base_price" gorm:"not null"`
+	Modifiers  database.JSONList[menumodel.SelectedOption] `json:"modifiers" gorm:"type:jsonb;not null;default:'[]'"`
+	UnitPrice  int64                                       `json:"unit_price" gorm:"not null"`
+	Quantity   int                                         `json:"quantity" gorm:"not null"`
+	LineTotal  int64                                       `json:"line_total" gorm:"not null"`
+	Discount   int64                                       `json:"discount" gorm:"not null;default:0"`
+	Tax        int64                                       `json:"tax" gorm:"not null;default:0"`
+	Tags       database.StringList                         `json:"tags" gorm:"type:jsonb;not null;default:'[]'"`
+	Notes      string                                      `json:"notes" gorm:"type:text"`
+	CreatedAt  time.Time                                   `json:"created_at"`
+}
+
+// StatusChange is an entry of the append-only status history of an order
+type StatusChange struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	OrderID    uint      `json:"order_id" gorm:"not null;index"`
+	FromStatus string    `json:"from_status" gorm:"type:varchar(20)"`
+	ToStatus   string    `json:"to_status" gorm:"type:varchar(20);not null"`
+	ActorID    *uint     `json:"actor_id"`
+	ActorRole  string    `json:"actor_role" gorm:"type:varchar(20);not null"`
+	Reason     string    `json:"reason" gorm:"type:text"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (StatusChange) TableName() string {
+	return "order_status_history"
+}
+
+// CreateOrderRequest is the body of a new order. Guests of a table session
+// may omit the branch and type; their orders are dine-in at the table.
+// ScheduledFor asks for the order at a later time, when the branch must be
+// open; unscheduled orders need the branch to be open now. RedeemPoints
+// spends loyalty points of the user on a discount when the order is placed.
+type CreateOrderRequest struct {
+	BranchID     uint          `json:"branch_id"`
+	Type         string        `json:"type" binding:"omitempty,oneof=dine_in takeaway"`
+	Notes        string        `json:"notes" binding:"max=500"`
+	Lines        []LineRequest `json:"lines" binding:"required,min=1,dive"`
+	DiscountCode string        `json:"discount_code" binding:"max=50"`
+	RedeemPoints int64         `json:"redeem_points" binding:"min=0"`
+	ScheduledFor *time.Time    `json:"scheduled_for"`
+	Place        bool          `json:"place"`
+}
+
+type QuoteRequest struct {
+	BranchID     uint          `json:"branch_id" binding:"required"`
+	Lines        []LineRequest `json:"lines" binding:"required,min=1,dive"`
+	DiscountCode string        `json:"discount_code" binding:"max=50"`
+	RedeemPoints int64         `json:"redeem_points" binding:"min=0"`
+}
+
+// Quote is the price of prospective order lines
+type Quote struct {
+	Lines     []OrderLine             `json:"lines"`
+	Breakdown *pricingmodel.Breakdown `json:"breakdown"`
+}
+
+type LineRequest struct {
+	MenuItemID uint   `json:"menu_item_id" binding:"required"`
+	Quantity   int    `json:"quantity" binding:"required,min=1,max=99"`
+	OptionIDs  []uint `json:"option_ids"`
+	Notes      string `json:"notes" binding:"max=200"`
+}
+
+type UpdateLinesRequest struct {
+	Lines        []LineRequest `json:"lines" binding:"required,min=1,dive"`
+	DiscountCode string        `json:"discount_code" binding:"max=50"`
+	RedeemPoints int64         `json:"redeem_points" binding:"min=0"`
+}
+
+type TransitionRequest struct {
+	Status string `json:"status" binding:"required"`
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// OrderFilter narrows down order listings
+type OrderFilter struct {
+	Status string
+}
+
+// OrderDetails is an order together with the statuses the requesting user
+// may move it to
+type OrderDetails struct {
+	Order
+	AllowedTransitions []string `json:"allowed_transitions"`
+}
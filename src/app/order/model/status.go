@@ -0,0 +1,135 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+
+	usermodel "github.com/faisd405/go-restapi-gin/src/app/user/model"
+)
+
+// Order statuses
+const (
+	StatusDraft     = "draft"
+	StatusPlaced    = "placed"
+	StatusAccepted  = "accepted"
+	StatusPreparing = "preparing"
+	StatusReady     = "ready"
+	StatusServed    = "served"
+	StatusPickedUp  = "picked_up"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+	StatusRejected  = "rejected"
+)
+
+// RoleCustomer is the role of the user who owns an order and RoleKitchen
+// the role under which kitchen tickets move their order along. Staff roles
+// are the user roles of the branch staff.
+const (
+	RoleCustomer = "customer"
+	RoleKitchen  = "kitchen"
+)
+
+var (
+	ErrInvalidTransition    = errors.New("invalid status transition")
+	ErrTransitionNotAllowed = errors.New("you are not allowed to make this status transition")
+)
+
+// Transition is an allowed edge of the order state machine. Types limits
+// the edge to some fulfillment types; empty means all.
+type Transition struct {
+	From  string
+	To    string
+	Roles []string
+	Types []string
+}
+
+var (
+	staffRoles   = []string{usermodel.RoleStaff, usermodel.RoleManager, usermodel.RoleAdmin}
+	managerRoles = []string{usermodel.RoleManager, usermodel.RoleAdmin}
+	kitchenRoles = append([]string{RoleKitchen}, staffRoles...)
+)
+
+// Transitions is the order state machine:
+//
+//	draft → placed → accepted → preparing → ready → served/picked_up → completed
+//
+// Orders can be cancelled before they are ready, by the customer only until
+// the branch accepts them, and rejected by the branch while placed.
+var Transitions = []Transition{
+	{From: StatusDraft, To: StatusPlaced, Roles: []string{RoleCustomer}},
+	{From: StatusDraft, To: StatusCancelled, Roles: []string{RoleCustomer}},
+
+	{From: StatusPlaced, To: StatusAccepted, Roles: staffRoles},
+	{From: StatusPlaced, To: StatusRejected, Roles: staffRoles},
+	{From: StatusPlaced, To: StatusCancelled, Roles: append([]string{RoleCustomer}, staffRoles...)},
+
+	{From: StatusAccepted, To: StatusPreparing, Roles: kitchenRoles},
+	{From: StatusAccepted, To: StatusCancelled, Roles: managerRoles},
+
+	{From: StatusPreparing, To: StatusReady, Roles: kitchenRoles},
+	{From: StatusPreparing, To: StatusCancelled, Roles: managerRoles},
+
+	{From: StatusReady, To: StatusServed, Roles: staffRoles, Types: []string{TypeDineIn}},
+	{From: StatusReady, To: StatusPickedUp, Roles: staffRoles, Types: []string{TypeTakeaway}},
+
+	{From: StatusServed, To: StatusCompleted, Roles: staffRoles},
+	{From: StatusPickedUp, To: StatusCompleted, Roles: staffRoles},
+}
+
+// IsTerminal reports whether no transition leaves status
+func IsTerminal(status string) bool {
+	return status == StatusCompleted || status == StatusCancelled || status == StatusRejected
+}
+
+// IsValidStatus reports whether status is one of the order statuses
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusDraft, StatusPlaced, StatusAccepted, StatusPreparing, StatusReady,
+		StatusServed, StatusPickedUp, StatusCompleted, StatusCancelled, StatusRejected:
+		return true
+	}
+	return false
+}
+
+// CheckTransition validates moving an order of orderType from one status to
+// another by an actor holding roles. It returns ErrInvalidTransition when
+// the state machine has no such edge and ErrTransitionNotAllowed when none
+// of the roles may take it.
+func CheckTransition(from, to, orderType string, roles []string) error {
+	for _, t := range Transitions {
+		if t.From != from || t.To != to {
+			continue
+		}
+		if len(t.Types) > 0 && !containsString(t.Types, orderType) {
+			break
+		}
+		for _, role := range roles {
+			if containsString(t.Roles, role) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %s → %s", ErrTransitionNotAllowed, from, to)
+	}
+	return fmt.Errorf("%w: %s → %s", ErrInvalidTransition, from, to)
+}
+
+// NextStatuses returns the statuses an actor holding roles may move an
+// order to from its current status
+func NextStatuses(order *Order, roles []string) []string {
+	next := []string{}
+	for _, t := range Transitions {
+		if t.From == order.Status && CheckTransition(t.From, t.To, order.Type, roles) == nil {
+			next = append(next, t.To)
+		}
+	}
+	return next
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"github.com/faisd405/go-restapi-gin/src/app/order/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+)
+
+// HistoryRepository appends to and reads the order status history. Entries
+// are never updated or deleted.
+type HistoryRepository interface {
+	Create(change *model.StatusChange) error
+	FindByOrder(orderID uint) ([]model.StatusChange, error)
+	WithTx(tx *gorm.DB) HistoryRepository
+}
+
+type historyRepository struct {
+	database.Repository[model.StatusChange]
+}
+
+func NewHistoryRepository(db *gorm.DB) HistoryRepository {
+	return &historyRepository{Repository: database.NewRepository[model.StatusChange](db)}
+}
+
+func (r *historyRepository) FindByOrder(orderID uint) ([]model.StatusChange, error) {
+	return r.Find(database.NewQuery().Eq("order_id", orderID).OrderBy("created_at").OrderBy("id"))
+}
+
+func (r *historyRepository) WithTx(tx *gorm.DB) HistoryRepository {
+	return &historyRepository{Repository: r.Repository.WithTx(tx)}
+}
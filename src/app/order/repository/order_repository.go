@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"github.com/faisd405/go-restapi-gin/src/app/order/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrderRepository interface {
+	Create(order *model.Order) error
+	GetByID(id uint) (*model.Order, error)
+	GetForUpdate(id uint) (*model.Order, error)
+	Update(order *model.Order) error
+	FindPage(q *database.Query) ([]model.Order, int64, error)
+	ReplaceLines(order *model.Order, lines []model.OrderLine) error
+	SaveLines(lines []model.OrderLine) error
+	WithTx(tx *gorm.DB) OrderRepository
+}
+
+type orderRepository struct {
+	database.Repository[model.Order]
+}
+
+func NewOrderRepository(db *gorm.DB) OrderRepository {
+	return &orderRepository{Repository: database.NewRepository[model.Order](db)}
+}
+
+// GetByID loads an order with its lines
+func (r *orderRepository) GetByID(id uint) (*model.Order, error) {
+	return r.FindByID(id, database.NewQuery().Preload("Lines"))
+}
+
+// GetForUpdate loads an order with its lines and locks the order row until
+// the surrounding transaction ends
+func (r *orderRepository) GetForUpdate(id uint) (*model.Order, error) {
+	var order model.Order
+	err := r.DB().Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Lines").First(&order, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// Update saves the order columns only; lines are managed by ReplaceLines
+func (r *orderRepository) Update(order *model.Order) error {
+	return database.TranslateError(r.DB().Omit("Lines").Save(order).Error)
+}
+
+// ReplaceLines deletes the lines of order and inserts lines in their place
+func (r *orderRepository) ReplaceLines(order *model.Order, lines []model.OrderLine) error {
+	if err := r.DB().Where("order_id = ?", order.ID).Delete(&model.OrderLine{}).Error; err != nil {
+		return database.TranslateError(err)
+	}
+	for i := range lines {
+		lines[i].OrderID = order.ID
+	}
+	if err := r.DB().Create(&lines).Error; err != nil {
+		return database.TranslateError(err)
+	}
+	order.Lines = lines
+	return nil
+}
+
+// SaveLines updates existing order lines
+func (r *orderRepository) SaveLines(lines []model.OrderLine) error {
+	for i := range lines {
+		if err := r.DB().Save(&lines[i]).Error; err != nil {
+			return database.TranslateError(err)
+		}
+	}
+	return nil
+}
+
+func (r *orderRepository) WithTx(tx *gorm.DB) OrderRepository {
+	return &orderRepository{Repository: r.Repository.WithTx(tx)}
+}
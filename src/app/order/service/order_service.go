@@ -0,0 +1,650 @@
+package service
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	loyaltymodel "github.com/faisd405/go-restapi-gin/src/app/loyalty/model"
+	loyaltyservice "github.com/faisd405/go-restapi-gin/src/app/loyalty/service"
+	menuservice "github.com/faisd405/go-restapi-gin/src/app/menu/service"
+	"github.com/faisd405/go-restapi-gin/src/app/order/model"
+	"github.com/faisd405/go-restapi-gin/src/app/order/repository"
+	pricingmodel "github.com/faisd405/go-restapi-gin/src/app/pricing/model"
+	pricingservice "github.com/faisd405/go-restapi-gin/src/app/pricing/service"
+	restaurantmodel "github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	tableservice "github.com/faisd405/go-restapi-gin/src/app/table/service"
+	usermodel "github.com/faisd405/go-restapi-gin/src/app/user/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/realtime"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrBranchNotAccepting = errors.New("branch is not accepting orders")
+	ErrItemBranchMismatch = errors.New("menu item belongs to a different branch")
+	ErrOrderNotEditable   = errors.New("only draft orders can be changed")
+	ErrInvalidStatus      = errors.New("unknown order status")
+	ErrBranchRequired     = errors.New("branch_id and type are required")
+	ErrTableOrderType     = errors.New("orders placed at a table must be dine-in")
+	ErrBranchClosed       = errors.New("branch is closed at this time")
+	ErrScheduleInPast     = errors.New("scheduled_for must be in the future")
+)
+
+type OrderService interface {
+	Quote(req model.QuoteRequest) (*model.Quote, error)
+	CreateOrder(actor utils.Actor, req model.CreateOrderRequest) (*model.Order, error)
+	UpdateLines(actor utils.Actor, id uint, req model.UpdateLinesRequest) (*model.Order, error)
+	GetOrder(actor utils.Actor, id uint) (*model.OrderDetails, error)
+	GetMyOrders(actor utils.Actor, page, limit int, filter model.OrderFilter) ([]model.Order, int64, error)
+	GetBranchOrders(actor utils.Actor, branchID uint, page, limit int, filter model.OrderFilter) ([]model.Order, int64, error)
+	Transition(actor utils.Actor, id uint, req model.TransitionRequest) (*model.Order, error)
+	GetHistory(actor utils.Actor, id uint) ([]model.StatusChange, error)
+	// SyncKitchen moves an order along as its kitchen tickets progress
+	SyncKitchen(actor utils.Actor, id uint, progress model.ProgressFunc) (*model.Order, error)
+}
+
+type orderService struct {
+	orderRepo     repository.OrderRepository
+	historyRepo   repository.HistoryRepository
+	menuSvc       menuservice.MenuService
+	pricingSvc    pricingservice.PricingService
+	loyaltySvc    loyaltyservice.LoyaltyService
+	restaurantSvc restaurantservice.RestaurantService
+	hoursSvc      restaurantservice.HoursService
+	tableSvc      tableservice.TableService
+	events        realtime.Publisher
+	txManager     database.TxManager
+	hooks         []model.StatusHook
+}
+
+func NewOrderService(
+	orderRepo repository.OrderRepository,
+	historyRepo repository.HistoryRepository,
+	menuSvc menuservice.MenuService,
+	pricingSvc pricingservice.PricingService,
+	loyaltySvc loyaltyservice.LoyaltyService,
+	restaurantSvc restaurantservice.RestaurantService,
+	hoursSvc restaurantservice.HoursService,
+	tableSvc tableservice.TableService,
+	events realtime.Publisher,
+	txManager database.TxManager,
+	hooks ...model.StatusHook,
+) OrderService {
+	return &orderService{
+		orderRepo:     orderRepo,
+		historyRepo:   historyRepo,
+		menuSvc:       menuSvc,
+		pricingSvc:    pricingSvc,
+		loyaltySvc:    loyaltySvc,
+		restaurantSvc: restaurantSvc,
+		hoursSvc:      hoursSvc,
+		tableSvc:      tableSvc,
+		events:        events,
+		txManager:     txManager,
+		hooks:         hooks,
+	}
+}
+
+// Quote prices prospective order lines without creating an order. Points
+// to redeem are priced as a discount; the balance is checked once the
+// order is placed.
+func (s *orderService) Quote(req model.QuoteRequest) (*model.Quote, error) {
+	if _, err := s.restaurantSvc.GetBranch(req.BranchID, true); err != nil {
+		return nil, err
+	}
+
+	lines, err := s.priceLines(req.BranchID, req.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown, err := s.price(req.BranchID, lines, req.DiscountCode, req.RedeemPoints)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Quote{Lines: lines, Breakdown: breakdown}, nil
+}
+
+// CreateOrder creates a draft order, or places it right away when asked
+// to. Guests of a table session order dine-in at their table; everybody
+// else names the branch and type.
+func (s *orderService) CreateOrder(actor utils.Actor, req model.CreateOrderRequest) (*model.Order, error) {
+	order := &model.Order{
+		BranchID:     req.BranchID,
+		Type:         req.Type,
+		Status:       model.StatusDraft,
+		DiscountCode: req.DiscountCode,
+		RedeemPoints: req.RedeemPoints,
+		ScheduledFor: req.ScheduledFor,
+		Notes:        req.Notes,
+	}
+
+	if actor.IsGuest() {
+		session, err := s.tableSvc.GetActiveSession(actor.TableSessionID)
+		if err != nil {
+			return nil, err
+		}
+		if order.Type == "" {
+			order.Type = model.TypeDineIn
+		}
+		if order.Type != model.TypeDineIn {
+			return nil, ErrTableOrderType
+		}
+		order.BranchID = session.BranchID
+		order.TableID = &session.TableID
+		order.TableSessionID = &session.ID
+	} else {
+		if order.BranchID == 0 || order.Type == "" {
+			return nil, ErrBranchRequired
+		}
+		userID := actor.UserID
+		order.UserID = &userID
+	}
+
+	branch, err := s.restaurantSvc.GetBranch(order.BranchID, true)
+	if err != nil {
+		return nil, err
+	}
+	if branch.Status != restaurantmodel.BranchStatusActive {
+		return nil, ErrBranchNotAccepting
+	}
+	if err := s.checkOpen(order); err != nil {
+		return nil, err
+	}
+
+	lines, err := s.priceLines(branch.ID, req.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	order.Currency = branch.EffectiveCurrency()
+	order.Lines = lines
+	if err := s.applyPricing(order); err != nil {
+		return nil, err
+	}
+
+	var event *model.StatusEvent
+	err = s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		if err := s.orderRepo.WithTx(tx).Create(order); err != nil {
+			return err
+		}
+		if err := s.recordChange(tx, order, "", actor, model.RoleCustomer, ""); err != nil {
+			return err
+		}
+		if req.Place {
+			event, err = s.applyTransition(tx, order, model.StatusPlaced, actor, []string{model.RoleCustomer}, "")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if event != nil {
+		s.committed(event)
+	}
+
+	return order, nil
+}
+
+func (s *orderService) UpdateLines(actor utils.Actor, id uint, req model.UpdateLinesRequest) (*model.Order, error) {
+	var order *model.Order
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		orderRepo := s.orderRepo.WithTx(tx)
+
+		var err error
+		order, err = orderRepo.GetForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if !isOwner(actor, order) {
+			return gorm.ErrRecordNotFound
+		}
+		if err := s.checkGuestSession(actor); err != nil {
+			return err
+		}
+		if order.Status != model.StatusDraft {
+			return ErrOrderNotEditable
+		}
+
+		lines, err := s.priceLines(order.BranchID, req.Lines)
+		if err != nil {
+			return err
+		}
+
+		order.Lines = lines
+		order.DiscountCode = req.DiscountCode
+		order.RedeemPoints = req.RedeemPoints
+		if err := s.applyPricing(order); err != nil {
+			return err
+		}
+
+		if err := orderRepo.ReplaceLines(order, order.Lines); err != nil {
+			return err
+		}
+		return orderRepo.Update(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *orderService) GetOrder(actor utils.Actor, id uint) (*model.OrderDetails, error) {
+	order, err := s.orderRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := s.actorRoles(actor, order)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.OrderDetails{Order: *order, AllowedTransitions: model.NextStatuses(order, roles)}, nil
+}
+
+// GetMyOrders lists the orders of the user, or for guests the orders of
+// their table session
+func (s *orderService) GetMyOrders(actor utils.Actor, page, limit int, filter model.OrderFilter) ([]model.Order, int64, error) {
+	q := database.NewQuery().Eq("user_id", actor.UserID)
+	if actor.IsGuest() {
+		q = database.NewQuery().Eq("table_session_id", actor.TableSessionID)
+	}
+	if err := applyFilter(q, filter); err != nil {
+		return nil, 0, err
+	}
+
+	return s.orderRepo.FindPage(q.Preload("Lines").OrderByDesc("created_at").Paginate(utils.Offset(page, limit), limit))
+}
+
+func (s *orderService) GetBranchOrders(actor utils.Actor, branchID uint, page, limit int, filter model.OrderFilter) ([]model.Order, int64, error) {
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, 0, err
+	}
+
+	q := database.NewQuery().Eq("branch_id", branchID)
+	if filter.Status == "" {
+		// Drafts are only visible to the customer building them
+		q.Where("status", database.OpNotEq, model.StatusDraft)
+	}
+	if err := applyFilter(q, filter); err != nil {
+		return nil, 0, err
+	}
+
+	return s.orderRepo.FindPage(q.Preload("Lines").OrderBy("created_at").Paginate(utils.Offset(page, limit), limit))
+}
+
+func (s *orderService) Transition(actor utils.Actor, id uint, req model.TransitionRequest) (*model.Order, error) {
+	if !model.IsValidStatus(req.Status) {
+		return nil, ErrInvalidStatus
+	}
+
+	var order *model.Order
+	var event *model.StatusEvent
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		order, err = s.orderRepo.WithTx(tx).GetForUpdate(id)
+		if err != nil {
+			return err
+		}
+
+		roles, err := s.actorRoles(actor, order)
+		if err != nil {
+			return err
+		}
+		if err := s.checkGuestSession(actor); err != nil {
+			return err
+		}
+
+		event, err = s.applyTransition(tx, order, req.Status, actor, roles, req.Reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.committed(event)
+
+	return order, nil
+}
+
+// SyncKitchen moves an accepted order to preparing once its first kitchen
+// ticket is done and to ready once none is pending any more. Orders
+// without tickets, or that staff moved on already, are left alone.
+func (s *orderService) SyncKitchen(actor utils.Actor, id uint, progress model.ProgressFunc) (*model.Order, error) {
+	var order *model.Order
+	var events []*model.StatusEvent
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		order, err = s.orderRepo.WithTx(tx).GetForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if err := utils.CheckBranchAccess(s.restaurantSvc, actor, order.BranchID); err != nil {
+			return err
+		}
+
+		// Reading the tickets under the order lock lets the last of
+		// several stations finishing at once see all of them done
+		tickets, err := progress(tx, order.ID)
+		if err != nil {
+			return err
+		}
+		if tickets.Done == 0 {
+			return nil
+		}
+
+		roles := []string{model.RoleKitchen}
+		if order.Status == model.StatusAccepted {
+			event, err := s.applyTransition(tx, order, model.StatusPreparing, actor, roles, "")
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		if order.Status == model.StatusPreparing && tickets.Open == 0 {
+			event, err := s.applyTransition(tx, order, model.StatusReady, actor, roles, "")
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		s.committed(event)
+	}
+
+	return order, nil
+}
+
+func (s *orderService) GetHistory(actor utils.Actor, id uint) ([]model.StatusChange, error) {
+	order, err := s.orderRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.actorRoles(actor, order); err != nil {
+		return nil, err
+	}
+
+	return s.historyRepo.FindByOrder(order.ID)
+}
+
+// applyTransition moves a locked order to status, runs the status hooks
+// and appends the change to its history. The returned event is passed to
+// committed once the transaction committed.
+func (s *orderService) applyTransition(tx *gorm.DB, order *model.Order, status string, actor utils.Actor, roles []string, reason string) (*model.StatusEvent, error) {
+	if err := model.CheckTransition(order.Status, status, order.Type, roles); err != nil {
+		return nil, err
+	}
+
+	orderRepo := s.orderRepo.WithTx(tx)
+
+	from := order.Status
+	order.Status = status
+	if status == model.StatusPlaced {
+		if err := s.checkOpen(order); err != nil {
+			return nil, err
+		}
+		// The final price uses the tax rates and discounts in force when
+		// the order is placed
+		if err := s.applyPricing(order); err != nil {
+			return nil, err
+		}
+		if err := orderRepo.SaveLines(order.Lines); err != nil {
+			return nil, err
+		}
+		now := time.Now()
+		order.PlacedAt = &now
+	}
+	if err := orderRepo.Update(order); err != nil {
+		return nil, err
+	}
+
+	event := &model.StatusEvent{Order: order, From: from}
+	for _, hook := range s.hooks {
+		if err := hook.OnStatusChange(tx, event); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.recordChange(tx, order, from, actor, transitionRole(from, status, order.Type, roles), reason); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// recordChange appends a status change to the history. Guests have no
+// user, so their changes are recorded without an actor ID.
+func (s *orderService) recordChange(tx *gorm.DB, order *model.Order, from string, actor utils.Actor, role, reason string) error {
+	change := &model.StatusChange{
+		OrderID:    order.ID,
+		FromStatus: from,
+		ToStatus:   order.Status,
+		ActorRole:  role,
+		Reason:     reason,
+		ActorID:    utils.ActorID(actor),
+	}
+	return s.historyRepo.WithTx(tx).Create(change)
+}
+
+// checkOpen requires the branch to be open now, or for scheduled orders at
+// the scheduled time, judged by the opening hours in the branch's time zone
+func (s *orderService) checkOpen(order *model.Order) error {
+	schedule, err := s.hoursSvc.GetSchedule(order.BranchID)
+	if err != nil {
+		return err
+	}
+
+	at := time.Now()
+	if order.ScheduledFor != nil {
+		if !order.ScheduledFor.After(at) {
+			return ErrScheduleInPast
+		}
+		at = *order.ScheduledFor
+	}
+	if !schedule.IsOpenAt(at) {
+		return ErrBranchClosed
+	}
+	return nil
+}
+
+// committed publishes a status change once its transaction committed and
+// runs what the hooks queued for then. Drafts are never published, so a
+// draft that is cancelled without being placed stays off the feed.
+func (s *orderService) committed(event *model.StatusEvent) {
+	if event.From != model.StatusDraft || event.Order.Status == model.StatusPlaced {
+		s.publish(event.Order, event.From)
+	}
+	event.Committed()
+}
+
+// publish sends a committed status change of order to the kitchen feed of
+// its branch. The order is already saved, so a failing feed is only logged;
+// screens catch up when they reload.
+func (s *orderService) publish(order *model.Order, from string) {
+	eventType := model.EventOrderUpdated
+	if order.Status == model.StatusPlaced {
+		eventType = model.EventOrderPlaced
+	}
+
+	event := model.OrderEvent{FromStatus: from, Order: order}
+	if err := s.events.Publish(realtime.BranchTopic(order.BranchID), eventType, event); err != nil {
+		log.Println("Failed to publish order event:", err)
+	}
+}
+
+// checkGuestSession stops guests from changing orders once staff have
+// ended their table session
+func (s *orderService) checkGuestSession(actor utils.Actor) error {
+	if !actor.IsGuest() {
+		return nil
+	}
+	_, err := s.tableSvc.GetActiveSession(actor.TableSessionID)
+	return err
+}
+
+// actorRoles returns the roles the actor holds on order: customer when they
+// own it and their staff role when they work at its branch. Users without
+// any role get a not found error so orders of others stay hidden.
+func (s *orderService) actorRoles(actor utils.Actor, order *model.Order) ([]string, error) {
+	roles := []string{}
+	if isOwner(actor, order) {
+		roles = append(roles, model.RoleCustomer)
+	}
+
+	switch actor.Role {
+	case usermodel.RoleStaff, usermodel.RoleManager, usermodel.RoleAdmin:
+		err := utils.CheckBranchAccess(s.restaurantSvc, actor, order.BranchID)
+		if err == nil {
+			roles = append(roles, actor.Role)
+		} else if !errors.Is(err, utils.ErrBranchAccessDenied) {
+			return nil, err
+		}
+	}
+
+	if len(roles) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return roles, nil
+}
+
+// priceLines snapshots the current menu price and modifiers of each line
+func (s *orderService) priceLines(branchID uint, requests []model.LineRequest) ([]model.OrderLine, error) {
+	lines := make([]model.OrderLine, 0, len(requests))
+	for _, req := range requests {
+		priced, err := s.menuSvc.PriceItem(req.MenuItemID, req.OptionIDs)
+		if err != nil {
+			return nil, err
+		}
+		if priced.Item.BranchID != branchID {
+			return nil, ErrItemBranchMismatch
+		}
+
+		lines = append(lines, model.OrderLine{
+			MenuItemID: priced.Item.ID,
+			Name:       priced.Item.Name,
+			BasePrice:  priced.Item.Price,
+			Modifiers:  priced.Options,
+			UnitPrice:  priced.UnitPrice,
+			Quantity:   req.Quantity,
+			LineTotal:  priced.UnitPrice * int64(req.Quantity),
+			Tags:       priced.Item.Tags,
+			Notes:      req.Notes,
+		})
+	}
+	return lines, nil
+}
+
+// applyPricing prices the lines of order with the pricing engine and copies
+// the breakdown onto the order and its lines. Only users with an account
+// can redeem points.
+func (s *orderService) applyPricing(order *model.Order) error {
+	if order.RedeemPoints > 0 && order.UserID == nil {
+		return loyaltyservice.ErrAccountRequired
+	}
+	breakdown, err := s.price(order.BranchID, order.Lines, order.DiscountCode, order.RedeemPoints)
+	if err != nil {
+		return err
+	}
+
+	for i := range order.Lines {
+		order.Lines[i].Discount = breakdown.Lines[i].Discount
+		order.Lines[i].Tax = breakdown.Lines[i].Tax
+	}
+	order.Currency = breakdown.Currency
+	order.Subtotal = breakdown.Subtotal
+	order.Discounts = breakdown.Discounts
+	order.DiscountTotal = breakdown.DiscountTotal
+	order.ServiceCharge = breakdown.ServiceCharge
+	order.Taxes = breakdown.Taxes
+	order.TaxTotal = breakdown.TaxTotal
+	order.InclusiveTaxTotal = breakdown.InclusiveTaxTotal
+	order.Rounding = breakdown.Rounding
+	order.Total = breakdown.Total
+	return nil
+}
+
+// price quotes lines at a branch with the pricing engine. Redeemed points
+// are the last discount, after the discount code, and must be worth no
+// more than what is left of the order by then.
+func (s *orderService) price(branchID uint, lines []model.OrderLine, code string, points int64) (*pricingmodel.Breakdown, error) {
+	req := pricingmodel.QuoteRequest{
+		BranchID:     branchID,
+		Lines:        lineInputs(lines),
+		DiscountCode: code,
+	}
+	if points == 0 {
+		return s.pricingSvc.Quote(req)
+	}
+
+	redemption, err := s.loyaltySvc.Redemption(branchID, points)
+	if err != nil {
+		return nil, err
+	}
+	req.Discounts = append(req.Discounts, *redemption)
+
+	breakdown, err := s.pricingSvc.Quote(req)
+	if err != nil {
+		return nil, err
+	}
+	// The engine caps discounts at what is left and drops those left
+	// with nothing
+	n := len(breakdown.Discounts)
+	if n == 0 || breakdown.Discounts[n-1].Code != loyaltymodel.DiscountCode || breakdown.Discounts[n-1].Amount < redemption.Amount {
+		return nil, loyaltyservice.ErrRedemptionExceedsOrder
+	}
+	return breakdown, nil
+}
+
+func lineInputs(lines []model.OrderLine) []pricingmodel.LineInput {
+	inputs := make([]pricingmodel.LineInput, len(lines))
+	for i, line := range lines {
+		inputs[i] = pricingmodel.LineInput{
+			Name:      line.Name,
+			UnitPrice: line.UnitPrice,
+			Quantity:  line.Quantity,
+			Tags:      line.Tags,
+		}
+	}
+	return inputs
+}
+
+func applyFilter(q *database.Query, filter model.OrderFilter) error {
+	if filter.Status == "" {
+		return nil
+	}
+	if !model.IsValidStatus(filter.Status) {
+		return ErrInvalidStatus
+	}
+	q.Eq("status", filter.Status)
+	return nil
+}
+
+// transitionRole picks the role recorded in the history: the first of the
+// actor's roles that is allowed to make the transition
+func transitionRole(from, to, orderType string, roles []string) string {
+	for _, role := range roles {
+		if model.CheckTransition(from, to, orderType, []string{role}) == nil {
+			return role
+		}
+	}
+	return roles[0]
+}
+
+// isOwner reports whether the actor placed order, as a user or as a guest
+// of its table session
+func isOwner(actor utils.Actor, order *model.Order) bool {
+	if actor.IsGuest() {
+		return order.TableSessionID != nil && *order.TableSessionID == actor.TableSessionID
+	}
+	return order.UserID != nil && *order.UserID == actor.UserID
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/faisd405/go-restapi-gin/src/app/payment/model"
+	"github.com/faisd405/go-restapi-gin/src/app/payment/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type BillController struct {
+	billService service.BillService
+}
+
+func NewBillController(billService service.BillService) *BillController {
+	return &BillController{billService: billService}
+}
+
+// GetBills godoc
+// @Summary Get order bills
+// @Description Get the bills an order is split into with their paid and outstanding amounts
+// @Tags payments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /orders/{id}/bills [get]
+func (ctrl *BillController) GetBills(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID", err.Error())
+		return
+	}
+
+	bills, err := ctrl.billService.GetBills(actor, id)
+	if err != nil {
+		paymentErrorResponse(c, "Failed to retrieve bills", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bills retrieved successfully", bills)
+}
+
+// Split godoc
+// @Summary Split order into bills
+// @Description Split the outstanding balance by items, into equal shares or into custom amounts. Each bill is paid on its own by passing its bill_id to the payment routes. Unpaid bills are replaced by a new split.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Param split body model.SplitRequest true "Split"
+// @Success 201 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /orders/{id}/bills [post]
+func (ctrl *BillController) Split(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID", err.Error())
+		return
+	}
+
+	var req model.SplitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	bills, err := ctrl.billService.Split(actor, id, req)
+	if err != nil {
+		paymentErrorResponse(c, "Order split failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Order split successfully", bills)
+}
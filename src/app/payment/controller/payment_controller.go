@@ -0,0 +1,301 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/faisd405/go-restapi-gin/src/app/payment/model"
+	"github.com/faisd405/go-restapi-gin/src/app/payment/service"
+	"github.com/faisd405/go-restapi-gin/src/gateway"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Error codes of the payments, bills and refunds API
+const (
+	ErrCodePaymentDeclined   = "PAYMENT_DECLINED"
+	ErrCodeOrderNotPayable   = "ORDER_NOT_PAYABLE"
+	ErrCodeAmountExceeds     = "AMOUNT_EXCEEDS_BALANCE"
+	ErrCodePaymentState      = "PAYMENT_NOT_CHANGEABLE"
+	ErrCodeInvalidSignature  = "INVALID_SIGNATURE"
+	ErrCodeSignatureRequired = "SIGNATURE_REQUIRED"
+	ErrCodeRefundExceeds     = "REFUND_EXCEEDS_CAPTURED"
+	ErrCodeBillRequired      = "BILL_REQUIRED"
+	ErrCodeBillsLocked       = "BILLS_LOCKED"
+	ErrCodeSplitMismatch     = "SPLIT_MISMATCH"
+	ErrCodePaymentPending    = "PAYMENT_PENDING"
+	ErrCodeRefundPending     = "REFUND_PENDING"
+)
+
+// SignatureHeader carries the signature of provider webhooks
+const SignatureHeader = "X-Webhook-Signature"
+
+type PaymentController struct {
+	paymentService service.PaymentService
+}
+
+func NewPaymentController(paymentService service.PaymentService) *PaymentController {
+	return &PaymentController{paymentService: paymentService}
+}
+
+// GetPayments godoc
+// @Summary Get order payments
+// @Description Get the payments of an order with the paid, pending and outstanding amounts
+// @Tags payments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /orders/{id}/payments [get]
+func (ctrl *PaymentController) GetPayments(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID", err.Error())
+		return
+	}
+
+	summary, err := ctrl.paymentService.GetPayments(actor, id)
+	if err != nil {
+		paymentErrorResponse(c, "Failed to retrieve payments", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Payments retrieved successfully", summary)
+}
+
+// Pay godoc
+// @Summary Pay order by card
+// @Description Charge a card token through the payment provider. Without an amount the outstanding balance is paid; "capture": false only authorizes it.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Param payment body model.PaymentRequest true "Card payment"
+// @Success 201 {object} utils.Response
+// @Failure 402 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /orders/{id}/payments [post]
+func (ctrl *PaymentController) Pay(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID", err.Error())
+		return
+	}
+
+	var req model.PaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	payment, err := ctrl.paymentService.Pay(actor, id, req)
+	if err != nil {
+		paymentErrorResponse(c, "Payment failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Payment processed successfully", payment)
+}
+
+// RecordManual godoc
+// @Summary Record cash or terminal payment (Staff)
+// @Description Record a cash payment, with the change for the tendered cash, or a card payment taken on the branch terminal
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Param payment body model.ManualPaymentRequest true "Manual payment"
+// @Success 201 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /orders/{id}/payments/manual [post]
+func (ctrl *PaymentController) RecordManual(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID", err.Error())
+		return
+	}
+
+	var req model.ManualPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	payment, err := ctrl.paymentService.RecordManual(actor, id, req)
+	if err != nil {
+		paymentErrorResponse(c, "Payment recording failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Payment recorded successfully", payment)
+}
+
+// Capture godoc
+// @Summary Capture payment (Staff)
+// @Description Capture an authorized card payment, in full or for a lower amount
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Payment ID"
+// @Param capture body model.CaptureRequest false "Amount to capture"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /payments/{id}/capture [post]
+func (ctrl *PaymentController) Capture(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid payment ID", err.Error())
+		return
+	}
+
+	var req model.CaptureRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+
+	payment, err := ctrl.paymentService.Capture(actor, id, req)
+	if err != nil {
+		paymentErrorResponse(c, "Payment capture failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Payment captured successfully", payment)
+}
+
+// Void godoc
+// @Summary Void payment (Staff)
+// @Description Release an authorized card payment that has not been captured
+// @Tags payments
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Payment ID"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /payments/{id}/void [post]
+func (ctrl *PaymentController) Void(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid payment ID", err.Error())
+		return
+	}
+
+	payment, err := ctrl.paymentService.Void(actor, id)
+	if err != nil {
+		paymentErrorResponse(c, "Payment void failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Payment voided successfully", payment)
+}
+
+// Webhook godoc
+// @Summary Payment provider webhook
+// @Description Receive a payment state change from the provider. The body must be signed in the X-Webhook-Signature header as "t=<unix time>,v1=<HMAC-SHA256 of '<unix time>.<body>'>". Redelivered events are acknowledged without being applied again.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param X-Webhook-Signature header string true "Webhook signature"
+// @Param event body model.WebhookPayload true "Webhook event"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /payments/webhooks/{provider} [post]
+func (ctrl *PaymentController) Webhook(c *gin.Context) {
+	signature := c.GetHeader(SignatureHeader)
+	if signature == "" {
+		utils.ErrorResponseWithCode(c, http.StatusUnauthorized, ErrCodeSignatureRequired, "Webhook rejected", "missing "+SignatureHeader+" header")
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Webhook rejected", err.Error())
+		return
+	}
+
+	event, err := ctrl.paymentService.HandleWebhook(c.Param("provider"), body, signature)
+	if err != nil {
+		paymentErrorResponse(c, "Webhook rejected", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhook processed successfully", event)
+}
+
+func paymentErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, gateway.ErrDeclined):
+		utils.ErrorResponseWithCode(c, http.StatusPaymentRequired, ErrCodePaymentDeclined, message, err.Error())
+	case errors.Is(err, service.ErrPaymentPending):
+		utils.ErrorResponseWithCode(c, http.StatusBadGateway, ErrCodePaymentPending, message, err.Error())
+	case errors.Is(err, service.ErrRefundPending):
+		utils.ErrorResponseWithCode(c, http.StatusBadGateway, ErrCodeRefundPending, message, err.Error())
+	case errors.Is(err, gateway.ErrInvalidSignature):
+		utils.ErrorResponseWithCode(c, http.StatusUnauthorized, ErrCodeInvalidSignature, message, err.Error())
+	case errors.Is(err, service.ErrOrderNotPayable):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeOrderNotPayable, message, err.Error())
+	case errors.Is(err, service.ErrNothingOutstanding),
+		errors.Is(err, service.ErrAmountExceeds),
+		errors.Is(err, gateway.ErrInvalidAmount):
+		utils.ErrorResponseWithCode(c, http.StatusUnprocessableEntity, ErrCodeAmountExceeds, message, err.Error())
+	case errors.Is(err, service.ErrPaymentState),
+		errors.Is(err, gateway.ErrInvalidState),
+		errors.Is(err, gateway.ErrUnknownReference):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodePaymentState, message, err.Error())
+	case errors.Is(err, service.ErrNothingRefundable),
+		errors.Is(err, service.ErrRefundExceeds),
+		errors.Is(err, service.ErrRefundQuantity):
+		utils.ErrorResponseWithCode(c, http.StatusUnprocessableEntity, ErrCodeRefundExceeds, message, err.Error())
+	case errors.Is(err, service.ErrBillRequired):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeBillRequired, message, err.Error())
+	case errors.Is(err, service.ErrSplitLocked):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeBillsLocked, message, err.Error())
+	case errors.Is(err, service.ErrSplitMismatch),
+		errors.Is(err, service.ErrSplitIncomplete):
+		utils.ErrorResponseWithCode(c, http.StatusUnprocessableEntity, ErrCodeSplitMismatch, message, err.Error())
+	case errors.Is(err, service.ErrTenderedTooLow),
+		errors.Is(err, service.ErrTipStaffNotFound):
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, message, err.Error())
+	case errors.Is(err, service.ErrInvalidPayload),
+		errors.Is(err, service.ErrAmountAndLines),
+		errors.Is(err, service.ErrInvalidSplit),
+		errors.Is(err, service.ErrUnknownOrderLine),
+		errors.Is(err, service.ErrInvalidDate),
+		errors.Is(err, service.ErrInvalidRange):
+		utils.ErrorResponse(c, http.StatusBadRequest, message, err.Error())
+	default:
+		utils.DatabaseErrorResponse(c, message, err)
+	}
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/faisd405/go-restapi-gin/src/app/payment/model"
+	"github.com/faisd405/go-restapi-gin/src/app/payment/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type RefundController struct {
+	refundService service.RefundService
+}
+
+func NewRefundController(refundService service.RefundService) *RefundController {
+	return &RefundController{refundService: refundService}
+}
+
+// GetRefunds godoc
+// @Summary Get order refunds (Staff)
+// @Description Get the refund groups of an order with their lines, reasons, who made them and the refund of each payment
+// @Tags refunds
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /orders/{id}/refunds [get]
+func (ctrl *RefundController) GetRefunds(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID", err.Error())
+		return
+	}
+
+	refunds, err := ctrl.refundService.GetRefunds(actor, id)
+	if err != nil {
+		paymentErrorResponse(c, "Failed to retrieve refunds", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Refunds retrieved successfully", refunds)
+}
+
+// Refund godoc
+// @Summary Refund order (Admin/Manager)
+// @Description Refund order lines, an amount, or everything still refundable. Card payments are refunded through the payment provider; the total never exceeds the captured amount. Returns the refund group with one refund per payment the amount was taken from.
+// @Tags refunds
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Param refund body model.RefundRequest true "Refund"
+// @Success 201 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /orders/{id}/refunds [post]
+func (ctrl *RefundController) Refund(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID", err.Error())
+		return
+	}
+
+	var req model.RefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	group, err := ctrl.refundService.Refund(actor, id, req)
+	if err != nil {
+		paymentErrorResponse(c, "Refund failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Refund processed successfully", group)
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/faisd405/go-restapi-gin/src/app/payment/model"
+	"github.com/faisd405/go-restapi-gin/src/app/payment/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ReportController struct {
+	reportService service.ReportService
+}
+
+func NewReportController(reportService service.ReportService) *ReportController {
+	return &ReportController{reportService: reportService}
+}
+
+// GetSalesReport godoc
+// @Summary Get sales report (Admin/Manager)
+// @Description List the captured payments and, as negative entries, the refunds of a branch with gross, refund and net totals
+// @Tags reports
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param from query string false "First local date (YYYY-MM-DD), defaults to today"
+// @Param to query string false "Last local date (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/reports/sales [get]
+func (ctrl *ReportController) GetSalesReport(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	filter := model.ReportFilter{From: c.Query("from"), To: c.Query("to")}
+	report, err := ctrl.reportService.GetSalesReport(actor, id, filter)
+	if err != nil {
+		paymentErrorResponse(c, "Failed to retrieve sales report", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Sales report retrieved successfully", report)
+}
+
+// GetTipReport godoc
+// @Summary Get tip report (Admin/Manager)
+// @Description Add up the tips of captured payments per staff member for payout
+// @Tags reports
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param from query string false "First local date (YYYY-MM-DD), defaults to today"
+// @Param to query string false "Last local date (YYYY-MM-DD), defaults to today"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/reports/tips [get]
+func (ctrl *ReportController) GetTipReport(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	filter := model.ReportFilter{From: c.Query("from"), To: c.Query("to")}
+	report, err := ctrl.reportService.GetTipReport(actor, id, filter)
+	if err != nil {
+		paymentErrorResponse(c, "Failed to retrieve tip report", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tip report retrieved successfully", report)
+}
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/database"
+)
+
+// Ways of splitting an order into bills
+const (
+	SplitItems  = "items"
+	SplitEqual  = "equal"
+	SplitCustom = "custom"
+)
+
+// Bill statuses, derived from the payments of a bill
+const (
+	BillOpen = "open"
+	BillPaid = "paid"
+)
+
+// Bill is a share of an order's balance that is paid on its own. Once an
+// order is split, every payment names one of its bills. Bills split by
+// items list the order lines they cover; their amounts also carry a share
+// of the service charge and rounding.
+type Bill struct {
+	ID        uint                        `json:"id" gorm:"primaryKey"`
+	OrderID   uint                        `json:"order_id" gorm:"not null;index"`
+	BranchID  uint                        `json:"branch_id" gorm:"not null;index"`
+	Position  int                         `json:"position" gorm:"not null"`
+	Label     string                      `json:"label" gorm:"type:varchar(100);not null"`
+	Mode      string                      `json:"mode" gorm:"type:varchar(20);not null"`
+	Currency  string                      `json:"currency" gorm:"type:char(3);not null"`
+	Amount    int64                       `json:"amount" gorm:"not null"`
+	Lines     database.JSONList[BillLine] `json:"lines" gorm:"type:jsonb;not null;default:'[]'"`
+	CreatedAt time.Time                   `json:"created_at"`
+	UpdatedAt time.Time                   `json:"updated_at"`
+}
+
+func (Bill) TableName() string {
+	return "order_bills"
+}
+
+// BillLine is the part of an order line a bill covers
+type BillLine struct {
+	OrderLineID uint   `json:"order_line_id"`
+	Name        string `json:"name"`
+	Quantity    int    `json:"quantity"`
+	Amount      int64  `json:"amount"`
+}
+
+// SplitRequest splits the balance of an order into bills: by items, with
+// every order line assigned to a bill; into equal shares; or into custom
+// amounts that add up to the balance.
+type SplitRequest struct {
+	Mode    string             `json:"mode" binding:"required,oneof=items equal custom"`
+	Shares  int                `json:"shares" binding:"omitempty,min=2,max=50"`
+	Amounts []int64            `json:"amounts" binding:"omitempty,min=2,max=50,dive,min=1"`
+	Bills   []SplitBillRequest `json:"bills" binding:"omitempty,min=2,max=50,dive"`
+}
+
+type SplitBillRequest struct {
+	Label string            `json:"label" binding:"max=100"`
+	Lines []BillLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+type BillLineRequest struct {
+	OrderLineID uint `json:"order_line_id" binding:"required"`
+	Quantity    int  `json:"quantity" binding:"required,min=1"`
+}
+
+// BillSummary is the payment state of a bill
+type BillSummary struct {
+	Bill
+	Status      string `json:"status"`
+	Paid        int64  `json:"paid"`
+	Pending     int64  `json:"pending"`
+	Outstanding int64  `json:"outstanding"`
+}
+
+// SummarizeBills adds up the payments of each bill
+func SummarizeBills(bills []Bill, payments []Payment) []BillSummary {
+	summaries := make([]BillSummary, len(bills))
+	index := make(map[uint]int, len(bills))
+	for i, bill := range bills {
+		summaries[i] = BillSummary{Bill: bill}
+		index[bill.ID] = i
+	}
+
+	for _, p := range payments {
+		if p.BillID == nil {
+			continue
+		}
+		i, ok := index[*p.BillID]
+		if !ok {
+			continue
+		}
+		switch {
+		case p.Status == StatusCaptured:
+			summaries[i].Paid += p.CapturedAmount
+		case p.IsOpen():
+			summaries[i].Pending += p.Amount
+		}
+	}
+
+	for i := range summaries {
+		s := &summaries[i]
+		s.Outstanding = max(s.Amount-s.Paid-s.Pending, 0)
+		s.Status = BillOpen
+		if s.Paid >= s.Amount {
+			s.Status = BillPaid
+		}
+	}
+	return summaries
+}
+
+// CanSplit reports whether the bills of an order may be replaced: no
+// payment is in progress and none of its bills was paid
+func CanSplit(payments []Payment) bool {
+	for _, p := range payments {
+		if p.IsOpen() || (p.BillID != nil && p.Status == StatusCaptured) {
+			return false
+		}
+	}
+	return true
+}
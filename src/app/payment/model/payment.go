@@ -0,0 +1,170 @@
+package model
+
+import (
+	"time"
+)
+
+// Payment methods. Card payments go through the payment provider; cash and
+// card-on-terminal payments are recorded by staff.
+const (
+	MethodCard     = "card"
+	MethodCash     = "cash"
+	MethodTerminal = "terminal"
+)
+
+// Payment statuses
+const (
+	StatusPending    = "pending"
+	StatusAuthorized = "authorized"
+	StatusCaptured   = "captured"
+	StatusVoided     = "voided"
+	StatusFailed     = "failed"
+)
+
+// ProviderManual is the provider of payments staff record by hand
+const ProviderManual = "manual"
+
+// Payment is money taken for an order. Card payments start pending, are
+// authorized and then captured by the provider; their state may also be
+// advanced by provider webhooks. Manual payments are captured when
+// recorded. RefundedAmount includes refunds still pending with the
+// provider. Tip is charged on top of Amount and paid out to TipStaffID; it
+// does not count towards the order balance. Amounts are in the minor unit
+// of Currency.
+type Payment struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	OrderID        uint       `json:"order_id" gorm:"not null;index"`
+	BranchID       uint       `json:"branch_id" gorm:"not null;index"`
+	BillID         *uint      `json:"bill_id" gorm:"index"`
+	Method         string     `json:"method" gorm:"type:varchar(20);not null"`
+	Provider       string     `json:"provider" gorm:"type:varchar(30);not null;uniqueIndex:idx_payments_provider_ref"`
+	ProviderRef    *string    `json:"provider_ref" gorm:"type:varchar(100);uniqueIndex:idx_payments_provider_ref"`
+	Status         string     `json:"status" gorm:"type:varchar(20);not null;index"`
+	Currency       string     `json:"currency" gorm:"type:char(3);not null"`
+	Amount         int64      `json:"amount" gorm:"not null"`
+	CapturedAmount int64      `json:"captured_amount" gorm:"not null;default:0"`
+	RefundedAmount int64      `json:"refunded_amount" gorm:"not null;default:0"`
+	Tip            int64      `json:"tip" gorm:"not null;default:0"`
+	TipStaffID     *uint      `json:"tip_staff_id" gorm:"index"`
+	Tendered       int64      `json:"tendered" gorm:"not null;default:0"`
+	Change         int64      `json:"change" gorm:"not null;default:0"`
+	Reference      string     `json:"reference" gorm:"type:varchar(100)"`
+	FailureReason  string     `json:"failure_reason,omitempty" gorm:"type:text"`
+	CreatedByID    *uint      `json:"created_by_id"`
+	AuthorizedAt   *time.Time `json:"authorized_at"`
+	CapturedAt     *time.Time `json:"captured_at"`
+	VoidedAt       *time.Time `json:"voided_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// IsOpen reports whether the payment still holds part of the order balance
+// without having been captured
+func (p *Payment) IsOpen() bool {
+	return p.Status == StatusPending || p.Status == StatusAuthorized
+}
+
+// Charge is the amount the provider charges, tip included
+func (p *Payment) Charge() int64 {
+	return p.Amount + p.Tip
+}
+
+// MoveTo moves the payment to status at t and reports whether it did.
+// Amount is what the provider captured, tip included. Payments only move
+// forward, so outcomes reported late or twice, by the provider call and
+// its webhook, leave the payment alone.
+func (p *Payment) MoveTo(status string, amount int64, t time.Time) bool {
+	if !p.IsOpen() || p.Status == status {
+		return false
+	}
+	if p.Status == StatusAuthorized && status == StatusPending {
+		return false
+	}
+
+	switch status {
+	case StatusAuthorized:
+		p.AuthorizedAt = &t
+	case StatusCaptured:
+		if p.AuthorizedAt == nil {
+			p.AuthorizedAt = &t
+		}
+		p.CapturedAt = &t
+		p.CapturedAmount = max(amount-p.Tip, 0)
+	case StatusVoided:
+		p.VoidedAt = &t
+	case StatusFailed:
+	default:
+		return false
+	}
+	p.Status = status
+	return true
+}
+
+// PaymentRequest pays an order, or one of its bills, by card. Without an
+// amount the outstanding balance is paid; with capture set to false the
+// amount is only authorized and captured later by staff. A tip is charged
+// on top and goes to TipStaffID, or to the staff member taking the
+// payment.
+type PaymentRequest struct {
+	BillID     *uint  `json:"bill_id"`
+	Amount     int64  `json:"amount" binding:"min=0"`
+	Tip        int64  `json:"tip" binding:"min=0"`
+	TipStaffID *uint  `json:"tip_staff_id"`
+	Token      string `json:"token" binding:"required,max=255"`
+	Capture    *bool  `json:"capture"`
+}
+
+// ManualPaymentRequest records cash or a card-on-terminal payment taken by
+// staff. Tendered is the cash handed over for the amount and tip; the
+// change is computed.
+type ManualPaymentRequest struct {
+	BillID     *uint  `json:"bill_id"`
+	Method     string `json:"method" binding:"required,oneof=cash terminal"`
+	Amount     int64  `json:"amount" binding:"min=0"`
+	Tip        int64  `json:"tip" binding:"min=0"`
+	TipStaffID *uint  `json:"tip_staff_id"`
+	Tendered   int64  `json:"tendered" binding:"min=0"`
+	Reference  string `json:"reference" binding:"max=100"`
+}
+
+// CaptureRequest captures an authorized payment, in full without an
+// amount
+type CaptureRequest struct {
+	Amount int64 `json:"amount" binding:"min=0"`
+}
+
+// PaymentSummary is the payment state of an order. Pending counts the
+// payments that are authorized but not yet captured. Refunds do not reopen
+// the balance, so Outstanding ignores them.
+type PaymentSummary struct {
+	OrderID     uint      `json:"order_id"`
+	Currency    string    `json:"currency"`
+	Total       int64     `json:"total"`
+	Paid        int64     `json:"paid"`
+	Pending     int64     `json:"pending"`
+	Refunded    int64     `json:"refunded"`
+	Tips        int64     `json:"tips"`
+	Outstanding int64     `json:"outstanding"`
+	Payments    []Payment `json:"payments"`
+}
+
+// Summarize adds up the payments of an order with the given total
+func Summarize(orderID uint, currency string, total int64, payments []Payment) *PaymentSummary {
+	summary := &PaymentSummary{OrderID: orderID, Currency: currency, Total: total, Payments: payments}
+	for _, p := range payments {
+		switch {
+		case p.Status == StatusCaptured:
+			summary.Paid += p.CapturedAmount
+			summary.Refunded += p.RefundedAmount
+			summary.Tips += p.Tip
+		case p.IsOpen():
+			summary.Pending += p.Amount
+		}
+	}
+
+	summary.Outstanding = total - summary.Paid - summary.Pending
+	if summary.Outstanding < 0 {
+		summary.Outstanding = 0
+	}
+	return summary
+}
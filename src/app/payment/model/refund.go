@@ -0,0 +1,127 @@
+package model
+
+import (
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/database"
+)
+
+// Refund statuses. Card refunds are pending while the provider is called;
+// refunds of manual payments are handed back by staff and succeed at once.
+// A refund group that is partly refunded had some of its refunds fail.
+const (
+	RefundPending   = "pending"
+	RefundSucceeded = "succeeded"
+	RefundFailed    = "failed"
+	RefundPartial   = "partial"
+)
+
+// RefundGroup is one refund request. It keeps the refunded lines and is
+// split into one refund per payment the amount is taken from. Its status
+// follows its refunds.
+type RefundGroup struct {
+	ID          uint                          `json:"id" gorm:"primaryKey"`
+	OrderID     uint                          `json:"order_id" gorm:"not null;index"`
+	BranchID    uint                          `json:"branch_id" gorm:"not null;index"`
+	Status      string                        `json:"status" gorm:"type:varchar(20);not null;index"`
+	Currency    string                        `json:"currency" gorm:"type:char(3);not null"`
+	Amount      int64                         `json:"amount" gorm:"not null"`
+	Reason      string                        `json:"reason" gorm:"type:text;not null"`
+	Lines       database.JSONList[RefundLine] `json:"lines" gorm:"type:jsonb;not null;default:'[]'"`
+	CreatedByID *uint                         `json:"created_by_id"`
+	Refunds     []Refund                      `json:"refunds" gorm:"foreignKey:GroupID"`
+	CreatedAt   time.Time                     `json:"created_at"`
+	UpdatedAt   time.Time                     `json:"updated_at"`
+}
+
+// Follow sets the status of the group from that of its refunds: pending
+// while one is, succeeded or failed when all are, and partial when some
+// failed and others went through
+func (g *RefundGroup) Follow(refunds []Refund) {
+	counts := map[string]int{}
+	for _, r := range refunds {
+		counts[r.Status]++
+	}
+
+	switch {
+	case counts[RefundPending] > 0:
+		g.Status = RefundPending
+	case counts[RefundFailed] == 0:
+		g.Status = RefundSucceeded
+	case counts[RefundSucceeded] == 0:
+		g.Status = RefundFailed
+	default:
+		g.Status = RefundPartial
+	}
+}
+
+// Refund is money given back from one captured payment for a refund
+// group. Refunds are never changed after they settle and form the audit
+// trail of who refunded what and why.
+type Refund struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	GroupID       uint       `json:"group_id" gorm:"not null;index"`
+	PaymentID     uint       `json:"payment_id" gorm:"not null;index"`
+	OrderID       uint       `json:"order_id" gorm:"not null;index"`
+	BranchID      uint       `json:"branch_id" gorm:"not null;index"`
+	Method        string     `json:"method" gorm:"type:varchar(20);not null"`
+	Provider      string     `json:"provider" gorm:"type:varchar(30);not null"`
+	ProviderRef   *string    `json:"provider_ref" gorm:"type:varchar(100)"`
+	Status        string     `json:"status" gorm:"type:varchar(20);not null;index"`
+	Currency      string     `json:"currency" gorm:"type:char(3);not null"`
+	Amount        int64      `json:"amount" gorm:"not null"`
+	Reason        string     `json:"reason" gorm:"type:text;not null"`
+	FailureReason string     `json:"failure_reason,omitempty" gorm:"type:text"`
+	CreatedByID   *uint      `json:"created_by_id"`
+	RefundedAt    *time.Time `json:"refunded_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// RefundLine is the part of an order line a refund gives back
+type RefundLine struct {
+	OrderLineID uint   `json:"order_line_id"`
+	Name        string `json:"name"`
+	Quantity    int    `json:"quantity"`
+	Amount      int64  `json:"amount"`
+}
+
+// RefundRequest refunds an order. Lines refund items at their price after
+// discounts and with tax; otherwise Amount is refunded, or everything still
+// refundable without one. Without a payment the amount is taken from the
+// captured payments, newest first.
+type RefundRequest struct {
+	PaymentID *uint               `json:"payment_id"`
+	Amount    int64               `json:"amount" binding:"min=0"`
+	Lines     []RefundLineRequest `json:"lines" binding:"omitempty,dive"`
+	Reason    string              `json:"reason" binding:"required,max=500"`
+}
+
+type RefundLineRequest struct {
+	OrderLineID uint `json:"order_line_id" binding:"required"`
+	Quantity    int  `json:"quantity" binding:"required,min=1"`
+}
+
+// Refundable is the captured amount of the payment not yet refunded
+func (p *Payment) Refundable() int64 {
+	if p.Status != StatusCaptured {
+		return 0
+	}
+	return p.CapturedAmount - p.RefundedAmount
+}
+
+// RefundedQuantities adds up the quantities refunded per order line by the
+// refund groups that have not failed. Partly refunded groups count, since
+// the customer got part of the money back; the rest is refunded by amount.
+func RefundedQuantities(groups []RefundGroup) map[uint]int {
+	quantities := map[uint]int{}
+	for _, g := range groups {
+		if g.Status == RefundFailed {
+			continue
+		}
+		for _, line := range g.Lines {
+			quantities[line.OrderLineID] += line.Quantity
+		}
+	}
+	return quantities
+}
@@ -0,0 +1,143 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// Sales entry types
+const (
+	EntryPayment = "payment"
+	EntryRefund  = "refund"
+)
+
+// SalesEntry is a captured payment or a refund of a branch. Refunds are
+// negative.
+type SalesEntry struct {
+	Type      string    `json:"type"`
+	ID        uint      `json:"id"`
+	PaymentID uint      `json:"payment_id"`
+	OrderID   uint      `json:"order_id"`
+	Method    string    `json:"method"`
+	Currency  string    `json:"currency"`
+	Amount    int64     `json:"amount"`
+	Reason    string    `json:"reason,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+// SalesReport lists the money taken and given back by a branch between two
+// local dates, both included
+type SalesReport struct {
+	BranchID uint             `json:"branch_id"`
+	From     string           `json:"from"`
+	To       string           `json:"to"`
+	Gross    int64            `json:"gross"`
+	Refunds  int64            `json:"refunds"`
+	Net      int64            `json:"net"`
+	ByMethod map[string]int64 `json:"by_method"`
+	Entries  []SalesEntry     `json:"entries"`
+}
+
+// ReportFilter selects the local dates of a report, formatted as
+// YYYY-MM-DD. Both default to today.
+type ReportFilter struct {
+	From string
+	To   string
+}
+
+// NewSalesReport adds up the captured payments and settled refunds in
+// chronological order
+func NewSalesReport(branchID uint, from, to string, payments []Payment, refunds []Refund) *SalesReport {
+	report := &SalesReport{BranchID: branchID, From: from, To: to, ByMethod: map[string]int64{}, Entries: []SalesEntry{}}
+
+	for _, p := range payments {
+		report.add(SalesEntry{
+			Type:      EntryPayment,
+			ID:        p.ID,
+			PaymentID: p.ID,
+			OrderID:   p.OrderID,
+			Method:    p.Method,
+			Currency:  p.Currency,
+			Amount:    p.CapturedAmount,
+			At:        *p.CapturedAt,
+		})
+	}
+	for _, r := range refunds {
+		report.add(SalesEntry{
+			Type:      EntryRefund,
+			ID:        r.ID,
+			PaymentID: r.PaymentID,
+			OrderID:   r.OrderID,
+			Method:    r.Method,
+			Currency:  r.Currency,
+			Amount:    -r.Amount,
+			Reason:    r.Reason,
+			At:        *r.RefundedAt,
+		})
+	}
+
+	sort.SliceStable(report.Entries, func(i, j int) bool { return report.Entries[i].At.Before(report.Entries[j].At) })
+	return report
+}
+
+func (r *SalesReport) add(entry SalesEntry) {
+	if entry.Amount < 0 {
+		r.Refunds += entry.Amount
+	} else {
+		r.Gross += entry.Amount
+	}
+	r.Net += entry.Amount
+	r.ByMethod[entry.Method] += entry.Amount
+	r.Entries = append(r.Entries, entry)
+}
+
+// StaffTips is the tips a staff member earned in a tip report. Tips not
+// attributed to anyone are listed without a staff ID.
+type StaffTips struct {
+	StaffID  *uint  `json:"staff_id"`
+	Name     string `json:"name"`
+	Payments int    `json:"payments"`
+	Tips     int64  `json:"tips"`
+}
+
+// TipReport adds up the tips of captured payments per staff member for
+// payout between two local dates, both included
+type TipReport struct {
+	BranchID uint        `json:"branch_id"`
+	From     string      `json:"from"`
+	To       string      `json:"to"`
+	Total    int64       `json:"total"`
+	Staff    []StaffTips `json:"staff"`
+}
+
+// NewTipReport adds up the tips of payments per staff member, naming them
+// from names. Staff are listed by their tips, highest first.
+func NewTipReport(branchID uint, from, to string, payments []Payment, names map[uint]string) *TipReport {
+	report := &TipReport{BranchID: branchID, From: from, To: to, Staff: []StaffTips{}}
+
+	// Unattributed tips are kept under staff ID 0
+	index := map[uint]int{}
+	for _, p := range payments {
+		if p.Tip == 0 {
+			continue
+		}
+
+		var staffID uint
+		if p.TipStaffID != nil {
+			staffID = *p.TipStaffID
+		}
+		i, ok := index[staffID]
+		if !ok {
+			report.Staff = append(report.Staff, StaffTips{StaffID: p.TipStaffID, Name: names[staffID]})
+			i = len(report.Staff) - 1
+			index[staffID] = i
+		}
+
+		report.Staff[i].Payments++
+		report.Staff[i].Tips += p.Tip
+		report.Total += p.Tip
+	}
+
+	sort.SliceStable(report.Staff, func(i, j int) bool { return report.Staff[i].Tips > report.Staff[j].Tips })
+	return report
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"time"
+)
+
+// Webhook event types sent by payment providers
+const (
+	EventAuthorized = "payment.authorized"
+	EventCaptured   = "payment.captured"
+	EventVoided     = "payment.voided"
+	EventFailed     = "payment.failed"
+)
+
+// Webhook event outcomes. Events for unknown payments or that would move a
+// payment backwards are ignored.
+const (
+	OutcomeApplied = "applied"
+	OutcomeIgnored = "ignored"
+)
+
+// WebhookEvent is a processed provider webhook. The provider and event ID
+// are unique so redelivered events are not applied twice.
+type WebhookEvent struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Provider  string    `json:"provider" gorm:"type:varchar(30);not null;uniqueIndex:idx_payment_webhook_events_event"`
+	EventID   string    `json:"event_id" gorm:"type:varchar(100);not null;uniqueIndex:idx_payment_webhook_events_event"`
+	Type      string    `json:"type" gorm:"type:varchar(50);not null"`
+	Reference string    `json:"reference" gorm:"type:varchar(100);not null"`
+	Amount    int64     `json:"amount" gorm:"not null;default:0"`
+	PaymentID *uint     `json:"payment_id" gorm:"index"`
+	Outcome   string    `json:"outcome" gorm:"type:varchar(20);not null"`
+	Duplicate bool      `json:"duplicate" gorm:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (WebhookEvent) TableName() string {
+	return "payment_webhook_events"
+}
+
+// WebhookPayload is the body of a provider webhook. Reference is the
+// provider reference of the payment.
+type WebhookPayload struct {
+	ID        string `json:"id" binding:"required,max=100"`
+	Type      string `json:"type" binding:"required,max=50"`
+	Reference string `json:"reference" binding:"required,max=100"`
+	Amount    int64  `json:"amount" binding:"min=0"`
+	Reason    string `json:"reason" binding:"max=500"`
+}
+
+// StatusForEvent returns the payment status a webhook event type reports
+func StatusForEvent(eventType string) (string, bool) {
+	switch eventType {
+	case EventAuthorized:
+		return StatusAuthorized, true
+	case EventCaptured:
+		return StatusCaptured, true
+	case EventVoided:
+		return StatusVoided, true
+	case EventFailed:
+		return StatusFailed, true
+	}
+	return "", false
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"github.com/faisd405/go-restapi-gin/src/app/payment/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lockNamespace keeps the advisory locks of payments apart from other
+// users of pg_advisory_xact_lock
+const lockNamespace = 42
+
+type PaymentRepository interface {
+	Create(payment *model.Payment) error
+	GetByID(id uint) (*model.Payment, error)
+	GetForUpdate(id uint) (*model.Payment, error)
+	// GetByProviderRef loads and locks the payment a provider knows by
+	// reference
+	GetByProviderRef(provider, reference string) (*model.Payment, error)
+	Update(payment *model.Payment) error
+	Find(q *database.Query) ([]model.Payment, error)
+	// LockOrder serializes payments of an order until the surrounding
+	// transaction ends
+	LockOrder(orderID uint) error
+	// Refunded sums what was refunded of the payments of an order,
+	// including refunds still pending
+	Refunded(orderID uint) (int64, error)
+	WithTx(tx *gorm.DB) PaymentRepository
+}
+
+type paymentRepository struct {
+	database.Repository[model.Payment]
+}
+
+func NewPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &paymentRepository{Repository: database.NewRepository[model.Payment](db)}
+}
+
+func (r *paymentRepository) GetForUpdate(id uint) (*model.Payment, error) {
+	var payment model.Payment
+	if err := r.DB().Clauses(clause.Locking{Strength: "UPDATE"}).First(&payment, id).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepository) GetByProviderRef(provider, reference string) (*model.Payment, error) {
+	var payment model.Payment
+	err := r.DB().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("provider = ? AND provider_ref = ?", provider, reference).
+		First(&payment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepository) LockOrder(orderID uint) error {
+	return r.DB().Exec("SELECT pg_advisory_xact_lock(?, ?)", lockNamespace, orderID).Error
+}
+
+func (r *paymentRepository) Refunded(orderID uint) (int64, error) {
+	var amount int64
+	err := r.DB().Model(&model.Payment{}).
+		Where("order_id = ?", orderID).
+		Select("COALESCE(SUM(refunded_amount), 0)").
+		Scan(&amount).Error
+	return amount, err
+}
+
+func (r *paymentRepository) WithTx(tx *gorm.DB) PaymentRepository {
+	return &paymentRepository{Repository: r.Repository.WithTx(tx)}
+}
+
+type WebhookEventRepository interface {
+	Create(event *model.WebhookEvent) error
+	// GetByEventID returns an event a provider delivered before
+	GetByEventID(provider, eventID string) (*model.WebhookEvent, error)
+	WithTx(tx *gorm.DB) WebhookEventRepository
+}
+
+type webhookEventRepository struct {
+	database.Repository[model.WebhookEvent]
+}
+
+func NewWebhookEventRepository(db *gorm.DB) WebhookEventRepository {
+	return &webhookEventRepository{Repository: database.NewRepository[model.WebhookEvent](db)}
+}
+
+func (r *webhookEventRepository) GetByEventID(provider, eventID string) (*model.WebhookEvent, error) {
+	return r.First(database.NewQuery().Eq("provider", provider).Eq("event_id", eventID))
+}
+
+func (r *webhookEventRepository) WithTx(tx *gorm.DB) WebhookEventRepository {
+	return &webhookEventRepository{Repository: r.Repository.WithTx(tx)}
+}
+
+type RefundRepository interface {
+	Create(refund *model.Refund) error
+	GetByID(id uint) (*model.Refund, error)
+	GetForUpdate(id uint) (*model.Refund, error)
+	Update(refund *model.Refund) error
+	Find(q *database.Query) ([]model.Refund, error)
+	WithTx(tx *gorm.DB) RefundRepository
+}
+
+type refundRepository struct {
+	database.Repository[model.Refund]
+}
+
+func NewRefundRepository(db *gorm.DB) RefundRepository {
+	return &refundRepository{Repository: database.NewRepository[model.Refund](db)}
+}
+
+func (r *refundRepository) GetForUpdate(id uint) (*model.Refund, error) {
+	var refund model.Refund
+	if err := r.DB().Clauses(clause.Locking{Strength: "UPDATE"}).First(&refund, id).Error; err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+func (r *refundRepository) WithTx(tx *gorm.DB) RefundRepository {
+	return &refundRepository{Repository: r.Repository.WithTx(tx)}
+}
+
+type RefundGroupRepository interface {
+	Create(group *model.RefundGroup) error
+	First(q *database.Query) (*model.RefundGroup, error)
+	GetForUpdate(id uint) (*model.RefundGroup, error)
+	Update(group *model.RefundGroup) error
+	Find(q *database.Query) ([]model.RefundGroup, error)
+	WithTx(tx *gorm.DB) RefundGroupRepository
+}
+
+type refundGroupRepository struct {
+	database.Repository[model.RefundGroup]
+}
+
+func NewRefundGroupRepository(db *gorm.DB) RefundGroupRepository {
+	return &refundGroupRepository{Repository: database.NewRepository[model.RefundGroup](db)}
+}
+
+func (r *refundGroupRepository) GetForUpdate(id uint) (*model.RefundGroup, error) {
+	var group model.RefundGroup
+	if err := r.DB().Clauses(clause.Locking{Strength: "UPDATE"}).First(&group, id).Error; err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *refundGroupRepository) WithTx(tx *gorm.DB) RefundGroupRepository {
+	return &refundGroupRepository{Repository: r.Repository.WithTx(tx)}
+}
+
+type BillRepository interface {
+	Create(bill *model.Bill) error
+	Find(q *database.Query) ([]model.Bill, error)
+	// DeleteByOrder removes the bills an order was split into
+	DeleteByOrder(orderID uint) error
+	WithTx(tx *gorm.DB) BillRepository
+}
+
+type billRepository struct {
+	database.Repository[model.Bill]
+}
+
+func NewBillRepository(db *gorm.DB) BillRepository {
+	return &billRepository{Repository: database.NewRepository[model.Bill](db)}
+}
+
+func (r *billRepository) DeleteByOrder(orderID uint) error {
+	return r.DB().Where("order_id = ?", orderID).Delete(&model.Bill{}).Error
+}
+
+func (r *billRepository) WithTx(tx *gorm.DB) BillRepository {
+	return &billRepository{Repository: r.Repository.WithTx(tx)}
+}
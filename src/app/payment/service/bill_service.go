@@ -0,0 +1,246 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	ordermodel "github.com/faisd405/go-restapi-gin/src/app/order/model"
+	orderservice "github.com/faisd405/go-restapi-gin/src/app/order/service"
+	"github.com/faisd405/go-restapi-gin/src/app/payment/model"
+	"github.com/faisd405/go-restapi-gin/src/app/payment/repository"
+	pricingservice "github.com/faisd405/go-restapi-gin/src/app/pricing/service"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidSplit    = errors.New("split needs shares, amounts or bills for its mode")
+	ErrSplitMismatch   = errors.New("bill amounts must add up to the outstanding balance")
+	ErrSplitIncomplete = errors.New("every order line must be assigned to a bill exactly once")
+	ErrSplitLocked     = errors.New("bills cannot be changed while payments are in progress or after a bill was paid")
+)
+
+type BillService interface {
+	GetBills(actor utils.Actor, orderID uint) ([]model.BillSummary, error)
+
+	// Split divides the outstanding balance of an order into bills, which
+	// replace any unpaid bills from an earlier split
+	Split(actor utils.Actor, orderID uint, req model.SplitRequest) ([]model.BillSummary, error)
+}
+
+type billService struct {
+	billRepo    repository.BillRepository
+	paymentRepo repository.PaymentRepository
+	orderSvc    orderservice.OrderService
+	txManager   database.TxManager
+}
+
+func NewBillService(
+	billRepo repository.BillRepository,
+	paymentRepo repository.PaymentRepository,
+	orderSvc orderservice.OrderService,
+	txManager database.TxManager,
+) BillService {
+	return &billService{
+		billRepo:    billRepo,
+		paymentRepo: paymentRepo,
+		orderSvc:    orderSvc,
+		txManager:   txManager,
+	}
+}
+
+func (s *billService) GetBills(actor utils.Actor, orderID uint) ([]model.BillSummary, error) {
+	order, err := s.orderSvc.GetOrder(actor, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	bills, err := s.billRepo.Find(database.NewQuery().Eq("order_id", order.ID).OrderBy("position"))
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.paymentRepo.Find(database.NewQuery().Eq("order_id", order.ID))
+	if err != nil {
+		return nil, err
+	}
+	return model.SummarizeBills(bills, payments), nil
+}
+
+// Split holds the payment lock of the order so no payment starts while
+// its bills are replaced
+func (s *billService) Split(actor utils.Actor, orderID uint, req model.SplitRequest) ([]model.BillSummary, error) {
+	order, err := s.orderSvc.GetOrder(actor, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !isPayable(order) {
+		return nil, ErrOrderNotPayable
+	}
+
+	var summaries []model.BillSummary
+	err = s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		paymentRepo := s.paymentRepo.WithTx(tx)
+		billRepo := s.billRepo.WithTx(tx)
+
+		if err := paymentRepo.LockOrder(order.ID); err != nil {
+			return err
+		}
+		payments, err := paymentRepo.Find(database.NewQuery().Eq("order_id", order.ID))
+		if err != nil {
+			return err
+		}
+		if !model.CanSplit(payments) {
+			return ErrSplitLocked
+		}
+
+		balance := model.Summarize(order.ID, order.Currency, order.Total, payments).Outstanding
+		if balance == 0 {
+			return ErrNothingOutstanding
+		}
+
+		bills, err := splitBills(order, req, balance)
+		if err != nil {
+			return err
+		}
+
+		if err := billRepo.DeleteByOrder(order.ID); err != nil {
+			return err
+		}
+		for i := range bills {
+			if err := billRepo.Create(&bills[i]); err != nil {
+				return err
+			}
+		}
+
+		summaries = model.SummarizeBills(bills, payments)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+// splitBills divides balance into the bills asked for. Bills split by
+// items get the balance in proportion to the price of their lines, which
+// spreads the service charge and any earlier payments over them.
+func splitBills(order *ordermodel.OrderDetails, req model.SplitRequest, balance int64) ([]model.Bill, error) {
+	var amounts []int64
+	var lines [][]model.BillLine
+	var labels []string
+
+	switch req.Mode {
+	case model.SplitEqual:
+		if req.Shares == 0 {
+			return nil, ErrInvalidSplit
+		}
+		weights := make([]int64, req.Shares)
+		for i := range weights {
+			weights[i] = 1
+		}
+		amounts = pricingservice.Allocate(balance, weights)
+	case model.SplitCustom:
+		if len(req.Amounts) == 0 {
+			return nil, ErrInvalidSplit
+		}
+		var total int64
+		for _, amount := range req.Amounts {
+			total += amount
+		}
+		if total != balance {
+			return nil, ErrSplitMismatch
+		}
+		amounts = req.Amounts
+	case model.SplitItems:
+		if len(req.Bills) == 0 {
+			return nil, ErrInvalidSplit
+		}
+		var weights []int64
+		var err error
+		lines, weights, err = billLines(order, req.Bills)
+		if err != nil {
+			return nil, err
+		}
+		amounts = pricingservice.Allocate(balance, weights)
+		for _, bill := range req.Bills {
+			labels = append(labels, bill.Label)
+		}
+	}
+
+	bills := make([]model.Bill, len(amounts))
+	for i, amount := range amounts {
+		bills[i] = model.Bill{
+			OrderID:  order.ID,
+			BranchID: order.BranchID,
+			Position: i + 1,
+			Label:    fmt.Sprintf("Bill %d", i+1),
+			Mode:     req.Mode,
+			Currency: order.Currency,
+			Amount:   amount,
+		}
+		if i < len(labels) && labels[i] != "" {
+			bills[i].Label = labels[i]
+		}
+		if i < len(lines) {
+			bills[i].Lines = lines[i]
+		}
+	}
+	return bills, nil
+}
+
+// billLines assigns the order lines to the requested bills and weighs each
+// bill by the price of its lines. Every unit of every line has to be on
+// exactly one bill.
+func billLines(order *ordermodel.OrderDetails, requested []model.SplitBillRequest) ([][]model.BillLine, []int64, error) {
+	orderLines := make(map[uint]ordermodel.OrderLine, len(order.Lines))
+	for _, line := range order.Lines {
+		orderLines[line.ID] = line
+	}
+
+	assigned := map[uint]int{}
+	lines := make([][]model.BillLine, len(requested))
+	weights := make([]int64, len(requested))
+	for i, bill := range requested {
+		for _, req := range bill.Lines {
+			line, ok := orderLines[req.OrderLineID]
+			if !ok {
+				return nil, nil, ErrUnknownOrderLine
+			}
+			before := assigned[line.ID]
+			after := before + req.Quantity
+			if after > line.Quantity {
+				return nil, nil, ErrSplitIncomplete
+			}
+			assigned[line.ID] = after
+
+			amount := lineAmount(line, before, after)
+			lines[i] = append(lines[i], model.BillLine{
+				OrderLineID: line.ID,
+				Name:        line.Name,
+				Quantity:    req.Quantity,
+				Amount:      amount,
+			})
+			weights[i] += amount
+		}
+	}
+
+	for _, line := range order.Lines {
+		if assigned[line.ID] != line.Quantity {
+			return nil, nil, ErrSplitIncomplete
+		}
+	}
+
+	// Bills of free items only are split evenly
+	var total int64
+	for _, w := range weights {
+		total += w
+	}
+	if total == 0 {
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+	return lines, weights, nil
+}
@@ -0,0 +1,580 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	ordermodel "github.com/faisd405/go-restapi-gin/src/app/order/model"
+	orderservice "github.com/faisd405/go-restapi-gin/src/app/order/service"
+	"github.com/faisd405/go-restapi-gin/src/app/payment/model"
+	"github.com/faisd405/go-restapi-gin/src/app/payment/repository"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/config"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/gateway"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrderNotPayable    = errors.New("order cannot be paid in its current status")
+	ErrNothingOutstanding = errors.New("order has no outstanding balance")
+	ErrAmountExceeds      = errors.New("amount exceeds the outstanding balance")
+	ErrTenderedTooLow     = errors.New("tendered cash is less than the amount")
+	ErrPaymentState       = errors.New("payment cannot be changed in its current status")
+	ErrInvalidPayload     = errors.New("invalid webhook payload")
+	ErrBillRequired       = errors.New("order is split into bills; pay one of them")
+	ErrTipStaffNotFound   = errors.New("tip staff member is not assigned to the branch")
+	ErrPaymentPending     = errors.New("payment outcome is unknown; it is reconciled with the provider")
+)
+
+type PaymentService interface {
+	GetPayments(actor utils.Actor, orderID uint) (*model.PaymentSummary, error)
+	// Pay charges a card through the payment provider
+	Pay(actor utils.Actor, orderID uint, req model.PaymentRequest) (*model.Payment, error)
+	// RecordManual records cash or a card-on-terminal payment taken by
+	// staff
+	RecordManual(actor utils.Actor, orderID uint, req model.ManualPaymentRequest) (*model.Payment, error)
+	Capture(actor utils.Actor, id uint, req model.CaptureRequest) (*model.Payment, error)
+	Void(actor utils.Actor, id uint) (*model.Payment, error)
+
+	// HandleWebhook verifies and applies a provider webhook. Events that
+	// were delivered before are returned without being applied again.
+	HandleWebhook(provider string, body []byte, signature string) (*model.WebhookEvent, error)
+	// ExpirePending reconciles card payments left pending past the
+	// pending timeout with the provider
+	ExpirePending() error
+}
+
+type paymentService struct {
+	paymentRepo   repository.PaymentRepository
+	webhookRepo   repository.WebhookEventRepository
+	billRepo      repository.BillRepository
+	orderSvc      orderservice.OrderService
+	restaurantSvc restaurantservice.RestaurantService
+	provider      gateway.PaymentProvider
+	txManager     database.TxManager
+	cfg           config.PaymentConfig
+	now           func() time.Time
+}
+
+func NewPaymentService(
+	paymentRepo repository.PaymentRepository,
+	webhookRepo repository.WebhookEventRepository,
+	billRepo repository.BillRepository,
+	orderSvc orderservice.OrderService,
+	restaurantSvc restaurantservice.RestaurantService,
+	provider gateway.PaymentProvider,
+	txManager database.TxManager,
+	cfg config.PaymentConfig,
+) PaymentService {
+	return &paymentService{
+		paymentRepo:   paymentRepo,
+		webhookRepo:   webhookRepo,
+		billRepo:      billRepo,
+		orderSvc:      orderSvc,
+		restaurantSvc: restaurantSvc,
+		provider:      provider,
+		txManager:     txManager,
+		cfg:           cfg,
+		now:           time.Now,
+	}
+}
+
+// GetPayments returns the payments of an order its owner or branch staff
+// can see
+func (s *paymentService) GetPayments(actor utils.Actor, orderID uint) (*model.PaymentSummary, error) {
+	order, err := s.orderSvc.GetOrder(actor, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.paymentRepo.Find(database.NewQuery().Eq("order_id", order.ID).OrderBy("created_at"))
+	if err != nil {
+		return nil, err
+	}
+	return model.Summarize(order.ID, order.Currency, order.Total, payments), nil
+}
+
+// Pay authorizes the amount on the card and captures it unless asked not
+// to. The payment is recorded as pending first so concurrent payments
+// cannot exceed the balance while the provider is called.
+func (s *paymentService) Pay(actor utils.Actor, orderID uint, req model.PaymentRequest) (*model.Payment, error) {
+	order, err := s.payableOrder(actor, orderID)
+	if err != nil {
+		return nil, err
+	}
+	tipStaffID, err := s.tipStaff(actor, order.BranchID, req.Tip, req.TipStaffID)
+	if err != nil {
+		return nil, err
+	}
+
+	payment := &model.Payment{
+		OrderID:     order.ID,
+		BranchID:    order.BranchID,
+		BillID:      req.BillID,
+		Method:      model.MethodCard,
+		Provider:    s.provider.Name(),
+		Status:      model.StatusPending,
+		Currency:    order.Currency,
+		Amount:      req.Amount,
+		Tip:         req.Tip,
+		TipStaffID:  tipStaffID,
+		CreatedByID: utils.ActorID(actor),
+	}
+	if err := s.reserve(order, payment); err != nil {
+		return nil, err
+	}
+
+	result, err := s.provider.Authorize(gateway.AuthorizeRequest{
+		Amount:    payment.Charge(),
+		Currency:  payment.Currency,
+		Token:     req.Token,
+		Reference: authorizeReference(payment.ID),
+	})
+	if err != nil {
+		// Only a refusal is final. After other errors the provider may
+		// still have authorized the card, so the payment stays pending
+		// until ExpirePending asks the provider.
+		if !errors.Is(err, gateway.ErrDeclined) && !errors.Is(err, gateway.ErrInvalidAmount) {
+			return nil, fmt.Errorf("%w: %v", ErrPaymentPending, err)
+		}
+		if _, failErr := s.record(payment.ID, "", model.StatusFailed, 0, err.Error()); failErr != nil {
+			return nil, failErr
+		}
+		return nil, err
+	}
+
+	payment, err = s.record(payment.ID, result.Reference, model.StatusAuthorized, result.Amount, "")
+	if err != nil {
+		return nil, err
+	}
+	if req.Capture != nil && !*req.Capture {
+		return payment, nil
+	}
+
+	result, err = s.provider.Capture(result.Reference, payment.Charge())
+	if err != nil {
+		return nil, err
+	}
+	return s.record(payment.ID, result.Reference, model.StatusCaptured, result.Amount, "")
+}
+
+func (s *paymentService) RecordManual(actor utils.Actor, orderID uint, req model.ManualPaymentRequest) (*model.Payment, error) {
+	order, err := s.payableOrder(actor, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, order.BranchID); err != nil {
+		return nil, err
+	}
+	tipStaffID, err := s.tipStaff(actor, order.BranchID, req.Tip, req.TipStaffID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	payment := &model.Payment{
+		OrderID:      order.ID,
+		BranchID:     order.BranchID,
+		BillID:       req.BillID,
+		Method:       req.Method,
+		Provider:     model.ProviderManual,
+		Status:       model.StatusCaptured,
+		Currency:     order.Currency,
+		Amount:       req.Amount,
+		Tip:          req.Tip,
+		TipStaffID:   tipStaffID,
+		Reference:    req.Reference,
+		CreatedByID:  utils.ActorID(actor),
+		AuthorizedAt: &now,
+		CapturedAt:   &now,
+	}
+	err = s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		paymentRepo := s.paymentRepo.WithTx(tx)
+		if err := s.checkAmount(tx, order, payment); err != nil {
+			return err
+		}
+
+		payment.CapturedAmount = payment.Amount
+		if payment.Method == model.MethodCash {
+			payment.Tendered = req.Tendered
+			if payment.Tendered == 0 {
+				payment.Tendered = payment.Charge()
+			}
+			if payment.Tendered < payment.Charge() {
+				return ErrTenderedTooLow
+			}
+			payment.Change = payment.Tendered - payment.Charge()
+		}
+		return paymentRepo.Create(payment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// Capture captures an authorized card payment, in full or in part, with
+// its tip. The rest of a partly captured authorization is released.
+func (s *paymentService) Capture(actor utils.Actor, id uint, req model.CaptureRequest) (*model.Payment, error) {
+	payment, err := s.getAccessiblePayment(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != model.StatusAuthorized || payment.ProviderRef == nil {
+		return nil, ErrPaymentState
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = payment.Amount
+	}
+	result, err := s.provider.Capture(*payment.ProviderRef, amount+payment.Tip)
+	if err != nil {
+		return nil, err
+	}
+	return s.record(payment.ID, result.Reference, model.StatusCaptured, result.Amount, "")
+}
+
+// Void releases an authorized card payment
+func (s *paymentService) Void(actor utils.Actor, id uint) (*model.Payment, error) {
+	payment, err := s.getAccessiblePayment(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != model.StatusAuthorized || payment.ProviderRef == nil {
+		return nil, ErrPaymentState
+	}
+
+	result, err := s.provider.Void(*payment.ProviderRef)
+	if err != nil {
+		return nil, err
+	}
+	return s.record(payment.ID, result.Reference, model.StatusVoided, 0, "")
+}
+
+func (s *paymentService) HandleWebhook(provider string, body []byte, signature string) (*model.WebhookEvent, error) {
+	if provider != s.provider.Name() {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if err := gateway.VerifyWebhook(s.cfg.WebhookSecret, signature, body, s.cfg.WebhookTolerance, s.now()); err != nil {
+		return nil, err
+	}
+
+	var payload model.WebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, ErrInvalidPayload
+	}
+	if payload.ID == "" || payload.Type == "" || payload.Reference == "" {
+		return nil, ErrInvalidPayload
+	}
+
+	var event *model.WebhookEvent
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		webhookRepo := s.webhookRepo.WithTx(tx)
+
+		existing, err := webhookRepo.GetByEventID(provider, payload.ID)
+		if err == nil {
+			event = existing
+			event.Duplicate = true
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		event = &model.WebhookEvent{
+			Provider:  provider,
+			EventID:   payload.ID,
+			Type:      payload.Type,
+			Reference: payload.Reference,
+			Amount:    payload.Amount,
+			Outcome:   model.OutcomeIgnored,
+		}
+
+		if status, ok := model.StatusForEvent(payload.Type); ok {
+			applied, paymentID, err := s.applyEvent(tx, provider, payload, status)
+			if err != nil {
+				return err
+			}
+			event.PaymentID = paymentID
+			if applied {
+				event.Outcome = model.OutcomeApplied
+			}
+		}
+
+		return webhookRepo.Create(event)
+	})
+	if errors.Is(err, database.ErrUniqueViolation) {
+		// The same event was delivered concurrently and recorded first
+		existing, getErr := s.webhookRepo.GetByEventID(provider, payload.ID)
+		if getErr != nil {
+			return nil, getErr
+		}
+		existing.Duplicate = true
+		return existing, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// ExpirePending settles the card payments left pending longer than the
+// pending timeout, because the provider call failed without an answer or
+// the server stopped during it. The provider is asked what became of the
+// authorize request; payments it never authorized fail as expired.
+func (s *paymentService) ExpirePending() error {
+	payments, err := s.paymentRepo.Find(database.NewQuery().
+		Eq("status", model.StatusPending).
+		Eq("provider", s.provider.Name()).
+		Where("created_at", database.OpLt, s.now().Add(-s.cfg.PendingTimeout)))
+	if err != nil {
+		return err
+	}
+
+	for _, payment := range payments {
+		if err := s.reconcile(payment.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcile moves a pending payment to what the provider holds for it
+func (s *paymentService) reconcile(id uint) error {
+	auth, err := s.provider.Lookup(authorizeReference(id))
+	if errors.Is(err, gateway.ErrUnknownReference) {
+		_, err = s.record(id, "", model.StatusFailed, 0, "expired without an answer from the provider")
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	status, amount := model.StatusAuthorized, auth.Authorized
+	switch {
+	case auth.Voided:
+		status, amount = model.StatusVoided, 0
+	case auth.Captured > 0:
+		status, amount = model.StatusCaptured, auth.Captured
+	}
+	_, err = s.record(id, auth.Reference, status, amount, "")
+	return err
+}
+
+// StartReaper reconciles pending card payments and refunds in the
+// background, checking every interval
+func StartReaper(paymentSvc PaymentService, refundSvc RefundService, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := paymentSvc.ExpirePending(); err != nil {
+				log.Println("Failed to reconcile pending payments:", err)
+			}
+			if err := refundSvc.ResendPending(); err != nil {
+				log.Println("Failed to resend pending refunds:", err)
+			}
+		}
+	}()
+}
+
+// authorizeReference is our reference of a payment at the provider, which
+// lets it drop repeated authorize requests and find them again
+func authorizeReference(paymentID uint) string {
+	return fmt.Sprintf("payment_%d", paymentID)
+}
+
+// applyEvent moves the payment a webhook reports on. Unknown payments and
+// payments already past the reported status are left alone.
+func (s *paymentService) applyEvent(tx *gorm.DB, provider string, payload model.WebhookPayload, status string) (bool, *uint, error) {
+	paymentRepo := s.paymentRepo.WithTx(tx)
+
+	payment, err := paymentRepo.GetByProviderRef(provider, payload.Reference)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+
+	amount := payload.Amount
+	if amount == 0 {
+		amount = payment.Charge()
+	}
+	if !payment.MoveTo(status, amount, s.now()) {
+		return false, &payment.ID, nil
+	}
+	if status == model.StatusFailed {
+		payment.FailureReason = payload.Reason
+	}
+	return true, &payment.ID, paymentRepo.Update(payment)
+}
+
+// payableOrder loads an order the actor can see and that takes payments
+func (s *paymentService) payableOrder(actor utils.Actor, orderID uint) (*ordermodel.OrderDetails, error) {
+	order, err := s.orderSvc.GetOrder(actor, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isPayable(order) {
+		return nil, ErrOrderNotPayable
+	}
+	return order, nil
+}
+
+// isPayable reports whether the order takes payments in its status
+func isPayable(order *ordermodel.OrderDetails) bool {
+	switch order.Status {
+	case ordermodel.StatusDraft, ordermodel.StatusCancelled, ordermodel.StatusRejected:
+		return false
+	}
+	return true
+}
+
+// reserve records a pending payment after checking it against the
+// outstanding balance
+func (s *paymentService) reserve(order *ordermodel.OrderDetails, payment *model.Payment) error {
+	return s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		paymentRepo := s.paymentRepo.WithTx(tx)
+		if err := s.checkAmount(tx, order, payment); err != nil {
+			return err
+		}
+		return paymentRepo.Create(payment)
+	})
+}
+
+// checkAmount locks the payments of the order and fills in or checks the
+// amount of a new payment against the outstanding balance, or that of its
+// bill when the order is split
+func (s *paymentService) checkAmount(tx *gorm.DB, order *ordermodel.OrderDetails, payment *model.Payment) error {
+	paymentRepo := s.paymentRepo.WithTx(tx)
+	if err := paymentRepo.LockOrder(order.ID); err != nil {
+		return err
+	}
+	payments, err := paymentRepo.Find(database.NewQuery().Eq("order_id", order.ID))
+	if err != nil {
+		return err
+	}
+
+	outstanding := model.Summarize(order.ID, order.Currency, order.Total, payments).Outstanding
+	if outstanding > 0 {
+		outstanding, err = s.billOutstanding(s.billRepo.WithTx(tx), order, payment, payments, outstanding)
+		if err != nil {
+			return err
+		}
+	}
+
+	if outstanding == 0 {
+		return ErrNothingOutstanding
+	}
+	if payment.Amount == 0 {
+		payment.Amount = outstanding
+	}
+	if payment.Amount > outstanding {
+		return ErrAmountExceeds
+	}
+	return nil
+}
+
+// billOutstanding narrows the outstanding balance of a split order to the
+// bill the payment is for
+func (s *paymentService) billOutstanding(billRepo repository.BillRepository, order *ordermodel.OrderDetails, payment *model.Payment, payments []model.Payment, outstanding int64) (int64, error) {
+	bills, err := billRepo.Find(database.NewQuery().Eq("order_id", order.ID))
+	if err != nil {
+		return 0, err
+	}
+	if len(bills) == 0 {
+		if payment.BillID != nil {
+			return 0, gorm.ErrRecordNotFound
+		}
+		return outstanding, nil
+	}
+	if payment.BillID == nil {
+		return 0, ErrBillRequired
+	}
+
+	for _, bill := range model.SummarizeBills(bills, payments) {
+		if bill.ID == *payment.BillID {
+			return min(bill.Outstanding, outstanding), nil
+		}
+	}
+	return 0, gorm.ErrRecordNotFound
+}
+
+// record moves a payment to the outcome of a provider call unless a
+// webhook has already moved it further
+func (s *paymentService) record(id uint, reference, status string, amount int64, reason string) (*model.Payment, error) {
+	var payment *model.Payment
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		paymentRepo := s.paymentRepo.WithTx(tx)
+
+		var err error
+		payment, err = paymentRepo.GetForUpdate(id)
+		if err != nil {
+			return err
+		}
+
+		if payment.ProviderRef == nil && reference != "" {
+			payment.ProviderRef = &reference
+		}
+		if payment.MoveTo(status, amount, s.now()) && status == model.StatusFailed {
+			payment.FailureReason = reason
+		}
+		return paymentRepo.Update(payment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// getAccessiblePayment loads a payment of a branch the actor works at
+func (s *paymentService) getAccessiblePayment(actor utils.Actor, id uint) (*model.Payment, error) {
+	payment, err := s.paymentRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, payment.BranchID); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// tipStaff returns the staff member a tip goes to: the one asked for, or
+// the staff member taking the payment
+func (s *paymentService) tipStaff(actor utils.Actor, branchID uint, tip int64, requested *uint) (*uint, error) {
+	if tip == 0 {
+		return nil, nil
+	}
+
+	if requested != nil {
+		ok, err := s.restaurantSvc.IsBranchStaff(branchID, *requested)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrTipStaffNotFound
+		}
+		return requested, nil
+	}
+
+	if actor.IsGuest() || actor.IsStation() || actor.UserID == 0 {
+		return nil, nil
+	}
+	ok, err := s.restaurantSvc.IsBranchStaff(branchID, actor.UserID)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return utils.ActorID(actor), nil
+}
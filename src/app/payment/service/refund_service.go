@@ -0,0 +1,478 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	loyaltyservice "github.com/faisd405/go-restapi-gin/src/app/loyalty/service"
+	ordermodel "github.com/faisd405/go-restapi-gin/src/app/order/model"
+	orderservice "github.com/faisd405/go-restapi-gin/src/app/order/service"
+	"github.com/faisd405/go-restapi-gin/src/app/payment/model"
+	"github.com/faisd405/go-restapi-gin/src/app/payment/repository"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/config"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/gateway"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNothingRefundable = errors.New("nothing left to refund")
+	ErrRefundExceeds     = errors.New("refund exceeds the captured amount not yet refunded")
+	ErrRefundQuantity    = errors.New("refund quantity exceeds the quantity not yet refunded")
+	ErrAmountAndLines    = errors.New("refund either an amount or lines, not both")
+	ErrUnknownOrderLine  = errors.New("order line does not belong to the order")
+	ErrRefundPending     = errors.New("refund outcome is unknown; it is sent again until the provider answers")
+)
+
+type RefundService interface {
+	GetRefunds(actor utils.Actor, orderID uint) ([]model.RefundGroup, error)
+
+	// Refund gives back part or all of the captured payments of an order.
+	// Card refunds go through the payment provider; refunds of cash and
+	// terminal payments are recorded for staff to hand back.
+	Refund(actor utils.Actor, orderID uint, req model.RefundRequest) (*model.RefundGroup, error)
+	// ResendPending sends card refunds left pending past the pending
+	// timeout to the provider again
+	ResendPending() error
+}
+
+type refundService struct {
+	paymentRepo   repository.PaymentRepository
+	refundRepo    repository.RefundRepository
+	groupRepo     repository.RefundGroupRepository
+	orderSvc      orderservice.OrderService
+	restaurantSvc restaurantservice.RestaurantService
+	loyaltySvc    loyaltyservice.LoyaltyService
+	provider      gateway.PaymentProvider
+	txManager     database.TxManager
+	cfg           config.PaymentConfig
+	now           func() time.Time
+}
+
+func NewRefundService(
+	paymentRepo repository.PaymentRepository,
+	refundRepo repository.RefundRepository,
+	groupRepo repository.RefundGroupRepository,
+	orderSvc orderservice.OrderService,
+	restaurantSvc restaurantservice.RestaurantService,
+	loyaltySvc loyaltyservice.LoyaltyService,
+	provider gateway.PaymentProvider,
+	txManager database.TxManager,
+	cfg config.PaymentConfig,
+) RefundService {
+	return &refundService{
+		paymentRepo:   paymentRepo,
+		refundRepo:    refundRepo,
+		groupRepo:     groupRepo,
+		orderSvc:      orderSvc,
+		restaurantSvc: restaurantSvc,
+		loyaltySvc:    loyaltySvc,
+		provider:      provider,
+		txManager:     txManager,
+		cfg:           cfg,
+		now:           time.Now,
+	}
+}
+
+// Refunded reads what was refunded of an order for the loyalty order hook
+func Refunded(paymentRepo repository.PaymentRepository) loyaltyservice.RefundedFunc {
+	return func(tx *gorm.DB, orderID uint) (int64, error) {
+		return paymentRepo.WithTx(tx).Refunded(orderID)
+	}
+}
+
+func (s *refundService) GetRefunds(actor utils.Actor, orderID uint) ([]model.RefundGroup, error) {
+	order, err := s.refundableOrder(actor, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return s.groupRepo.Find(database.NewQuery().Eq("order_id", order.ID).Preload("Refunds").OrderBy("created_at"))
+}
+
+// Refund reserves the refunds on their payments under the order lock, so
+// concurrent refunds cannot give back more than was captured, and then
+// asks the provider for the card refunds. When the provider refuses a
+// refund, it and the refunds not yet sent are marked failed and their
+// amounts are released. When it does not answer, they stay pending for
+// ResendPending. What did go back takes its share of the loyalty points of
+// the order with it.
+func (s *refundService) Refund(actor utils.Actor, orderID uint, req model.RefundRequest) (*model.RefundGroup, error) {
+	if req.Amount > 0 && len(req.Lines) > 0 {
+		return nil, ErrAmountAndLines
+	}
+
+	order, err := s.refundableOrder(actor, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var group *model.RefundGroup
+	var refunds []model.Refund
+	err = s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		paymentRepo := s.paymentRepo.WithTx(tx)
+		refundRepo := s.refundRepo.WithTx(tx)
+		groupRepo := s.groupRepo.WithTx(tx)
+
+		if err := paymentRepo.LockOrder(order.ID); err != nil {
+			return err
+		}
+
+		q := database.NewQuery().Eq("order_id", order.ID).Eq("status", model.StatusCaptured).OrderByDesc("captured_at")
+		if req.PaymentID != nil {
+			q.Eq("id", *req.PaymentID)
+		}
+		payments, err := paymentRepo.Find(q)
+		if err != nil {
+			return err
+		}
+		if req.PaymentID != nil && len(payments) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var available int64
+		for _, p := range payments {
+			available += p.Refundable()
+		}
+
+		amount, lines, err := s.refundAmount(groupRepo, order, req, available)
+		if err != nil {
+			return err
+		}
+		if amount == 0 || available == 0 {
+			return ErrNothingRefundable
+		}
+		if amount > available {
+			return ErrRefundExceeds
+		}
+
+		group = &model.RefundGroup{
+			OrderID:     order.ID,
+			BranchID:    order.BranchID,
+			Status:      model.RefundPending,
+			Currency:    order.Currency,
+			Amount:      amount,
+			Reason:      req.Reason,
+			Lines:       lines,
+			CreatedByID: utils.ActorID(actor),
+		}
+		if err := groupRepo.Create(group); err != nil {
+			return err
+		}
+
+		now := s.now()
+		for i := range payments {
+			payment := &payments[i]
+			part := min(amount, payment.Refundable())
+			if part == 0 {
+				continue
+			}
+
+			payment.RefundedAmount += part
+			if err := paymentRepo.Update(payment); err != nil {
+				return err
+			}
+
+			refund := model.Refund{
+				GroupID:     group.ID,
+				PaymentID:   payment.ID,
+				OrderID:     order.ID,
+				BranchID:    order.BranchID,
+				Method:      payment.Method,
+				Provider:    payment.Provider,
+				Status:      model.RefundPending,
+				Currency:    payment.Currency,
+				Amount:      part,
+				Reason:      req.Reason,
+				CreatedByID: utils.ActorID(actor),
+			}
+			if payment.Provider == model.ProviderManual {
+				refund.Status = model.RefundSucceeded
+				refund.RefundedAt = &now
+			}
+			if err := refundRepo.Create(&refund); err != nil {
+				return err
+			}
+			refunds = append(refunds, refund)
+
+			amount -= part
+			if amount == 0 {
+				break
+			}
+		}
+
+		group.Follow(refunds)
+		return groupRepo.Update(group)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var sendErr error
+	for i := range refunds {
+		if refunds[i].Status != model.RefundPending {
+			continue
+		}
+
+		settled, err := s.send(&refunds[i])
+		if err != nil {
+			if !errors.Is(err, ErrRefundPending) {
+				s.abandon(refunds[i+1:])
+			}
+			sendErr = err
+			break
+		}
+		refunds[i] = *settled
+	}
+	s.reclaimPoints(order.ID, refunds)
+	if sendErr != nil {
+		return nil, sendErr
+	}
+
+	return s.groupRepo.First(database.NewQuery().Eq("id", group.ID).Preload("Refunds"))
+}
+
+// reclaimPoints takes back the loyalty points earned on what the succeeded
+// refunds gave back. The money is back with the customer by now, so a
+// failure is only logged.
+func (s *refundService) reclaimPoints(orderID uint, refunds []model.Refund) {
+	var refunded int64
+	for _, refund := range refunds {
+		if refund.Status == model.RefundSucceeded {
+			refunded += refund.Amount
+		}
+	}
+	if refunded == 0 {
+		return
+	}
+
+	payments, err := s.paymentRepo.Find(database.NewQuery().Eq("order_id", orderID).Eq("status", model.StatusCaptured))
+	if err == nil {
+		paid := refunded
+		for _, p := range payments {
+			paid += p.Refundable()
+		}
+		err = s.loyaltySvc.Reclaim(orderID, refunded, paid)
+	}
+	if err != nil {
+		log.Println("Failed to take back loyalty points:", err)
+	}
+}
+
+// send asks the provider to refund a pending card refund and records the
+// outcome
+func (s *refundService) send(refund *model.Refund) (*model.Refund, error) {
+	payment, err := s.paymentRepo.GetByID(refund.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment.ProviderRef == nil {
+		if _, err := s.settle(refund.ID, "", model.RefundFailed, ErrPaymentState.Error()); err != nil {
+			return nil, err
+		}
+		return nil, ErrPaymentState
+	}
+
+	result, err := s.provider.Refund(*payment.ProviderRef, refund.Amount, refundReference(refund.ID))
+	if err != nil {
+		// Only a refusal is final. After other errors the provider may
+		// still have refunded, so the refund keeps its amount until
+		// ResendPending sends it again under the same reference.
+		if !isRefusal(err) {
+			return nil, fmt.Errorf("%w: %v", ErrRefundPending, err)
+		}
+		if _, failErr := s.settle(refund.ID, "", model.RefundFailed, err.Error()); failErr != nil {
+			return nil, failErr
+		}
+		return nil, err
+	}
+	return s.settle(refund.ID, result.Reference, model.RefundSucceeded, "")
+}
+
+// ResendPending sends the card refunds left pending longer than the
+// pending timeout, because the provider call failed without an answer or
+// the server stopped before or during it, to the provider again. It drops
+// repeated refund references, so a refund it made before is only reported.
+func (s *refundService) ResendPending() error {
+	refunds, err := s.refundRepo.Find(database.NewQuery().
+		Eq("status", model.RefundPending).
+		Eq("provider", s.provider.Name()).
+		Where("created_at", database.OpLt, s.now().Add(-s.cfg.PendingTimeout)))
+	if err != nil {
+		return err
+	}
+
+	for i := range refunds {
+		settled, err := s.send(&refunds[i])
+		switch {
+		case err == nil:
+			s.reclaimPoints(settled.OrderID, []model.Refund{*settled})
+		case errors.Is(err, ErrRefundPending):
+			log.Println("Refund still pending:", err)
+		case errors.Is(err, ErrPaymentState), isRefusal(err):
+			// send marked the refund failed
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// abandon fails the pending refunds left over after a failed one
+func (s *refundService) abandon(refunds []model.Refund) {
+	for _, refund := range refunds {
+		if refund.Status != model.RefundPending {
+			continue
+		}
+		if _, err := s.settle(refund.ID, "", model.RefundFailed, "not sent after an earlier refund failed"); err != nil {
+			log.Println("Failed to release refund:", err)
+		}
+	}
+}
+
+// settle moves a pending refund to its outcome and its group along. A
+// failed refund releases its amount on the payment.
+func (s *refundService) settle(id uint, reference, status, reason string) (*model.Refund, error) {
+	var refund *model.Refund
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		paymentRepo := s.paymentRepo.WithTx(tx)
+		refundRepo := s.refundRepo.WithTx(tx)
+
+		var err error
+		refund, err = refundRepo.GetForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if refund.Status != model.RefundPending {
+			return nil
+		}
+
+		refund.Status = status
+		if status == model.RefundSucceeded {
+			now := s.now()
+			refund.RefundedAt = &now
+			if reference != "" {
+				refund.ProviderRef = &reference
+			}
+		} else {
+			refund.FailureReason = reason
+			payment, err := paymentRepo.GetForUpdate(refund.PaymentID)
+			if err != nil {
+				return err
+			}
+			payment.RefundedAmount -= refund.Amount
+			if err := paymentRepo.Update(payment); err != nil {
+				return err
+			}
+		}
+		if err := refundRepo.Update(refund); err != nil {
+			return err
+		}
+		return s.follow(tx, refund.GroupID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return refund, nil
+}
+
+// follow updates the status of a refund group to that of its refunds
+func (s *refundService) follow(tx *gorm.DB, groupID uint) error {
+	groupRepo := s.groupRepo.WithTx(tx)
+
+	group, err := groupRepo.GetForUpdate(groupID)
+	if err != nil {
+		return err
+	}
+	refunds, err := s.refundRepo.WithTx(tx).Find(database.NewQuery().Eq("group_id", groupID))
+	if err != nil {
+		return err
+	}
+
+	group.Follow(refunds)
+	return groupRepo.Update(group)
+}
+
+// isRefusal tells whether the provider refused a refund for good
+func isRefusal(err error) bool {
+	return errors.Is(err, gateway.ErrInvalidAmount) ||
+		errors.Is(err, gateway.ErrInvalidState) ||
+		errors.Is(err, gateway.ErrUnknownReference)
+}
+
+// refundReference is our reference of a refund at the provider, which lets
+// it drop repeated refund requests
+func refundReference(refundID uint) string {
+	return fmt.Sprintf("refund_%d", refundID)
+}
+
+// refundAmount works out the amount of a refund request. Lines are
+// refunded at their net price including tax.
+func (s *refundService) refundAmount(groupRepo repository.RefundGroupRepository, order *ordermodel.OrderDetails, req model.RefundRequest, available int64) (int64, []model.RefundLine, error) {
+	if len(req.Lines) == 0 {
+		if req.Amount == 0 {
+			return available, nil, nil
+		}
+		return req.Amount, nil, nil
+	}
+
+	previous, err := groupRepo.Find(database.NewQuery().Eq("order_id", order.ID))
+	if err != nil {
+		return 0, nil, err
+	}
+	refunded := model.RefundedQuantities(previous)
+
+	orderLines := make(map[uint]ordermodel.OrderLine, len(order.Lines))
+	for _, line := range order.Lines {
+		orderLines[line.ID] = line
+	}
+
+	var amount int64
+	lines := make([]model.RefundLine, 0, len(req.Lines))
+	for _, requested := range req.Lines {
+		line, ok := orderLines[requested.OrderLineID]
+		if !ok {
+			return 0, nil, ErrUnknownOrderLine
+		}
+		before := refunded[line.ID]
+		after := before + requested.Quantity
+		if after > line.Quantity {
+			return 0, nil, ErrRefundQuantity
+		}
+		refunded[line.ID] = after
+
+		part := lineAmount(line, before, after)
+		amount += part
+		lines = append(lines, model.RefundLine{
+			OrderLineID: line.ID,
+			Name:        line.Name,
+			Quantity:    requested.Quantity,
+			Amount:      part,
+		})
+	}
+	return amount, lines, nil
+}
+
+// refundableOrder loads an order of a branch the actor works at
+func (s *refundService) refundableOrder(actor utils.Actor, orderID uint) (*ordermodel.OrderDetails, error) {
+	order, err := s.orderSvc.GetOrder(actor, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, order.BranchID); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// lineAmount is the price after discounts and with tax of the units of
+// line from before up to after. The last unit gets the rounding remainder
+// so the units of a line add up to it exactly.
+func lineAmount(line ordermodel.OrderLine, before, after int) int64 {
+	net := line.LineTotal - line.Discount + line.Tax
+	return net*int64(after)/int64(line.Quantity) - net*int64(before)/int64(line.Quantity)
+}
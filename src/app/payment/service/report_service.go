@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/payment/model"
+	"github.com/faisd405/go-restapi-gin/src/app/payment/repository"
+	restaurantmodel "github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+)
+
+var (
+	ErrInvalidDate  = errors.New("date must be formatted as YYYY-MM-DD")
+	ErrInvalidRange = errors.New("from must not be after to")
+)
+
+type ReportService interface {
+	// GetSalesReport lists the captured payments and settled refunds of a
+	// branch between two local dates
+	GetSalesReport(actor utils.Actor, branchID uint, filter model.ReportFilter) (*model.SalesReport, error)
+
+	// GetTipReport adds up the tips of a branch per staff member for
+	// payout
+	GetTipReport(actor utils.Actor, branchID uint, filter model.ReportFilter) (*model.TipReport, error)
+}
+
+type reportService struct {
+	paymentRepo   repository.PaymentRepository
+	refundRepo    repository.RefundRepository
+	restaurantSvc restaurantservice.RestaurantService
+	now           func() time.Time
+}
+
+func NewReportService(
+	paymentRepo repository.PaymentRepository,
+	refundRepo repository.RefundRepository,
+	restaurantSvc restaurantservice.RestaurantService,
+) ReportService {
+	return &reportService{
+		paymentRepo:   paymentRepo,
+		refundRepo:    refundRepo,
+		restaurantSvc: restaurantSvc,
+		now:           time.Now,
+	}
+}
+
+func (s *reportService) GetSalesReport(actor utils.Actor, branchID uint, filter model.ReportFilter) (*model.SalesReport, error) {
+	branch, from, end, err := s.reportRange(actor, branchID, &filter)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.paymentRepo.Find(capturedBetween(branch.ID, from, end))
+	if err != nil {
+		return nil, err
+	}
+
+	refunds, err := s.refundRepo.Find(database.NewQuery().
+		Eq("branch_id", branch.ID).
+		Eq("status", model.RefundSucceeded).
+		Where("refunded_at", database.OpGte, from).
+		Where("refunded_at", database.OpLt, end))
+	if err != nil {
+		return nil, err
+	}
+
+	return model.NewSalesReport(branch.ID, filter.From, filter.To, payments, refunds), nil
+}
+
+func (s *reportService) GetTipReport(actor utils.Actor, branchID uint, filter model.ReportFilter) (*model.TipReport, error) {
+	branch, from, end, err := s.reportRange(actor, branchID, &filter)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.paymentRepo.Find(capturedBetween(branch.ID, from, end).Where("tip", database.OpGt, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	staff, err := s.restaurantSvc.GetStaff(branch.ID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(staff))
+	for _, member := range staff {
+		names[member.UserID] = member.Name
+	}
+
+	return model.NewTipReport(branch.ID, filter.From, filter.To, payments, names), nil
+}
+
+// reportRange loads a branch the actor works at and turns the local dates
+// of filter, defaulting to today, into the times from and end of the
+// report, end excluded
+func (s *reportService) reportRange(actor utils.Actor, branchID uint, filter *model.ReportFilter) (*restaurantmodel.Branch, time.Time, time.Time, error) {
+	branch, err := s.restaurantSvc.GetBranch(branchID, false)
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branch.ID); err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+
+	today := s.now().In(branch.Location()).Format("2006-01-02")
+	if filter.From == "" {
+		filter.From = today
+	}
+	if filter.To == "" {
+		filter.To = today
+	}
+	from, err := time.ParseInLocation("2006-01-02", filter.From, branch.Location())
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, ErrInvalidDate
+	}
+	to, err := time.ParseInLocation("2006-01-02", filter.To, branch.Location())
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, ErrInvalidDate
+	}
+	if to.Before(from) {
+		return nil, time.Time{}, time.Time{}, ErrInvalidRange
+	}
+	return branch, from, to.AddDate(0, 0, 1), nil
+}
+
+// capturedBetween selects the payments of a branch captured from start up
+// to end
+func capturedBetween(branchID uint, start, end time.Time) *database.Query {
+	return database.NewQuery().
+		Eq("branch_id", branchID).
+		Eq("status", model.StatusCaptured).
+		Where("captured_at", database.OpGte, start).
+		Where("captured_at", database.OpLt, end)
+}
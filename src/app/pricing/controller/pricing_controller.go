@@ -0,0 +1,348 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/faisd405/go-restapi-gin/src/app/pricing/model"
+	"github.com/faisd405/go-restapi-gin/src/app/pricing/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type PricingController struct {
+	pricingService service.PricingService
+}
+
+func NewPricingController(pricingService service.PricingService) *PricingController {
+	return &PricingController{pricingService: pricingService}
+}
+
+// GetConfig godoc
+// @Summary Get branch pricing (Admin/Manager)
+// @Description Get the service charge, rounding and tax rates of a branch
+// @Tags pricing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/pricing [get]
+func (ctrl *PricingController) GetConfig(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	config, err := ctrl.pricingService.GetConfig(actor, id)
+	if err != nil {
+		pricingErrorResponse(c, "Failed to retrieve pricing", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pricing retrieved successfully", config)
+}
+
+// UpdateSettings godoc
+// @Summary Update branch pricing settings (Admin/Manager)
+// @Description Set the service charge and rounding increment of a branch
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param settings body model.PricingSettingsRequest true "Pricing settings"
+// @Success 200 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /branches/{id}/pricing [put]
+func (ctrl *PricingController) UpdateSettings(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.PricingSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	settings, err := ctrl.pricingService.UpdateSettings(actor, id, req)
+	if err != nil {
+		pricingErrorResponse(c, "Pricing update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Pricing updated successfully", settings)
+}
+
+// CreateTaxRate godoc
+// @Summary Create tax rate (Admin/Manager)
+// @Description Add a tax rate to a branch
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param rate body model.TaxRateRequest true "Tax rate data"
+// @Success 201 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /branches/{id}/tax-rates [post]
+func (ctrl *PricingController) CreateTaxRate(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.TaxRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	rate, err := ctrl.pricingService.CreateTaxRate(actor, id, req)
+	if err != nil {
+		pricingErrorResponse(c, "Tax rate creation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Tax rate created successfully", rate)
+}
+
+// UpdateTaxRate godoc
+// @Summary Update tax rate (Admin/Manager)
+// @Description Update a tax rate by ID
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Tax rate ID"
+// @Param rate body model.TaxRateRequest true "Tax rate data"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tax-rates/{id} [put]
+func (ctrl *PricingController) UpdateTaxRate(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid tax rate ID", err.Error())
+		return
+	}
+
+	var req model.TaxRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	rate, err := ctrl.pricingService.UpdateTaxRate(actor, id, req)
+	if err != nil {
+		pricingErrorResponse(c, "Tax rate update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tax rate updated successfully", rate)
+}
+
+// DeleteTaxRate godoc
+// @Summary Delete tax rate (Admin/Manager)
+// @Description Delete a tax rate by ID
+// @Tags pricing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Tax rate ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tax-rates/{id} [delete]
+func (ctrl *PricingController) DeleteTaxRate(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid tax rate ID", err.Error())
+		return
+	}
+
+	if err := ctrl.pricingService.DeleteTaxRate(actor, id); err != nil {
+		pricingErrorResponse(c, "Tax rate deletion failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tax rate deleted successfully", nil)
+}
+
+// GetDiscounts godoc
+// @Summary List discounts (Admin/Manager)
+// @Description List the discount codes of a branch
+// @Tags pricing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/discounts [get]
+func (ctrl *PricingController) GetDiscounts(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	discounts, err := ctrl.pricingService.GetDiscounts(actor, id)
+	if err != nil {
+		pricingErrorResponse(c, "Failed to retrieve discounts", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Discounts retrieved successfully", discounts)
+}
+
+// CreateDiscount godoc
+// @Summary Create discount (Admin/Manager)
+// @Description Add a discount code to a branch
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param discount body model.DiscountRequest true "Discount data"
+// @Success 201 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /branches/{id}/discounts [post]
+func (ctrl *PricingController) CreateDiscount(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.DiscountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	discount, err := ctrl.pricingService.CreateDiscount(actor, id, req)
+	if err != nil {
+		pricingErrorResponse(c, "Discount creation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Discount created successfully", discount)
+}
+
+// UpdateDiscount godoc
+// @Summary Update discount (Admin/Manager)
+// @Description Update a discount code by ID
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Discount ID"
+// @Param discount body model.DiscountRequest true "Discount data"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /discounts/{id} [put]
+func (ctrl *PricingController) UpdateDiscount(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid discount ID", err.Error())
+		return
+	}
+
+	var req model.DiscountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	discount, err := ctrl.pricingService.UpdateDiscount(actor, id, req)
+	if err != nil {
+		pricingErrorResponse(c, "Discount update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Discount updated successfully", discount)
+}
+
+// DeleteDiscount godoc
+// @Summary Delete discount (Admin/Manager)
+// @Description Delete a discount code by ID
+// @Tags pricing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Discount ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /discounts/{id} [delete]
+func (ctrl *PricingController) DeleteDiscount(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid discount ID", err.Error())
+		return
+	}
+
+	if err := ctrl.pricingService.DeleteDiscount(actor, id); err != nil {
+		pricingErrorResponse(c, "Discount deletion failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Discount deleted successfully", nil)
+}
+
+func pricingErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidDiscountCode),
+		errors.Is(err, service.ErrDiscountMinSubtotal),
+		errors.Is(err, service.ErrInvalidPercent),
+		errors.Is(err, service.ErrInvalidDiscountRange):
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, message, err.Error())
+	default:
+		utils.DatabaseErrorResponse(c, message, err)
+	}
+}
@@ -0,0 +1,93 @@
+package model
+
+// LineInput is an order line to be priced. UnitPrice already includes
+// modifier surcharges.
+type LineInput struct {
+	Name      string   `json:"name"`
+	UnitPrice int64    `json:"unit_price"`
+	Quantity  int      `json:"quantity"`
+	Tags      []string `json:"tags"`
+}
+
+// QuoteRequest asks for the price of lines at a branch with an optional
+// discount code. Discounts apply after the code, e.g. loyalty points
+// redeemed at checkout.
+type QuoteRequest struct {
+	BranchID     uint
+	Lines        []LineInput
+	DiscountCode string
+	Discounts    []DiscountInput
+}
+
+// DiscountInput is an order level discount: either PercentBps of the
+// amount left after earlier discounts or a fixed Amount
+type DiscountInput struct {
+	Code       string
+	Name       string
+	PercentBps int
+	Amount     int64
+}
+
+// Input is everything the pricing engine needs to price an order
+type Input struct {
+	Currency             string
+	Lines                []LineInput
+	TaxRates             []TaxRate
+	Discounts            []DiscountInput
+	ServiceChargeBps     int
+	ServiceChargeTaxable bool
+	RoundingIncrement    int64
+}
+
+// LineBreakdown is a priced order line. Net is Gross minus its share of the
+// order discounts; Tax is the exclusive tax added on top of Net and
+// InclusiveTax the part of Net that is tax.
+type LineBreakdown struct {
+	Name         string `json:"name"`
+	Quantity     int    `json:"quantity"`
+	UnitPrice    int64  `json:"unit_price"`
+	Gross        int64  `json:"gross"`
+	Discount     int64  `json:"discount"`
+	Net          int64  `json:"net"`
+	Tax          int64  `json:"tax"`
+	InclusiveTax int64  `json:"inclusive_tax"`
+	Total        int64  `json:"total"`
+}
+
+// DiscountLine is a discount applied to the order
+type DiscountLine struct {
+	Code   string `json:"code,omitempty"`
+	Name   string `json:"name"`
+	Amount int64  `json:"amount"`
+}
+
+// TaxLine sums one tax rate over the order. Taxable is the amount the tax
+// was computed on, excluding the tax itself.
+type TaxLine struct {
+	Name      string `json:"name"`
+	RateBps   int    `json:"rate_bps"`
+	Inclusive bool   `json:"inclusive"`
+	Taxable   int64  `json:"taxable"`
+	Amount    int64  `json:"amount"`
+}
+
+// Breakdown is the itemized price of an order. All amounts are in minor
+// units of Currency:
+//
+//	Total = Subtotal - DiscountTotal + ServiceCharge + TaxTotal + Rounding
+//
+// Inclusive taxes are already part of the line prices and only reported.
+type Breakdown struct {
+	Currency          string          `json:"currency"`
+	Lines             []LineBreakdown `json:"lines"`
+	Subtotal          int64           `json:"subtotal"`
+	Discounts         []DiscountLine  `json:"discounts"`
+	DiscountTotal     int64           `json:"discount_total"`
+	ServiceChargeBps  int             `json:"service_charge_bps"`
+	ServiceCharge     int64           `json:"service_charge"`
+	Taxes             []TaxLine       `json:"taxes"`
+	TaxTotal          int64           `json:"tax_total"`
+	InclusiveTaxTotal int64           `json:"inclusive_tax_total"`
+	Rounding          int64           `json:"rounding"`
+	Total             int64           `json:"total"`
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+)
+
+// Discount types. Percent values are in basis points (1000 = 10%), fixed
+// values in minor units.
+const (
+	DiscountTypePercent = "percent"
+	DiscountTypeFixed   = "fixed"
+)
+
+// BranchPricing holds the pricing settings of a branch. ServiceChargeBps is
+// in basis points; RoundingIncrement overrides the currency default when
+// set, e.g. 5 to round CHF totals to 5 centimes.
+type BranchPricing struct {
+	BranchID             uint      `json:"branch_id" gorm:"primaryKey;autoIncrement:false"`
+	ServiceChargeBps     int       `json:"service_charge_bps" gorm:"not null;default:0"`
+	ServiceChargeTaxable bool      `json:"service_charge_taxable" gorm:"not null;default:false"`
+	RoundingIncrement    int64     `json:"rounding_increment" gorm:"not null;default:0"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+func (BranchPricing) TableName() string {
+	return "branch_pricing"
+}
+
+// TaxRate is one of the taxes of a branch. Inclusive taxes are contained
+// in menu prices, exclusive ones are added on top. A rate with Tags only
+// applies to items carrying one of them, e.g. "alcohol".
+type TaxRate struct {
+	ID        uint                `json:"id" gorm:"primaryKey"`
+	BranchID  uint                `json:"branch_id" gorm:"not null;index"`
+	Name      string              `json:"name" gorm:"not null"`
+	RateBps   int                 `json:"rate_bps" gorm:"not null"`
+	Inclusive bool                `json:"inclusive" gorm:"not null;default:false"`
+	Tags      database.StringList `json:"tags" gorm:"type:jsonb;not null;default:'[]'"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	DeletedAt gorm.DeletedAt      `json:"-" gorm:"index"`
+}
+
+// Discount is a code customers can apply to an order at a branch
+type Discount struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	BranchID    uint           `json:"branch_id" gorm:"not null;uniqueIndex:idx_discounts_branch_code,where:deleted_at IS NULL"`
+	Code        string         `json:"code" gorm:"type:varchar(50);not null;uniqueIndex:idx_discounts_branch_code,where:deleted_at IS NULL"`
+	Name        string         `json:"name" gorm:"not null"`
+	Type        string         `json:"type" gorm:"type:varchar(20);not null"`
+	Value       int64          `json:"value" gorm:"not null"`
+	MinSubtotal int64          `json:"min_subtotal" gorm:"not null;default:0"`
+	IsActive    bool           `json:"is_active" gorm:"not null;default:true"`
+	StartsAt    *time.Time     `json:"starts_at"`
+	EndsAt      *time.Time     `json:"ends_at"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// IsValidAt reports whether the discount can be used at t
+func (d *Discount) IsValidAt(t time.Time) bool {
+	if !d.IsActive {
+		return false
+	}
+	if d.StartsAt != nil && t.Before(*d.StartsAt) {
+		return false
+	}
+	if d.EndsAt != nil && !t.Before(*d.EndsAt) {
+		return false
+	}
+	return true
+}
+
+type PricingSettingsRequest struct {
+	ServiceChargeBps     int   `json:"service_charge_bps" binding:"min=0,max=10000"`
+	ServiceChargeTaxable bool  `json:"service_charge_taxable"`
+	RoundingIncrement    int64 `json:"rounding_increment" binding:"min=0,max=10000"`
+}
+
+type TaxRateRequest struct {
+	Name      string   `json:"name" binding:"required"`
+	RateBps   int      `json:"rate_bps" binding:"min=0,max=10000"`
+	Inclusive bool     `json:"inclusive"`
+	Tags      []string `json:"tags" binding:"omitempty,dive,required,max=50"`
+}
+
+type DiscountRequest struct {
+	Code        string     `json:"code" binding:"required,max=50"`
+	Name        string     `json:"name" binding:"required"`
+	Type        string     `json:"type" binding:"required,oneof=percent fixed"`
+	Value       int64      `json:"value" binding:"min=1"`
+	MinSubtotal int64      `json:"min_subtotal" binding:"min=0"`
+	IsActive    *bool      `json:"is_active"`
+	StartsAt    *time.Time `json:"starts_at"`
+	EndsAt      *time.Time `json:"ends_at"`
+}
+
+// PricingConfig is the pricing setup of a branch as returned by the API
+type PricingConfig struct {
+	Settings BranchPricing `json:"settings"`
+	TaxRates []TaxRate     `json:"tax_rates"`
+	Currency string        `json:"currency"`
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/faisd405/go-restapi-gin/src/app/pricing/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+)
+
+type SettingsRepository interface {
+	// GetByBranch returns the settings of a branch, or defaults when the
+	// branch has none yet
+	GetByBranch(branchID uint) (*model.BranchPricing, error)
+	Save(settings *model.BranchPricing) error
+}
+
+type settingsRepository struct {
+	database.Repository[model.BranchPricing]
+}
+
+func NewSettingsRepository(db *gorm.DB) SettingsRepository {
+	return &settingsRepository{Repository: database.NewRepository[model.BranchPricing](db)}
+}
+
+func (r *settingsRepository) GetByBranch(branchID uint) (*model.BranchPricing, error) {
+	settings, err := r.First(database.NewQuery().Eq("branch_id", branchID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &model.BranchPricing{BranchID: branchID}, nil
+	}
+	return settings, err
+}
+
+func (r *settingsRepository) Save(settings *model.BranchPricing) error {
+	return r.Upsert(settings, []string{"branch_id"},
+		"service_charge_bps", "service_charge_taxable", "rounding_increment", "updated_at")
+}
+
+type TaxRateRepository interface {
+	Create(rate *model.TaxRate) error
+	GetByID(id uint) (*model.TaxRate, error)
+	Update(rate *model.TaxRate) error
+	Delete(id uint) error
+	Find(q *database.Query) ([]model.TaxRate, error)
+}
+
+type taxRateRepository struct {
+	database.Repository[model.TaxRate]
+}
+
+func NewTaxRateRepository(db *gorm.DB) TaxRateRepository {
+	return &taxRateRepository{Repository: database.NewRepository[model.TaxRate](db)}
+}
+
+type DiscountRepository interface {
+	Create(discount *model.Discount) error
+	GetByID(id uint) (*model.Discount, error)
+	Update(discount *model.Discount) error
+	Delete(id uint) error
+	Find(q *database.Query) ([]model.Discount, error)
+	GetByCode(branchID uint, code string) (*model.Discount, error)
+}
+
+type discountRepository struct {
+	database.Repository[model.Discount]
+}
+
+func NewDiscountRepository(db *gorm.DB) DiscountRepository {
+	return &discountRepository{Repository: database.NewRepository[model.Discount](db)}
+}
+
+func (r *discountRepository) GetByCode(branchID uint, code string) (*model.Discount, error) {
+	return r.First(database.NewQuery().Eq("branch_id", branchID).Eq("code", code))
+}
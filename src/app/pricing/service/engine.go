@@ -0,0 +1,212 @@
+package service
+
+import (
+	"github.com/faisd405/go-restapi-gin/src/app/pricing/model"
+)
+
+// basisPoints is 100% expressed in basis points
+const basisPoints = 10000
+
+// currencyRounding is the default rounding increment of currencies whose
+// smallest coin is larger than their minor unit
+var currencyRounding = map[string]int64{
+	"CHF": 5,
+}
+
+// Calculate prices an order. It only uses integer minor-unit arithmetic;
+// every division rounds half up.
+//
+// Discounts are applied to the subtotal in order and spread over the lines
+// in proportion to their gross amount, so taxes are computed on what the
+// customer actually pays. The service charge is a share of the discounted
+// subtotal and is taxed by the untagged rates when ServiceChargeTaxable is
+// set. Taxes are computed per line and summed per rate.
+func Calculate(in model.Input) *model.Breakdown {
+	b := &model.Breakdown{
+		Currency:         in.Currency,
+		Lines:            make([]model.LineBreakdown, len(in.Lines)),
+		Discounts:        []model.DiscountLine{},
+		ServiceChargeBps: in.ServiceChargeBps,
+		Taxes:            make([]model.TaxLine, len(in.TaxRates)),
+	}
+
+	for i, rate := range in.TaxRates {
+		b.Taxes[i] = model.TaxLine{Name: rate.Name, RateBps: rate.RateBps, Inclusive: rate.Inclusive}
+	}
+
+	gross := make([]int64, len(in.Lines))
+	for i, line := range in.Lines {
+		gross[i] = line.UnitPrice * int64(line.Quantity)
+		b.Subtotal += gross[i]
+		b.Lines[i] = model.LineBreakdown{
+			Name:      line.Name,
+			Quantity:  line.Quantity,
+			UnitPrice: line.UnitPrice,
+			Gross:     gross[i],
+		}
+	}
+
+	remaining := b.Subtotal
+	for _, discount := range in.Discounts {
+		amount := discount.Amount
+		if discount.PercentBps > 0 {
+			amount = divRound(remaining*int64(discount.PercentBps), basisPoints)
+		}
+		if amount > remaining {
+			amount = remaining
+		}
+		if amount <= 0 {
+			continue
+		}
+		remaining -= amount
+		b.DiscountTotal += amount
+		b.Discounts = append(b.Discounts, model.DiscountLine{Code: discount.Code, Name: discount.Name, Amount: amount})
+	}
+
+	var net int64
+	for i, share := range Allocate(b.DiscountTotal, gross) {
+		line := &b.Lines[i]
+		line.Discount = share
+		line.Net = line.Gross - share
+		line.Tax, line.InclusiveTax = applyTaxes(b.Taxes, in.TaxRates, in.Lines[i].Tags, line.Net)
+		line.Total = line.Net + line.Tax
+		net += line.Net
+		b.TaxTotal += line.Tax
+		b.InclusiveTaxTotal += line.InclusiveTax
+	}
+
+	b.ServiceCharge = divRound(net*int64(in.ServiceChargeBps), basisPoints)
+	if in.ServiceChargeTaxable && b.ServiceCharge > 0 {
+		tax, inclusiveTax := applyTaxes(b.Taxes, in.TaxRates, nil, b.ServiceCharge)
+		b.TaxTotal += tax
+		b.InclusiveTaxTotal += inclusiveTax
+	}
+
+	raw := b.Subtotal - b.DiscountTotal + b.ServiceCharge + b.TaxTotal
+	b.Total = roundTo(raw, roundingIncrement(in))
+	b.Rounding = b.Total - raw
+
+	return b
+}
+
+// applyTaxes computes the taxes of an amount carrying tags, adds them to the
+// per-rate totals and returns the exclusive and inclusive tax. Several
+// inclusive rates share the tax part of the amount in proportion to their
+// rates. Exclusive rates are computed on the amount net of inclusive tax, so
+// taxes never compound.
+func applyTaxes(totals []model.TaxLine, rates []model.TaxRate, tags []string, amount int64) (int64, int64) {
+	var inclusiveBps int64
+	for _, rate := range rates {
+		if rate.Inclusive && appliesTo(rate, tags) {
+			inclusiveBps += int64(rate.RateBps)
+		}
+	}
+
+	inclusiveTax, base := int64(0), amount
+	if inclusiveBps > 0 {
+		base = divRound(amount*basisPoints, basisPoints+inclusiveBps)
+		inclusiveTax = amount - base
+	}
+
+	var exclusiveTax int64
+	inclusiveLeft, inclusiveBpsLeft := inclusiveTax, inclusiveBps
+	for i, rate := range rates {
+		if !appliesTo(rate, tags) {
+			continue
+		}
+
+		var tax int64
+		if rate.Inclusive {
+			// Sharing what is left makes the last rate take the remainder,
+			// so the shares add up to the inclusive tax exactly
+			tax = divRound(inclusiveLeft*int64(rate.RateBps), inclusiveBpsLeft)
+			inclusiveLeft -= tax
+			inclusiveBpsLeft -= int64(rate.RateBps)
+			totals[i].Taxable += base
+		} else {
+			tax = divRound(base*int64(rate.RateBps), basisPoints)
+			exclusiveTax += tax
+			totals[i].Taxable += base
+		}
+		totals[i].Amount += tax
+	}
+
+	return exclusiveTax, inclusiveTax
+}
+
+// appliesTo reports whether a rate applies to an amount carrying tags.
+// Untagged rates apply to everything.
+func appliesTo(rate model.TaxRate, tags []string) bool {
+	if len(rate.Tags) == 0 {
+		return true
+	}
+	for _, tag := range tags {
+		if rate.Tags.Contains(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allocate splits amount over weights in proportion, handing the rounding
+// remainder to the largest fractional shares so the parts add up exactly
+func Allocate(amount int64, weights []int64) []int64 {
+	shares := make([]int64, len(weights))
+
+	var total int64
+	for _, w := range weights {
+		total += w
+	}
+	if amount == 0 || total == 0 {
+		return shares
+	}
+
+	remainders := make([]int64, len(weights))
+	var allocated int64
+	for i, w := range weights {
+		shares[i] = amount * w / total
+		remainders[i] = amount * w % total
+		allocated += shares[i]
+	}
+
+	for left := amount - allocated; left > 0; left-- {
+		best := 0
+		for i := range remainders {
+			if remainders[i] > remainders[best] {
+				best = i
+			}
+		}
+		shares[best]++
+		remainders[best] = -1
+	}
+	return shares
+}
+
+func roundingIncrement(in model.Input) int64 {
+	if in.RoundingIncrement > 0 {
+		return in.RoundingIncrement
+	}
+	if increment, ok := currencyRounding[in.Currency]; ok {
+		return increment
+	}
+	return 1
+}
+
+// roundTo rounds amount half up to a multiple of increment
+func roundTo(amount, increment int64) int64 {
+	if increment <= 1 {
+		return amount
+	}
+	return divRound(amount, increment) * increment
+}
+
+// divRound divides rounding half away from zero
+func divRound(a, b int64) int64 {
+	if b == 0 {
+		return 0
+	}
+	if a < 0 {
+		return -divRound(-a, b)
+	}
+	return (a + b/2) / b
+}
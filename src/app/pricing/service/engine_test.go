@@ -0,0 +1,227 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/faisd405/go-restapi-gin/src/app/pricing/model"
+)
+
+func TestCalculate(t *testing.T) {
+	vat := model.TaxRate{Name: "VAT", RateBps: 1000}
+	vatIncl := model.TaxRate{Name: "VAT", RateBps: 1000, Inclusive: true}
+	cityIncl := model.TaxRate{Name: "City", RateBps: 500, Inclusive: true}
+	city := model.TaxRate{Name: "City", RateBps: 500}
+
+	type taxWant struct {
+		taxable, amount int64
+	}
+
+	tests := []struct {
+		name          string
+		in            model.Input
+		discountTotal int64
+		lineDiscounts []int64
+		serviceCharge int64
+		taxTotal      int64
+		inclusiveTax  int64
+		taxes         []taxWant
+		rounding      int64
+		total         int64
+	}{
+		{
+			name: "exclusive rate",
+			in: model.Input{
+				Lines:    []model.LineInput{{Name: "Soup", UnitPrice: 1000, Quantity: 2}},
+				TaxRates: []model.TaxRate{vat},
+			},
+			lineDiscounts: []int64{0},
+			taxTotal:      200,
+			taxes:         []taxWant{{2000, 200}},
+			total:         2200,
+		},
+		{
+			name: "inclusive rate",
+			in: model.Input{
+				Lines:    []model.LineInput{{Name: "Soup", UnitPrice: 1100, Quantity: 1}},
+				TaxRates: []model.TaxRate{vatIncl},
+			},
+			lineDiscounts: []int64{0},
+			inclusiveTax:  100,
+			taxes:         []taxWant{{1000, 100}},
+			total:         1100,
+		},
+		{
+			name: "inclusive rates share the tax part",
+			in: model.Input{
+				Lines:    []model.LineInput{{Name: "Soup", UnitPrice: 1150, Quantity: 1}},
+				TaxRates: []model.TaxRate{vatIncl, cityIncl},
+			},
+			lineDiscounts: []int64{0},
+			inclusiveTax:  150,
+			taxes:         []taxWant{{1000, 100}, {1000, 50}},
+			total:         1150,
+		},
+		{
+			name: "exclusive rate on the net of inclusive tax",
+			in: model.Input{
+				Lines:    []model.LineInput{{Name: "Soup", UnitPrice: 1100, Quantity: 1}},
+				TaxRates: []model.TaxRate{vatIncl, city},
+			},
+			lineDiscounts: []int64{0},
+			taxTotal:      50,
+			inclusiveTax:  100,
+			taxes:         []taxWant{{1000, 100}, {1000, 50}},
+			total:         1150,
+		},
+		{
+			name: "tagged rate only taxes matching lines",
+			in: model.Input{
+				Lines: []model.LineInput{
+					{Name: "Soup", UnitPrice: 1000, Quantity: 1},
+					{Name: "Beer", UnitPrice: 500, Quantity: 1, Tags: []string{"alcohol"}},
+				},
+				TaxRates: []model.TaxRate{vat, {Name: "Alcohol", RateBps: 2000, Tags: []string{"alcohol"}}},
+			},
+			lineDiscounts: []int64{0, 0},
+			taxTotal:      250,
+			taxes:         []taxWant{{1500, 150}, {500, 100}},
+			total:         1750,
+		},
+		{
+			name: "discount spread over lines by gross",
+			in: model.Input{
+				Lines: []model.LineInput{
+					{Name: "Soup", UnitPrice: 1000, Quantity: 1},
+					{Name: "Steak", UnitPrice: 3000, Quantity: 1},
+				},
+				TaxRates:  []model.TaxRate{vat},
+				Discounts: []model.DiscountInput{{Code: "TEN", PercentBps: 1000}},
+			},
+			discountTotal: 400,
+			lineDiscounts: []int64{100, 300},
+			taxTotal:      360,
+			taxes:         []taxWant{{3600, 360}},
+			total:         3960,
+		},
+		{
+			name: "fixed discounts capped at what is left",
+			in: model.Input{
+				Lines:     []model.LineInput{{Name: "Soup", UnitPrice: 500, Quantity: 1}},
+				Discounts: []model.DiscountInput{{Code: "A", Amount: 300}, {Code: "B", Amount: 300}, {Code: "C", Amount: 300}},
+			},
+			discountTotal: 500,
+			lineDiscounts: []int64{500},
+			total:         0,
+		},
+		{
+			name: "taxable service charge",
+			in: model.Input{
+				Lines:                []model.LineInput{{Name: "Soup", UnitPrice: 2000, Quantity: 1}},
+				TaxRates:             []model.TaxRate{vat},
+				ServiceChargeBps:     1000,
+				ServiceChargeTaxable: true,
+			},
+			lineDiscounts: []int64{0},
+			serviceCharge: 200,
+			taxTotal:      220,
+			taxes:         []taxWant{{2200, 220}},
+			total:         2420,
+		},
+		{
+			name: "CHF rounds up to five",
+			in: model.Input{
+				Currency: "CHF",
+				Lines:    []model.LineInput{{Name: "Soup", UnitPrice: 1003, Quantity: 1}},
+			},
+			lineDiscounts: []int64{0},
+			rounding:      2,
+			total:         1005,
+		},
+		{
+			name: "CHF rounds down to five",
+			in: model.Input{
+				Currency: "CHF",
+				Lines:    []model.LineInput{{Name: "Soup", UnitPrice: 1002, Quantity: 1}},
+			},
+			lineDiscounts: []int64{0},
+			rounding:      -2,
+			total:         1000,
+		},
+		{
+			name: "explicit increment overrides the currency",
+			in: model.Input{
+				Currency:          "CHF",
+				RoundingIncrement: 10,
+				Lines:             []model.LineInput{{Name: "Soup", UnitPrice: 1003, Quantity: 1}},
+			},
+			lineDiscounts: []int64{0},
+			rounding:      -3,
+			total:         1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Calculate(tt.in)
+
+			if b.DiscountTotal != tt.discountTotal {
+				t.Errorf("DiscountTotal = %d, want %d", b.DiscountTotal, tt.discountTotal)
+			}
+			for i, want := range tt.lineDiscounts {
+				if b.Lines[i].Discount != want {
+					t.Errorf("Lines[%d].Discount = %d, want %d", i, b.Lines[i].Discount, want)
+				}
+			}
+			if b.ServiceCharge != tt.serviceCharge {
+				t.Errorf("ServiceCharge = %d, want %d", b.ServiceCharge, tt.serviceCharge)
+			}
+			if b.TaxTotal != tt.taxTotal {
+				t.Errorf("TaxTotal = %d, want %d", b.TaxTotal, tt.taxTotal)
+			}
+			if b.InclusiveTaxTotal != tt.inclusiveTax {
+				t.Errorf("InclusiveTaxTotal = %d, want %d", b.InclusiveTaxTotal, tt.inclusiveTax)
+			}
+			for i, want := range tt.taxes {
+				if b.Taxes[i].Taxable != want.taxable || b.Taxes[i].Amount != want.amount {
+					t.Errorf("Taxes[%d] = %d on %d, want %d on %d",
+						i, b.Taxes[i].Amount, b.Taxes[i].Taxable, want.amount, want.taxable)
+				}
+			}
+			if b.Rounding != tt.rounding {
+				t.Errorf("Rounding = %d, want %d", b.Rounding, tt.rounding)
+			}
+			if b.Total != tt.total {
+				t.Errorf("Total = %d, want %d", b.Total, tt.total)
+			}
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int64
+		weights []int64
+		want    []int64
+	}{
+		{"proportional", 400, []int64{1000, 3000}, []int64{100, 300}},
+		{"remainder to the largest fraction", 100, []int64{1, 2}, []int64{33, 67}},
+		{"remainder ties go first", 10, []int64{1, 1, 1}, []int64{4, 3, 3}},
+		{"zero amount", 0, []int64{1, 2}, []int64{0, 0}},
+		{"zero weights", 5, []int64{0, 0}, []int64{0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Allocate(tt.amount, tt.weights)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Allocate() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("Allocate() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/pricing/model"
+	"github.com/faisd405/go-restapi-gin/src/app/pricing/repository"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidDiscountCode  = errors.New("discount code is not valid")
+	ErrDiscountMinSubtotal  = errors.New("order subtotal is below the minimum for this discount")
+	ErrInvalidPercent       = errors.New("percent discounts must be between 1 and 10000 basis points")
+	ErrInvalidDiscountRange = errors.New("ends_at must be after starts_at")
+)
+
+type PricingService interface {
+	Quote(req model.QuoteRequest) (*model.Breakdown, error)
+
+	GetConfig(actor utils.Actor, branchID uint) (*model.PricingConfig, error)
+	UpdateSettings(actor utils.Actor, branchID uint, req model.PricingSettingsRequest) (*model.BranchPricing, error)
+	CreateTaxRate(actor utils.Actor, branchID uint, req model.TaxRateRequest) (*model.TaxRate, error)
+	UpdateTaxRate(actor utils.Actor, id uint, req model.TaxRateRequest) (*model.TaxRate, error)
+	DeleteTaxRate(actor utils.Actor, id uint) error
+
+	GetDiscounts(actor utils.Actor, branchID uint) ([]model.Discount, error)
+	CreateDiscount(actor utils.Actor, branchID uint, req model.DiscountRequest) (*model.Discount, error)
+	UpdateDiscount(actor utils.Actor, id uint, req model.DiscountRequest) (*model.Discount, error)
+	DeleteDiscount(actor utils.Actor, id uint) error
+}
+
+type pricingService struct {
+	settingsRepo  repository.SettingsRepository
+	taxRateRepo   repository.TaxRateRepository
+	discountRepo  repository.DiscountRepository
+	restaurantSvc restaurantservice.RestaurantService
+}
+
+func NewPricingService(
+	settingsRepo repository.SettingsRepository,
+	taxRateRepo repository.TaxRateRepository,
+	discountRepo repository.DiscountRepository,
+	restaurantSvc restaurantservice.RestaurantService,
+) PricingService {
+	return &pricingService{
+		settingsRepo:  settingsRepo,
+		taxRateRepo:   taxRateRepo,
+		discountRepo:  discountRepo,
+		restaurantSvc: restaurantSvc,
+	}
+}
+
+// Quote prices lines with the current settings, tax rates and discount of
+// a branch
+func (s *pricingService) Quote(req model.QuoteRequest) (*model.Breakdown, error) {
+	branch, err := s.restaurantSvc.GetBranch(req.BranchID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.settingsRepo.GetByBranch(branch.ID)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := s.taxRateRepo.Find(database.NewQuery().Eq("branch_id", branch.ID).OrderBy("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	input := model.Input{
+		Currency:             branch.EffectiveCurrency(),
+		Lines:                req.Lines,
+		TaxRates:             rates,
+		ServiceChargeBps:     settings.ServiceChargeBps,
+		ServiceChargeTaxable: settings.ServiceChargeTaxable,
+		RoundingIncrement:    settings.RoundingIncrement,
+	}
+
+	if code := normalizeCode(req.DiscountCode); code != "" {
+		discount, err := s.validDiscount(branch.ID, code, req.Lines)
+		if err != nil {
+			return nil, err
+		}
+		input.Discounts = append(input.Discounts, discountInput(discount))
+	}
+	input.Discounts = append(input.Discounts, req.Discounts...)
+
+	return Calculate(input), nil
+}
+
+func (s *pricingService) GetConfig(actor utils.Actor, branchID uint) (*model.PricingConfig, error) {
+	branch, err := s.restaurantSvc.GetBranch(branchID, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.settingsRepo.GetByBranch(branchID)
+	if err != nil {
+		return nil, err
+	}
+	rates, err := s.taxRateRepo.Find(database.NewQuery().Eq("branch_id", branchID).OrderBy("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.PricingConfig{Settings: *settings, TaxRates: rates, Currency: branch.EffectiveCurrency()}, nil
+}
+
+func (s *pricingService) UpdateSettings(actor utils.Actor, branchID uint, req model.PricingSettingsRequest) (*model.BranchPricing, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	settings := &model.BranchPricing{
+		BranchID:             branchID,
+		ServiceChargeBps:     req.ServiceChargeBps,
+		ServiceChargeTaxable: req.ServiceChargeTaxable,
+		RoundingIncrement:    req.RoundingIncrement,
+	}
+	if err := s.settingsRepo.Save(settings); err != nil {
+		return nil, err
+	}
+
+	return s.settingsRepo.GetByBranch(branchID)
+}
+
+func (s *pricingService) CreateTaxRate(actor utils.Actor, branchID uint, req model.TaxRateRequest) (*model.TaxRate, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	rate := &model.TaxRate{BranchID: branchID}
+	applyTaxRateRequest(rate, req)
+
+	if err := s.taxRateRepo.Create(rate); err != nil {
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+func (s *pricingService) UpdateTaxRate(actor utils.Actor, id uint, req model.TaxRateRequest) (*model.TaxRate, error) {
+	rate, err := s.getManagedTaxRate(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyTaxRateRequest(rate, req)
+
+	if err := s.taxRateRepo.Update(rate); err != nil {
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+func (s *pricingService) DeleteTaxRate(actor utils.Actor, id uint) error {
+	if _, err := s.getManagedTaxRate(actor, id); err != nil {
+		return err
+	}
+
+	return s.taxRateRepo.Delete(id)
+}
+
+func (s *pricingService) GetDiscounts(actor utils.Actor, branchID uint) ([]model.Discount, error) {
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	return s.discountRepo.Find(database.NewQuery().Eq("branch_id", branchID).OrderBy("code"))
+}
+
+func (s *pricingService) CreateDiscount(actor utils.Actor, branchID uint, req model.DiscountRequest) (*model.Discount, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	discount := &model.Discount{BranchID: branchID, IsActive: true}
+	if err := applyDiscountRequest(discount, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.discountRepo.Create(discount); err != nil {
+		return nil, err
+	}
+
+	return discount, nil
+}
+
+func (s *pricingService) UpdateDiscount(actor utils.Actor, id uint, req model.DiscountRequest) (*model.Discount, error) {
+	discount, err := s.getManagedDiscount(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyDiscountRequest(discount, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.discountRepo.Update(discount); err != nil {
+		return nil, err
+	}
+
+	return discount, nil
+}
+
+func (s *pricingService) DeleteDiscount(actor utils.Actor, id uint) error {
+	if _, err := s.getManagedDiscount(actor, id); err != nil {
+		return err
+	}
+
+	return s.discountRepo.Delete(id)
+}
+
+// validDiscount loads a discount code and checks it can be used for lines
+// right now
+func (s *pricingService) validDiscount(branchID uint, code string, lines []model.LineInput) (*model.Discount, error) {
+	discount, err := s.discountRepo.GetByCode(branchID, code)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidDiscountCode
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !discount.IsValidAt(time.Now()) {
+		return nil, ErrInvalidDiscountCode
+	}
+
+	var subtotal int64
+	for _, line := range lines {
+		subtotal += line.UnitPrice * int64(line.Quantity)
+	}
+	if subtotal < discount.MinSubtotal {
+		return nil, ErrDiscountMinSubtotal
+	}
+
+	return discount, nil
+}
+
+func (s *pricingService) getManagedTaxRate(actor utils.Actor, id uint) (*model.TaxRate, error) {
+	rate, err := s.taxRateRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, rate.BranchID); err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+func (s *pricingService) getManagedDiscount(actor utils.Actor, id uint) (*model.Discount, error) {
+	discount, err := s.discountRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, discount.BranchID); err != nil {
+		return nil, err
+	}
+	return discount, nil
+}
+
+func discountInput(discount *model.Discount) model.DiscountInput {
+	input := model.DiscountInput{Code: discount.Code, Name: discount.Name}
+	if discount.Type == model.DiscountTypePercent {
+		input.PercentBps = int(discount.Value)
+	} else {
+		input.Amount = discount.Value
+	}
+	return input
+}
+
+func applyTaxRateRequest(rate *model.TaxRate, req model.TaxRateRequest) {
+	rate.Name = req.Name
+	rate.RateBps = req.RateBps
+	rate.Inclusive = req.Inclusive
+
+	rate.Tags = database.StringList{}
+	for _, tag := range req.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !rate.Tags.Contains(tag) {
+			rate.Tags = append(rate.Tags, tag)
+		}
+	}
+}
+
+func applyDiscountRequest(discount *model.Discount, req model.DiscountRequest) error {
+	if req.Type == model.DiscountTypePercent && req.Value > 10000 {
+		return ErrInvalidPercent
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return ErrInvalidDiscountRange
+	}
+
+	discount.Code = normalizeCode(req.Code)
+	discount.Name = req.Name
+	discount.Type = req.Type
+	discount.Value = req.Value
+	discount.MinSubtotal = req.MinSubtotal
+	discount.StartsAt = req.StartsAt
+	discount.EndsAt = req.EndsAt
+	if req.IsActive != nil {
+		discount.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
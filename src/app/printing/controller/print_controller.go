@@ -0,0 +1,231 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/faisd405/go-restapi-gin/src/app/printing/model"
+	"github.com/faisd405/go-restapi-gin/src/app/printing/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type PrintController struct {
+	printService service.PrintService
+}
+
+func NewPrintController(printService service.PrintService) *PrintController {
+	return &PrintController{printService: printService}
+}
+
+// GetJobs godoc
+// @Summary Get print jobs (Staff)
+// @Description Get the print jobs of a printer, newest first
+// @Tags printing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Printer ID"
+// @Param status query string false "queued, printed or failed"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /printers/{id}/jobs [get]
+func (ctrl *PrintController) GetJobs(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid printer ID", err.Error())
+		return
+	}
+
+	page, limit := utils.GetPagination(c)
+	filter := model.JobFilter{Status: c.Query("status")}
+
+	jobs, total, err := ctrl.printService.GetJobs(actor, id, page, limit, filter)
+	if err != nil {
+		printingErrorResponse(c, "Failed to retrieve print jobs", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Print jobs retrieved successfully",
+		utils.PaginatedData("jobs", jobs, page, limit, total))
+}
+
+// GetJob godoc
+// @Summary Get print job (Staff)
+// @Description Get the status of a print job
+// @Tags printing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Print job ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /print-jobs/{id} [get]
+func (ctrl *PrintController) GetJob(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid print job ID", err.Error())
+		return
+	}
+
+	job, err := ctrl.printService.GetJob(actor, id)
+	if err != nil {
+		printingErrorResponse(c, "Failed to retrieve print job", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Print job retrieved successfully", job)
+}
+
+// Retry godoc
+// @Summary Retry print job (Staff)
+// @Description Queue a failed print job again with all of its attempts, or print a printed one again
+// @Tags printing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Print job ID"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /print-jobs/{id}/retry [post]
+func (ctrl *PrintController) Retry(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid print job ID", err.Error())
+		return
+	}
+
+	job, err := ctrl.printService.Retry(actor, id)
+	if err != nil {
+		printingErrorResponse(c, "Print job retry failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Print job queued successfully", job)
+}
+
+// PrintTest godoc
+// @Summary Print test page (Admin/Manager)
+// @Description Queue a test page showing the connection settings and a ruler of the line width
+// @Tags printing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Printer ID"
+// @Success 201 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /printers/{id}/test [post]
+func (ctrl *PrintController) PrintTest(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid printer ID", err.Error())
+		return
+	}
+
+	job, err := ctrl.printService.PrintTest(actor, id)
+	if err != nil {
+		printingErrorResponse(c, "Test page failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Test page queued successfully", job)
+}
+
+// PrintTicket godoc
+// @Summary Reprint kitchen ticket (Staff/Station)
+// @Description Queue a kitchen ticket on the printers of its station, or on the printer given
+// @Tags printing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Ticket ID"
+// @Param print body model.PrintRequest false "Printer"
+// @Success 201 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /tickets/{id}/print [post]
+func (ctrl *PrintController) PrintTicket(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid ticket ID", err.Error())
+		return
+	}
+
+	var req model.PrintRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+
+	jobs, err := ctrl.printService.PrintTicket(actor, id, req)
+	if err != nil {
+		printingErrorResponse(c, "Ticket print failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Ticket queued successfully", jobs)
+}
+
+// PrintReceipt godoc
+// @Summary Print receipt (Staff)
+// @Description Queue the receipt of a paid order on the receipt printer of its branch, or on the printer given
+// @Tags printing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Param print body model.PrintRequest false "Printer"
+// @Success 201 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /orders/{id}/receipt/print [post]
+func (ctrl *PrintController) PrintReceipt(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID", err.Error())
+		return
+	}
+
+	var req model.PrintRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+
+	job, err := ctrl.printService.PrintReceipt(actor, id, req)
+	if err != nil {
+		printingErrorResponse(c, "Receipt print failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Receipt queued successfully", job)
+}
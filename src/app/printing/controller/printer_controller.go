@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	kitchencontroller "github.com/faisd405/go-restapi-gin/src/app/kitchen/controller"
+	kitchenservice "github.com/faisd405/go-restapi-gin/src/app/kitchen/service"
+	"github.com/faisd405/go-restapi-gin/src/app/printing/model"
+	"github.com/faisd405/go-restapi-gin/src/app/printing/service"
+	receiptcontroller "github.com/faisd405/go-restapi-gin/src/app/receipt/controller"
+	receiptservice "github.com/faisd405/go-restapi-gin/src/app/receipt/service"
+	"github.com/faisd405/go-restapi-gin/src/printer"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Error codes of the printing API
+const (
+	ErrCodePrinterInactive = "PRINTER_INACTIVE"
+	ErrCodeNoPrinter       = "NO_PRINTER"
+	ErrCodeJobNotRetryable = "JOB_NOT_RETRYABLE"
+)
+
+type PrinterController struct {
+	printerService service.PrinterService
+}
+
+func NewPrinterController(printerService service.PrinterService) *PrinterController {
+	return &PrinterController{printerService: printerService}
+}
+
+// GetPrinters godoc
+// @Summary Get printers (Admin/Manager)
+// @Description Get the printers registered for a branch
+// @Tags printing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/printers [get]
+func (ctrl *PrinterController) GetPrinters(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	printers, err := ctrl.printerService.GetPrinters(actor, id)
+	if err != nil {
+		printingErrorResponse(c, "Failed to retrieve printers", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Printers retrieved successfully", printers)
+}
+
+// GetPrinter godoc
+// @Summary Get printer (Admin/Manager)
+// @Description Get a printer by ID
+// @Tags printing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Printer ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /printers/{id} [get]
+func (ctrl *PrinterController) GetPrinter(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid printer ID", err.Error())
+		return
+	}
+
+	p, err := ctrl.printerService.GetPrinter(actor, id)
+	if err != nil {
+		printingErrorResponse(c, "Failed to retrieve printer", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Printer retrieved successfully", p)
+}
+
+// CreatePrinter godoc
+// @Summary Register printer (Admin/Manager)
+// @Description Register a thermal printer for a branch. Printers bound to a kitchen station print its tickets as they come in.
+// @Tags printing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param printer body model.PrinterRequest true "Printer"
+// @Success 201 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /branches/{id}/printers [post]
+func (ctrl *PrinterController) CreatePrinter(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.PrinterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	p, err := ctrl.printerService.CreatePrinter(actor, id, req)
+	if err != nil {
+		printingErrorResponse(c, "Printer creation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Printer created successfully", p)
+}
+
+// UpdatePrinter godoc
+// @Summary Update printer (Admin/Manager)
+// @Description Update a printer
+// @Tags printing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Printer ID"
+// @Param printer body model.PrinterRequest true "Printer"
+// @Success 200 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /printers/{id} [put]
+func (ctrl *PrinterController) UpdatePrinter(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid printer ID", err.Error())
+		return
+	}
+
+	var req model.PrinterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	p, err := ctrl.printerService.UpdatePrinter(actor, id, req)
+	if err != nil {
+		printingErrorResponse(c, "Printer update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Printer updated successfully", p)
+}
+
+// DeletePrinter godoc
+// @Summary Delete printer (Admin/Manager)
+// @Description Delete a printer. Jobs still queued for it fail.
+// @Tags printing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Printer ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /printers/{id} [delete]
+func (ctrl *PrinterController) DeletePrinter(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid printer ID", err.Error())
+		return
+	}
+
+	if err := ctrl.printerService.DeletePrinter(actor, id); err != nil {
+		printingErrorResponse(c, "Printer deletion failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Printer deleted successfully", nil)
+}
+
+func printingErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrPrinterInactive):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodePrinterInactive, message, err.Error())
+	case errors.Is(err, service.ErrNoPrinter):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeNoPrinter, message, err.Error())
+	case errors.Is(err, service.ErrJobNotRetryable):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeJobNotRetryable, message, err.Error())
+	case errors.Is(err, kitchenservice.ErrStationAccessDenied):
+		utils.ErrorResponseWithCode(c, http.StatusForbidden, kitchencontroller.ErrCodeStationAccessDenied, message, err.Error())
+	case errors.Is(err, receiptservice.ErrOrderNotPaid):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, receiptcontroller.ErrCodeOrderNotPaid, message, err.Error())
+	case errors.Is(err, printer.ErrInvalidAddress),
+		errors.Is(err, service.ErrStationBranchMismatch),
+		errors.Is(err, service.ErrPrinterBranchMismatch),
+		errors.Is(err, model.ErrInvalidJobStatus):
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, message, err.Error())
+	default:
+		utils.DatabaseErrorResponse(c, message, err)
+	}
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Job statuses. Queued jobs are sent in order, one printer at a time; a job
+// that cannot be delivered is tried again later and fails once it runs out
+// of attempts, which lets the jobs behind it through.
+const (
+	JobQueued  = "queued"
+	JobPrinted = "printed"
+	JobFailed  = "failed"
+)
+
+// Job kinds. Tickets and cancellations are rendered from the kitchen
+// ticket when they are sent; receipts and test pages when they are queued.
+const (
+	KindTicket       = "ticket"
+	KindCancellation = "cancellation"
+	KindReceipt      = "receipt"
+	KindTest         = "test"
+)
+
+var ErrInvalidJobStatus = errors.New("unknown print job status")
+
+// Job is a print job queued for a printer. SourceID is the kitchen ticket
+// of ticket jobs and the order of receipts.
+type Job struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	PrinterID     uint       `json:"printer_id" gorm:"not null;index"`
+	BranchID      uint       `json:"branch_id" gorm:"not null;index"`
+	Kind          string     `json:"kind" gorm:"type:varchar(20);not null"`
+	SourceID      *uint      `json:"source_id"`
+	Data          []byte     `json:"-" gorm:"type:bytea"`
+	Status        string     `json:"status" gorm:"type:varchar(20);not null;index"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	LastError     string     `json:"last_error" gorm:"type:text"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	PrintedAt     *time.Time `json:"printed_at"`
+	CreatedByID   *uint      `json:"created_by_id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (Job) TableName() string {
+	return "print_jobs"
+}
+
+// IsValidJobStatus reports whether status is one of the job statuses
+func IsValidJobStatus(status string) bool {
+	switch status {
+	case JobQueued, JobPrinted, JobFailed:
+		return true
+	}
+	return false
+}
+
+// Fail records a failed delivery. The job is tried again at retryAt, or
+// fails for good once it has used maxAttempts.
+func (j *Job) Fail(err error, maxAttempts int, retryAt time.Time) {
+	j.Attempts++
+	j.LastError = err.Error()
+	if j.Attempts >= maxAttempts {
+		j.Status = JobFailed
+		return
+	}
+	j.NextAttemptAt = retryAt
+}
+
+// Printed records a delivered job
+func (j *Job) Printed(now time.Time) {
+	j.Attempts++
+	j.Status = JobPrinted
+	j.LastError = ""
+	j.PrintedAt = &now
+}
+
+// Requeue puts a failed or printed job back into the queue to be sent
+// again, with all of its attempts
+func (j *Job) Requeue(now time.Time) {
+	j.Status = JobQueued
+	j.Attempts = 0
+	j.NextAttemptAt = now
+	j.PrintedAt = nil
+}
+
+// JobFilter narrows down the jobs of a printer
+type JobFilter struct {
+	Status string
+}
+
+// PrintRequest names the printer of a reprint. Without one, tickets go to
+// the printers of their station and receipts to the first receipt printer
+// of the branch.
+type PrintRequest struct {
+	PrinterID *uint `json:"printer_id"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Limits of the line width of printers, in characters at normal size. 80 mm
+// paper takes 48 characters in the standard font and 42 in a larger one,
+// 58 mm paper 32.
+const (
+	MinWidth     = 32
+	MaxWidth     = 64
+	DefaultWidth = 42
+)
+
+// Printer is a thermal printer of a branch. Printers bound to a kitchen
+// station print its tickets as they come in; receipt printers take the
+// receipts printed without naming a printer.
+type Printer struct {
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	BranchID       uint           `json:"branch_id" gorm:"not null;uniqueIndex:idx_printers_branch_name,where:deleted_at IS NULL"`
+	Name           string         `json:"name" gorm:"type:varchar(50);not null;uniqueIndex:idx_printers_branch_name,where:deleted_at IS NULL"`
+	Transport      string         `json:"transport" gorm:"type:varchar(10);not null"`
+	Address        string         `json:"address" gorm:"type:varchar(255);not null"`
+	Width          int            `json:"width" gorm:"not null"`
+	StationID      *uint          `json:"station_id" gorm:"index"`
+	PrintsReceipts bool           `json:"prints_receipts" gorm:"not null;default:false"`
+	IsActive       bool           `json:"is_active" gorm:"not null;default:true"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// PrinterRequest registers a printer. Addresses are host or host:port for
+// tcp printers, port 9100 by default, and a spool directory name for file
+// printers.
+type PrinterRequest struct {
+	Name           string `json:"name" binding:"required,max=50"`
+	Transport      string `json:"transport" binding:"required,oneof=tcp file"`
+	Address        string `json:"address" binding:"required,max=255"`
+	Width          int    `json:"width" binding:"omitempty,min=32,max=64"`
+	StationID      *uint  `json:"station_id"`
+	PrintsReceipts bool   `json:"prints_receipts"`
+	IsActive       *bool  `json:"is_active"`
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"time"
+
+	kitchenmodel "github.com/faisd405/go-restapi-gin/src/app/kitchen/model"
+	"github.com/faisd405/go-restapi-gin/src/app/printing/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+)
+
+// lockNamespace keeps the advisory locks of printer queues apart from other
+// advisory locks
+const lockNamespace = 46
+
+type PrinterRepository interface {
+	Create(printer *model.Printer) error
+	GetByID(id uint) (*model.Printer, error)
+	Update(printer *model.Printer) error
+	Delete(id uint) error
+	First(q *database.Query) (*model.Printer, error)
+	Find(q *database.Query) ([]model.Printer, error)
+	WithTx(tx *gorm.DB) PrinterRepository
+}
+
+type printerRepository struct {
+	database.Repository[model.Printer]
+}
+
+func NewPrinterRepository(db *gorm.DB) PrinterRepository {
+	return &printerRepository{Repository: database.NewRepository[model.Printer](db)}
+}
+
+func (r *printerRepository) WithTx(tx *gorm.DB) PrinterRepository {
+	return &printerRepository{Repository: r.Repository.WithTx(tx)}
+}
+
+type JobRepository interface {
+	Create(job *model.Job) error
+	GetByID(id uint) (*model.Job, error)
+	Update(job *model.Job) error
+	FindPage(q *database.Query) ([]model.Job, int64, error)
+	// EnqueueTickets queues new kitchen tickets, and cancellations of
+	// tickets that were cancelled, on the active printers of their
+	// stations
+	EnqueueTickets(tickets []kitchenmodel.Ticket) error
+	// FailQueued fails the queued jobs of a printer
+	FailQueued(printerID uint, reason string) error
+	// QueuedPrinters returns the printers with queued jobs
+	QueuedPrinters() ([]uint, error)
+	// LockQueue takes the queue of a printer until the surrounding
+	// transaction ends. It reports false when another dispatcher holds it.
+	LockQueue(printerID uint) (bool, error)
+	// Head returns the oldest queued job of a printer
+	Head(printerID uint) (*model.Job, error)
+	WithTx(tx *gorm.DB) JobRepository
+}
+
+type jobRepository struct {
+	database.Repository[model.Job]
+}
+
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{Repository: database.NewRepository[model.Job](db)}
+}
+
+func (r *jobRepository) EnqueueTickets(tickets []kitchenmodel.Ticket) error {
+	if len(tickets) == 0 {
+		return nil
+	}
+
+	stationIDs := make([]uint, 0, len(tickets))
+	for _, ticket := range tickets {
+		stationIDs = append(stationIDs, ticket.StationID)
+	}
+	var printers []model.Printer
+	err := r.DB().Where("station_id IN ? AND is_active", stationIDs).Order("id").Find(&printers).Error
+	if err != nil {
+		return database.TranslateError(err)
+	}
+
+	now := time.Now()
+	var jobs []model.Job
+	for _, ticket := range tickets {
+		kind := model.KindTicket
+		if ticket.Status == kitchenmodel.TicketCancelled {
+			kind = model.KindCancellation
+		}
+		for _, printer := range printers {
+			if *printer.StationID != ticket.StationID {
+				continue
+			}
+			ticketID := ticket.ID
+			jobs = append(jobs, model.Job{
+				PrinterID:     printer.ID,
+				BranchID:      ticket.BranchID,
+				Kind:          kind,
+				SourceID:      &ticketID,
+				Status:        model.JobQueued,
+				NextAttemptAt: now,
+			})
+		}
+	}
+	if len(jobs) == 0 {
+		return nil
+	}
+	return database.TranslateError(r.DB().Create(&jobs).Error)
+}
+
+func (r *jobRepository) FailQueued(printerID uint, reason string) error {
+	err := r.DB().Model(&model.Job{}).
+		Where("printer_id = ? AND status = ?", printerID, model.JobQueued).
+		Updates(map[string]interface{}{"status": model.JobFailed, "last_error": reason}).Error
+	return database.TranslateError(err)
+}
+
+func (r *jobRepository) QueuedPrinters() ([]uint, error) {
+	var ids []uint
+	err := r.DB().Model(&model.Job{}).
+		Where("status = ?", model.JobQueued).
+		Distinct().
+		Pluck("printer_id", &ids).Error
+	if err != nil {
+		return nil, database.TranslateError(err)
+	}
+	return ids, nil
+}
+
+func (r *jobRepository) LockQueue(printerID uint) (bool, error) {
+	var locked bool
+	err := r.DB().Raw("SELECT pg_try_advisory_xact_lock(?, ?)", lockNamespace, printerID).Scan(&locked).Error
+	if err != nil {
+		return false, database.TranslateError(err)
+	}
+	return locked, nil
+}
+
+func (r *jobRepository) Head(printerID uint) (*model.Job, error) {
+	return r.First(database.NewQuery().
+		Eq("printer_id", printerID).
+		Eq("status", model.JobQueued).
+		OrderBy("id"))
+}
+
+func (r *jobRepository) WithTx(tx *gorm.DB) JobRepository {
+	return &jobRepository{Repository: r.Repository.WithTx(tx)}
+}
@@ -0,0 +1,410 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	kitchenmodel "github.com/faisd405/go-restapi-gin/src/app/kitchen/model"
+	kitchenrepository "github.com/faisd405/go-restapi-gin/src/app/kitchen/repository"
+	kitchenservice "github.com/faisd405/go-restapi-gin/src/app/kitchen/service"
+	orderservice "github.com/faisd405/go-restapi-gin/src/app/order/service"
+	"github.com/faisd405/go-restapi-gin/src/app/printing/model"
+	"github.com/faisd405/go-restapi-gin/src/app/printing/repository"
+	receiptservice "github.com/faisd405/go-restapi-gin/src/app/receipt/service"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	tablerepository "github.com/faisd405/go-restapi-gin/src/app/table/repository"
+	"github.com/faisd405/go-restapi-gin/src/config"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/printer"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+// maxRetryDelay caps the growing delay between attempts of a job
+const maxRetryDelay = 5 * time.Minute
+
+type PrintService interface {
+	GetJobs(actor utils.Actor, printerID uint, page, limit int, filter model.JobFilter) ([]model.Job, int64, error)
+	GetJob(actor utils.Actor, id uint) (*model.Job, error)
+	// Retry queues a failed job again, or reprints a printed one
+	Retry(actor utils.Actor, id uint) (*model.Job, error)
+
+	PrintTest(actor utils.Actor, printerID uint) (*model.Job, error)
+	// PrintTicket reprints a kitchen ticket
+	PrintTicket(actor utils.Actor, ticketID uint, req model.PrintRequest) ([]model.Job, error)
+	// PrintReceipt prints the receipt of a paid order
+	PrintReceipt(actor utils.Actor, orderID uint, req model.PrintRequest) (*model.Job, error)
+
+	// ProcessQueues sends the jobs that are due, the queues of different
+	// printers in parallel and the jobs of one printer in order
+	ProcessQueues(ctx context.Context) error
+}
+
+type printService struct {
+	printerRepo   repository.PrinterRepository
+	jobRepo       repository.JobRepository
+	ticketRepo    kitchenrepository.TicketRepository
+	stationRepo   kitchenrepository.StationRepository
+	tableRepo     tablerepository.TableRepository
+	ticketSvc     kitchenservice.TicketService
+	orderSvc      orderservice.OrderService
+	receiptSvc    receiptservice.ReceiptService
+	restaurantSvc restaurantservice.RestaurantService
+	transports    map[string]printer.Transport
+	txManager     database.TxManager
+	cfg           config.PrintingConfig
+	now           func() time.Time
+}
+
+func NewPrintService(
+	printerRepo repository.PrinterRepository,
+	jobRepo repository.JobRepository,
+	ticketRepo kitchenrepository.TicketRepository,
+	stationRepo kitchenrepository.StationRepository,
+	tableRepo tablerepository.TableRepository,
+	ticketSvc kitchenservice.TicketService,
+	orderSvc orderservice.OrderService,
+	receiptSvc receiptservice.ReceiptService,
+	restaurantSvc restaurantservice.RestaurantService,
+	transports []printer.Transport,
+	txManager database.TxManager,
+	cfg config.PrintingConfig,
+) PrintService {
+	byName := make(map[string]printer.Transport, len(transports))
+	for _, transport := range transports {
+		byName[transport.Name()] = transport
+	}
+
+	return &printService{
+		printerRepo:   printerRepo,
+		jobRepo:       jobRepo,
+		ticketRepo:    ticketRepo,
+		stationRepo:   stationRepo,
+		tableRepo:     tableRepo,
+		ticketSvc:     ticketSvc,
+		orderSvc:      orderSvc,
+		receiptSvc:    receiptSvc,
+		restaurantSvc: restaurantSvc,
+		transports:    byName,
+		txManager:     txManager,
+		cfg:           cfg,
+		now:           time.Now,
+	}
+}
+
+func (s *printService) GetJobs(actor utils.Actor, printerID uint, page, limit int, filter model.JobFilter) ([]model.Job, int64, error) {
+	p, err := getAccessiblePrinter(s.printerRepo, s.restaurantSvc, actor, printerID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q := database.NewQuery().Eq("printer_id", p.ID)
+	if filter.Status != "" {
+		if !model.IsValidJobStatus(filter.Status) {
+			return nil, 0, model.ErrInvalidJobStatus
+		}
+		q.Eq("status", filter.Status)
+	}
+
+	return s.jobRepo.FindPage(q.OrderByDesc("id").Paginate(utils.Offset(page, limit), limit))
+}
+
+func (s *printService) GetJob(actor utils.Actor, id uint) (*model.Job, error) {
+	job, err := s.jobRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, job.BranchID); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *printService) Retry(actor utils.Actor, id uint) (*model.Job, error) {
+	job, err := s.GetJob(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status == model.JobQueued {
+		return nil, ErrJobNotRetryable
+	}
+
+	job.Requeue(s.now())
+	if err := s.jobRepo.Update(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *printService) PrintTest(actor utils.Actor, printerID uint) (*model.Job, error) {
+	p, err := getAccessiblePrinter(s.printerRepo, s.restaurantSvc, actor, printerID)
+	if err != nil {
+		return nil, err
+	}
+	if !p.IsActive {
+		return nil, ErrPrinterInactive
+	}
+
+	job := s.newJob(actor, p, model.KindTest, nil, renderTest(p, s.now()))
+	if err := s.jobRepo.Create(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *printService) PrintTicket(actor utils.Actor, ticketID uint, req model.PrintRequest) ([]model.Job, error) {
+	ticket, err := s.ticketSvc.GetTicket(actor, ticketID)
+	if err != nil {
+		return nil, err
+	}
+
+	var printers []model.Printer
+	if req.PrinterID != nil {
+		p, err := s.printerFor(*req.PrinterID, ticket.BranchID)
+		if err != nil {
+			return nil, err
+		}
+		printers = []model.Printer{*p}
+	} else {
+		printers, err = s.printerRepo.Find(database.NewQuery().
+			Eq("station_id", ticket.StationID).
+			Eq("is_active", true).
+			OrderBy("id"))
+		if err != nil {
+			return nil, err
+		}
+		if len(printers) == 0 {
+			return nil, ErrNoPrinter
+		}
+	}
+
+	kind := model.KindTicket
+	if ticket.Status == kitchenmodel.TicketCancelled {
+		kind = model.KindCancellation
+	}
+	jobs := make([]model.Job, len(printers))
+	err = s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		jobRepo := s.jobRepo.WithTx(tx)
+		for i := range printers {
+			jobs[i] = *s.newJob(actor, &printers[i], kind, &ticket.ID, nil)
+			if err := jobRepo.Create(&jobs[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (s *printService) PrintReceipt(actor utils.Actor, orderID uint, req model.PrintRequest) (*model.Job, error) {
+	order, err := s.orderSvc.GetOrder(actor, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var p *model.Printer
+	if req.PrinterID != nil {
+		if p, err = s.printerFor(*req.PrinterID, order.BranchID); err != nil {
+			return nil, err
+		}
+	} else {
+		p, err = s.printerRepo.First(database.NewQuery().
+			Eq("branch_id", order.BranchID).
+			Eq("prints_receipts", true).
+			Eq("is_active", true).
+			OrderBy("id"))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNoPrinter
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	doc, err := s.receiptSvc.GetReceiptDocument(actor, order.ID, p.Width)
+	if err != nil {
+		return nil, err
+	}
+
+	job := s.newJob(actor, p, model.KindReceipt, &order.ID, renderDocument(doc))
+	if err := s.jobRepo.Create(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *printService) ProcessQueues(ctx context.Context) error {
+	printerIDs, err := s.jobRepo.QueuedPrinters()
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, printerID := range printerIDs {
+		wg.Add(1)
+		go func(printerID uint) {
+			defer wg.Done()
+			for {
+				sent, err := s.sendNext(ctx, printerID)
+				if err != nil {
+					log.Printf("Failed to process print queue of printer %d: %v", printerID, err)
+				}
+				if err != nil || !sent {
+					return
+				}
+			}
+		}(printerID)
+	}
+	wg.Wait()
+	return nil
+}
+
+// sendNext sends the oldest queued job of a printer when it is due. The
+// queue stays locked while the job is sent so no other dispatcher sends
+// the jobs behind it first. It reports whether the job was printed and the
+// next one may follow.
+func (s *printService) sendNext(ctx context.Context, printerID uint) (bool, error) {
+	var sent bool
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		jobRepo := s.jobRepo.WithTx(tx)
+
+		locked, err := jobRepo.LockQueue(printerID)
+		if err != nil || !locked {
+			return err
+		}
+		job, err := jobRepo.Head(printerID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		now := s.now()
+		if job.NextAttemptAt.After(now) {
+			return nil
+		}
+
+		// Inactive printers keep their jobs until they are switched on again
+		p, err := s.printerRepo.GetByID(printerID)
+		if err != nil {
+			return err
+		}
+		if !p.IsActive {
+			return nil
+		}
+
+		data, err := s.jobData(job, p)
+		if err != nil {
+			job.Fail(err, 0, now)
+			return jobRepo.Update(job)
+		}
+		transport, ok := s.transports[p.Transport]
+		if !ok {
+			job.Fail(fmt.Errorf("transport %q is not available", p.Transport), 0, now)
+			return jobRepo.Update(job)
+		}
+
+		if err := transport.Send(ctx, p.Address, data); err != nil {
+			job.Fail(err, s.cfg.MaxAttempts, now.Add(s.retryDelay(job.Attempts)))
+			return jobRepo.Update(job)
+		}
+		job.Printed(s.now())
+		sent = true
+		return jobRepo.Update(job)
+	})
+	return sent, err
+}
+
+// jobData returns the bytes of a job, rendering tickets as they are now
+func (s *printService) jobData(job *model.Job, p *model.Printer) ([]byte, error) {
+	switch job.Kind {
+	case model.KindTicket, model.KindCancellation:
+		if job.SourceID == nil {
+			return nil, errors.New("ticket job without a ticket")
+		}
+		ticket, err := s.ticketRepo.GetByID(*job.SourceID)
+		if err != nil {
+			return nil, fmt.Errorf("loading ticket: %w", err)
+		}
+		data := ticketData{ticket: ticket, cancelled: job.Kind == model.KindCancellation}
+		if station, err := s.stationRepo.GetByID(ticket.StationID); err == nil {
+			data.station = station.Name
+		}
+		branch, err := s.restaurantSvc.GetBranch(ticket.BranchID, false)
+		if err != nil {
+			return nil, fmt.Errorf("loading branch: %w", err)
+		}
+		data.location = branch.Location()
+		if ticket.TableID != nil {
+			data.table = strconv.FormatUint(uint64(*ticket.TableID), 10)
+			if table, err := s.tableRepo.GetByID(*ticket.TableID); err == nil {
+				data.table = table.Name
+			}
+		}
+		return renderTicket(data, p.Width), nil
+	default:
+		return job.Data, nil
+	}
+}
+
+// retryDelay doubles the configured delay with every attempt made
+func (s *printService) retryDelay(attempts int) time.Duration {
+	delay := s.cfg.RetryDelay
+	for i := 0; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// printerFor loads a printer asked for by ID, which has to be an active
+// printer of the branch
+func (s *printService) printerFor(printerID, branchID uint) (*model.Printer, error) {
+	p, err := s.printerRepo.GetByID(printerID)
+	if err != nil {
+		return nil, err
+	}
+	if p.BranchID != branchID {
+		return nil, ErrPrinterBranchMismatch
+	}
+	if !p.IsActive {
+		return nil, ErrPrinterInactive
+	}
+	return p, nil
+}
+
+func (s *printService) newJob(actor utils.Actor, p *model.Printer, kind string, sourceID *uint, data []byte) *model.Job {
+	job := &model.Job{
+		PrinterID:     p.ID,
+		BranchID:      p.BranchID,
+		Kind:          kind,
+		SourceID:      sourceID,
+		Data:          data,
+		Status:        model.JobQueued,
+		NextAttemptAt: s.now(),
+		CreatedByID:   utils.ActorID(actor),
+	}
+	return job
+}
+
+// StartDispatcher sends queued print jobs in the background, checking the
+// queues every interval
+func StartDispatcher(printSvc PrintService, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := printSvc.ProcessQueues(context.Background()); err != nil {
+				log.Println("Failed to process print queues:", err)
+			}
+		}
+	}()
+}
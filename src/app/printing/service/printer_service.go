@@ -0,0 +1,170 @@
+package service
+
+import (
+	"errors"
+
+	kitchenrepository "github.com/faisd405/go-restapi-gin/src/app/kitchen/repository"
+	"github.com/faisd405/go-restapi-gin/src/app/printing/model"
+	"github.com/faisd405/go-restapi-gin/src/app/printing/repository"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/printer"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrStationBranchMismatch = errors.New("station belongs to a different branch")
+	ErrPrinterBranchMismatch = errors.New("printer belongs to a different branch")
+	ErrPrinterInactive       = errors.New("printer is not active")
+	ErrNoPrinter             = errors.New("no active printer to print on")
+	ErrJobNotRetryable       = errors.New("only failed and printed jobs can be sent again")
+)
+
+type PrinterService interface {
+	GetPrinters(actor utils.Actor, branchID uint) ([]model.Printer, error)
+	GetPrinter(actor utils.Actor, id uint) (*model.Printer, error)
+	CreatePrinter(actor utils.Actor, branchID uint, req model.PrinterRequest) (*model.Printer, error)
+	UpdatePrinter(actor utils.Actor, id uint, req model.PrinterRequest) (*model.Printer, error)
+	// DeletePrinter removes a printer and fails the jobs still queued for it
+	DeletePrinter(actor utils.Actor, id uint) error
+}
+
+type printerService struct {
+	printerRepo   repository.PrinterRepository
+	jobRepo       repository.JobRepository
+	stationRepo   kitchenrepository.StationRepository
+	restaurantSvc restaurantservice.RestaurantService
+	txManager     database.TxManager
+}
+
+func NewPrinterService(
+	printerRepo repository.PrinterRepository,
+	jobRepo repository.JobRepository,
+	stationRepo kitchenrepository.StationRepository,
+	restaurantSvc restaurantservice.RestaurantService,
+	txManager database.TxManager,
+) PrinterService {
+	return &printerService{
+		printerRepo:   printerRepo,
+		jobRepo:       jobRepo,
+		stationRepo:   stationRepo,
+		restaurantSvc: restaurantSvc,
+		txManager:     txManager,
+	}
+}
+
+func (s *printerService) GetPrinters(actor utils.Actor, branchID uint) ([]model.Printer, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	return s.printerRepo.Find(database.NewQuery().Eq("branch_id", branchID).OrderBy("name"))
+}
+
+func (s *printerService) GetPrinter(actor utils.Actor, id uint) (*model.Printer, error) {
+	return getAccessiblePrinter(s.printerRepo, s.restaurantSvc, actor, id)
+}
+
+func (s *printerService) CreatePrinter(actor utils.Actor, branchID uint, req model.PrinterRequest) (*model.Printer, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	p := &model.Printer{BranchID: branchID, IsActive: true}
+	if err := s.applyPrinterRequest(p, req); err != nil {
+		return nil, err
+	}
+	if err := s.printerRepo.Create(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *printerService) UpdatePrinter(actor utils.Actor, id uint, req model.PrinterRequest) (*model.Printer, error) {
+	p, err := getAccessiblePrinter(s.printerRepo, s.restaurantSvc, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyPrinterRequest(p, req); err != nil {
+		return nil, err
+	}
+	if err := s.printerRepo.Update(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *printerService) DeletePrinter(actor utils.Actor, id uint) error {
+	p, err := getAccessiblePrinter(s.printerRepo, s.restaurantSvc, actor, id)
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		if err := s.jobRepo.WithTx(tx).FailQueued(p.ID, "printer was removed"); err != nil {
+			return err
+		}
+		return s.printerRepo.WithTx(tx).Delete(p.ID)
+	})
+}
+
+// applyPrinterRequest checks the address against the transport and the
+// station against the branch of the printer
+func (s *printerService) applyPrinterRequest(p *model.Printer, req model.PrinterRequest) error {
+	address := req.Address
+	switch req.Transport {
+	case printer.TransportTCP:
+		var err error
+		if address, err = printer.TCPAddress(address); err != nil {
+			return err
+		}
+	case printer.TransportFile:
+		if err := printer.CheckSpoolName(address); err != nil {
+			return err
+		}
+	}
+
+	if req.StationID != nil {
+		station, err := s.stationRepo.GetByID(*req.StationID)
+		if err != nil {
+			return err
+		}
+		if station.BranchID != p.BranchID {
+			return ErrStationBranchMismatch
+		}
+	}
+
+	p.Name = req.Name
+	p.Transport = req.Transport
+	p.Address = address
+	p.Width = req.Width
+	if p.Width == 0 {
+		p.Width = model.DefaultWidth
+	}
+	p.StationID = req.StationID
+	p.PrintsReceipts = req.PrintsReceipts
+	if req.IsActive != nil {
+		p.IsActive = *req.IsActive
+	}
+	return nil
+}
+
+// getAccessiblePrinter loads a printer of a branch the actor works at
+func getAccessiblePrinter(printerRepo repository.PrinterRepository, checker utils.BranchAccessChecker, actor utils.Actor, id uint) (*model.Printer, error) {
+	p, err := printerRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(checker, actor, p.BranchID); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	kitchenmodel "github.com/faisd405/go-restapi-gin/src/app/kitchen/model"
+	ordermodel "github.com/faisd405/go-restapi-gin/src/app/order/model"
+	"github.com/faisd405/go-restapi-gin/src/app/printing/model"
+	receiptmodel "github.com/faisd405/go-restapi-gin/src/app/receipt/model"
+	"github.com/faisd405/go-restapi-gin/src/escpos"
+)
+
+// ticketData is everything printed on a kitchen ticket
+type ticketData struct {
+	ticket    *kitchenmodel.Ticket
+	station   string
+	table     string
+	location  *time.Location
+	cancelled bool
+}
+
+// renderTicket prints a kitchen ticket in large type so it can be read
+// from a distance. Cancellations repeat the ticket under a banner.
+func renderTicket(data ticketData, width int) []byte {
+	ticket := data.ticket
+	b := escpos.New()
+
+	b.Align(escpos.AlignCenter).Bold(true).Size(2, 2)
+	for _, line := range wrap(data.station, width/2) {
+		b.Line(line)
+	}
+	if data.cancelled {
+		b.Line("CANCELLED")
+	}
+	b.Size(1, 1).Bold(false)
+
+	service := "Dine-in"
+	if ticket.OrderType == ordermodel.TypeTakeaway {
+		service = "Takeaway"
+	}
+	b.Align(escpos.AlignLeft).Size(1, 2)
+	b.Line(pair(fmt.Sprintf("Order #%d", ticket.OrderID), service, width))
+	b.Size(1, 1)
+	if data.table != "" {
+		b.Bold(true).Line("Table " + data.table).Bold(false)
+	}
+	b.Line(pair(fmt.Sprintf("Ticket #%d", ticket.ID), ticket.CreatedAt.In(data.location).Format("15:04"), width))
+	b.Line(strings.Repeat("-", width))
+
+	for _, line := range ticket.Lines {
+		b.Bold(true).Size(1, 2)
+		for _, text := range wrap(fmt.Sprintf("%d x %s", line.Quantity, line.Name), width) {
+			b.Line(text)
+		}
+		b.Size(1, 1).Bold(false)
+		for _, option := range line.Modifiers {
+			for _, text := range wrap(option.OptionName, width-4) {
+				b.Line("  + " + text)
+			}
+		}
+		for _, text := range wrap(line.Notes, width-4) {
+			b.Line("  ! " + text)
+		}
+	}
+
+	if ticket.Notes != "" {
+		b.Line(strings.Repeat("-", width))
+		b.Bold(true).Line("Notes:").Bold(false)
+		for _, text := range wrap(ticket.Notes, width) {
+			b.Line(text)
+		}
+	}
+
+	return b.Feed(3).Cut().Bytes()
+}
+
+// renderDocument prints a receipt or invoice laid out by the receipt
+// service. Its lines are already fitted to the width of the printer.
+func renderDocument(doc *receiptmodel.Document) []byte {
+	b := escpos.New()
+	for _, line := range doc.Lines {
+		switch {
+		case line.Rule:
+			b.Line(strings.Repeat("-", doc.Width))
+		case line.Bold:
+			b.Bold(true).Line(line.Text).Bold(false)
+		default:
+			b.Line(line.Text)
+		}
+	}
+	return b.Feed(3).Cut().Bytes()
+}
+
+// renderTest prints a page to check the connection and the line width of
+// a printer
+func renderTest(printer *model.Printer, now time.Time) []byte {
+	b := escpos.New()
+	b.Align(escpos.AlignCenter).Bold(true).Size(2, 2).Line("TEST PAGE").Size(1, 1).Bold(false)
+	b.Line(printer.Name)
+	b.Align(escpos.AlignLeft)
+	b.Line(strings.Repeat("-", printer.Width))
+	b.Line(pair("Transport", printer.Transport, printer.Width))
+	b.Line(pair("Address", printer.Address, printer.Width))
+	b.Line(pair("Width", fmt.Sprintf("%d characters", printer.Width), printer.Width))
+	b.Line(pair("Printed", now.UTC().Format("2006-01-02 15:04 UTC"), printer.Width))
+	b.Line(strings.Repeat("-", printer.Width))
+
+	// The ruler ends exactly at the right edge when the width is right
+	ruler := make([]byte, printer.Width)
+	for i := range ruler {
+		ruler[i] = byte('0' + (i+1)%10)
+	}
+	b.Line(string(ruler))
+	b.Bold(true).Line("Bold text").Bold(false)
+	b.Size(2, 2).Line("Large text").Size(1, 1)
+	return b.Feed(3).Cut().Bytes()
+}
+
+// pair puts label and value on one line of width characters, the value
+// right-aligned
+func pair(label, value string, width int) string {
+	gap := width - utf8.RuneCountInString(label) - utf8.RuneCountInString(value)
+	return label + strings.Repeat(" ", max(gap, 1)) + value
+}
+
+// wrap breaks text into lines of at most width characters
+func wrap(text string, width int) []string {
+	width = max(width, 1)
+	var lines []string
+	var line []rune
+	for _, word := range strings.Fields(text) {
+		runes := []rune(word)
+		for len(runes) > width {
+			if len(line) > 0 {
+				lines = append(lines, string(line))
+				line = nil
+			}
+			lines = append(lines, string(runes[:width]))
+			runes = runes[width:]
+		}
+		if len(line) > 0 && len(line)+1+len(runes) > width {
+			lines = append(lines, string(line))
+			line = nil
+		}
+		if len(line) > 0 {
+			line = append(line, ' ')
+		}
+		line = append(line, runes...)
+	}
+	if len(line) > 0 {
+		lines = append(lines, string(line))
+	}
+	return lines
+}
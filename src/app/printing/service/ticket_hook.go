@@ -0,0 +1,22 @@
+package service
+
+import (
+	kitchenmodel "github.com/faisd405/go-restapi-gin/src/app/kitchen/model"
+	kitchenservice "github.com/faisd405/go-restapi-gin/src/app/kitchen/service"
+	"github.com/faisd405/go-restapi-gin/src/app/printing/repository"
+	"gorm.io/gorm"
+)
+
+type ticketPrintHook struct {
+	jobRepo repository.JobRepository
+}
+
+// NewTicketPrintHook returns the kitchen ticket hook that queues new
+// tickets, and slips for cancelled ones, on the printers of their stations
+func NewTicketPrintHook(jobRepo repository.JobRepository) kitchenservice.TicketHook {
+	return &ticketPrintHook{jobRepo: jobRepo}
+}
+
+func (h *ticketPrintHook) OnTickets(tx *gorm.DB, tickets []kitchenmodel.Ticket) error {
+	return h.jobRepo.WithTx(tx).EnqueueTickets(tickets)
+}
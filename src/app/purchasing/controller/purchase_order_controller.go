@@ -0,0 +1,373 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/faisd405/go-restapi-gin/src/app/purchasing/model"
+	"github.com/faisd405/go-restapi-gin/src/app/purchasing/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type PurchaseOrderController struct {
+	purchaseOrderService service.PurchaseOrderService
+}
+
+func NewPurchaseOrderController(purchaseOrderService service.PurchaseOrderService) *PurchaseOrderController {
+	return &PurchaseOrderController{purchaseOrderService: purchaseOrderService}
+}
+
+// GetPurchaseOrders godoc
+// @Summary Get purchase orders (Admin/Manager)
+// @Description Get the purchase orders of a branch, newest first
+// @Tags purchasing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param status query string false "draft, sent, partially_received or received"
+// @Param supplier_id query int false "Supplier ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/purchase-orders [get]
+func (ctrl *PurchaseOrderController) GetPurchaseOrders(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	filter := model.PurchaseOrderFilter{Status: c.Query("status")}
+	if value := c.Query("supplier_id"); value != "" {
+		supplierID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid supplier ID", err.Error())
+			return
+		}
+		filter.SupplierID = uint(supplierID)
+	}
+
+	page, limit := utils.GetPagination(c)
+
+	orders, total, err := ctrl.purchaseOrderService.GetPurchaseOrders(actor, id, page, limit, filter)
+	if err != nil {
+		purchasingErrorResponse(c, "Failed to retrieve purchase orders", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Purchase orders retrieved successfully",
+		utils.PaginatedData("purchase_orders", orders, page, limit, total))
+}
+
+// GetPurchaseOrder godoc
+// @Summary Get purchase order (Admin/Manager)
+// @Description Get a purchase order with its lines
+// @Tags purchasing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /purchase-orders/{id} [get]
+func (ctrl *PurchaseOrderController) GetPurchaseOrder(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid purchase order ID", err.Error())
+		return
+	}
+
+	order, err := ctrl.purchaseOrderService.GetPurchaseOrder(actor, id)
+	if err != nil {
+		purchasingErrorResponse(c, "Failed to retrieve purchase order", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Purchase order retrieved successfully", order)
+}
+
+// CreatePurchaseOrder godoc
+// @Summary Create purchase order (Admin/Manager)
+// @Description Create a draft purchase order. Lines without a unit cost take it from the supplier's catalog.
+// @Tags purchasing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param order body model.PurchaseOrderRequest true "Purchase order"
+// @Success 201 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /branches/{id}/purchase-orders [post]
+func (ctrl *PurchaseOrderController) CreatePurchaseOrder(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.PurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	order, err := ctrl.purchaseOrderService.CreatePurchaseOrder(actor, id, req)
+	if err != nil {
+		purchasingErrorResponse(c, "Failed to create purchase order", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Purchase order created successfully", order)
+}
+
+// UpdatePurchaseOrder godoc
+// @Summary Update purchase order (Admin/Manager)
+// @Description Replace the supplier, note and lines of a draft purchase order
+// @Tags purchasing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Purchase order ID"
+// @Param order body model.PurchaseOrderRequest true "Purchase order"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /purchase-orders/{id} [put]
+func (ctrl *PurchaseOrderController) UpdatePurchaseOrder(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid purchase order ID", err.Error())
+		return
+	}
+
+	var req model.PurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	order, err := ctrl.purchaseOrderService.UpdatePurchaseOrder(actor, id, req)
+	if err != nil {
+		purchasingErrorResponse(c, "Failed to update purchase order", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Purchase order updated successfully", order)
+}
+
+// DeletePurchaseOrder godoc
+// @Summary Delete purchase order (Admin/Manager)
+// @Description Delete a draft purchase order
+// @Tags purchasing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /purchase-orders/{id} [delete]
+func (ctrl *PurchaseOrderController) DeletePurchaseOrder(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid purchase order ID", err.Error())
+		return
+	}
+
+	if err := ctrl.purchaseOrderService.DeletePurchaseOrder(actor, id); err != nil {
+		purchasingErrorResponse(c, "Failed to delete purchase order", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Purchase order deleted successfully", nil)
+}
+
+// SendPurchaseOrder godoc
+// @Summary Send purchase order (Admin/Manager)
+// @Description Mark a draft purchase order as sent to the supplier. Sent orders can no longer be changed.
+// @Tags purchasing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /purchase-orders/{id}/send [post]
+func (ctrl *PurchaseOrderController) SendPurchaseOrder(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid purchase order ID", err.Error())
+		return
+	}
+
+	order, err := ctrl.purchaseOrderService.SendPurchaseOrder(actor, id)
+	if err != nil {
+		purchasingErrorResponse(c, "Failed to send purchase order", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Purchase order sent successfully", order)
+}
+
+// ReceivePurchaseOrder godoc
+// @Summary Receive purchase order (Admin/Manager)
+// @Description Record a delivery of a sent purchase order. The quantities received are booked as purchases on stock.
+// @Tags purchasing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Purchase order ID"
+// @Param delivery body model.ReceiveRequest true "Delivery"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /purchase-orders/{id}/receive [post]
+func (ctrl *PurchaseOrderController) ReceivePurchaseOrder(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid purchase order ID", err.Error())
+		return
+	}
+
+	var req model.ReceiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	order, err := ctrl.purchaseOrderService.ReceivePurchaseOrder(actor, id, req)
+	if err != nil {
+		purchasingErrorResponse(c, "Failed to receive purchase order", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Delivery recorded successfully", order)
+}
+
+// ExportPurchaseOrder godoc
+// @Summary Export purchase order (Admin/Manager)
+// @Description Download a purchase order as PDF or CSV
+// @Tags purchasing
+// @Produce application/pdf,text/csv
+// @Security ApiKeyAuth
+// @Param id path int true "Purchase order ID"
+// @Param format query string false "pdf (default) or csv"
+// @Success 200 {file} file
+// @Failure 404 {object} utils.Response
+// @Router /purchase-orders/{id}/export [get]
+func (ctrl *PurchaseOrderController) ExportPurchaseOrder(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid purchase order ID", err.Error())
+		return
+	}
+
+	rendered, err := ctrl.purchaseOrderService.ExportPurchaseOrder(actor, id, c.DefaultQuery("format", model.FormatPDF))
+	if err != nil {
+		purchasingErrorResponse(c, "Failed to export purchase order", err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", rendered.Filename))
+	c.Data(http.StatusOK, rendered.ContentType, rendered.Body)
+}
+
+// GetSuggestions godoc
+// @Summary Get suggested purchase orders (Admin/Manager)
+// @Description Propose purchase orders for the ingredients at or below their reorder level, from the supplier that sells each cheapest. Ingredients already on an open purchase order are left out.
+// @Tags purchasing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/purchase-orders/suggestions [get]
+func (ctrl *PurchaseOrderController) GetSuggestions(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	suggestions, err := ctrl.purchaseOrderService.GetSuggestions(actor, id)
+	if err != nil {
+		purchasingErrorResponse(c, "Failed to suggest purchase orders", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Suggested purchase orders retrieved successfully", suggestions)
+}
+
+// CreateSuggested godoc
+// @Summary Create suggested purchase orders (Admin/Manager)
+// @Description Save the suggested purchase orders that have a supplier as drafts
+// @Tags purchasing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 201 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/purchase-orders/suggestions [post]
+func (ctrl *PurchaseOrderController) CreateSuggested(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	orders, err := ctrl.purchaseOrderService.CreateSuggested(actor, id)
+	if err != nil {
+		purchasingErrorResponse(c, "Failed to create suggested purchase orders", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Suggested purchase orders created successfully", orders)
+}
@@ -0,0 +1,220 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	inventoryservice "github.com/faisd405/go-restapi-gin/src/app/inventory/service"
+	"github.com/faisd405/go-restapi-gin/src/app/purchasing/model"
+	"github.com/faisd405/go-restapi-gin/src/app/purchasing/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Error codes of the purchasing API
+const (
+	ErrCodeSupplierInactive      = "SUPPLIER_INACTIVE"
+	ErrCodePurchaseOrderNotDraft = "PURCHASE_ORDER_NOT_DRAFT"
+	ErrCodePurchaseOrderNotSent  = "PURCHASE_ORDER_NOT_SENT"
+)
+
+type SupplierController struct {
+	supplierService service.SupplierService
+}
+
+func NewSupplierController(supplierService service.SupplierService) *SupplierController {
+	return &SupplierController{supplierService: supplierService}
+}
+
+// GetSuppliers godoc
+// @Summary Get suppliers (Admin/Manager)
+// @Description Get the suppliers of a branch with their catalogs
+// @Tags purchasing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/suppliers [get]
+func (ctrl *SupplierController) GetSuppliers(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	suppliers, err := ctrl.supplierService.GetSuppliers(actor, id)
+	if err != nil {
+		purchasingErrorResponse(c, "Failed to retrieve suppliers", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Suppliers retrieved successfully", suppliers)
+}
+
+// GetSupplier godoc
+// @Summary Get supplier (Admin/Manager)
+// @Description Get a supplier with its catalog
+// @Tags purchasing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Supplier ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /suppliers/{id} [get]
+func (ctrl *SupplierController) GetSupplier(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid supplier ID", err.Error())
+		return
+	}
+
+	supplier, err := ctrl.supplierService.GetSupplier(actor, id)
+	if err != nil {
+		purchasingErrorResponse(c, "Failed to retrieve supplier", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Supplier retrieved successfully", supplier)
+}
+
+// CreateSupplier godoc
+// @Summary Create supplier (Admin/Manager)
+// @Description Create a supplier of a branch. The catalog lists the ingredients it sells and their unit cost.
+// @Tags purchasing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param supplier body model.SupplierRequest true "Supplier"
+// @Success 201 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /branches/{id}/suppliers [post]
+func (ctrl *SupplierController) CreateSupplier(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.SupplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	supplier, err := ctrl.supplierService.CreateSupplier(actor, id, req)
+	if err != nil {
+		purchasingErrorResponse(c, "Failed to create supplier", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Supplier created successfully", supplier)
+}
+
+// UpdateSupplier godoc
+// @Summary Update supplier (Admin/Manager)
+// @Description Replace a supplier and its catalog
+// @Tags purchasing
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Supplier ID"
+// @Param supplier body model.SupplierRequest true "Supplier"
+// @Success 200 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /suppliers/{id} [put]
+func (ctrl *SupplierController) UpdateSupplier(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid supplier ID", err.Error())
+		return
+	}
+
+	var req model.SupplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	supplier, err := ctrl.supplierService.UpdateSupplier(actor, id, req)
+	if err != nil {
+		purchasingErrorResponse(c, "Failed to update supplier", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Supplier updated successfully", supplier)
+}
+
+// DeleteSupplier godoc
+// @Summary Delete supplier (Admin/Manager)
+// @Description Delete a supplier. Its purchase orders are kept.
+// @Tags purchasing
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Supplier ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /suppliers/{id} [delete]
+func (ctrl *SupplierController) DeleteSupplier(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid supplier ID", err.Error())
+		return
+	}
+
+	if err := ctrl.supplierService.DeleteSupplier(actor, id); err != nil {
+		purchasingErrorResponse(c, "Failed to delete supplier", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Supplier deleted successfully", nil)
+}
+
+func purchasingErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrSupplierInactive):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeSupplierInactive, message, err.Error())
+	case errors.Is(err, service.ErrNotDraft):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodePurchaseOrderNotDraft, message, err.Error())
+	case errors.Is(err, service.ErrNotReceivable):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodePurchaseOrderNotSent, message, err.Error())
+	case errors.Is(err, service.ErrInvalidFormat):
+		utils.ErrorResponse(c, http.StatusBadRequest, message, err.Error())
+	case errors.Is(err, inventoryservice.ErrIngredientNotFound):
+		utils.ErrorResponse(c, http.StatusNotFound, message, err.Error())
+	case errors.Is(err, service.ErrDuplicateIngredient),
+		errors.Is(err, service.ErrSupplierBranchMismatch),
+		errors.Is(err, service.ErrLineNotFound),
+		errors.Is(err, service.ErrOverReceipt),
+		errors.Is(err, model.ErrInvalidStatus):
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, message, err.Error())
+	default:
+		utils.DatabaseErrorResponse(c, message, err)
+	}
+}
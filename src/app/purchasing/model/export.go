@@ -0,0 +1,19 @@
+package model
+
+// Export formats of purchase orders
+const (
+	FormatPDF = "pdf"
+	FormatCSV = "csv"
+)
+
+// IsValidFormat reports whether format is one of the export formats
+func IsValidFormat(format string) bool {
+	return format == FormatPDF || format == FormatCSV
+}
+
+// Rendered is a purchase order in an export format
+type Rendered struct {
+	ContentType string
+	Filename    string
+	Body        []byte
+}
@@ -0,0 +1,127 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Purchase order statuses. Drafts can be changed and deleted; once sent to
+// the supplier an order is received in one or more deliveries.
+const (
+	StatusDraft             = "draft"
+	StatusSent              = "sent"
+	StatusPartiallyReceived = "partially_received"
+	StatusReceived          = "received"
+)
+
+var ErrInvalidStatus = errors.New("unknown purchase order status")
+
+// PurchaseOrder is an order of ingredients from a supplier. Costs are in
+// minor units of Currency, the currency of the branch.
+type PurchaseOrder struct {
+	ID          uint                `json:"id" gorm:"primaryKey"`
+	BranchID    uint                `json:"branch_id" gorm:"not null;index"`
+	SupplierID  uint                `json:"supplier_id" gorm:"not null;index"`
+	Supplier    *Supplier           `json:"supplier,omitempty" gorm:"foreignKey:SupplierID"`
+	Status      string              `json:"status" gorm:"type:varchar(20);not null;index"`
+	Currency    string              `json:"currency" gorm:"type:char(3)"`
+	Note        string              `json:"note" gorm:"type:text"`
+	Total       int64               `json:"total" gorm:"not null;default:0"`
+	Lines       []PurchaseOrderLine `json:"lines" gorm:"foreignKey:PurchaseOrderID"`
+	CreatedByID *uint               `json:"created_by_id"`
+	SentAt      *time.Time          `json:"sent_at"`
+	ReceivedAt  *time.Time          `json:"received_at"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+// PurchaseOrderLine is an ingredient ordered. Name and Unit are copied from
+// the ingredient so the order reads the same after it is renamed.
+type PurchaseOrderLine struct {
+	ID               uint    `json:"id" gorm:"primaryKey"`
+	PurchaseOrderID  uint    `json:"purchase_order_id" gorm:"not null;index"`
+	IngredientID     uint    `json:"ingredient_id" gorm:"not null;index"`
+	Name             string  `json:"name" gorm:"type:varchar(100);not null"`
+	Unit             string  `json:"unit" gorm:"type:varchar(10);not null"`
+	Quantity         float64 `json:"quantity" gorm:"type:numeric(14,3);not null"`
+	ReceivedQuantity float64 `json:"received_quantity" gorm:"type:numeric(14,3);not null;default:0"`
+	UnitCost         int64   `json:"unit_cost" gorm:"not null;default:0"`
+	Total            int64   `json:"total" gorm:"not null;default:0"`
+}
+
+// Outstanding is the quantity still to be delivered
+func (l *PurchaseOrderLine) Outstanding() float64 {
+	return math.Max(l.Quantity-l.ReceivedQuantity, 0)
+}
+
+// LineTotal is the cost of quantity units at unitCost, rounded to the minor unit
+func LineTotal(quantity float64, unitCost int64) int64 {
+	return int64(math.Round(quantity * float64(unitCost)))
+}
+
+// IsOpen reports whether goods of the order are still to be delivered
+func (o *PurchaseOrder) IsOpen() bool {
+	return o.Status != StatusReceived
+}
+
+// IsValidStatus reports whether status is one of the purchase order statuses
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusDraft, StatusSent, StatusPartiallyReceived, StatusReceived:
+		return true
+	}
+	return false
+}
+
+// PurchaseOrderRequest creates a draft or replaces its lines. Lines without
+// a unit cost take the cost from the supplier's catalog.
+type PurchaseOrderRequest struct {
+	SupplierID uint                       `json:"supplier_id" binding:"required"`
+	Note       string                     `json:"note" binding:"max=1000"`
+	Lines      []PurchaseOrderLineRequest `json:"lines" binding:"required,min=1,max=200,dive"`
+}
+
+type PurchaseOrderLineRequest struct {
+	IngredientID uint    `json:"ingredient_id" binding:"required"`
+	Quantity     float64 `json:"quantity" binding:"gt=0,lte=1000000"`
+	UnitCost     *int64  `json:"unit_cost" binding:"omitempty,gte=0"`
+}
+
+// ReceiveRequest records a delivery of a sent purchase order
+type ReceiveRequest struct {
+	Lines []ReceiveLineRequest `json:"lines" binding:"required,min=1,dive"`
+	Note  string               `json:"note" binding:"max=500"`
+}
+
+type ReceiveLineRequest struct {
+	LineID   uint    `json:"line_id" binding:"required"`
+	Quantity float64 `json:"quantity" binding:"gt=0,lte=1000000"`
+}
+
+// PurchaseOrderFilter narrows down the purchase orders of a branch
+type PurchaseOrderFilter struct {
+	Status     string
+	SupplierID uint
+}
+
+// Suggestion is a purchase order proposed for the ingredients at or below
+// their reorder level. Ingredients no active supplier sells are suggested
+// without a supplier.
+type Suggestion struct {
+	SupplierID *uint            `json:"supplier_id"`
+	Supplier   *Supplier        `json:"supplier,omitempty"`
+	Lines      []SuggestionLine `json:"lines"`
+	Total      int64            `json:"total"`
+}
+
+type SuggestionLine struct {
+	IngredientID uint    `json:"ingredient_id"`
+	Name         string  `json:"name"`
+	Unit         string  `json:"unit"`
+	Stock        float64 `json:"stock"`
+	ReorderLevel float64 `json:"reorder_level"`
+	Quantity     float64 `json:"quantity"`
+	UnitCost     int64   `json:"unit_cost"`
+	Total        int64   `json:"total"`
+}
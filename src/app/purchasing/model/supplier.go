@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Supplier is a business a branch buys ingredients from. Its catalog lists
+// the ingredients it sells and their unit cost.
+type Supplier struct {
+	ID          uint                 `json:"id" gorm:"primaryKey"`
+	BranchID    uint                 `json:"branch_id" gorm:"not null;index"`
+	Name        string               `json:"name" gorm:"type:varchar(100);not null"`
+	ContactName string               `json:"contact_name" gorm:"type:varchar(100)"`
+	Email       string               `json:"email" gorm:"type:varchar(255)"`
+	Phone       string               `json:"phone" gorm:"type:varchar(30)"`
+	Notes       string               `json:"notes" gorm:"type:text"`
+	IsActive    bool                 `json:"is_active" gorm:"not null;default:true"`
+	Catalog     []SupplierIngredient `json:"catalog" gorm:"foreignKey:SupplierID"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt       `json:"-" gorm:"index"`
+}
+
+// SupplierIngredient is an entry of a supplier's catalog. UnitCost is in
+// minor units per unit of the ingredient.
+type SupplierIngredient struct {
+	SupplierID   uint  `json:"-" gorm:"primaryKey"`
+	IngredientID uint  `json:"ingredient_id" gorm:"primaryKey;index"`
+	UnitCost     int64 `json:"unit_cost" gorm:"not null;default:0"`
+}
+
+func (SupplierIngredient) TableName() string {
+	return "supplier_ingredients"
+}
+
+// SupplierRequest creates or replaces a supplier with its catalog
+type SupplierRequest struct {
+	Name        string                      `json:"name" binding:"required,max=100"`
+	ContactName string                      `json:"contact_name" binding:"max=100"`
+	Email       string                      `json:"email" binding:"omitempty,email,max=255"`
+	Phone       string                      `json:"phone" binding:"max=30"`
+	Notes       string                      `json:"notes" binding:"max=1000"`
+	IsActive    *bool                       `json:"is_active"`
+	Catalog     []SupplierIngredientRequest `json:"catalog" binding:"dive"`
+}
+
+type SupplierIngredientRequest struct {
+	IngredientID uint  `json:"ingredient_id" binding:"required"`
+	UnitCost     int64 `json:"unit_cost" binding:"gte=0"`
+}
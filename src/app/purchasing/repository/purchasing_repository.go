@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"github.com/faisd405/go-restapi-gin/src/app/purchasing/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SupplierRepository interface {
+	Create(supplier *model.Supplier) error
+	GetByID(id uint) (*model.Supplier, error)
+	Update(supplier *model.Supplier) error
+	Delete(id uint) error
+	Find(q *database.Query) ([]model.Supplier, error)
+	// ReplaceCatalog deletes the catalog of supplier and inserts catalog in
+	// its place
+	ReplaceCatalog(supplier *model.Supplier, catalog []model.SupplierIngredient) error
+	WithTx(tx *gorm.DB) SupplierRepository
+}
+
+type supplierRepository struct {
+	database.Repository[model.Supplier]
+}
+
+func NewSupplierRepository(db *gorm.DB) SupplierRepository {
+	return &supplierRepository{Repository: database.NewRepository[model.Supplier](db)}
+}
+
+// GetByID loads a supplier with its catalog
+func (r *supplierRepository) GetByID(id uint) (*model.Supplier, error) {
+	return r.FindByID(id, database.NewQuery().Preload("Catalog"))
+}
+
+// Update saves the supplier columns only; the catalog is managed by
+// ReplaceCatalog
+func (r *supplierRepository) Update(supplier *model.Supplier) error {
+	return database.TranslateError(r.DB().Omit("Catalog").Save(supplier).Error)
+}
+
+func (r *supplierRepository) ReplaceCatalog(supplier *model.Supplier, catalog []model.SupplierIngredient) error {
+	if err := r.DB().Where("supplier_id = ?", supplier.ID).Delete(&model.SupplierIngredient{}).Error; err != nil {
+		return database.TranslateError(err)
+	}
+	for i := range catalog {
+		catalog[i].SupplierID = supplier.ID
+	}
+	if len(catalog) > 0 {
+		if err := r.DB().Create(&catalog).Error; err != nil {
+			return database.TranslateError(err)
+		}
+	}
+	supplier.Catalog = catalog
+	return nil
+}
+
+func (r *supplierRepository) WithTx(tx *gorm.DB) SupplierRepository {
+	return &supplierRepository{Repository: r.Repository.WithTx(tx)}
+}
+
+type PurchaseOrderRepository interface {
+	Create(order *model.PurchaseOrder) error
+	GetByID(id uint) (*model.PurchaseOrder, error)
+	GetForUpdate(id uint) (*model.PurchaseOrder, error)
+	Update(order *model.PurchaseOrder) error
+	Delete(id uint) error
+	Find(q *database.Query) ([]model.PurchaseOrder, error)
+	FindPage(q *database.Query) ([]model.PurchaseOrder, int64, error)
+	ReplaceLines(order *model.PurchaseOrder, lines []model.PurchaseOrderLine) error
+	SaveLines(lines []model.PurchaseOrderLine) error
+	WithTx(tx *gorm.DB) PurchaseOrderRepository
+}
+
+type purchaseOrderRepository struct {
+	database.Repository[model.PurchaseOrder]
+}
+
+func NewPurchaseOrderRepository(db *gorm.DB) PurchaseOrderRepository {
+	return &purchaseOrderRepository{Repository: database.NewRepository[model.PurchaseOrder](db)}
+}
+
+// GetByID loads a purchase order with its lines and supplier, also when the
+// supplier was deleted since
+func (r *purchaseOrderRepository) GetByID(id uint) (*model.PurchaseOrder, error) {
+	var order model.PurchaseOrder
+	err := r.DB().
+		Preload("Supplier", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Preload("Lines", orderLines).
+		First(&order, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetForUpdate loads a purchase order with its lines and locks the order
+// row until the surrounding transaction ends
+func (r *purchaseOrderRepository) GetForUpdate(id uint) (*model.PurchaseOrder, error) {
+	var order model.PurchaseOrder
+	err := r.DB().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Lines", orderLines).
+		First(&order, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// Update saves the order columns only; lines are managed by ReplaceLines
+// and SaveLines
+func (r *purchaseOrderRepository) Update(order *model.PurchaseOrder) error {
+	return database.TranslateError(r.DB().Omit("Supplier", "Lines").Save(order).Error)
+}
+
+// Delete removes a purchase order with its lines
+func (r *purchaseOrderRepository) Delete(id uint) error {
+	if err := r.DB().Where("purchase_order_id = ?", id).Delete(&model.PurchaseOrderLine{}).Error; err != nil {
+		return database.TranslateError(err)
+	}
+	return r.Repository.Delete(id)
+}
+
+// ReplaceLines deletes the lines of order and inserts lines in their place
+func (r *purchaseOrderRepository) ReplaceLines(order *model.PurchaseOrder, lines []model.PurchaseOrderLine) error {
+	if err := r.DB().Where("purchase_order_id = ?", order.ID).Delete(&model.PurchaseOrderLine{}).Error; err != nil {
+		return database.TranslateError(err)
+	}
+	for i := range lines {
+		lines[i].PurchaseOrderID = order.ID
+	}
+	if err := r.DB().Create(&lines).Error; err != nil {
+		return database.TranslateError(err)
+	}
+	order.Lines = lines
+	return nil
+}
+
+// SaveLines updates existing purchase order lines
+func (r *purchaseOrderRepository) SaveLines(lines []model.PurchaseOrderLine) error {
+	for i := range lines {
+		if err := r.DB().Save(&lines[i]).Error; err != nil {
+			return database.TranslateError(err)
+		}
+	}
+	return nil
+}
+
+// orderLines keeps the lines of a purchase order in the order they were added
+func orderLines(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
+
+func (r *purchaseOrderRepository) WithTx(tx *gorm.DB) PurchaseOrderRepository {
+	return &purchaseOrderRepository{Repository: r.Repository.WithTx(tx)}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/faisd405/go-restapi-gin/src/app/purchasing/model"
+	restaurantmodel "github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	"github.com/faisd405/go-restapi-gin/src/pdf"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+)
+
+// Layout of the PDF export, in points. The lines are set in Courier so the
+// columns line up.
+const (
+	pageMargin   = 56
+	titleSize    = 16
+	textSize     = 10
+	tableSize    = 9
+	lineSpacing  = 1.4
+	ruleWidth    = 0.5
+	nameColumn   = 36
+	amountColumn = 14
+	numberColumn = 11
+	unitColumn   = 4
+	// rowWidth is the characters in a table row
+	rowWidth   = nameColumn + numberColumn + unitColumn + 2*amountColumn + 4
+	ellipsis   = "..."
+	dateFormat = "2006-01-02"
+)
+
+var csvHeader = []string{"ingredient_id", "ingredient", "unit", "quantity", "received_quantity", "unit_cost", "total"}
+
+// renderCSV writes the lines of a purchase order as a spreadsheet, one row
+// per line, with costs as decimals in the order currency. Text cells are
+// escaped so spreadsheets do not run them as formulas.
+func renderCSV(order *model.PurchaseOrder) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	for _, line := range order.Lines {
+		err := w.Write([]string{
+			strconv.FormatUint(uint64(line.IngredientID), 10),
+			utils.CSVText(line.Name),
+			utils.CSVText(line.Unit),
+			quantity(line.Quantity),
+			quantity(line.ReceivedQuantity),
+			utils.FormatMoney(line.UnitCost, order.Currency),
+			utils.FormatMoney(line.Total, order.Currency),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderPDF lays out a purchase order on A4 pages: the branch and supplier,
+// then a table of the lines, continued on further pages when needed
+func renderPDF(order *model.PurchaseOrder, branch *restaurantmodel.Branch) []byte {
+	header := []string{branch.Name, branch.AddressLine1}
+	if branch.AddressLine2 != "" {
+		header = append(header, branch.AddressLine2)
+	}
+	header = append(header, "")
+
+	date := order.CreatedAt
+	if order.SentAt != nil {
+		date = *order.SentAt
+	}
+	header = append(header, "Date: "+date.In(branch.Location()).Format(dateFormat))
+	header = append(header, "Status: "+strings.ReplaceAll(order.Status, "_", " "))
+	header = append(header, "")
+	if order.Supplier != nil {
+		header = append(header, "Supplier: "+order.Supplier.Name)
+		for _, contact := range []string{order.Supplier.ContactName, order.Supplier.Email, order.Supplier.Phone} {
+			if contact != "" {
+				header = append(header, contact)
+			}
+		}
+	}
+
+	rows := []string{tableRow("Ingredient", "Quantity", "Unit", "Unit cost", "Total")}
+	for _, line := range order.Lines {
+		rows = append(rows, tableRow(
+			line.Name,
+			quantity(line.Quantity),
+			line.Unit,
+			utils.FormatMoney(line.UnitCost, order.Currency),
+			utils.FormatMoney(line.Total, order.Currency),
+		))
+	}
+	rows = append(rows, "", tableRow("Total "+order.Currency, "", "", "", utils.FormatMoney(order.Total, order.Currency)))
+	if order.Note != "" {
+		rows = append(rows, "")
+		rows = append(rows, wrap("Note: "+order.Note, rowWidth)...)
+	}
+
+	out := pdf.New()
+	page := out.AddPage(pdf.A4Width, pdf.A4Height)
+	y := pdf.A4Height - pageMargin - titleSize
+	page.Text(pageMargin, y, pdf.HelveticaBold, titleSize, fmt.Sprintf("Purchase order #%d", order.ID))
+	y -= titleSize * lineSpacing
+
+	for _, text := range header {
+		y -= textSize * lineSpacing
+		page.Text(pageMargin, y, pdf.Helvetica, textSize, text)
+	}
+	y -= textSize * lineSpacing
+
+	leading := tableSize * lineSpacing
+	for i, row := range rows {
+		if y-leading < pageMargin {
+			page = out.AddPage(pdf.A4Width, pdf.A4Height)
+			y = pdf.A4Height - pageMargin
+		}
+		y -= leading
+		font := pdf.Courier
+		if i == 0 {
+			font = pdf.CourierBold
+		}
+		page.Text(pageMargin, y, font, tableSize, row)
+		if i == 0 {
+			page.Line(pageMargin, y-tableSize/3, pdf.A4Width-pageMargin, y-tableSize/3, ruleWidth)
+		}
+	}
+	return out.Bytes()
+}
+
+// tableRow lays out a line of the PDF table: the name on the left, the
+// numbers right aligned
+func tableRow(name, qty, unit, unitCost, total string) string {
+	return fmt.Sprintf("%-*s %*s %-*s %*s %*s",
+		nameColumn, truncate(name, nameColumn),
+		numberColumn, qty,
+		unitColumn, unit,
+		amountColumn, unitCost,
+		amountColumn, total)
+}
+
+// truncate shortens text to width characters
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	return string(runes[:width-len(ellipsis)]) + ellipsis
+}
+
+// wrap breaks text into lines of at most width characters at spaces
+func wrap(text string, width int) []string {
+	var lines []string
+	var line []rune
+	for _, word := range strings.Fields(text) {
+		runes := []rune(word)
+		for len(runes) > width {
+			if len(line) > 0 {
+				lines = append(lines, string(line))
+				line = nil
+			}
+			lines = append(lines, string(runes[:width]))
+			runes = runes[width:]
+		}
+		if len(line) > 0 && len(line)+1+len(runes) > width {
+			lines = append(lines, string(line))
+			line = nil
+		}
+		if len(line) > 0 {
+			line = append(line, ' ')
+		}
+		line = append(line, runes...)
+	}
+	if len(line) > 0 {
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+// quantity formats a stock quantity without trailing zeros
+func quantity(q float64) string {
+	return strconv.FormatFloat(q, 'f', -1, 64)
+}
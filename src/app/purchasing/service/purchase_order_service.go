@@ -0,0 +1,522 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	inventorymodel "github.com/faisd405/go-restapi-gin/src/app/inventory/model"
+	inventoryrepository "github.com/faisd405/go-restapi-gin/src/app/inventory/repository"
+	inventoryservice "github.com/faisd405/go-restapi-gin/src/app/inventory/service"
+	menuservice "github.com/faisd405/go-restapi-gin/src/app/menu/service"
+	"github.com/faisd405/go-restapi-gin/src/app/purchasing/model"
+	"github.com/faisd405/go-restapi-gin/src/app/purchasing/repository"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotDraft      = errors.New("only draft purchase orders can be changed")
+	ErrNotReceivable = errors.New("only sent purchase orders can be received")
+	ErrLineNotFound  = errors.New("line not found on this purchase order")
+	ErrOverReceipt   = errors.New("more was received than is outstanding")
+	ErrInvalidFormat = errors.New("format must be pdf or csv")
+)
+
+type PurchaseOrderService interface {
+	GetPurchaseOrders(actor utils.Actor, branchID uint, page, limit int, filter model.PurchaseOrderFilter) ([]model.PurchaseOrder, int64, error)
+	GetPurchaseOrder(actor utils.Actor, id uint) (*model.PurchaseOrder, error)
+	CreatePurchaseOrder(actor utils.Actor, branchID uint, req model.PurchaseOrderRequest) (*model.PurchaseOrder, error)
+	// UpdatePurchaseOrder replaces the supplier, note and lines of a draft
+	UpdatePurchaseOrder(actor utils.Actor, id uint, req model.PurchaseOrderRequest) (*model.PurchaseOrder, error)
+	DeletePurchaseOrder(actor utils.Actor, id uint) error
+	// SendPurchaseOrder marks a draft as sent to the supplier
+	SendPurchaseOrder(actor utils.Actor, id uint) (*model.PurchaseOrder, error)
+	// ReceivePurchaseOrder books a delivery as purchases on stock
+	ReceivePurchaseOrder(actor utils.Actor, id uint, req model.ReceiveRequest) (*model.PurchaseOrder, error)
+	// GetSuggestions proposes purchase orders for the ingredients at or
+	// below their reorder level that are not on an open purchase order
+	GetSuggestions(actor utils.Actor, branchID uint) ([]model.Suggestion, error)
+	// CreateSuggested saves the suggestions that have a supplier as drafts
+	CreateSuggested(actor utils.Actor, branchID uint) ([]model.PurchaseOrder, error)
+	ExportPurchaseOrder(actor utils.Actor, id uint, format string) (*model.Rendered, error)
+}
+
+type purchaseOrderService struct {
+	purchaseOrderRepo repository.PurchaseOrderRepository
+	supplierRepo      repository.SupplierRepository
+	stockRepo         inventoryrepository.StockRepository
+	inventorySvc      inventoryservice.InventoryService
+	menuSvc           menuservice.MenuService
+	restaurantSvc     restaurantservice.RestaurantService
+	txManager         database.TxManager
+}
+
+func NewPurchaseOrderService(
+	purchaseOrderRepo repository.PurchaseOrderRepository,
+	supplierRepo repository.SupplierRepository,
+	stockRepo inventoryrepository.StockRepository,
+	inventorySvc inventoryservice.InventoryService,
+	menuSvc menuservice.MenuService,
+	restaurantSvc restaurantservice.RestaurantService,
+	txManager database.TxManager,
+) PurchaseOrderService {
+	return &purchaseOrderService{
+		purchaseOrderRepo: purchaseOrderRepo,
+		supplierRepo:      supplierRepo,
+		stockRepo:         stockRepo,
+		inventorySvc:      inventorySvc,
+		menuSvc:           menuSvc,
+		restaurantSvc:     restaurantSvc,
+		txManager:         txManager,
+	}
+}
+
+func (s *purchaseOrderService) GetPurchaseOrders(actor utils.Actor, branchID uint, page, limit int, filter model.PurchaseOrderFilter) ([]model.PurchaseOrder, int64, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, 0, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, 0, err
+	}
+
+	q := database.NewQuery().Eq("branch_id", branchID)
+	if filter.Status != "" {
+		if !model.IsValidStatus(filter.Status) {
+			return nil, 0, model.ErrInvalidStatus
+		}
+		q.Eq("status", filter.Status)
+	}
+	if filter.SupplierID != 0 {
+		q.Eq("supplier_id", filter.SupplierID)
+	}
+
+	return s.purchaseOrderRepo.FindPage(q.Preload("Lines").OrderByDesc("id").Paginate(utils.Offset(page, limit), limit))
+}
+
+func (s *purchaseOrderService) GetPurchaseOrder(actor utils.Actor, id uint) (*model.PurchaseOrder, error) {
+	return s.getAccessible(actor, id)
+}
+
+func (s *purchaseOrderService) CreatePurchaseOrder(actor utils.Actor, branchID uint, req model.PurchaseOrderRequest) (*model.PurchaseOrder, error) {
+	branch, err := s.restaurantSvc.GetBranch(branchID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &model.PurchaseOrder{
+		BranchID:    branchID,
+		Status:      model.StatusDraft,
+		Currency:    branch.EffectiveCurrency(),
+		CreatedByID: utils.ActorID(actor),
+	}
+	if err := s.applyRequest(actor, order, req); err != nil {
+		return nil, err
+	}
+	if err := s.purchaseOrderRepo.Create(order); err != nil {
+		return nil, err
+	}
+	return s.purchaseOrderRepo.GetByID(order.ID)
+}
+
+func (s *purchaseOrderService) UpdatePurchaseOrder(actor utils.Actor, id uint, req model.PurchaseOrderRequest) (*model.PurchaseOrder, error) {
+	if _, err := s.getAccessible(actor, id); err != nil {
+		return nil, err
+	}
+
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		purchaseOrderRepo := s.purchaseOrderRepo.WithTx(tx)
+		order, err := purchaseOrderRepo.GetForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if order.Status != model.StatusDraft {
+			return ErrNotDraft
+		}
+
+		if err := s.applyRequest(actor, order, req); err != nil {
+			return err
+		}
+		if err := purchaseOrderRepo.Update(order); err != nil {
+			return err
+		}
+		return purchaseOrderRepo.ReplaceLines(order, order.Lines)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.purchaseOrderRepo.GetByID(id)
+}
+
+func (s *purchaseOrderService) DeletePurchaseOrder(actor utils.Actor, id uint) error {
+	if _, err := s.getAccessible(actor, id); err != nil {
+		return err
+	}
+
+	return s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		purchaseOrderRepo := s.purchaseOrderRepo.WithTx(tx)
+		order, err := purchaseOrderRepo.GetForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if order.Status != model.StatusDraft {
+			return ErrNotDraft
+		}
+		return purchaseOrderRepo.Delete(order.ID)
+	})
+}
+
+func (s *purchaseOrderService) SendPurchaseOrder(actor utils.Actor, id uint) (*model.PurchaseOrder, error) {
+	if _, err := s.getAccessible(actor, id); err != nil {
+		return nil, err
+	}
+
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		purchaseOrderRepo := s.purchaseOrderRepo.WithTx(tx)
+		order, err := purchaseOrderRepo.GetForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if order.Status != model.StatusDraft {
+			return ErrNotDraft
+		}
+
+		now := time.Now()
+		order.Status = model.StatusSent
+		order.SentAt = &now
+		return purchaseOrderRepo.Update(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.purchaseOrderRepo.GetByID(id)
+}
+
+func (s *purchaseOrderService) ReceivePurchaseOrder(actor utils.Actor, id uint, req model.ReceiveRequest) (*model.PurchaseOrder, error) {
+	if _, err := s.getAccessible(actor, id); err != nil {
+		return nil, err
+	}
+
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		purchaseOrderRepo := s.purchaseOrderRepo.WithTx(tx)
+		stockRepo := s.stockRepo.WithTx(tx)
+
+		order, err := purchaseOrderRepo.GetForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if order.Status != model.StatusSent && order.Status != model.StatusPartiallyReceived {
+			return ErrNotReceivable
+		}
+
+		received := make(map[uint]float64, len(req.Lines))
+		for _, line := range req.Lines {
+			received[line.LineID] += line.Quantity
+		}
+		note := req.Note
+		if note == "" {
+			note = fmt.Sprintf("Purchase order #%d", order.ID)
+		}
+
+		var changed []model.PurchaseOrderLine
+		for lineID := range received {
+			if !hasLine(order, lineID) {
+				return ErrLineNotFound
+			}
+		}
+		for i := range order.Lines {
+			line := &order.Lines[i]
+			quantity, ok := received[line.ID]
+			if !ok {
+				continue
+			}
+			quantity = inventorymodel.RoundQuantity(quantity)
+			if quantity > line.Outstanding() {
+				return ErrOverReceipt
+			}
+
+			movement := &inventorymodel.Movement{
+				BranchID:        order.BranchID,
+				IngredientID:    line.IngredientID,
+				Type:            inventorymodel.MovementPurchase,
+				Quantity:        quantity,
+				PurchaseOrderID: &order.ID,
+				Note:            note,
+				CreatedByID:     utils.ActorID(actor),
+			}
+			if err := stockRepo.Apply(movement); err != nil {
+				return err
+			}
+			line.ReceivedQuantity = inventorymodel.RoundQuantity(line.ReceivedQuantity + quantity)
+			changed = append(changed, *line)
+		}
+		if err := purchaseOrderRepo.SaveLines(changed); err != nil {
+			return err
+		}
+
+		order.Status = model.StatusReceived
+		for _, line := range order.Lines {
+			if line.Outstanding() > 0 {
+				order.Status = model.StatusPartiallyReceived
+				break
+			}
+		}
+		if order.Status == model.StatusReceived {
+			now := time.Now()
+			order.ReceivedAt = &now
+		}
+		return purchaseOrderRepo.Update(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.purchaseOrderRepo.GetByID(id)
+}
+
+func (s *purchaseOrderService) GetSuggestions(actor utils.Actor, branchID uint) ([]model.Suggestion, error) {
+	levels, err := s.inventorySvc.GetStock(actor, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	open, err := s.purchaseOrderRepo.Find(database.NewQuery().
+		Eq("branch_id", branchID).
+		In("status", []string{model.StatusDraft, model.StatusSent, model.StatusPartiallyReceived}).
+		Preload("Lines"))
+	if err != nil {
+		return nil, err
+	}
+	onOrder := make(map[uint]bool)
+	for _, order := range open {
+		for _, line := range order.Lines {
+			if line.Outstanding() > 0 {
+				onOrder[line.IngredientID] = true
+			}
+		}
+	}
+
+	suppliers, err := s.supplierRepo.Find(database.NewQuery().
+		Eq("branch_id", branchID).
+		Eq("is_active", true).
+		Preload("Catalog").
+		OrderBy("name").OrderBy("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Each ingredient goes to the supplier that sells it cheapest
+	bySupplier := make(map[uint]*model.Suggestion)
+	unassigned := &model.Suggestion{}
+	for _, level := range levels {
+		if !level.Low || onOrder[level.IngredientID] || level.ReorderLevel == nil {
+			continue
+		}
+
+		var cheapest *model.Supplier
+		var unitCost int64
+		for i := range suppliers {
+			for _, entry := range suppliers[i].Catalog {
+				if entry.IngredientID == level.IngredientID && (cheapest == nil || entry.UnitCost < unitCost) {
+					cheapest = &suppliers[i]
+					unitCost = entry.UnitCost
+				}
+			}
+		}
+
+		line := model.SuggestionLine{
+			IngredientID: level.IngredientID,
+			Name:         level.Name,
+			Unit:         level.Unit,
+			Stock:        level.Quantity,
+			ReorderLevel: *level.ReorderLevel,
+			Quantity:     level.ReorderQuantity,
+			UnitCost:     unitCost,
+			Total:        model.LineTotal(level.ReorderQuantity, unitCost),
+		}
+
+		suggestion := unassigned
+		if cheapest != nil {
+			if suggestion = bySupplier[cheapest.ID]; suggestion == nil {
+				suggestion = &model.Suggestion{SupplierID: &cheapest.ID, Supplier: cheapest}
+				bySupplier[cheapest.ID] = suggestion
+			}
+		}
+		suggestion.Lines = append(suggestion.Lines, line)
+		suggestion.Total += line.Total
+	}
+
+	suggestions := make([]model.Suggestion, 0, len(bySupplier)+1)
+	for i := range suppliers {
+		if suggestion, ok := bySupplier[suppliers[i].ID]; ok {
+			suggestion.Supplier.Catalog = nil
+			suggestions = append(suggestions, *suggestion)
+		}
+	}
+	if len(unassigned.Lines) > 0 {
+		suggestions = append(suggestions, *unassigned)
+	}
+	return suggestions, nil
+}
+
+func (s *purchaseOrderService) CreateSuggested(actor utils.Actor, branchID uint) ([]model.PurchaseOrder, error) {
+	suggestions, err := s.GetSuggestions(actor, branchID)
+	if err != nil {
+		return nil, err
+	}
+	branch, err := s.restaurantSvc.GetBranch(branchID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]model.PurchaseOrder, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		if suggestion.SupplierID == nil {
+			continue
+		}
+		order := model.PurchaseOrder{
+			BranchID:    branchID,
+			SupplierID:  *suggestion.SupplierID,
+			Status:      model.StatusDraft,
+			Currency:    branch.EffectiveCurrency(),
+			Note:        "Suggested from reorder levels",
+			Total:       suggestion.Total,
+			CreatedByID: utils.ActorID(actor),
+		}
+		for _, line := range suggestion.Lines {
+			order.Lines = append(order.Lines, model.PurchaseOrderLine{
+				IngredientID: line.IngredientID,
+				Name:         line.Name,
+				Unit:         line.Unit,
+				Quantity:     line.Quantity,
+				UnitCost:     line.UnitCost,
+				Total:        line.Total,
+			})
+		}
+		orders = append(orders, order)
+	}
+
+	err = s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		purchaseOrderRepo := s.purchaseOrderRepo.WithTx(tx)
+		for i := range orders {
+			if err := purchaseOrderRepo.Create(&orders[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (s *purchaseOrderService) ExportPurchaseOrder(actor utils.Actor, id uint, format string) (*model.Rendered, error) {
+	if !model.IsValidFormat(format) {
+		return nil, ErrInvalidFormat
+	}
+	order, err := s.getAccessible(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered := &model.Rendered{Filename: fmt.Sprintf("purchase-order-%d.%s", order.ID, format)}
+	if format == model.FormatCSV {
+		rendered.ContentType = "text/csv; charset=utf-8"
+		rendered.Body, err = renderCSV(order)
+		if err != nil {
+			return nil, err
+		}
+		return rendered, nil
+	}
+
+	branch, err := s.restaurantSvc.GetBranch(order.BranchID, false)
+	if err != nil {
+		return nil, err
+	}
+	rendered.ContentType = "application/pdf"
+	rendered.Body = renderPDF(order, branch)
+	return rendered, nil
+}
+
+// applyRequest checks the supplier and ingredients of a request against the
+// branch of order and prices its lines
+func (s *purchaseOrderService) applyRequest(actor utils.Actor, order *model.PurchaseOrder, req model.PurchaseOrderRequest) error {
+	ingredients, err := branchIngredients(s.menuSvc, actor, order.BranchID)
+	if err != nil {
+		return err
+	}
+	supplier, err := s.supplierRepo.GetByID(req.SupplierID)
+	if err != nil {
+		return err
+	}
+	if supplier.BranchID != order.BranchID {
+		return ErrSupplierBranchMismatch
+	}
+	if !supplier.IsActive {
+		return ErrSupplierInactive
+	}
+	costs := make(map[uint]int64, len(supplier.Catalog))
+	for _, entry := range supplier.Catalog {
+		costs[entry.IngredientID] = entry.UnitCost
+	}
+
+	lines := make([]model.PurchaseOrderLine, 0, len(req.Lines))
+	seen := make(map[uint]bool, len(req.Lines))
+	var total int64
+	for _, lineReq := range req.Lines {
+		ingredient, ok := ingredients[lineReq.IngredientID]
+		if !ok {
+			return inventoryservice.ErrIngredientNotFound
+		}
+		if seen[ingredient.ID] {
+			return ErrDuplicateIngredient
+		}
+		seen[ingredient.ID] = true
+
+		unitCost := costs[ingredient.ID]
+		if lineReq.UnitCost != nil {
+			unitCost = *lineReq.UnitCost
+		}
+		quantity := inventorymodel.RoundQuantity(lineReq.Quantity)
+		line := model.PurchaseOrderLine{
+			IngredientID: ingredient.ID,
+			Name:         ingredient.Name,
+			Unit:         ingredient.Unit,
+			Quantity:     quantity,
+			UnitCost:     unitCost,
+			Total:        model.LineTotal(quantity, unitCost),
+		}
+		total += line.Total
+		lines = append(lines, line)
+	}
+
+	order.SupplierID = supplier.ID
+	order.Supplier = nil
+	order.Note = req.Note
+	order.Lines = lines
+	order.Total = total
+	return nil
+}
+
+// getAccessible loads a purchase order of a branch the actor works at
+func (s *purchaseOrderService) getAccessible(actor utils.Actor, id uint) (*model.PurchaseOrder, error) {
+	order, err := s.purchaseOrderRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, order.BranchID); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func hasLine(order *model.PurchaseOrder, lineID uint) bool {
+	for _, line := range order.Lines {
+		if line.ID == lineID {
+			return true
+		}
+	}
+	return false
+}
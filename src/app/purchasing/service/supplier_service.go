@@ -0,0 +1,176 @@
+package service
+
+import (
+	"errors"
+
+	inventoryservice "github.com/faisd405/go-restapi-gin/src/app/inventory/service"
+	menumodel "github.com/faisd405/go-restapi-gin/src/app/menu/model"
+	menuservice "github.com/faisd405/go-restapi-gin/src/app/menu/service"
+	"github.com/faisd405/go-restapi-gin/src/app/purchasing/model"
+	"github.com/faisd405/go-restapi-gin/src/app/purchasing/repository"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDuplicateIngredient    = errors.New("an ingredient must not be listed twice")
+	ErrSupplierBranchMismatch = errors.New("supplier belongs to a different branch")
+	ErrSupplierInactive       = errors.New("supplier is not active")
+)
+
+type SupplierService interface {
+	GetSuppliers(actor utils.Actor, branchID uint) ([]model.Supplier, error)
+	GetSupplier(actor utils.Actor, id uint) (*model.Supplier, error)
+	CreateSupplier(actor utils.Actor, branchID uint, req model.SupplierRequest) (*model.Supplier, error)
+	// UpdateSupplier replaces a supplier and its catalog
+	UpdateSupplier(actor utils.Actor, id uint, req model.SupplierRequest) (*model.Supplier, error)
+	DeleteSupplier(actor utils.Actor, id uint) error
+}
+
+type supplierService struct {
+	supplierRepo  repository.SupplierRepository
+	menuSvc       menuservice.MenuService
+	restaurantSvc restaurantservice.RestaurantService
+	txManager     database.TxManager
+}
+
+func NewSupplierService(
+	supplierRepo repository.SupplierRepository,
+	menuSvc menuservice.MenuService,
+	restaurantSvc restaurantservice.RestaurantService,
+	txManager database.TxManager,
+) SupplierService {
+	return &supplierService{
+		supplierRepo:  supplierRepo,
+		menuSvc:       menuSvc,
+		restaurantSvc: restaurantSvc,
+		txManager:     txManager,
+	}
+}
+
+func (s *supplierService) GetSuppliers(actor utils.Actor, branchID uint) ([]model.Supplier, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	return s.supplierRepo.Find(database.NewQuery().Eq("branch_id", branchID).Preload("Catalog").OrderBy("name"))
+}
+
+func (s *supplierService) GetSupplier(actor utils.Actor, id uint) (*model.Supplier, error) {
+	return getAccessibleSupplier(s.supplierRepo, s.restaurantSvc, actor, id)
+}
+
+func (s *supplierService) CreateSupplier(actor utils.Actor, branchID uint, req model.SupplierRequest) (*model.Supplier, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	catalog, err := s.catalog(actor, branchID, req.Catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	supplier := &model.Supplier{BranchID: branchID, IsActive: true}
+	applySupplierRequest(supplier, req)
+	supplier.Catalog = catalog
+	if err := s.supplierRepo.Create(supplier); err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
+
+func (s *supplierService) UpdateSupplier(actor utils.Actor, id uint, req model.SupplierRequest) (*model.Supplier, error) {
+	supplier, err := getAccessibleSupplier(s.supplierRepo, s.restaurantSvc, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := s.catalog(actor, supplier.BranchID, req.Catalog)
+	if err != nil {
+		return nil, err
+	}
+
+	applySupplierRequest(supplier, req)
+	err = s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		supplierRepo := s.supplierRepo.WithTx(tx)
+		if err := supplierRepo.Update(supplier); err != nil {
+			return err
+		}
+		return supplierRepo.ReplaceCatalog(supplier, catalog)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
+
+// DeleteSupplier removes a supplier; its purchase orders keep referring to it
+func (s *supplierService) DeleteSupplier(actor utils.Actor, id uint) error {
+	supplier, err := getAccessibleSupplier(s.supplierRepo, s.restaurantSvc, actor, id)
+	if err != nil {
+		return err
+	}
+	return s.supplierRepo.Delete(supplier.ID)
+}
+
+// catalog checks that the catalog lists ingredients of the branch once each
+func (s *supplierService) catalog(actor utils.Actor, branchID uint, entries []model.SupplierIngredientRequest) ([]model.SupplierIngredient, error) {
+	ingredients, err := branchIngredients(s.menuSvc, actor, branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := make([]model.SupplierIngredient, 0, len(entries))
+	seen := make(map[uint]bool, len(entries))
+	for _, entry := range entries {
+		if _, ok := ingredients[entry.IngredientID]; !ok {
+			return nil, inventoryservice.ErrIngredientNotFound
+		}
+		if seen[entry.IngredientID] {
+			return nil, ErrDuplicateIngredient
+		}
+		seen[entry.IngredientID] = true
+		catalog = append(catalog, model.SupplierIngredient{IngredientID: entry.IngredientID, UnitCost: entry.UnitCost})
+	}
+	return catalog, nil
+}
+
+func applySupplierRequest(supplier *model.Supplier, req model.SupplierRequest) {
+	supplier.Name = req.Name
+	supplier.ContactName = req.ContactName
+	supplier.Email = req.Email
+	supplier.Phone = req.Phone
+	supplier.Notes = req.Notes
+	if req.IsActive != nil {
+		supplier.IsActive = *req.IsActive
+	}
+}
+
+// branchIngredients loads the ingredients of a branch the actor manages,
+// by ID
+func branchIngredients(menuSvc menuservice.MenuService, actor utils.Actor, branchID uint) (map[uint]menumodel.Ingredient, error) {
+	ingredients, err := menuSvc.GetIngredients(actor, branchID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]menumodel.Ingredient, len(ingredients))
+	for _, ingredient := range ingredients {
+		byID[ingredient.ID] = ingredient
+	}
+	return byID, nil
+}
+
+// getAccessibleSupplier loads a supplier of a branch the actor works at
+func getAccessibleSupplier(supplierRepo repository.SupplierRepository, checker utils.BranchAccessChecker, actor utils.Actor, id uint) (*model.Supplier, error) {
+	supplier, err := supplierRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(checker, actor, supplier.BranchID); err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
@@ -0,0 +1,215 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/faisd405/go-restapi-gin/src/app/receipt/model"
+	"github.com/faisd405/go-restapi-gin/src/app/receipt/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrCodeOrderNotPaid is returned for receipts of orders with a balance
+const ErrCodeOrderNotPaid = "ORDER_NOT_PAID"
+
+type ReceiptController struct {
+	receiptService service.ReceiptService
+}
+
+func NewReceiptController(receiptService service.ReceiptService) *ReceiptController {
+	return &ReceiptController{receiptService: receiptService}
+}
+
+// GetReceipt godoc
+// @Summary Get order receipt
+// @Description Render the receipt of a paid order as PDF or as fixed-width text for thermal printers
+// @Tags receipts
+// @Produce application/pdf
+// @Produce text/plain
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Param format query string false "pdf (default) or txt"
+// @Success 200 {file} file
+// @Failure 409 {object} utils.Response
+// @Router /orders/{id}/receipt [get]
+func (ctrl *ReceiptController) GetReceipt(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID", err.Error())
+		return
+	}
+
+	rendered, err := ctrl.receiptService.GetReceipt(actor, id, c.DefaultQuery("format", model.FormatPDF))
+	if err != nil {
+		receiptErrorResponse(c, "Failed to render receipt", err)
+		return
+	}
+
+	writeRendered(c, rendered)
+}
+
+// IssueInvoice godoc
+// @Summary Issue tax invoice
+// @Description Issue the tax invoice of a paid order for a business customer. Invoice numbers run per branch; an order has one invoice and issuing it again returns it.
+// @Tags receipts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Param invoice body model.InvoiceRequest true "Business customer"
+// @Success 201 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /orders/{id}/invoice [post]
+func (ctrl *ReceiptController) IssueInvoice(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID", err.Error())
+		return
+	}
+
+	var req model.InvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	invoice, err := ctrl.receiptService.IssueInvoice(actor, id, req)
+	if err != nil {
+		receiptErrorResponse(c, "Invoice issue failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Invoice issued successfully", invoice)
+}
+
+// GetInvoice godoc
+// @Summary Get tax invoice
+// @Description Render the tax invoice of an order as PDF or as fixed-width text
+// @Tags receipts
+// @Produce application/pdf
+// @Produce text/plain
+// @Security ApiKeyAuth
+// @Param id path int true "Order ID"
+// @Param format query string false "pdf (default) or txt"
+// @Success 200 {file} file
+// @Failure 404 {object} utils.Response
+// @Router /orders/{id}/invoice [get]
+func (ctrl *ReceiptController) GetInvoice(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid order ID", err.Error())
+		return
+	}
+
+	rendered, err := ctrl.receiptService.GetInvoice(actor, id, c.DefaultQuery("format", model.FormatPDF))
+	if err != nil {
+		receiptErrorResponse(c, "Failed to render invoice", err)
+		return
+	}
+
+	writeRendered(c, rendered)
+}
+
+// GetTemplate godoc
+// @Summary Get receipt template (Admin/Manager)
+// @Description Get the header, footer, seller details and text width of a restaurant's receipts and invoices
+// @Tags receipts
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Restaurant ID"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /restaurants/{id}/receipt-template [get]
+func (ctrl *ReceiptController) GetTemplate(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid restaurant ID", err.Error())
+		return
+	}
+
+	template, err := ctrl.receiptService.GetTemplate(actor, id)
+	if err != nil {
+		receiptErrorResponse(c, "Failed to retrieve receipt template", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Receipt template retrieved successfully", template)
+}
+
+// SaveTemplate godoc
+// @Summary Update receipt template (Admin/Manager)
+// @Description Set the header and footer lines, seller details, invoice number prefix and text width of a restaurant's receipts and invoices
+// @Tags receipts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Restaurant ID"
+// @Param template body model.TemplateRequest true "Template"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /restaurants/{id}/receipt-template [put]
+func (ctrl *ReceiptController) SaveTemplate(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid restaurant ID", err.Error())
+		return
+	}
+
+	var req model.TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	template, err := ctrl.receiptService.SaveTemplate(actor, id, req)
+	if err != nil {
+		receiptErrorResponse(c, "Receipt template update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Receipt template updated successfully", template)
+}
+
+// writeRendered sends a rendered document to be shown inline
+func writeRendered(c *gin.Context, rendered *model.Rendered) {
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", rendered.Filename))
+	c.Data(http.StatusOK, rendered.ContentType, rendered.Body)
+}
+
+func receiptErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrOrderNotPaid):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeOrderNotPaid, message, err.Error())
+	case errors.Is(err, service.ErrInvalidFormat):
+		utils.ErrorResponse(c, http.StatusBadRequest, message, err.Error())
+	default:
+		utils.DatabaseErrorResponse(c, message, err)
+	}
+}
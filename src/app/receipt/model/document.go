@@ -0,0 +1,34 @@
+package model
+
+// Output formats of receipts and invoices
+const (
+	FormatPDF  = "pdf"
+	FormatText = "txt"
+)
+
+// IsValidFormat reports whether format is one of the output formats
+func IsValidFormat(format string) bool {
+	return format == FormatPDF || format == FormatText
+}
+
+// Document is a receipt or invoice laid out in fixed-width lines, ready to
+// be rendered as text or PDF
+type Document struct {
+	Title string
+	Width int
+	Lines []Line
+}
+
+// Line is a line of a document. Rule lines separate its sections.
+type Line struct {
+	Text string
+	Bold bool
+	Rule bool
+}
+
+// Rendered is a document in an output format
+type Rendered struct {
+	ContentType string
+	Filename    string
+	Body        []byte
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+)
+
+// Invoice is a tax invoice issued for a paid order. Numbers run without
+// gaps per branch.
+type Invoice struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	OrderID         uint      `json:"order_id" gorm:"not null;uniqueIndex"`
+	BranchID        uint      `json:"branch_id" gorm:"not null;uniqueIndex:idx_invoices_branch_sequence"`
+	Sequence        int64     `json:"sequence" gorm:"not null;uniqueIndex:idx_invoices_branch_sequence"`
+	Number          string    `json:"number" gorm:"type:varchar(50);not null"`
+	CustomerName    string    `json:"customer_name" gorm:"type:varchar(200);not null"`
+	CustomerTaxID   string    `json:"customer_tax_id" gorm:"type:varchar(50);not null"`
+	CustomerAddress string    `json:"customer_address" gorm:"type:text"`
+	CreatedByID     *uint     `json:"created_by_id"`
+	IssuedAt        time.Time `json:"issued_at"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// InvoiceCounter holds the last invoice number of a branch
+type InvoiceCounter struct {
+	BranchID uint  `gorm:"primaryKey;autoIncrement:false"`
+	Last     int64 `gorm:"not null"`
+}
+
+func (InvoiceCounter) TableName() string {
+	return "invoice_counters"
+}
+
+// InvoiceRequest names the business customer of a tax invoice
+type InvoiceRequest struct {
+	CustomerName    string `json:"customer_name" binding:"required,max=200"`
+	CustomerTaxID   string `json:"customer_tax_id" binding:"required,max=50"`
+	CustomerAddress string `json:"customer_address" binding:"max=500"`
+}
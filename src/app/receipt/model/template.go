@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/database"
+)
+
+// Limits of the text width of receipts, in characters. 32, 42 and 48 match
+// the common thermal printer widths.
+const (
+	MinWidth     = 32
+	MaxWidth     = 64
+	DefaultWidth = 42
+)
+
+// DefaultInvoicePrefix starts the invoice numbers of restaurants without a
+// template
+const DefaultInvoicePrefix = "INV-"
+
+// Template is how a restaurant's receipts and invoices look. LegalName and
+// TaxID identify the seller on tax invoices.
+type Template struct {
+	ID            uint                `json:"id" gorm:"primaryKey"`
+	RestaurantID  uint                `json:"restaurant_id" gorm:"not null;uniqueIndex"`
+	Header        database.StringList `json:"header" gorm:"type:jsonb;not null;default:'[]'"`
+	Footer        database.StringList `json:"footer" gorm:"type:jsonb;not null;default:'[]'"`
+	LegalName     string              `json:"legal_name" gorm:"type:varchar(200)"`
+	TaxID         string              `json:"tax_id" gorm:"type:varchar(50)"`
+	InvoicePrefix string              `json:"invoice_prefix" gorm:"type:varchar(20);not null"`
+	Width         int                 `json:"width" gorm:"not null"`
+	ShowTaxes     bool                `json:"show_taxes" gorm:"not null"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+}
+
+func (Template) TableName() string {
+	return "receipt_templates"
+}
+
+// DefaultTemplate is used by restaurants that have not set up a template
+func DefaultTemplate(restaurantID uint) *Template {
+	return &Template{
+		RestaurantID:  restaurantID,
+		Header:        database.StringList{},
+		Footer:        database.StringList{"Thank you for your visit!"},
+		InvoicePrefix: DefaultInvoicePrefix,
+		Width:         DefaultWidth,
+		ShowTaxes:     true,
+	}
+}
+
+type TemplateRequest struct {
+	Header        []string `json:"header" binding:"max=10,dive,max=64"`
+	Footer        []string `json:"footer" binding:"max=10,dive,max=64"`
+	LegalName     string   `json:"legal_name" binding:"max=200"`
+	TaxID         string   `json:"tax_id" binding:"max=50"`
+	InvoicePrefix string   `json:"invoice_prefix" binding:"max=20"`
+	Width         int      `json:"width" binding:"omitempty,min=32,max=64"`
+	ShowTaxes     *bool    `json:"show_taxes"`
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/faisd405/go-restapi-gin/src/app/receipt/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+)
+
+type TemplateRepository interface {
+	// GetByRestaurant returns the template of a restaurant, or the default
+	// when the restaurant has none yet
+	GetByRestaurant(restaurantID uint) (*model.Template, error)
+	Save(template *model.Template) error
+}
+
+type templateRepository struct {
+	database.Repository[model.Template]
+}
+
+func NewTemplateRepository(db *gorm.DB) TemplateRepository {
+	return &templateRepository{Repository: database.NewRepository[model.Template](db)}
+}
+
+func (r *templateRepository) GetByRestaurant(restaurantID uint) (*model.Template, error) {
+	template, err := r.First(database.NewQuery().Eq("restaurant_id", restaurantID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.DefaultTemplate(restaurantID), nil
+	}
+	return template, err
+}
+
+func (r *templateRepository) Save(template *model.Template) error {
+	return r.Upsert(template, []string{"restaurant_id"},
+		"header", "footer", "legal_name", "tax_id", "invoice_prefix", "width", "show_taxes", "updated_at")
+}
+
+type InvoiceRepository interface {
+	Create(invoice *model.Invoice) error
+	GetByOrder(orderID uint) (*model.Invoice, error)
+	// NextSequence takes the next invoice number of a branch. The counter
+	// stays locked until the surrounding transaction ends, so numbers have
+	// no gaps.
+	NextSequence(branchID uint) (int64, error)
+	WithTx(tx *gorm.DB) InvoiceRepository
+}
+
+type invoiceRepository struct {
+	database.Repository[model.Invoice]
+}
+
+func NewInvoiceRepository(db *gorm.DB) InvoiceRepository {
+	return &invoiceRepository{Repository: database.NewRepository[model.Invoice](db)}
+}
+
+func (r *invoiceRepository) GetByOrder(orderID uint) (*model.Invoice, error) {
+	return r.First(database.NewQuery().Eq("order_id", orderID))
+}
+
+func (r *invoiceRepository) NextSequence(branchID uint) (int64, error) {
+	var last int64
+	err := r.DB().Raw(`INSERT INTO invoice_counters (branch_id, last) VALUES (?, 1)
+		ON CONFLICT (branch_id) DO UPDATE SET last = invoice_counters.last + 1
+		RETURNING last`, branchID).Scan(&last).Error
+	return last, err
+}
+
+func (r *invoiceRepository) WithTx(tx *gorm.DB) InvoiceRepository {
+	return &invoiceRepository{Repository: r.Repository.WithTx(tx)}
+}
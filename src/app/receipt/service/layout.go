@@ -0,0 +1,247 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	ordermodel "github.com/faisd405/go-restapi-gin/src/app/order/model"
+	paymentmodel "github.com/faisd405/go-restapi-gin/src/app/payment/model"
+	"github.com/faisd405/go-restapi-gin/src/app/receipt/model"
+	restaurantmodel "github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+)
+
+var paymentMethodLabels = map[string]string{
+	paymentmodel.MethodCard:     "Card",
+	paymentmodel.MethodCash:     "Cash",
+	paymentmodel.MethodTerminal: "Card (terminal)",
+}
+
+// receiptData is everything printed on a receipt or invoice
+type receiptData struct {
+	order    *ordermodel.OrderDetails
+	branch   *restaurantmodel.Branch
+	template *model.Template
+	payments *paymentmodel.PaymentSummary
+	invoice  *model.Invoice
+}
+
+// buildDocument lays out a receipt, or a tax invoice when data has one
+func buildDocument(data receiptData) *model.Document {
+	order, branch, template := data.order, data.branch, data.template
+	loc := branch.Location()
+	l := &layout{width: template.Width}
+
+	if branch.Restaurant != nil {
+		l.center(branch.Restaurant.Name, true)
+		if branch.Name != branch.Restaurant.Name {
+			l.center(branch.Name, false)
+		}
+	} else {
+		l.center(branch.Name, true)
+	}
+	l.center(branch.AddressLine1, false)
+	l.center(branch.AddressLine2, false)
+	l.center(strings.TrimSpace(branch.PostalCode+" "+branch.City), false)
+	l.center(branch.Phone, false)
+	for _, line := range template.Header {
+		l.center(line, false)
+	}
+	if template.LegalName != "" {
+		l.center(template.LegalName, false)
+	}
+	if template.TaxID != "" {
+		l.center("Tax ID: "+template.TaxID, false)
+	}
+	l.rule()
+
+	title := "RECEIPT"
+	if data.invoice != nil {
+		title = "TAX INVOICE"
+	}
+	l.center(title, true)
+	if data.invoice != nil {
+		l.pair("Invoice No.", data.invoice.Number, false)
+		l.pair("Invoice date", data.invoice.IssuedAt.In(loc).Format("2006-01-02"), false)
+	}
+	l.pair("Order", fmt.Sprintf("#%d", order.ID), false)
+	placed := order.CreatedAt
+	if order.PlacedAt != nil {
+		placed = *order.PlacedAt
+	}
+	l.pair("Date", placed.In(loc).Format("2006-01-02 15:04"), false)
+	if order.Type == ordermodel.TypeTakeaway {
+		l.pair("Service", "Takeaway", false)
+	} else {
+		l.pair("Service", "Dine-in", false)
+	}
+
+	if data.invoice != nil {
+		l.rule()
+		l.text("Bill to:", true)
+		l.text(data.invoice.CustomerName, false)
+		l.text("Tax ID: "+data.invoice.CustomerTaxID, false)
+		for _, line := range strings.Split(data.invoice.CustomerAddress, "\n") {
+			l.text(line, false)
+		}
+	}
+	l.rule()
+
+	currency := order.Currency
+	for _, line := range order.Lines {
+		l.pair(fmt.Sprintf("%d x %s", line.Quantity, line.Name), utils.FormatMoney(line.LineTotal, currency), false)
+		for _, option := range line.Modifiers {
+			l.text("  + "+option.OptionName, false)
+		}
+	}
+	l.rule()
+
+	l.pair("Subtotal", utils.FormatMoney(order.Subtotal, currency), false)
+	for _, discount := range order.Discounts {
+		l.pair(discount.Name, utils.FormatMoney(-discount.Amount, currency), false)
+	}
+	if order.ServiceCharge != 0 {
+		l.pair("Service charge", utils.FormatMoney(order.ServiceCharge, currency), false)
+	}
+	if template.ShowTaxes || data.invoice != nil {
+		for _, tax := range order.Taxes {
+			label := fmt.Sprintf("%s %s", tax.Name, percent(tax.RateBps))
+			if tax.Inclusive {
+				label += " incl."
+			}
+			l.pair(label, utils.FormatMoney(tax.Amount, currency), false)
+		}
+	}
+	if order.Rounding != 0 {
+		l.pair("Rounding", utils.FormatMoney(order.Rounding, currency), false)
+	}
+	l.pair("TOTAL "+currency, utils.FormatMoney(order.Total, currency), true)
+
+	if data.payments != nil && len(data.payments.Payments) > 0 {
+		l.rule()
+		for _, p := range data.payments.Payments {
+			if p.Status != paymentmodel.StatusCaptured {
+				continue
+			}
+			label, ok := paymentMethodLabels[p.Method]
+			if !ok {
+				label = p.Method
+			}
+			l.pair(label, utils.FormatMoney(p.CapturedAmount, currency), false)
+			if p.Tip > 0 {
+				l.pair("  Tip", utils.FormatMoney(p.Tip, currency), false)
+			}
+			if p.Method == paymentmodel.MethodCash && p.Tendered > 0 {
+				l.pair("  Tendered", utils.FormatMoney(p.Tendered, currency), false)
+				l.pair("  Change", utils.FormatMoney(p.Change, currency), false)
+			}
+		}
+		if data.payments.Refunded > 0 {
+			l.pair("Refunded", utils.FormatMoney(-data.payments.Refunded, currency), false)
+		}
+	}
+
+	if len(template.Footer) > 0 {
+		l.rule()
+		for _, line := range template.Footer {
+			l.center(line, false)
+		}
+	}
+
+	doc := &model.Document{Title: title, Width: l.width, Lines: l.lines}
+	if data.invoice != nil {
+		doc.Title = fmt.Sprintf("Invoice %s", data.invoice.Number)
+	}
+	return doc
+}
+
+// layout collects the lines of a document of a fixed width
+type layout struct {
+	width int
+	lines []model.Line
+}
+
+func (l *layout) add(text string, bold bool) {
+	l.lines = append(l.lines, model.Line{Text: text, Bold: bold})
+}
+
+func (l *layout) rule() {
+	l.lines = append(l.lines, model.Line{Rule: true})
+}
+
+// text adds left-aligned text, wrapped at word boundaries and keeping its
+// indentation. Empty text is left out.
+func (l *layout) text(text string, bold bool) {
+	indent, text := splitIndent(text)
+	for _, line := range wrap(text, max(l.width-len(indent), 1)) {
+		l.add(indent+line, bold)
+	}
+}
+
+// center adds centered text. Empty text is left out.
+func (l *layout) center(text string, bold bool) {
+	for _, line := range wrap(strings.TrimSpace(text), l.width) {
+		pad := (l.width - utf8.RuneCountInString(line)) / 2
+		l.add(strings.Repeat(" ", pad)+line, bold)
+	}
+}
+
+// pair adds a label with a right-aligned value on the same line. Labels
+// too long to fit are wrapped and the value goes on their last line.
+func (l *layout) pair(label, value string, bold bool) {
+	indent, label := splitIndent(label)
+	room := l.width - utf8.RuneCountInString(value) - 1 - len(indent)
+	lines := wrap(label, max(room, 1))
+	if len(lines) == 0 {
+		lines = []string{""}
+	}
+	for _, line := range lines[:len(lines)-1] {
+		l.add(indent+line, bold)
+	}
+	last := indent + lines[len(lines)-1]
+	gap := l.width - utf8.RuneCountInString(last) - utf8.RuneCountInString(value)
+	l.add(last+strings.Repeat(" ", max(gap, 1))+value, bold)
+}
+
+// splitIndent splits the leading spaces off text and trims the rest
+func splitIndent(text string) (string, string) {
+	trimmed := strings.TrimLeft(text, " ")
+	return text[:len(text)-len(trimmed)], strings.TrimSpace(trimmed)
+}
+
+// wrap breaks text into lines of at most width characters
+func wrap(text string, width int) []string {
+	var lines []string
+	var line []rune
+	for _, word := range strings.Fields(text) {
+		runes := []rune(word)
+		for len(runes) > width {
+			if len(line) > 0 {
+				lines = append(lines, string(line))
+				line = nil
+			}
+			lines = append(lines, string(runes[:width]))
+			runes = runes[width:]
+		}
+		if len(line) > 0 && len(line)+1+len(runes) > width {
+			lines = append(lines, string(line))
+			line = nil
+		}
+		if len(line) > 0 {
+			line = append(line, ' ')
+		}
+		line = append(line, runes...)
+	}
+	if len(line) > 0 {
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+// percent formats basis points as a percentage, e.g. 810 as "8.1%"
+func percent(bps int) string {
+	s := fmt.Sprintf("%d.%02d", bps/100, bps%100)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	return s + "%"
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	ordermodel "github.com/faisd405/go-restapi-gin/src/app/order/model"
+	orderservice "github.com/faisd405/go-restapi-gin/src/app/order/service"
+	paymentservice "github.com/faisd405/go-restapi-gin/src/app/payment/service"
+	"github.com/faisd405/go-restapi-gin/src/app/receipt/model"
+	"github.com/faisd405/go-restapi-gin/src/app/receipt/repository"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrderNotPaid  = errors.New("order has not been paid in full")
+	ErrInvalidFormat = errors.New("format must be pdf or txt")
+)
+
+type ReceiptService interface {
+	GetTemplate(actor utils.Actor, restaurantID uint) (*model.Template, error)
+	SaveTemplate(actor utils.Actor, restaurantID uint, req model.TemplateRequest) (*model.Template, error)
+
+	// GetReceipt renders the receipt of a paid order
+	GetReceipt(actor utils.Actor, orderID uint, format string) (*model.Rendered, error)
+	// GetReceiptDocument lays out the receipt of a paid order for printing.
+	// A width other than zero overrides the text width of the template.
+	GetReceiptDocument(actor utils.Actor, orderID uint, width int) (*model.Document, error)
+
+	// IssueInvoice issues the tax invoice of a paid order with the next
+	// number of its branch. An order has one invoice; issuing it again
+	// returns the invoice issued first.
+	IssueInvoice(actor utils.Actor, orderID uint, req model.InvoiceRequest) (*model.Invoice, error)
+	GetInvoice(actor utils.Actor, orderID uint, format string) (*model.Rendered, error)
+}
+
+type receiptService struct {
+	templateRepo  repository.TemplateRepository
+	invoiceRepo   repository.InvoiceRepository
+	orderSvc      orderservice.OrderService
+	paymentSvc    paymentservice.PaymentService
+	restaurantSvc restaurantservice.RestaurantService
+	txManager     database.TxManager
+	now           func() time.Time
+}
+
+func NewReceiptService(
+	templateRepo repository.TemplateRepository,
+	invoiceRepo repository.InvoiceRepository,
+	orderSvc orderservice.OrderService,
+	paymentSvc paymentservice.PaymentService,
+	restaurantSvc restaurantservice.RestaurantService,
+	txManager database.TxManager,
+) ReceiptService {
+	return &receiptService{
+		templateRepo:  templateRepo,
+		invoiceRepo:   invoiceRepo,
+		orderSvc:      orderSvc,
+		paymentSvc:    paymentSvc,
+		restaurantSvc: restaurantSvc,
+		txManager:     txManager,
+		now:           time.Now,
+	}
+}
+
+func (s *receiptService) GetTemplate(actor utils.Actor, restaurantID uint) (*model.Template, error) {
+	if err := s.checkRestaurantAccess(actor, restaurantID); err != nil {
+		return nil, err
+	}
+	return s.templateRepo.GetByRestaurant(restaurantID)
+}
+
+func (s *receiptService) SaveTemplate(actor utils.Actor, restaurantID uint, req model.TemplateRequest) (*model.Template, error) {
+	if err := s.checkRestaurantAccess(actor, restaurantID); err != nil {
+		return nil, err
+	}
+
+	template, err := s.templateRepo.GetByRestaurant(restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Header = database.StringList(req.Header)
+	if template.Header == nil {
+		template.Header = database.StringList{}
+	}
+	template.Footer = database.StringList(req.Footer)
+	if template.Footer == nil {
+		template.Footer = database.StringList{}
+	}
+	template.LegalName = req.LegalName
+	template.TaxID = req.TaxID
+	template.InvoicePrefix = req.InvoicePrefix
+	if template.InvoicePrefix == "" {
+		template.InvoicePrefix = model.DefaultInvoicePrefix
+	}
+	template.Width = req.Width
+	if template.Width == 0 {
+		template.Width = model.DefaultWidth
+	}
+	if req.ShowTaxes != nil {
+		template.ShowTaxes = *req.ShowTaxes
+	}
+
+	if err := s.templateRepo.Save(template); err != nil {
+		return nil, err
+	}
+	return s.templateRepo.GetByRestaurant(restaurantID)
+}
+
+func (s *receiptService) GetReceipt(actor utils.Actor, orderID uint, format string) (*model.Rendered, error) {
+	if !model.IsValidFormat(format) {
+		return nil, ErrInvalidFormat
+	}
+
+	data, err := s.load(actor, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := buildDocument(*data)
+	filename := fmt.Sprintf("receipt-%d", data.order.ID)
+	if format == model.FormatText {
+		return &model.Rendered{ContentType: "text/plain; charset=utf-8", Filename: filename + ".txt", Body: renderText(doc)}, nil
+	}
+	return &model.Rendered{ContentType: "application/pdf", Filename: filename + ".pdf", Body: renderReceiptPDF(doc)}, nil
+}
+
+func (s *receiptService) GetReceiptDocument(actor utils.Actor, orderID uint, width int) (*model.Document, error) {
+	data, err := s.load(actor, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if width != 0 {
+		template := *data.template
+		template.Width = width
+		data.template = &template
+	}
+	return buildDocument(*data), nil
+}
+
+func (s *receiptService) IssueInvoice(actor utils.Actor, orderID uint, req model.InvoiceRequest) (*model.Invoice, error) {
+	data, err := s.load(actor, orderID)
+	if err != nil {
+		return nil, err
+	}
+	order := data.order
+
+	var invoice *model.Invoice
+	err = s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		invoiceRepo := s.invoiceRepo.WithTx(tx)
+
+		existing, err := invoiceRepo.GetByOrder(order.ID)
+		if err == nil {
+			invoice = existing
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		sequence, err := invoiceRepo.NextSequence(order.BranchID)
+		if err != nil {
+			return err
+		}
+		invoice = &model.Invoice{
+			OrderID:         order.ID,
+			BranchID:        order.BranchID,
+			Sequence:        sequence,
+			Number:          fmt.Sprintf("%s%d-%06d", data.template.InvoicePrefix, order.BranchID, sequence),
+			CustomerName:    req.CustomerName,
+			CustomerTaxID:   req.CustomerTaxID,
+			CustomerAddress: req.CustomerAddress,
+			CreatedByID:     utils.ActorID(actor),
+			IssuedAt:        s.now(),
+		}
+		return invoiceRepo.Create(invoice)
+	})
+	if errors.Is(err, database.ErrUniqueViolation) {
+		// The invoice was issued concurrently; its number was rolled back
+		return s.invoiceRepo.GetByOrder(order.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
+
+func (s *receiptService) GetInvoice(actor utils.Actor, orderID uint, format string) (*model.Rendered, error) {
+	if !model.IsValidFormat(format) {
+		return nil, ErrInvalidFormat
+	}
+
+	data, err := s.load(actor, orderID)
+	if err != nil {
+		return nil, err
+	}
+	data.invoice, err = s.invoiceRepo.GetByOrder(data.order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := buildDocument(*data)
+	filename := "invoice-" + data.invoice.Number
+	if format == model.FormatText {
+		return &model.Rendered{ContentType: "text/plain; charset=utf-8", Filename: filename + ".txt", Body: renderText(doc)}, nil
+	}
+	return &model.Rendered{ContentType: "application/pdf", Filename: filename + ".pdf", Body: renderInvoicePDF(doc)}, nil
+}
+
+// load gathers what is printed for a paid order the actor can see
+func (s *receiptService) load(actor utils.Actor, orderID uint) (*receiptData, error) {
+	order, err := s.orderSvc.GetOrder(actor, orderID)
+	if err != nil {
+		return nil, err
+	}
+	switch order.Status {
+	case ordermodel.StatusDraft, ordermodel.StatusCancelled, ordermodel.StatusRejected:
+		return nil, ErrOrderNotPaid
+	}
+
+	payments, err := s.paymentSvc.GetPayments(actor, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if payments.Outstanding > 0 || payments.Pending > 0 {
+		return nil, ErrOrderNotPaid
+	}
+
+	branch, err := s.restaurantSvc.GetBranch(order.BranchID, false)
+	if err != nil {
+		return nil, err
+	}
+	template, err := s.templateRepo.GetByRestaurant(branch.RestaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &receiptData{order: order, branch: branch, template: template, payments: payments}, nil
+}
+
+// checkRestaurantAccess lets admins and the staff of any branch of a
+// restaurant manage its templates
+func (s *receiptService) checkRestaurantAccess(actor utils.Actor, restaurantID uint) error {
+	if _, err := s.restaurantSvc.GetRestaurant(restaurantID, false); err != nil {
+		return err
+	}
+	if actor.IsAdmin() {
+		return nil
+	}
+
+	branches, err := s.restaurantSvc.GetStaffBranches(actor.UserID)
+	if err != nil {
+		return err
+	}
+	for _, branch := range branches {
+		if branch.RestaurantID == restaurantID {
+			return nil
+		}
+	}
+	return utils.ErrBranchAccessDenied
+}
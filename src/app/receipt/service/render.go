@@ -0,0 +1,82 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/faisd405/go-restapi-gin/src/app/receipt/model"
+	"github.com/faisd405/go-restapi-gin/src/pdf"
+)
+
+// Margins and font sizes of PDF output, in points
+const (
+	receiptMargin = 12
+	invoiceMargin = 56
+	maxFontSize   = 10
+	lineSpacing   = 1.3
+	ruleWidth     = 0.5
+	// fitContent sizes a page to its content
+	fitContent = 0
+)
+
+// renderText renders a document as fixed-width text for thermal printers
+func renderText(doc *model.Document) []byte {
+	var b strings.Builder
+	for _, line := range doc.Lines {
+		if line.Rule {
+			b.WriteString(strings.Repeat("-", doc.Width))
+		} else {
+			b.WriteString(strings.TrimRight(line.Text, " "))
+		}
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
+
+// renderReceiptPDF renders a document on a single page of receipt paper
+// that fits its content
+func renderReceiptPDF(doc *model.Document) []byte {
+	return renderPDF(doc, pdf.ReceiptWidth, fitContent, receiptMargin)
+}
+
+// renderInvoicePDF renders a document on A4 pages
+func renderInvoicePDF(doc *model.Document) []byte {
+	return renderPDF(doc, pdf.A4Width, pdf.A4Height, invoiceMargin)
+}
+
+// renderPDF draws the lines of a document in Courier so the layout matches
+// the text format. A page height of 0 fits a single page to the content.
+func renderPDF(doc *model.Document, width, height, margin float64) []byte {
+	size := min((width-2*margin)/(float64(doc.Width)*pdf.CourierAdvance), maxFontSize)
+	leading := size * lineSpacing
+
+	perPage := len(doc.Lines)
+	if height == fitContent {
+		height = 2*margin + float64(len(doc.Lines))*leading
+	} else {
+		perPage = max(int((height-2*margin)/leading), 1)
+	}
+
+	out := pdf.New()
+	var page *pdf.Page
+	var y float64
+	for i, line := range doc.Lines {
+		if i%perPage == 0 {
+			page = out.AddPage(width, height)
+			y = height - margin - size
+		}
+
+		switch {
+		case line.Rule:
+			page.Line(margin, y+size/3, width-margin, y+size/3, ruleWidth)
+		case line.Bold:
+			page.Text(margin, y, pdf.CourierBold, size, line.Text)
+		default:
+			page.Text(margin, y, pdf.Courier, size, line.Text)
+		}
+		y -= leading
+	}
+	if page == nil {
+		out.AddPage(width, height)
+	}
+	return out.Bytes()
+}
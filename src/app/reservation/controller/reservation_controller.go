@@ -0,0 +1,430 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/faisd405/go-restapi-gin/src/app/reservation/model"
+	"github.com/faisd405/go-restapi-gin/src/app/reservation/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Error codes of the reservations API
+const (
+	ErrCodeSlotUnavailable         = "SLOT_UNAVAILABLE"
+	ErrCodeInvalidTransition       = "INVALID_STATUS_TRANSITION"
+	ErrCodeTransitionNotAllowed    = "STATUS_TRANSITION_NOT_ALLOWED"
+	ErrCodeReservationNotEditable  = "RESERVATION_NOT_EDITABLE"
+	ErrCodeReservationRuleViolated = "RESERVATION_RULE_VIOLATED"
+)
+
+type ReservationController struct {
+	reservationService service.ReservationService
+}
+
+func NewReservationController(reservationService service.ReservationService) *ReservationController {
+	return &ReservationController{reservationService: reservationService}
+}
+
+// GetAvailability godoc
+// @Summary Get reservation availability
+// @Description List the seatings of a day, in the branch's time zone, and how many tables are free for the party at each
+// @Tags reservations
+// @Produce json
+// @Param id path int true "Branch ID"
+// @Param date query string true "Local date (YYYY-MM-DD)"
+// @Param party_size query int true "Party size"
+// @Param time query string false "Only this seating (HH:MM)"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /branches/{id}/availability [get]
+func (ctrl *ReservationController) GetAvailability(c *gin.Context) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var query model.AvailabilityQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	availability, err := ctrl.reservationService.GetAvailability(id, query)
+	if err != nil {
+		reservationErrorResponse(c, "Failed to retrieve availability", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Availability retrieved successfully", availability)
+}
+
+// CreateReservation godoc
+// @Summary Create reservation
+// @Description Book a table. Logged-in users book on their account; anonymous guests give a name and an email or phone number.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param reservation body model.ReservationRequest true "Reservation data"
+// @Success 201 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /reservations [post]
+func (ctrl *ReservationController) CreateReservation(c *gin.Context) {
+	var req model.ReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	// Anonymous guests have no actor
+	actor, _ := utils.GetActor(c)
+	reservation, err := ctrl.reservationService.CreateReservation(actor, req)
+	if err != nil {
+		reservationErrorResponse(c, "Reservation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Reservation created successfully", reservation)
+}
+
+// CancelAsGuest godoc
+// @Summary Cancel reservation as guest
+// @Description Cancel a reservation by its reference and the email or phone number it was made with
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param cancel body model.GuestCancelRequest true "Reference and contact"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /reservations/cancel [post]
+func (ctrl *ReservationController) CancelAsGuest(c *gin.Context) {
+	var req model.GuestCancelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	reservation, err := ctrl.reservationService.CancelAsGuest(req)
+	if err != nil {
+		reservationErrorResponse(c, "Cancellation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Reservation cancelled successfully", reservation)
+}
+
+// GetMyReservations godoc
+// @Summary Get my reservations
+// @Description List the reservations of the authenticated user, latest first
+// @Tags reservations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} utils.Response
+// @Router /reservations [get]
+func (ctrl *ReservationController) GetMyReservations(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	page, limit := utils.GetPagination(c)
+	reservations, total, err := ctrl.reservationService.GetMyReservations(actor, page, limit)
+	if err != nil {
+		reservationErrorResponse(c, "Failed to retrieve reservations", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Reservations retrieved successfully",
+		utils.PaginatedData("reservations", reservations, page, limit, total))
+}
+
+// GetReservation godoc
+// @Summary Get reservation
+// @Description Get a reservation of the user or of a branch they work at
+// @Tags reservations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Reservation ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /reservations/{id} [get]
+func (ctrl *ReservationController) GetReservation(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid reservation ID", err.Error())
+		return
+	}
+
+	reservation, err := ctrl.reservationService.GetReservation(actor, id)
+	if err != nil {
+		reservationErrorResponse(c, "Failed to retrieve reservation", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Reservation retrieved successfully", reservation)
+}
+
+// Transition godoc
+// @Summary Change reservation status
+// @Description Confirm, seat, complete, cancel or mark a reservation no-show. Guests may only cancel their own reservations before they start.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Reservation ID"
+// @Param transition body model.TransitionRequest true "Target status"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /reservations/{id}/status [post]
+func (ctrl *ReservationController) Transition(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid reservation ID", err.Error())
+		return
+	}
+
+	var req model.TransitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	reservation, err := ctrl.reservationService.Transition(actor, id, req)
+	if err != nil {
+		reservationErrorResponse(c, "Status change failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Reservation status updated successfully", reservation)
+}
+
+// CreateStaffReservation godoc
+// @Summary Create reservation for a guest (Staff)
+// @Description Book a table on behalf of a guest, optionally at a given table. Override skips the booking window, slot grid and capacity rules but never double-books a table.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param reservation body model.StaffReservationRequest true "Reservation data"
+// @Success 201 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /branches/{id}/reservations [post]
+func (ctrl *ReservationController) CreateStaffReservation(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.StaffReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	reservation, err := ctrl.reservationService.CreateStaffReservation(actor, id, req)
+	if err != nil {
+		reservationErrorResponse(c, "Reservation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Reservation created successfully", reservation)
+}
+
+// GetBranchReservations godoc
+// @Summary Get branch reservations (Staff)
+// @Description List the reservations of a branch in start order
+// @Tags reservations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param date query string false "Local date (YYYY-MM-DD)"
+// @Param status query string false "Filter by status"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/reservations [get]
+func (ctrl *ReservationController) GetBranchReservations(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	page, limit := utils.GetPagination(c)
+	filter := model.ReservationFilter{Date: c.Query("date"), Status: c.Query("status")}
+	reservations, total, err := ctrl.reservationService.GetBranchReservations(actor, id, page, limit, filter)
+	if err != nil {
+		reservationErrorResponse(c, "Failed to retrieve reservations", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Reservations retrieved successfully",
+		utils.PaginatedData("reservations", reservations, page, limit, total))
+}
+
+// Reschedule godoc
+// @Summary Reschedule reservation (Staff)
+// @Description Move a pending or confirmed reservation to another time, party size or table
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Reservation ID"
+// @Param reservation body model.RescheduleRequest true "New time, party size and table"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /reservations/{id} [put]
+func (ctrl *ReservationController) Reschedule(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid reservation ID", err.Error())
+		return
+	}
+
+	var req model.RescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	reservation, err := ctrl.reservationService.Reschedule(actor, id, req)
+	if err != nil {
+		reservationErrorResponse(c, "Reschedule failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Reservation rescheduled successfully", reservation)
+}
+
+// GetSettings godoc
+// @Summary Get reservation settings (Admin/Manager)
+// @Description Get the seating times, turn time, buffer and booking window of a branch
+// @Tags reservations
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/reservation-settings [get]
+func (ctrl *ReservationController) GetSettings(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	settings, err := ctrl.reservationService.GetSettings(actor, id)
+	if err != nil {
+		reservationErrorResponse(c, "Failed to retrieve reservation settings", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Reservation settings retrieved successfully", settings)
+}
+
+// UpdateSettings godoc
+// @Summary Update reservation settings (Admin/Manager)
+// @Description Set the seating times, turn time, buffer and booking window of a branch
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param settings body model.SettingsRequest true "Reservation settings"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/reservation-settings [put]
+func (ctrl *ReservationController) UpdateSettings(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.SettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	settings, err := ctrl.reservationService.UpdateSettings(actor, id, req)
+	if err != nil {
+		reservationErrorResponse(c, "Reservation settings update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Reservation settings updated successfully", settings)
+}
+
+func reservationErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrSlotUnavailable):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeSlotUnavailable, message, err.Error())
+	case errors.Is(err, model.ErrInvalidTransition):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeInvalidTransition, message, err.Error())
+	case errors.Is(err, model.ErrTransitionNotAllowed):
+		utils.ErrorResponseWithCode(c, http.StatusForbidden, ErrCodeTransitionNotAllowed, message, err.Error())
+	case errors.Is(err, service.ErrReservationNotEditable):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeReservationNotEditable, message, err.Error())
+	case errors.Is(err, service.ErrPartyTooLarge),
+		errors.Is(err, service.ErrBranchClosed),
+		errors.Is(err, service.ErrNotSeatingTime),
+		errors.Is(err, service.ErrTooShortNotice),
+		errors.Is(err, service.ErrTooFarAhead),
+		errors.Is(err, service.ErrTooLateToCancel),
+		errors.Is(err, service.ErrNoShowTooEarly):
+		utils.ErrorResponseWithCode(c, http.StatusUnprocessableEntity, ErrCodeReservationRuleViolated, message, err.Error())
+	case errors.Is(err, service.ErrBranchNotBooking),
+		errors.Is(err, service.ErrContactRequired),
+		errors.Is(err, service.ErrTableMismatch),
+		errors.Is(err, service.ErrInvalidStatus),
+		errors.Is(err, service.ErrInvalidDate):
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, message, err.Error())
+	default:
+		utils.DatabaseErrorResponse(c, message, err)
+	}
+}
@@ -0,0 +1,165 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Reservation books a table for a party. The table is blocked from
+// StartsAt until BlockedUntil, the end of the turn plus the buffer the
+// branch needs to turn the table around. Reservations belong to a user
+// account or only carry the guest's contact details.
+type Reservation struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	Reference    string         `json:"reference" gorm:"type:varchar(12);not null;uniqueIndex"`
+	BranchID     uint           `json:"branch_id" gorm:"not null;index"`
+	TableID      uint           `json:"table_id" gorm:"not null;index"`
+	UserID       *uint          `json:"user_id" gorm:"index"`
+	Name         string         `json:"name" gorm:"not null"`
+	Email        string         `json:"email"`
+	Phone        string         `json:"phone" gorm:"type:varchar(30)"`
+	PartySize    int            `json:"party_size" gorm:"not null"`
+	StartsAt     time.Time      `json:"starts_at" gorm:"not null;index"`
+	EndsAt       time.Time      `json:"ends_at" gorm:"not null"`
+	BlockedUntil time.Time      `json:"blocked_until" gorm:"not null"`
+	Status       string         `json:"status" gorm:"type:varchar(20);not null;index"`
+	StatusReason string         `json:"status_reason" gorm:"type:text"`
+	Notes        string         `json:"notes" gorm:"type:text"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Settings are the reservation rules of a branch. Seatings are offered
+// every SlotMinutes from FirstSeating to LastSeating, local time; a last
+// seating before the first one lies after midnight.
+type Settings struct {
+	BranchID         uint      `json:"branch_id" gorm:"primaryKey;autoIncrement:false"`
+	SlotMinutes      int       `json:"slot_minutes" gorm:"not null;default:15"`
+	TurnMinutes      int       `json:"turn_minutes" gorm:"not null;default:90"`
+	BufferMinutes    int       `json:"buffer_minutes" gorm:"not null;default:15"`
+	FirstSeating     string    `json:"first_seating" gorm:"type:varchar(5);not null;default:'11:00'"`
+	LastSeating      string    `json:"last_seating" gorm:"type:varchar(5);not null;default:'21:00'"`
+	MinNoticeMinutes int       `json:"min_notice_minutes" gorm:"not null;default:60"`
+	MaxAdvanceDays   int       `json:"max_advance_days" gorm:"not null;default:60"`
+	MaxPartySize     int       `json:"max_party_size" gorm:"not null;default:12"`
+	AutoConfirm      bool      `json:"auto_confirm" gorm:"not null;default:true"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func (Settings) TableName() string {
+	return "reservation_settings"
+}
+
+// DefaultSettings returns the rules of a branch that has not configured
+// reservations yet
+func DefaultSettings(branchID uint) *Settings {
+	return &Settings{
+		BranchID:         branchID,
+		SlotMinutes:      15,
+		TurnMinutes:      90,
+		BufferMinutes:    15,
+		FirstSeating:     "11:00",
+		LastSeating:      "21:00",
+		MinNoticeMinutes: 60,
+		MaxAdvanceDays:   60,
+		MaxPartySize:     12,
+		AutoConfirm:      true,
+	}
+}
+
+// ReservationRequest books a table as a customer. Anonymous guests must
+// give a name and an email or phone number; registered users default to
+// the details of their account.
+type ReservationRequest struct {
+	BranchID  uint   `json:"branch_id" binding:"required"`
+	Date      string `json:"date" binding:"required,datetime=2006-01-02"`
+	Time      string `json:"time" binding:"required,datetime=15:04"`
+	PartySize int    `json:"party_size" binding:"required,min=1,max=100"`
+	Name      string `json:"name" binding:"max=100"`
+	Email     string `json:"email" binding:"omitempty,email"`
+	Phone     string `json:"phone" binding:"max=30"`
+	Notes     string `json:"notes" binding:"max=500"`
+}
+
+// StaffReservationRequest books a table on behalf of a guest. Override
+// skips the booking window, slot grid, party size limit and table
+// capacity, but never allows two parties at one table.
+type StaffReservationRequest struct {
+	Date      string `json:"date" binding:"required,datetime=2006-01-02"`
+	Time      string `json:"time" binding:"required,datetime=15:04"`
+	PartySize int    `json:"party_size" binding:"required,min=1,max=100"`
+	TableID   *uint  `json:"table_id"`
+	Name      string `json:"name" binding:"required,max=100"`
+	Email     string `json:"email" binding:"omitempty,email"`
+	Phone     string `json:"phone" binding:"max=30"`
+	Notes     string `json:"notes" binding:"max=500"`
+	Override  bool   `json:"override"`
+}
+
+type RescheduleRequest struct {
+	Date      string `json:"date" binding:"required,datetime=2006-01-02"`
+	Time      string `json:"time" binding:"required,datetime=15:04"`
+	PartySize int    `json:"party_size" binding:"required,min=1,max=100"`
+	TableID   *uint  `json:"table_id"`
+	Override  bool   `json:"override"`
+}
+
+type TransitionRequest struct {
+	Status string `json:"status" binding:"required"`
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// GuestCancelRequest cancels a reservation without an account. Contact is
+// the email or phone number the reservation was made with.
+type GuestCancelRequest struct {
+	Reference string `json:"reference" binding:"required"`
+	Contact   string `json:"contact" binding:"required"`
+	Reason    string `json:"reason" binding:"max=500"`
+}
+
+type SettingsRequest struct {
+	SlotMinutes      int    `json:"slot_minutes" binding:"required,min=5,max=240"`
+	TurnMinutes      int    `json:"turn_minutes" binding:"required,min=15,max=720"`
+	BufferMinutes    int    `json:"buffer_minutes" binding:"min=0,max=240"`
+	FirstSeating     string `json:"first_seating" binding:"required,datetime=15:04"`
+	LastSeating      string `json:"last_seating" binding:"required,datetime=15:04"`
+	MinNoticeMinutes int    `json:"min_notice_minutes" binding:"min=0"`
+	MaxAdvanceDays   int    `json:"max_advance_days" binding:"required,min=1,max=365"`
+	MaxPartySize     int    `json:"max_party_size" binding:"required,min=1,max=100"`
+	AutoConfirm      *bool  `json:"auto_confirm"`
+}
+
+// AvailabilityQuery asks for the free seatings of a day. Time narrows the
+// answer down to one slot.
+type AvailabilityQuery struct {
+	Date      string `form:"date" binding:"required,datetime=2006-01-02"`
+	Time      string `form:"time" binding:"omitempty,datetime=15:04"`
+	PartySize int    `form:"party_size" binding:"required,min=1,max=100"`
+}
+
+// Slot is a seating time and how many tables are free for the party
+type Slot struct {
+	StartsAt        time.Time `json:"starts_at"`
+	Time            string    `json:"time"`
+	Available       bool      `json:"available"`
+	TablesAvailable int       `json:"tables_available"`
+}
+
+// Availability lists the seatings of a day in the branch's time zone
+type Availability struct {
+	BranchID  uint   `json:"branch_id"`
+	Date      string `json:"date"`
+	Timezone  string `json:"timezone"`
+	PartySize int    `json:"party_size"`
+	Slots     []Slot `json:"slots"`
+}
+
+// ReservationFilter narrows down reservation listings. Date is a local
+// date of the branch.
+type ReservationFilter struct {
+	Date   string
+	Status string
+}
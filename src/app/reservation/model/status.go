@@ -0,0 +1,96 @@
+package model
+
+import (
+	"errors"
+
+	usermodel "github.com/faisd405/go-restapi-gin/src/app/user/model"
+)
+
+// Reservation statuses
+const (
+	StatusPending   = "pending"
+	StatusConfirmed = "confirmed"
+	StatusSeated    = "seated"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+	StatusNoShow    = "no_show"
+)
+
+// RoleCustomer is the role of the guest a reservation was made for
+const RoleCustomer = "customer"
+
+var (
+	ErrInvalidTransition    = errors.New("invalid status transition")
+	ErrTransitionNotAllowed = errors.New("you are not allowed to make this status transition")
+)
+
+// BlockingStatuses are the statuses in which a reservation holds its table
+var BlockingStatuses = []string{StatusPending, StatusConfirmed, StatusSeated}
+
+// Transition is an allowed edge of the reservation lifecycle
+type Transition struct {
+	From  string
+	To    string
+	Roles []string
+}
+
+var staffRoles = []string{usermodel.RoleStaff, usermodel.RoleManager, usermodel.RoleAdmin}
+
+// Transitions is the reservation lifecycle:
+//
+//	pending → confirmed → seated → completed
+//
+// Guests and staff can cancel until the party is seated. Staff mark
+// parties that did not come as no-show, and can still seat them when they
+// turn up late.
+var Transitions = []Transition{
+	{From: StatusPending, To: StatusConfirmed, Roles: staffRoles},
+	{From: StatusPending, To: StatusCancelled, Roles: append([]string{RoleCustomer}, staffRoles...)},
+
+	{From: StatusConfirmed, To: StatusSeated, Roles: staffRoles},
+	{From: StatusConfirmed, To: StatusNoShow, Roles: staffRoles},
+	{From: StatusConfirmed, To: StatusCancelled, Roles: append([]string{RoleCustomer}, staffRoles...)},
+
+	{From: StatusNoShow, To: StatusSeated, Roles: staffRoles},
+
+	{From: StatusSeated, To: StatusCompleted, Roles: staffRoles},
+}
+
+// IsValidStatus reports whether status is one of the reservation statuses
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusPending, StatusConfirmed, StatusSeated, StatusCompleted, StatusCancelled, StatusNoShow:
+		return true
+	}
+	return false
+}
+
+// IsBlocking reports whether a reservation in status holds its table
+func IsBlocking(status string) bool {
+	for _, s := range BlockingStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTransition returns ErrInvalidTransition when the lifecycle has no
+// edge from one status to the other and ErrTransitionNotAllowed when none
+// of roles may take it
+func CheckTransition(from, to string, roles []string) error {
+	for _, t := range Transitions {
+		if t.From != from || t.To != to {
+			continue
+		}
+		for _, allowed := range t.Roles {
+			for _, role := range roles {
+				if role == allowed {
+					return nil
+				}
+			}
+		}
+		return ErrTransitionNotAllowed
+	}
+	return ErrInvalidTransition
+}
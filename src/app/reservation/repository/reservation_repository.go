@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/reservation/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// lockNamespace keeps the advisory locks of reservations apart from other
+// users of pg_advisory_xact_lock
+const lockNamespace = 38
+
+type ReservationRepository interface {
+	Create(reservation *model.Reservation) error
+	GetByID(id uint) (*model.Reservation, error)
+	GetForUpdate(id uint) (*model.Reservation, error)
+	GetByReference(reference string) (*model.Reservation, error)
+	Update(reservation *model.Reservation) error
+	FindPage(q *database.Query) ([]model.Reservation, int64, error)
+	// FindBlocking returns the reservations of a branch that hold a table
+	// at some point between from and to
+	FindBlocking(branchID uint, from, to time.Time) ([]model.Reservation, error)
+	// LockBranch serializes bookings of a branch until the surrounding
+	// transaction ends
+	LockBranch(branchID uint) error
+	WithTx(tx *gorm.DB) ReservationRepository
+}
+
+type reservationRepository struct {
+	database.Repository[model.Reservation]
+}
+
+func NewReservationRepository(db *gorm.DB) ReservationRepository {
+	return &reservationRepository{Repository: database.NewRepository[model.Reservation](db)}
+}
+
+// GetForUpdate loads a reservation and locks its row until the surrounding
+// transaction ends
+func (r *reservationRepository) GetForUpdate(id uint) (*model.Reservation, error) {
+	var reservation model.Reservation
+	if err := r.DB().Clauses(clause.Locking{Strength: "UPDATE"}).First(&reservation, id).Error; err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *reservationRepository) GetByReference(reference string) (*model.Reservation, error) {
+	return r.First(database.NewQuery().Eq("reference", reference))
+}
+
+func (r *reservationRepository) FindBlocking(branchID uint, from, to time.Time) ([]model.Reservation, error) {
+	return r.Find(database.NewQuery().
+		Eq("branch_id", branchID).
+		In("status", model.BlockingStatuses).
+		Where("starts_at", database.OpLt, to).
+		Where("blocked_until", database.OpGt, from).
+		OrderBy("starts_at"))
+}
+
+func (r *reservationRepository) LockBranch(branchID uint) error {
+	return r.DB().Exec("SELECT pg_advisory_xact_lock(?, ?)", lockNamespace, branchID).Error
+}
+
+func (r *reservationRepository) WithTx(tx *gorm.DB) ReservationRepository {
+	return &reservationRepository{Repository: r.Repository.WithTx(tx)}
+}
+
+type SettingsRepository interface {
+	// GetByBranch returns the settings of a branch, or defaults when the
+	// branch has none yet
+	GetByBranch(branchID uint) (*model.Settings, error)
+	Save(settings *model.Settings) error
+}
+
+type settingsRepository struct {
+	database.Repository[model.Settings]
+}
+
+func NewSettingsRepository(db *gorm.DB) SettingsRepository {
+	return &settingsRepository{Repository: database.NewRepository[model.Settings](db)}
+}
+
+func (r *settingsRepository) GetByBranch(branchID uint) (*model.Settings, error) {
+	settings, err := r.First(database.NewQuery().Eq("branch_id", branchID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.DefaultSettings(branchID), nil
+	}
+	return settings, err
+}
+
+func (r *settingsRepository) Save(settings *model.Settings) error {
+	return r.Upsert(settings, []string{"branch_id"},
+		"slot_minutes", "turn_minutes", "buffer_minutes", "first_seating", "last_seating",
+		"min_notice_minutes", "max_advance_days", "max_party_size", "auto_confirm", "updated_at")
+}
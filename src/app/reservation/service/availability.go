@@ -0,0 +1,82 @@
+package service
+
+import (
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/reservation/model"
+	tablemodel "github.com/faisd405/go-restapi-gin/src/app/table/model"
+)
+
+// seatingTimes returns the seatings the settings offer on a local date
+func seatingTimes(date time.Time, settings *model.Settings) []time.Time {
+	first := atClock(date, settings.FirstSeating)
+	last := atClock(date, settings.LastSeating)
+	if last.Before(first) {
+		last = atClock(date.AddDate(0, 0, 1), settings.LastSeating)
+	}
+
+	step := time.Duration(settings.SlotMinutes) * time.Minute
+	var times []time.Time
+	for t := first; !t.After(last); t = t.Add(step) {
+		times = append(times, t)
+	}
+	return times
+}
+
+// isSeatingTime reports whether start is one of the seatings of its date
+// or, for seatings after midnight, of the day before
+func isSeatingTime(start time.Time, settings *model.Settings) bool {
+	date := localDate(start)
+	for _, day := range []time.Time{date, date.AddDate(0, 0, -1)} {
+		for _, t := range seatingTimes(day, settings) {
+			if t.Equal(start) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// freeTables returns the tables that fit the party and are not held by any
+// of reservations between start and until, smallest first. Without
+// checkCapacity every table is considered.
+func freeTables(tables []tablemodel.Table, reservations []model.Reservation, start, until time.Time, partySize int, checkCapacity bool) []tablemodel.Table {
+	var free []tablemodel.Table
+	for _, table := range tables {
+		if checkCapacity && table.Capacity < partySize {
+			continue
+		}
+		if isHeld(table.ID, reservations, start, until) {
+			continue
+		}
+		free = append(free, table)
+	}
+	return free
+}
+
+// isHeld reports whether one of reservations holds a table between start
+// and until
+func isHeld(tableID uint, reservations []model.Reservation, start, until time.Time) bool {
+	for _, r := range reservations {
+		if r.TableID == tableID && r.StartsAt.Before(until) && start.Before(r.BlockedUntil) {
+			return true
+		}
+	}
+	return false
+}
+
+// localDate returns midnight of the day t falls on in its location
+func localDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// atClock returns the time of day "15:04" on date, in date's location
+func atClock(date time.Time, clock string) time.Time {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return date
+	}
+	y, m, d := date.Date()
+	return time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, date.Location())
+}
@@ -0,0 +1,694 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/reservation/model"
+	"github.com/faisd405/go-restapi-gin/src/app/reservation/repository"
+	restaurantmodel "github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	tablemodel "github.com/faisd405/go-restapi-gin/src/app/table/model"
+	tableservice "github.com/faisd405/go-restapi-gin/src/app/table/service"
+	usermodel "github.com/faisd405/go-restapi-gin/src/app/user/model"
+	userservice "github.com/faisd405/go-restapi-gin/src/app/user/service"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrBranchNotBooking       = errors.New("branch is not taking reservations")
+	ErrBranchClosed           = errors.New("branch is closed at the requested time")
+	ErrSlotUnavailable        = errors.New("no table is available for this party at the requested time")
+	ErrPartyTooLarge          = errors.New("party size exceeds what can be booked")
+	ErrNotSeatingTime         = errors.New("requested time is not a seating time")
+	ErrTooShortNotice         = errors.New("reservations must be made further in advance")
+	ErrTooFarAhead            = errors.New("reservations cannot be made that far ahead")
+	ErrContactRequired        = errors.New("name and an email or phone number are required")
+	ErrTableMismatch          = errors.New("table does not belong to this branch or is inactive")
+	ErrTooLateToCancel        = errors.New("reservations can only be cancelled before they start")
+	ErrNoShowTooEarly         = errors.New("a party can only be marked no-show once the reservation has started")
+	ErrReservationNotEditable = errors.New("only pending or confirmed reservations can be changed")
+	ErrInvalidStatus          = errors.New("unknown reservation status")
+	ErrInvalidDate            = errors.New("date must be formatted as YYYY-MM-DD")
+)
+
+type ReservationService interface {
+	GetAvailability(branchID uint, query model.AvailabilityQuery) (*model.Availability, error)
+
+	// CreateReservation books a table for a registered user or, when the
+	// actor is anonymous, for the guest whose contact details are given
+	CreateReservation(actor utils.Actor, req model.ReservationRequest) (*model.Reservation, error)
+	GetMyReservations(actor utils.Actor, page, limit int) ([]model.Reservation, int64, error)
+	GetReservation(actor utils.Actor, id uint) (*model.Reservation, error)
+	Transition(actor utils.Actor, id uint, req model.TransitionRequest) (*model.Reservation, error)
+	CancelAsGuest(req model.GuestCancelRequest) (*model.Reservation, error)
+
+	CreateStaffReservation(actor utils.Actor, branchID uint, req model.StaffReservationRequest) (*model.Reservation, error)
+	GetBranchReservations(actor utils.Actor, branchID uint, page, limit int, filter model.ReservationFilter) ([]model.Reservation, int64, error)
+	Reschedule(actor utils.Actor, id uint, req model.RescheduleRequest) (*model.Reservation, error)
+
+	GetSettings(actor utils.Actor, branchID uint) (*model.Settings, error)
+	UpdateSettings(actor utils.Actor, branchID uint, req model.SettingsRequest) (*model.Settings, error)
+}
+
+type reservationService struct {
+	reservationRepo repository.ReservationRepository
+	settingsRepo    repository.SettingsRepository
+	tableSvc        tableservice.TableService
+	restaurantSvc   restaurantservice.RestaurantService
+	hoursSvc        restaurantservice.HoursService
+	userSvc         userservice.UserService
+	txManager       database.TxManager
+}
+
+func NewReservationService(
+	reservationRepo repository.ReservationRepository,
+	settingsRepo repository.SettingsRepository,
+	tableSvc tableservice.TableService,
+	restaurantSvc restaurantservice.RestaurantService,
+	hoursSvc restaurantservice.HoursService,
+	userSvc userservice.UserService,
+	txManager database.TxManager,
+) ReservationService {
+	return &reservationService{
+		reservationRepo: reservationRepo,
+		settingsRepo:    settingsRepo,
+		tableSvc:        tableSvc,
+		restaurantSvc:   restaurantSvc,
+		hoursSvc:        hoursSvc,
+		userSvc:         userSvc,
+		txManager:       txManager,
+	}
+}
+
+// booking is a request for a table at a time, checked against the rules of
+// the branch unless overridden by staff
+type booking struct {
+	branch    *restaurantmodel.Branch
+	start     time.Time
+	partySize int
+	tableID   *uint
+	override  bool
+	// excludeID is the reservation being moved, which must not block itself
+	excludeID uint
+}
+
+// GetAvailability lists the seatings of a day and how many tables are
+// free for the party at each of them. Seatings while the branch is closed
+// are never available.
+func (s *reservationService) GetAvailability(branchID uint, query model.AvailabilityQuery) (*model.Availability, error) {
+	branch, err := s.restaurantSvc.GetBranch(branchID, true)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.settingsRepo.GetByBranch(branch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := time.ParseInLocation("2006-01-02", query.Date, branch.Location())
+	if err != nil {
+		return nil, ErrInvalidDate
+	}
+
+	times := seatingTimes(date, settings)
+	if query.Time != "" {
+		start := atClock(date, query.Time)
+		times = []time.Time{start}
+	}
+
+	availability := &model.Availability{
+		BranchID:  branch.ID,
+		Date:      query.Date,
+		Timezone:  branch.Location().String(),
+		PartySize: query.PartySize,
+		Slots:     []model.Slot{},
+	}
+	if len(times) == 0 {
+		return availability, nil
+	}
+
+	schedule, err := s.hoursSvc.GetSchedule(branch.ID)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := s.tableSvc.GetBookableTables(branch.ID)
+	if err != nil {
+		return nil, err
+	}
+	hold := holdDuration(settings)
+	reservations, err := s.reservationRepo.FindBlocking(branch.ID, times[0], times[len(times)-1].Add(hold))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	bookable := branch.Status == restaurantmodel.BranchStatusActive
+	for _, start := range times {
+		slot := model.Slot{StartsAt: start, Time: start.Format("15:04")}
+		if bookable && schedule.IsOpenAt(start) && checkRules(settings, start, query.PartySize, now) == nil {
+			slot.TablesAvailable = len(freeTables(tables, reservations, start, start.Add(hold), query.PartySize, true))
+			slot.Available = slot.TablesAvailable > 0
+		}
+		availability.Slots = append(availability.Slots, slot)
+	}
+
+	return availability, nil
+}
+
+func (s *reservationService) CreateReservation(actor utils.Actor, req model.ReservationRequest) (*model.Reservation, error) {
+	branch, err := s.restaurantSvc.GetBranch(req.BranchID, true)
+	if err != nil {
+		return nil, err
+	}
+	if branch.Status != restaurantmodel.BranchStatusActive {
+		return nil, ErrBranchNotBooking
+	}
+	settings, err := s.settingsRepo.GetByBranch(branch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation := &model.Reservation{
+		BranchID:  branch.ID,
+		Name:      strings.TrimSpace(req.Name),
+		Email:     usermodel.NormalizeEmail(req.Email),
+		Phone:     strings.TrimSpace(req.Phone),
+		PartySize: req.PartySize,
+		Status:    model.StatusPending,
+		Notes:     req.Notes,
+	}
+	if settings.AutoConfirm {
+		reservation.Status = model.StatusConfirmed
+	}
+
+	if actor.UserID != 0 {
+		user, err := s.userSvc.GetProfile(actor.UserID)
+		if err != nil {
+			return nil, err
+		}
+		userID := user.ID
+		reservation.UserID = &userID
+		if reservation.Name == "" {
+			reservation.Name = user.Name
+		}
+		if reservation.Email == "" {
+			reservation.Email = user.Email
+		}
+	}
+	if reservation.Name == "" || (reservation.Email == "" && reservation.Phone == "") {
+		return nil, ErrContactRequired
+	}
+
+	start, err := parseStart(branch, req.Date, req.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.book(reservation, settings, booking{branch: branch, start: start, partySize: req.PartySize})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+func (s *reservationService) GetMyReservations(actor utils.Actor, page, limit int) ([]model.Reservation, int64, error) {
+	q := database.NewQuery().Eq("user_id", actor.UserID)
+	return s.reservationRepo.FindPage(q.OrderByDesc("starts_at").Paginate(utils.Offset(page, limit), limit))
+}
+
+func (s *reservationService) GetReservation(actor utils.Actor, id uint) (*model.Reservation, error) {
+	reservation, err := s.reservationRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.actorRoles(actor, reservation); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+func (s *reservationService) Transition(actor utils.Actor, id uint, req model.TransitionRequest) (*model.Reservation, error) {
+	if !model.IsValidStatus(req.Status) {
+		return nil, ErrInvalidStatus
+	}
+
+	var reservation *model.Reservation
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		reservation, err = s.reservationRepo.WithTx(tx).GetForUpdate(id)
+		if err != nil {
+			return err
+		}
+
+		roles, err := s.actorRoles(actor, reservation)
+		if err != nil {
+			return err
+		}
+
+		return s.applyTransition(tx, reservation, req.Status, roles, req.Reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// CancelAsGuest cancels a reservation by its reference for guests without
+// an account. A contact that does not match is reported as not found so
+// references cannot be probed.
+func (s *reservationService) CancelAsGuest(req model.GuestCancelRequest) (*model.Reservation, error) {
+	reference := strings.ToUpper(strings.TrimSpace(req.Reference))
+	contact := strings.TrimSpace(req.Contact)
+
+	var reservation *model.Reservation
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		reservationRepo := s.reservationRepo.WithTx(tx)
+
+		found, err := reservationRepo.GetByReference(reference)
+		if err != nil {
+			return err
+		}
+		if !matchesContact(found, contact) {
+			return gorm.ErrRecordNotFound
+		}
+
+		reservation, err = reservationRepo.GetForUpdate(found.ID)
+		if err != nil {
+			return err
+		}
+		return s.applyTransition(tx, reservation, model.StatusCancelled, []string{model.RoleCustomer}, req.Reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+func (s *reservationService) CreateStaffReservation(actor utils.Actor, branchID uint, req model.StaffReservationRequest) (*model.Reservation, error) {
+	branch, err := s.restaurantSvc.GetBranch(branchID, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branch.ID); err != nil {
+		return nil, err
+	}
+	settings, err := s.settingsRepo.GetByBranch(branch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := parseStart(branch, req.Date, req.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation := &model.Reservation{
+		BranchID:  branch.ID,
+		Name:      strings.TrimSpace(req.Name),
+		Email:     usermodel.NormalizeEmail(req.Email),
+		Phone:     strings.TrimSpace(req.Phone),
+		PartySize: req.PartySize,
+		Status:    model.StatusConfirmed,
+		Notes:     req.Notes,
+	}
+
+	err = s.book(reservation, settings, booking{
+		branch:    branch,
+		start:     start,
+		partySize: req.PartySize,
+		tableID:   req.TableID,
+		override:  req.Override,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
+func (s *reservationService) GetBranchReservations(actor utils.Actor, branchID uint, page, limit int, filter model.ReservationFilter) ([]model.Reservation, int64, error) {
+	branch, err := s.restaurantSvc.GetBranch(branchID, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branch.ID); err != nil {
+		return nil, 0, err
+	}
+
+	q := database.NewQuery().Eq("branch_id", branch.ID)
+	if filter.Date != "" {
+		day, err := time.ParseInLocation("2006-01-02", filter.Date, branch.Location())
+		if err != nil {
+			return nil, 0, ErrInvalidDate
+		}
+		q.Where("starts_at", database.OpGte, day).Where("starts_at", database.OpLt, day.AddDate(0, 0, 1))
+	}
+	if filter.Status != "" {
+		if !model.IsValidStatus(filter.Status) {
+			return nil, 0, ErrInvalidStatus
+		}
+		q.Eq("status", filter.Status)
+	}
+
+	return s.reservationRepo.FindPage(q.OrderBy("starts_at").Paginate(utils.Offset(page, limit), limit))
+}
+
+// Reschedule moves a reservation to another time, party size or table
+func (s *reservationService) Reschedule(actor utils.Actor, id uint, req model.RescheduleRequest) (*model.Reservation, error) {
+	current, err := s.reservationRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, current.BranchID); err != nil {
+		return nil, err
+	}
+
+	branch, err := s.restaurantSvc.GetBranch(current.BranchID, false)
+	if err != nil {
+		return nil, err
+	}
+	settings, err := s.settingsRepo.GetByBranch(branch.ID)
+	if err != nil {
+		return nil, err
+	}
+	start, err := parseStart(branch, req.Date, req.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	var reservation *model.Reservation
+	err = s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		var err error
+		reservation, err = s.reservationRepo.WithTx(tx).GetForUpdate(id)
+		if err != nil {
+			return err
+		}
+		if reservation.Status != model.StatusPending && reservation.Status != model.StatusConfirmed {
+			return ErrReservationNotEditable
+		}
+
+		reservation.PartySize = req.PartySize
+		return s.assign(tx, reservation, settings, booking{
+			branch:    branch,
+			start:     start,
+			partySize: req.PartySize,
+			tableID:   req.TableID,
+			override:  req.Override,
+			excludeID: reservation.ID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+func (s *reservationService) GetSettings(actor utils.Actor, branchID uint) (*model.Settings, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+	return s.settingsRepo.GetByBranch(branchID)
+}
+
+func (s *reservationService) UpdateSettings(actor utils.Actor, branchID uint, req model.SettingsRequest) (*model.Settings, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.settingsRepo.GetByBranch(branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings.SlotMinutes = req.SlotMinutes
+	settings.TurnMinutes = req.TurnMinutes
+	settings.BufferMinutes = req.BufferMinutes
+	settings.FirstSeating = req.FirstSeating
+	settings.LastSeating = req.LastSeating
+	settings.MinNoticeMinutes = req.MinNoticeMinutes
+	settings.MaxAdvanceDays = req.MaxAdvanceDays
+	settings.MaxPartySize = req.MaxPartySize
+	if req.AutoConfirm != nil {
+		settings.AutoConfirm = *req.AutoConfirm
+	}
+
+	if err := s.settingsRepo.Save(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// book assigns a table to a new reservation and stores it
+func (s *reservationService) book(reservation *model.Reservation, settings *model.Settings, b booking) error {
+	reference, err := newReference()
+	if err != nil {
+		return err
+	}
+	reservation.Reference = reference
+
+	return s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		return s.assign(tx, reservation, settings, b)
+	})
+}
+
+// assign checks the booking rules and opening hours, picks a table and saves the
+// reservation. Bookings of a branch are serialized with a lock so two
+// guests can never get the same table; the exclusion constraint on the
+// reservations table backs this up.
+func (s *reservationService) assign(tx *gorm.DB, reservation *model.Reservation, settings *model.Settings, b booking) error {
+	if !b.override {
+		if err := checkRules(settings, b.start, b.partySize, time.Now()); err != nil {
+			return err
+		}
+		schedule, err := s.hoursSvc.GetSchedule(b.branch.ID)
+		if err != nil {
+			return err
+		}
+		if !schedule.IsOpenAt(b.start) {
+			return ErrBranchClosed
+		}
+	}
+
+	reservationRepo := s.reservationRepo.WithTx(tx)
+	if err := reservationRepo.LockBranch(b.branch.ID); err != nil {
+		return err
+	}
+
+	tables, err := s.tableSvc.GetBookableTables(b.branch.ID)
+	if err != nil {
+		return err
+	}
+
+	until := b.start.Add(holdDuration(settings))
+	held, err := reservationRepo.FindBlocking(b.branch.ID, b.start, until)
+	if err != nil {
+		return err
+	}
+	held = withoutReservation(held, b.excludeID)
+
+	free := freeTables(tables, held, b.start, until, b.partySize, !b.override)
+	if b.tableID != nil {
+		table, ok := findTable(tables, *b.tableID)
+		if !ok {
+			return ErrTableMismatch
+		}
+		if !b.override && table.Capacity < b.partySize {
+			return ErrPartyTooLarge
+		}
+		if isHeld(table.ID, held, b.start, until) {
+			return ErrSlotUnavailable
+		}
+		free = []tablemodel.Table{table}
+	}
+	if len(free) == 0 {
+		return ErrSlotUnavailable
+	}
+
+	reservation.TableID = free[0].ID
+	reservation.StartsAt = b.start
+	reservation.EndsAt = b.start.Add(time.Duration(settings.TurnMinutes) * time.Minute)
+	reservation.BlockedUntil = until
+
+	if reservation.ID == 0 {
+		err = reservationRepo.Create(reservation)
+	} else {
+		err = reservationRepo.Update(reservation)
+	}
+	if errors.Is(err, database.ErrExclusionViolation) {
+		return ErrSlotUnavailable
+	}
+	return err
+}
+
+// applyTransition moves a locked reservation to status. Bringing a
+// reservation back into a status that holds its table, e.g. seating a
+// late no-show, requires the table to still be free.
+func (s *reservationService) applyTransition(tx *gorm.DB, reservation *model.Reservation, status string, roles []string, reason string) error {
+	if err := model.CheckTransition(reservation.Status, status, roles); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	staff := hasStaffRole(roles)
+	if status == model.StatusCancelled && !staff && !now.Before(reservation.StartsAt) {
+		return ErrTooLateToCancel
+	}
+	if status == model.StatusNoShow && now.Before(reservation.StartsAt) {
+		return ErrNoShowTooEarly
+	}
+
+	reservationRepo := s.reservationRepo.WithTx(tx)
+	if !model.IsBlocking(reservation.Status) && model.IsBlocking(status) {
+		if err := reservationRepo.LockBranch(reservation.BranchID); err != nil {
+			return err
+		}
+		held, err := reservationRepo.FindBlocking(reservation.BranchID, reservation.StartsAt, reservation.BlockedUntil)
+		if err != nil {
+			return err
+		}
+		if isHeld(reservation.TableID, withoutReservation(held, reservation.ID), reservation.StartsAt, reservation.BlockedUntil) {
+			return ErrSlotUnavailable
+		}
+	}
+
+	reservation.Status = status
+	reservation.StatusReason = reason
+	err := reservationRepo.Update(reservation)
+	if errors.Is(err, database.ErrExclusionViolation) {
+		return ErrSlotUnavailable
+	}
+	return err
+}
+
+// actorRoles returns the roles the actor holds on a reservation: customer
+// when it is theirs and their staff role when they work at its branch.
+// Users without any role get a not found error.
+func (s *reservationService) actorRoles(actor utils.Actor, reservation *model.Reservation) ([]string, error) {
+	roles := []string{}
+	if actor.UserID != 0 && reservation.UserID != nil && *reservation.UserID == actor.UserID {
+		roles = append(roles, model.RoleCustomer)
+	}
+
+	switch actor.Role {
+	case usermodel.RoleStaff, usermodel.RoleManager, usermodel.RoleAdmin:
+		err := utils.CheckBranchAccess(s.restaurantSvc, actor, reservation.BranchID)
+		if err == nil {
+			roles = append(roles, actor.Role)
+		} else if !errors.Is(err, utils.ErrBranchAccessDenied) {
+			return nil, err
+		}
+	}
+
+	if len(roles) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return roles, nil
+}
+
+// checkRules applies the booking window, slot grid and party size limit of
+// a branch
+func checkRules(settings *model.Settings, start time.Time, partySize int, now time.Time) error {
+	switch {
+	case partySize > settings.MaxPartySize:
+		return ErrPartyTooLarge
+	case !isSeatingTime(start, settings):
+		return ErrNotSeatingTime
+	case start.Before(now.Add(time.Duration(settings.MinNoticeMinutes) * time.Minute)):
+		return ErrTooShortNotice
+	case start.After(now.AddDate(0, 0, settings.MaxAdvanceDays)):
+		return ErrTooFarAhead
+	}
+	return nil
+}
+
+// holdDuration is how long a reservation keeps its table: the turn time
+// plus the buffer to clear and reset it
+func holdDuration(settings *model.Settings) time.Duration {
+	return time.Duration(settings.TurnMinutes+settings.BufferMinutes) * time.Minute
+}
+
+// parseStart returns the local date and time of day in the time zone of
+// the branch
+func parseStart(branch *restaurantmodel.Branch, date, clock string) (time.Time, error) {
+	day, err := time.ParseInLocation("2006-01-02", date, branch.Location())
+	if err != nil {
+		return time.Time{}, ErrInvalidDate
+	}
+	return atClock(day, clock), nil
+}
+
+func withoutReservation(reservations []model.Reservation, id uint) []model.Reservation {
+	if id == 0 {
+		return reservations
+	}
+	kept := reservations[:0:0]
+	for _, r := range reservations {
+		if r.ID != id {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+func findTable(tables []tablemodel.Table, id uint) (tablemodel.Table, bool) {
+	for _, table := range tables {
+		if table.ID == id {
+			return table, true
+		}
+	}
+	return tablemodel.Table{}, false
+}
+
+func hasStaffRole(roles []string) bool {
+	for _, role := range roles {
+		if role != model.RoleCustomer {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesContact reports whether contact is the email or phone number of
+// a reservation
+func matchesContact(reservation *model.Reservation, contact string) bool {
+	if contact == "" {
+		return false
+	}
+	if reservation.Email != "" && strings.EqualFold(reservation.Email, contact) {
+		return true
+	}
+	return reservation.Phone != "" && normalizePhone(reservation.Phone) == normalizePhone(contact)
+}
+
+func normalizePhone(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '+' {
+			return r
+		}
+		return -1
+	}, phone)
+}
+
+// newReference returns a short random code guests quote to find their
+// reservation
+func newReference() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,312 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	"github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type BranchController struct {
+	restaurantService service.RestaurantService
+}
+
+func NewBranchController(restaurantService service.RestaurantService) *BranchController {
+	return &BranchController{restaurantService: restaurantService}
+}
+
+// GetBranches godoc
+// @Summary List restaurant branches
+// @Description Get the branches of an active restaurant
+// @Tags branches
+// @Produce json
+// @Param id path int true "Restaurant ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /restaurants/{id}/branches [get]
+func (ctrl *BranchController) GetBranches(c *gin.Context) {
+	restaurantID, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid restaurant ID", err.Error())
+		return
+	}
+
+	if _, err := ctrl.restaurantService.GetRestaurant(restaurantID, true); err != nil {
+		utils.DatabaseErrorResponse(c, "Restaurant not found", err)
+		return
+	}
+
+	branches, err := ctrl.restaurantService.GetBranches(restaurantID, true)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get branches", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Branches retrieved successfully", branches)
+}
+
+// GetBranch godoc
+// @Summary Get branch
+// @Description Get a branch of an active restaurant
+// @Tags branches
+// @Produce json
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /branches/{id} [get]
+func (ctrl *BranchController) GetBranch(c *gin.Context) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	branch, err := ctrl.restaurantService.GetBranch(id, true)
+	if err != nil {
+		utils.DatabaseErrorResponse(c, "Branch not found", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Branch retrieved successfully", branch)
+}
+
+// GetMyBranches godoc
+// @Summary Get my branches
+// @Description Get the branches the current user is assigned to as staff
+// @Tags users
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /users/branches [get]
+func (ctrl *BranchController) GetMyBranches(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated", "user ID not found")
+		return
+	}
+
+	branches, err := ctrl.restaurantService.GetStaffBranches(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get branches", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Branches retrieved successfully", branches)
+}
+
+// CreateBranch godoc
+// @Summary Create branch (Admin only)
+// @Description Add a branch to a restaurant
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Restaurant ID"
+// @Param branch body model.BranchRequest true "Branch data"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/restaurants/{id}/branches [post]
+func (ctrl *BranchController) CreateBranch(c *gin.Context) {
+	restaurantID, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid restaurant ID", err.Error())
+		return
+	}
+
+	var req model.BranchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	branch, err := ctrl.restaurantService.CreateBranch(restaurantID, req)
+	if err != nil {
+		utils.DatabaseErrorResponse(c, "Branch creation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Branch created successfully", branch)
+}
+
+// AdminGetBranch godoc
+// @Summary Get branch (Admin only)
+// @Description Get a branch in any status
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/branches/{id} [get]
+func (ctrl *BranchController) AdminGetBranch(c *gin.Context) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	branch, err := ctrl.restaurantService.GetBranch(id, false)
+	if err != nil {
+		utils.DatabaseErrorResponse(c, "Branch not found", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Branch retrieved successfully", branch)
+}
+
+// UpdateBranch godoc
+// @Summary Update branch (Admin only)
+// @Description Update a branch by ID
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param branch body model.BranchRequest true "Branch data"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/branches/{id} [put]
+func (ctrl *BranchController) UpdateBranch(c *gin.Context) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.BranchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	branch, err := ctrl.restaurantService.UpdateBranch(id, req)
+	if err != nil {
+		utils.DatabaseErrorResponse(c, "Branch update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Branch updated successfully", branch)
+}
+
+// DeleteBranch godoc
+// @Summary Delete branch (Admin only)
+// @Description Delete a branch by ID
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/branches/{id} [delete]
+func (ctrl *BranchController) DeleteBranch(c *gin.Context) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	if err := ctrl.restaurantService.DeleteBranch(id); err != nil {
+		utils.DatabaseErrorResponse(c, "Branch deletion failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Branch deleted successfully", nil)
+}
+
+// GetStaff godoc
+// @Summary List branch staff (Admin only)
+// @Description Get the user accounts assigned to a branch
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/branches/{id}/staff [get]
+func (ctrl *BranchController) GetStaff(c *gin.Context) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	staff, err := ctrl.restaurantService.GetStaff(id)
+	if err != nil {
+		utils.DatabaseErrorResponse(c, "Failed to get staff", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Staff retrieved successfully", staff)
+}
+
+// AssignStaff godoc
+// @Summary Assign staff to branch (Admin only)
+// @Description Link a staff, manager or admin user account to a branch
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param staff body model.AssignStaffRequest true "Staff assignment"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /admin/branches/{id}/staff [post]
+func (ctrl *BranchController) AssignStaff(c *gin.Context) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.AssignStaffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	member, err := ctrl.restaurantService.AssignStaff(id, req)
+	if err != nil {
+		restaurantErrorResponse(c, "Staff assignment failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Staff assigned successfully", member)
+}
+
+// RemoveStaff godoc
+// @Summary Remove staff from branch (Admin only)
+// @Description Unlink a user account from a branch
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param userId path int true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/branches/{id}/staff/{userId} [delete]
+func (ctrl *BranchController) RemoveStaff(c *gin.Context) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	userID, err := utils.ParseID(c, "userId")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	if err := ctrl.restaurantService.RemoveStaff(id, userID); err != nil {
+		restaurantErrorResponse(c, "Staff removal failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Staff removed successfully", nil)
+}
@@ -0,0 +1,198 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	"github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type HoursController struct {
+	hoursService service.HoursService
+}
+
+func NewHoursController(hoursService service.HoursService) *HoursController {
+	return &HoursController{hoursService: hoursService}
+}
+
+// GetHours godoc
+// @Summary Get branch opening hours
+// @Description Get the weekly shifts and upcoming exceptions of a branch in its local time
+// @Tags branches
+// @Produce json
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /branches/{id}/hours [get]
+func (ctrl *HoursController) GetHours(c *gin.Context) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	hours, err := ctrl.hoursService.GetHours(id)
+	if err != nil {
+		hoursErrorResponse(c, "Failed to retrieve opening hours", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Opening hours retrieved successfully", hours)
+}
+
+// GetOpenStatus godoc
+// @Summary Get branch open status
+// @Description Tell whether a branch is open now, or at the given time, and when it next opens or closes
+// @Tags branches
+// @Produce json
+// @Param id path int true "Branch ID"
+// @Param at query string false "Moment to check (RFC 3339), defaults to now"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /branches/{id}/open-status [get]
+func (ctrl *HoursController) GetOpenStatus(c *gin.Context) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	at := time.Now()
+	if raw := c.Query("at"); raw != "" {
+		at, err = time.Parse(time.RFC3339, raw)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid time", "at must be an RFC 3339 timestamp")
+			return
+		}
+	}
+
+	status, err := ctrl.hoursService.GetOpenStatus(id, at)
+	if err != nil {
+		hoursErrorResponse(c, "Failed to retrieve open status", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Open status retrieved successfully", status)
+}
+
+// SetWeeklyHours godoc
+// @Summary Set weekly opening hours (Admin/Manager)
+// @Description Replace the weekly shifts of a branch. Weekdays count from 0 for Sunday; a shift closing at or before its opening time ends the next day.
+// @Tags branches
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param hours body model.WeeklyHoursRequest true "Weekly shifts"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/hours [put]
+func (ctrl *HoursController) SetWeeklyHours(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.WeeklyHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	hours, err := ctrl.hoursService.SetWeeklyHours(actor, id, req)
+	if err != nil {
+		hoursErrorResponse(c, "Opening hours update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Opening hours updated successfully", hours)
+}
+
+// SaveException godoc
+// @Summary Save opening hours exception (Admin/Manager)
+// @Description Close a branch on a date or replace its hours that day, e.g. for a holiday. An existing exception of the date is replaced.
+// @Tags branches
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param exception body model.HoursExceptionRequest true "Exception data"
+// @Success 200 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /branches/{id}/hours/exceptions [post]
+func (ctrl *HoursController) SaveException(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.HoursExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	exception, err := ctrl.hoursService.SaveException(actor, id, req)
+	if err != nil {
+		hoursErrorResponse(c, "Exception update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Exception saved successfully", exception)
+}
+
+// DeleteException godoc
+// @Summary Delete opening hours exception (Admin/Manager)
+// @Description Remove an exception so the weekly hours apply again
+// @Tags branches
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Exception ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /hours-exceptions/{id} [delete]
+func (ctrl *HoursController) DeleteException(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid exception ID", err.Error())
+		return
+	}
+
+	if err := ctrl.hoursService.DeleteException(actor, id); err != nil {
+		hoursErrorResponse(c, "Exception deletion failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Exception deleted successfully", nil)
+}
+
+func hoursErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrShiftsOnClosedDay),
+		errors.Is(err, service.ErrShiftsRequired):
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, message, err.Error())
+	default:
+		utils.DatabaseErrorResponse(c, message, err)
+	}
+}
@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	"github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type RestaurantController struct {
+	restaurantService service.RestaurantService
+}
+
+func NewRestaurantController(restaurantService service.RestaurantService) *RestaurantController {
+	return &RestaurantController{restaurantService: restaurantService}
+}
+
+// GetRestaurants godoc
+// @Summary List restaurants
+// @Description Get paginated list of active restaurants
+// @Tags restaurants
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response
+// @Router /restaurants [get]
+func (ctrl *RestaurantController) GetRestaurants(c *gin.Context) {
+	ctrl.listRestaurants(c, true)
+}
+
+// GetRestaurant godoc
+// @Summary Get restaurant
+// @Description Get an active restaurant with its open branches
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /restaurants/{id} [get]
+func (ctrl *RestaurantController) GetRestaurant(c *gin.Context) {
+	ctrl.showRestaurant(c, true)
+}
+
+// AdminGetRestaurants godoc
+// @Summary List all restaurants (Admin only)
+// @Description Get paginated list of restaurants in every status
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} utils.Response
+// @Router /admin/restaurants [get]
+func (ctrl *RestaurantController) AdminGetRestaurants(c *gin.Context) {
+	ctrl.listRestaurants(c, false)
+}
+
+// AdminGetRestaurant godoc
+// @Summary Get restaurant (Admin only)
+// @Description Get a restaurant in any status with all of its branches
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Restaurant ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/restaurants/{id} [get]
+func (ctrl *RestaurantController) AdminGetRestaurant(c *gin.Context) {
+	ctrl.showRestaurant(c, false)
+}
+
+// CreateRestaurant godoc
+// @Summary Create restaurant (Admin only)
+// @Description Create a new restaurant
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param restaurant body model.CreateRestaurantRequest true "Restaurant data"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /admin/restaurants [post]
+func (ctrl *RestaurantController) CreateRestaurant(c *gin.Context) {
+	var req model.CreateRestaurantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	restaurant, err := ctrl.restaurantService.CreateRestaurant(req)
+	if err != nil {
+		restaurantErrorResponse(c, "Restaurant creation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Restaurant created successfully", restaurant)
+}
+
+// UpdateRestaurant godoc
+// @Summary Update restaurant (Admin only)
+// @Description Update a restaurant by ID
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Restaurant ID"
+// @Param restaurant body model.UpdateRestaurantRequest true "Restaurant data"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/restaurants/{id} [put]
+func (ctrl *RestaurantController) UpdateRestaurant(c *gin.Context) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid restaurant ID", err.Error())
+		return
+	}
+
+	var req model.UpdateRestaurantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	restaurant, err := ctrl.restaurantService.UpdateRestaurant(id, req)
+	if err != nil {
+		restaurantErrorResponse(c, "Restaurant update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Restaurant updated successfully", restaurant)
+}
+
+// DeleteRestaurant godoc
+// @Summary Delete restaurant (Admin only)
+// @Description Delete a restaurant and its branches
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Restaurant ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/restaurants/{id} [delete]
+func (ctrl *RestaurantController) DeleteRestaurant(c *gin.Context) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid restaurant ID", err.Error())
+		return
+	}
+
+	if err := ctrl.restaurantService.DeleteRestaurant(id); err != nil {
+		utils.DatabaseErrorResponse(c, "Restaurant deletion failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Restaurant deleted successfully", nil)
+}
+
+func (ctrl *RestaurantController) listRestaurants(c *gin.Context, publicOnly bool) {
+	page, limit := utils.GetPagination(c)
+
+	restaurants, total, err := ctrl.restaurantService.GetAllRestaurants(page, limit, publicOnly)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get restaurants", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Restaurants retrieved successfully",
+		utils.PaginatedData("restaurants", restaurants, page, limit, total))
+}
+
+func (ctrl *RestaurantController) showRestaurant(c *gin.Context, publicOnly bool) {
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid restaurant ID", err.Error())
+		return
+	}
+
+	restaurant, err := ctrl.restaurantService.GetRestaurant(id, publicOnly)
+	if err != nil {
+		utils.DatabaseErrorResponse(c, "Restaurant not found", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Restaurant retrieved successfully", restaurant)
+}
+
+func restaurantErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidSlug),
+		errors.Is(err, service.ErrUserInactive),
+		errors.Is(err, service.ErrStaffRoleRequired):
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, message, err.Error())
+	case errors.Is(err, service.ErrStaffNotAssigned):
+		utils.ErrorResponse(c, http.StatusNotFound, message, err.Error())
+	default:
+		utils.DatabaseErrorResponse(c, message, err)
+	}
+}
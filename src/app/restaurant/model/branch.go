@@ -0,0 +1,101 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Branch statuses
+const (
+	BranchStatusActive            = "active"
+	BranchStatusTemporarilyClosed = "temporarily_closed"
+	BranchStatusInactive          = "inactive"
+)
+
+type Branch struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	RestaurantID uint           `json:"restaurant_id" gorm:"not null;index"`
+	Restaurant   *Restaurant    `json:"restaurant,omitempty"`
+	Name         string         `json:"name" gorm:"not null"`
+	AddressLine1 string         `json:"address_line1" gorm:"not null"`
+	AddressLine2 string         `json:"address_line2"`
+	City         string         `json:"city" gorm:"not null"`
+	State        string         `json:"state"`
+	PostalCode   string         `json:"postal_code"`
+	Country      string         `json:"country" gorm:"type:char(2);not null"`
+	Latitude     *float64       `json:"latitude"`
+	Longitude    *float64       `json:"longitude"`
+	Timezone     string         `json:"timezone" gorm:"not null"`
+	Phone        string         `json:"phone"`
+	Email        string         `json:"email"`
+	Currency     string         `json:"currency" gorm:"type:char(3)"`
+	Status       string         `json:"status" gorm:"type:varchar(20);not null;default:active;index"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Location returns the branch's time zone, falling back to UTC when the
+// stored name cannot be loaded
+func (b *Branch) Location() *time.Location {
+	loc, err := time.LoadLocation(b.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// EffectiveCurrency returns the branch currency, or the restaurant's when
+// the branch does not override it
+func (b *Branch) EffectiveCurrency() string {
+	if b.Currency != "" || b.Restaurant == nil {
+		return b.Currency
+	}
+	return b.Restaurant.Currency
+}
+
+// BranchStaff links a user account to a branch it works at
+type BranchStaff struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	BranchID  uint      `json:"branch_id" gorm:"not null;uniqueIndex:idx_branch_staff_branch_user"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_branch_staff_branch_user;index"`
+	Position  string    `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (BranchStaff) TableName() string {
+	return "branch_staff"
+}
+
+type BranchRequest struct {
+	Name         string   `json:"name" binding:"required"`
+	AddressLine1 string   `json:"address_line1" binding:"required"`
+	AddressLine2 string   `json:"address_line2"`
+	City         string   `json:"city" binding:"required"`
+	State        string   `json:"state"`
+	PostalCode   string   `json:"postal_code"`
+	Country      string   `json:"country" binding:"required,iso3166_1_alpha2"`
+	Latitude     *float64 `json:"latitude" binding:"omitempty,latitude"`
+	Longitude    *float64 `json:"longitude" binding:"omitempty,longitude"`
+	Timezone     string   `json:"timezone" binding:"required,timezone"`
+	Phone        string   `json:"phone"`
+	Email        string   `json:"email" binding:"omitempty,email"`
+	Currency     string   `json:"currency" binding:"omitempty,iso4217"`
+	Status       string   `json:"status" binding:"omitempty,oneof=active temporarily_closed inactive"`
+}
+
+type AssignStaffRequest struct {
+	UserID   uint   `json:"user_id" binding:"required"`
+	Position string `json:"position"`
+}
+
+// StaffMember is a branch staff link together with the user's details
+type StaffMember struct {
+	UserID   uint   `json:"user_id"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	Position string `json:"position"`
+}
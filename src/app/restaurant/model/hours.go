@@ -0,0 +1,213 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/database"
+)
+
+// OpeningHours is a weekly shift of a branch in local time. A branch may
+// have several shifts a day; a shift closing at or before its opening time
+// ends the next day, so "18:00"–"02:00" runs past midnight and equal times
+// mean a 24 hour shift.
+type OpeningHours struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	BranchID  uint      `json:"branch_id" gorm:"not null;index"`
+	Weekday   int       `json:"weekday" gorm:"not null"`
+	Opens     string    `json:"opens" gorm:"type:varchar(5);not null"`
+	Closes    string    `json:"closes" gorm:"type:varchar(5);not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (OpeningHours) TableName() string {
+	return "branch_opening_hours"
+}
+
+// Shift is an opening interval of a day, "15:04" to "15:04"
+type Shift struct {
+	Opens  string `json:"opens" binding:"required,datetime=15:04"`
+	Closes string `json:"closes" binding:"required,datetime=15:04"`
+}
+
+// HoursException replaces the weekly hours of a branch on one local date,
+// e.g. for a public holiday. Closed exceptions have no shifts.
+type HoursException struct {
+	ID        uint                     `json:"id" gorm:"primaryKey"`
+	BranchID  uint                     `json:"branch_id" gorm:"not null;uniqueIndex:idx_hours_exceptions_branch_date"`
+	Date      string                   `json:"date" gorm:"type:varchar(10);not null;uniqueIndex:idx_hours_exceptions_branch_date"`
+	Name      string                   `json:"name"`
+	Closed    bool                     `json:"closed" gorm:"not null;default:false"`
+	Shifts    database.JSONList[Shift] `json:"shifts" gorm:"type:jsonb;not null;default:'[]'"`
+	CreatedAt time.Time                `json:"created_at"`
+	UpdatedAt time.Time                `json:"updated_at"`
+}
+
+func (HoursException) TableName() string {
+	return "branch_hours_exceptions"
+}
+
+type WeeklyShiftRequest struct {
+	Weekday int    `json:"weekday" binding:"min=0,max=6"`
+	Opens   string `json:"opens" binding:"required,datetime=15:04"`
+	Closes  string `json:"closes" binding:"required,datetime=15:04"`
+}
+
+// WeeklyHoursRequest replaces all weekly shifts of a branch. Weekdays
+// count from 0 for Sunday.
+type WeeklyHoursRequest struct {
+	Shifts []WeeklyShiftRequest `json:"shifts" binding:"dive"`
+}
+
+type HoursExceptionRequest struct {
+	Date   string  `json:"date" binding:"required,datetime=2006-01-02"`
+	Name   string  `json:"name" binding:"max=100"`
+	Closed bool    `json:"closed"`
+	Shifts []Shift `json:"shifts" binding:"dive"`
+}
+
+// BranchHours are the weekly shifts and date exceptions of a branch
+type BranchHours struct {
+	BranchID   uint             `json:"branch_id"`
+	Timezone   string           `json:"timezone"`
+	Weekly     []OpeningHours   `json:"weekly"`
+	Exceptions []HoursException `json:"exceptions"`
+}
+
+// OpenStatus tells whether a branch is open at a moment. ClosesAt is set
+// while open, OpensAt while closed when the branch opens again within a
+// week or two.
+type OpenStatus struct {
+	BranchID  uint       `json:"branch_id"`
+	Timezone  string     `json:"timezone"`
+	LocalTime string     `json:"local_time"`
+	IsOpen    bool       `json:"is_open"`
+	Reason    string     `json:"reason,omitempty"`
+	OpensAt   *time.Time `json:"opens_at,omitempty"`
+	ClosesAt  *time.Time `json:"closes_at,omitempty"`
+}
+
+// Interval is an opening interval in absolute time
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// lookahead is how many days NextOpening searches
+const lookahead = 14
+
+// Schedule answers opening hour questions for a branch in its own time
+// zone. A branch without weekly hours is open around the clock except on
+// its exceptions.
+type Schedule struct {
+	Location   *time.Location
+	Weekly     []OpeningHours
+	Exceptions map[string]HoursException
+}
+
+// NewSchedule builds the schedule of a branch from its hours
+func NewSchedule(loc *time.Location, weekly []OpeningHours, exceptions []HoursException) *Schedule {
+	s := &Schedule{Location: loc, Weekly: weekly, Exceptions: map[string]HoursException{}}
+	for _, e := range exceptions {
+		s.Exceptions[e.Date] = e
+	}
+	return s
+}
+
+// Intervals returns the opening intervals starting on a local date, in
+// start order. Intervals of shifts past midnight end on the next day.
+func (s *Schedule) Intervals(date time.Time) []Interval {
+	y, m, d := date.In(s.Location).Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, s.Location)
+
+	var shifts []Shift
+	if e, ok := s.Exceptions[day.Format("2006-01-02")]; ok {
+		if !e.Closed {
+			shifts = e.Shifts
+		}
+	} else if len(s.Weekly) == 0 {
+		shifts = []Shift{{Opens: "00:00", Closes: "00:00"}}
+	} else {
+		for _, h := range s.Weekly {
+			if time.Weekday(h.Weekday) == day.Weekday() {
+				shifts = append(shifts, Shift{Opens: h.Opens, Closes: h.Closes})
+			}
+		}
+	}
+
+	intervals := make([]Interval, 0, len(shifts))
+	for _, shift := range shifts {
+		start := clockOn(day, shift.Opens)
+		end := clockOn(day, shift.Closes)
+		if !end.After(start) {
+			end = clockOn(day.AddDate(0, 0, 1), shift.Closes)
+		}
+		intervals = append(intervals, Interval{Start: start, End: end})
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].Start.Before(intervals[j].Start) })
+	return intervals
+}
+
+// IsOpenAt reports whether the branch is open at t
+func (s *Schedule) IsOpenAt(t time.Time) bool {
+	_, ok := s.intervalAt(t)
+	return ok
+}
+
+// ClosesAt returns when the branch closes if it is open at t. Back to
+// back intervals, such as a shift to midnight followed by one from
+// midnight, count as one. Branches open throughout the lookahead have no
+// closing time.
+func (s *Schedule) ClosesAt(t time.Time) (time.Time, bool) {
+	current, ok := s.intervalAt(t)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	end := current.End
+	for i := 0; i < lookahead; i++ {
+		next, ok := s.intervalAt(end)
+		if !ok {
+			return end, true
+		}
+		end = next.End
+	}
+	return time.Time{}, false
+}
+
+// NextOpening returns the first opening after t within the lookahead
+func (s *Schedule) NextOpening(t time.Time) (time.Time, bool) {
+	local := t.In(s.Location)
+	for i := 0; i <= lookahead; i++ {
+		for _, interval := range s.Intervals(local.AddDate(0, 0, i)) {
+			if interval.Start.After(t) {
+				return interval.Start, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// intervalAt returns the interval containing t, looking at shifts of t's
+// local date and those of the day before that run past midnight
+func (s *Schedule) intervalAt(t time.Time) (Interval, bool) {
+	local := t.In(s.Location)
+	for _, day := range []time.Time{local, local.AddDate(0, 0, -1)} {
+		for _, interval := range s.Intervals(day) {
+			if !t.Before(interval.Start) && t.Before(interval.End) {
+				return interval, true
+			}
+		}
+	}
+	return Interval{}, false
+}
+
+// clockOn returns the time of day "15:04" on a local date
+func clockOn(day time.Time, clock string) time.Time {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return day
+	}
+	y, m, d := day.Date()
+	return time.Date(y, m, d, t.Hour(), t.Minute(), 0, 0, day.Location())
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Restaurant statuses
+const (
+	RestaurantStatusActive   = "active"
+	RestaurantStatusInactive = "inactive"
+	RestaurantStatusArchived = "archived"
+)
+
+type Restaurant struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Name        string         `json:"name" gorm:"not null"`
+	Slug        string         `json:"slug" gorm:"uniqueIndex;not null"`
+	Description string         `json:"description" gorm:"type:text"`
+	Email       string         `json:"email"`
+	Phone       string         `json:"phone"`
+	Website     string         `json:"website"`
+	Currency    string         `json:"currency" gorm:"type:char(3);not null;default:USD"`
+	Status      string         `json:"status" gorm:"type:varchar(20);not null;default:active;index"`
+	Branches    []Branch       `json:"branches,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type CreateRestaurantRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Slug        string `json:"slug" binding:"required,max=100"`
+	Description string `json:"description"`
+	Email       string `json:"email" binding:"omitempty,email"`
+	Phone       string `json:"phone"`
+	Website     string `json:"website" binding:"omitempty,url"`
+	Currency    string `json:"currency" binding:"required,iso4217"`
+	Status      string `json:"status" binding:"omitempty,oneof=active inactive archived"`
+}
+
+type UpdateRestaurantRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Slug        string `json:"slug" binding:"required,max=100"`
+	Description string `json:"description"`
+	Email       string `json:"email" binding:"omitempty,email"`
+	Phone       string `json:"phone"`
+	Website     string `json:"website" binding:"omitempty,url"`
+	Currency    string `json:"currency" binding:"required,iso4217"`
+	Status      string `json:"status" binding:"required,oneof=active inactive archived"`
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+)
+
+type BranchRepository interface {
+	Create(branch *model.Branch) error
+	GetByID(id uint) (*model.Branch, error)
+	Update(branch *model.Branch) error
+	Delete(id uint) error
+	Find(q *database.Query) ([]model.Branch, error)
+	FindByID(id uint, q *database.Query) (*model.Branch, error)
+	WithTx(tx *gorm.DB) BranchRepository
+}
+
+type branchRepository struct {
+	database.Repository[model.Branch]
+}
+
+func NewBranchRepository(db *gorm.DB) BranchRepository {
+	return &branchRepository{Repository: database.NewRepository[model.Branch](db)}
+}
+
+func (r *branchRepository) WithTx(tx *gorm.DB) BranchRepository {
+	return &branchRepository{Repository: r.Repository.WithTx(tx)}
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+)
+
+type HoursRepository interface {
+	GetWeekly(branchID uint) ([]model.OpeningHours, error)
+	// ReplaceWeekly deletes the weekly shifts of a branch and inserts hours
+	// in their place
+	ReplaceWeekly(branchID uint, hours []model.OpeningHours) error
+	WithTx(tx *gorm.DB) HoursRepository
+}
+
+type hoursRepository struct {
+	database.Repository[model.OpeningHours]
+}
+
+func NewHoursRepository(db *gorm.DB) HoursRepository {
+	return &hoursRepository{Repository: database.NewRepository[model.OpeningHours](db)}
+}
+
+func (r *hoursRepository) GetWeekly(branchID uint) ([]model.OpeningHours, error) {
+	return r.Find(database.NewQuery().Eq("branch_id", branchID).OrderBy("weekday").OrderBy("opens"))
+}
+
+func (r *hoursRepository) ReplaceWeekly(branchID uint, hours []model.OpeningHours) error {
+	if err := r.DB().Where("branch_id = ?", branchID).Delete(&model.OpeningHours{}).Error; err != nil {
+		return database.TranslateError(err)
+	}
+	if len(hours) == 0 {
+		return nil
+	}
+	for i := range hours {
+		hours[i].BranchID = branchID
+	}
+	return database.TranslateError(r.DB().Create(&hours).Error)
+}
+
+func (r *hoursRepository) WithTx(tx *gorm.DB) HoursRepository {
+	return &hoursRepository{Repository: r.Repository.WithTx(tx)}
+}
+
+type HoursExceptionRepository interface {
+	GetByID(id uint) (*model.HoursException, error)
+	ForceDelete(id uint) error
+	Find(q *database.Query) ([]model.HoursException, error)
+	Upsert(exception *model.HoursException, conflictColumns []string, updateColumns ...string) error
+	GetByDate(branchID uint, date string) (*model.HoursException, error)
+}
+
+type hoursExceptionRepository struct {
+	database.Repository[model.HoursException]
+}
+
+func NewHoursExceptionRepository(db *gorm.DB) HoursExceptionRepository {
+	return &hoursExceptionRepository{Repository: database.NewRepository[model.HoursException](db)}
+}
+
+func (r *hoursExceptionRepository) GetByDate(branchID uint, date string) (*model.HoursException, error) {
+	return r.First(database.NewQuery().Eq("branch_id", branchID).Eq("date", date))
+}
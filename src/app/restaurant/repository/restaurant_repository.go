@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+)
+
+type RestaurantRepository interface {
+	Create(restaurant *model.Restaurant) error
+	GetByID(id uint) (*model.Restaurant, error)
+	Update(restaurant *model.Restaurant) error
+	Delete(id uint) error
+	FindPage(q *database.Query) ([]model.Restaurant, int64, error)
+	FindByID(id uint, q *database.Query) (*model.Restaurant, error)
+	WithTx(tx *gorm.DB) RestaurantRepository
+}
+
+type restaurantRepository struct {
+	database.Repository[model.Restaurant]
+}
+
+func NewRestaurantRepository(db *gorm.DB) RestaurantRepository {
+	return &restaurantRepository{Repository: database.NewRepository[model.Restaurant](db)}
+}
+
+func (r *restaurantRepository) WithTx(tx *gorm.DB) RestaurantRepository {
+	return &restaurantRepository{Repository: r.Repository.WithTx(tx)}
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+)
+
+type StaffRepository interface {
+	Upsert(staff *model.BranchStaff, conflictColumns []string, updateColumns ...string) error
+	Remove(branchID, userID uint) (bool, error)
+	GetMembers(branchID uint) ([]model.StaffMember, error)
+	GetBranchIDs(userID uint) ([]uint, error)
+	IsStaff(branchID, userID uint) (bool, error)
+}
+
+type staffRepository struct {
+	database.Repository[model.BranchStaff]
+}
+
+func NewStaffRepository(db *gorm.DB) StaffRepository {
+	return &staffRepository{Repository: database.NewRepository[model.BranchStaff](db)}
+}
+
+func (r *staffRepository) Remove(branchID, userID uint) (bool, error) {
+	result := r.DB().Where("branch_id = ? AND user_id = ?", branchID, userID).Delete(&model.BranchStaff{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *staffRepository) GetMembers(branchID uint) ([]model.StaffMember, error) {
+	var members []model.StaffMember
+	err := r.DB().Table("branch_staff").
+		Select("users.id AS user_id, users.name, users.email, users.role, branch_staff.position").
+		Joins("JOIN users ON users.id = branch_staff.user_id AND users.deleted_at IS NULL").
+		Where("branch_staff.branch_id = ?", branchID).
+		Order("users.name").
+		Scan(&members).Error
+	return members, err
+}
+
+func (r *staffRepository) GetBranchIDs(userID uint) ([]uint, error) {
+	var ids []uint
+	err := r.DB().Model(&model.BranchStaff{}).Where("user_id = ?", userID).Order("branch_id").Pluck("branch_id", &ids).Error
+	return ids, err
+}
+
+func (r *staffRepository) IsStaff(branchID, userID uint) (bool, error) {
+	return r.Exists(database.NewQuery().Eq("branch_id", branchID).Eq("user_id", userID))
+}
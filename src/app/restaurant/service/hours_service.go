@@ -0,0 +1,232 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	"github.com/faisd405/go-restapi-gin/src/app/restaurant/repository"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrShiftsOnClosedDay = errors.New("closed days cannot have shifts")
+	ErrShiftsRequired    = errors.New("exceptions that are not closed need at least one shift")
+)
+
+// Reasons a branch is closed, reported by GetOpenStatus
+const (
+	ClosedReasonBranchStatus = "branch_status"
+	ClosedReasonException    = "exception"
+	ClosedReasonHours        = "outside_opening_hours"
+)
+
+// HoursService manages the opening hours of branches. All times are
+// evaluated in the branch's own time zone.
+type HoursService interface {
+	GetHours(branchID uint) (*model.BranchHours, error)
+	SetWeeklyHours(actor utils.Actor, branchID uint, req model.WeeklyHoursRequest) (*model.BranchHours, error)
+	SaveException(actor utils.Actor, branchID uint, req model.HoursExceptionRequest) (*model.HoursException, error)
+	DeleteException(actor utils.Actor, id uint) error
+
+	// GetOpenStatus tells whether a branch is open at t
+	GetOpenStatus(branchID uint, t time.Time) (*model.OpenStatus, error)
+	// GetSchedule returns the opening hours of a branch for callers that
+	// check many times at once
+	GetSchedule(branchID uint) (*model.Schedule, error)
+}
+
+type hoursService struct {
+	hoursRepo     repository.HoursRepository
+	exceptionRepo repository.HoursExceptionRepository
+	restaurantSvc RestaurantService
+	txManager     database.TxManager
+}
+
+func NewHoursService(
+	hoursRepo repository.HoursRepository,
+	exceptionRepo repository.HoursExceptionRepository,
+	restaurantSvc RestaurantService,
+	txManager database.TxManager,
+) HoursService {
+	return &hoursService{
+		hoursRepo:     hoursRepo,
+		exceptionRepo: exceptionRepo,
+		restaurantSvc: restaurantSvc,
+		txManager:     txManager,
+	}
+}
+
+// GetHours returns the weekly hours and the exceptions from today on of a
+// public branch
+func (s *hoursService) GetHours(branchID uint) (*model.BranchHours, error) {
+	branch, err := s.restaurantSvc.GetBranch(branchID, true)
+	if err != nil {
+		return nil, err
+	}
+	return s.hours(branch)
+}
+
+func (s *hoursService) SetWeeklyHours(actor utils.Actor, branchID uint, req model.WeeklyHoursRequest) (*model.BranchHours, error) {
+	branch, err := s.restaurantSvc.GetBranch(branchID, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	hours := make([]model.OpeningHours, len(req.Shifts))
+	for i, shift := range req.Shifts {
+		hours[i] = model.OpeningHours{Weekday: shift.Weekday, Opens: shift.Opens, Closes: shift.Closes}
+	}
+
+	err = s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		return s.hoursRepo.WithTx(tx).ReplaceWeekly(branchID, hours)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.hours(branch)
+}
+
+// SaveException creates the exception of a date or replaces the existing
+// one
+func (s *hoursService) SaveException(actor utils.Actor, branchID uint, req model.HoursExceptionRequest) (*model.HoursException, error) {
+	if req.Closed && len(req.Shifts) > 0 {
+		return nil, ErrShiftsOnClosedDay
+	}
+	if !req.Closed && len(req.Shifts) == 0 {
+		return nil, ErrShiftsRequired
+	}
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	exception := &model.HoursException{
+		BranchID: branchID,
+		Date:     req.Date,
+		Name:     req.Name,
+		Closed:   req.Closed,
+		Shifts:   req.Shifts,
+	}
+	err := s.exceptionRepo.Upsert(exception, []string{"branch_id", "date"}, "name", "closed", "shifts", "updated_at")
+	if err != nil {
+		return nil, err
+	}
+
+	return s.exceptionRepo.GetByDate(branchID, req.Date)
+}
+
+func (s *hoursService) DeleteException(actor utils.Actor, id uint) error {
+	exception, err := s.exceptionRepo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, exception.BranchID); err != nil {
+		return err
+	}
+	return s.exceptionRepo.ForceDelete(exception.ID)
+}
+
+func (s *hoursService) GetOpenStatus(branchID uint, t time.Time) (*model.OpenStatus, error) {
+	branch, err := s.restaurantSvc.GetBranch(branchID, true)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := s.schedule(branch)
+	if err != nil {
+		return nil, err
+	}
+
+	local := t.In(schedule.Location)
+	status := &model.OpenStatus{
+		BranchID:  branch.ID,
+		Timezone:  schedule.Location.String(),
+		LocalTime: local.Format(time.RFC3339),
+	}
+
+	if branch.Status != model.BranchStatusActive {
+		status.Reason = ClosedReasonBranchStatus
+		return status, nil
+	}
+
+	if closesAt, ok := schedule.ClosesAt(t); ok {
+		status.IsOpen = true
+		closesAt = closesAt.In(schedule.Location)
+		status.ClosesAt = &closesAt
+		return status, nil
+	}
+	if schedule.IsOpenAt(t) {
+		// Open around the clock
+		status.IsOpen = true
+		return status, nil
+	}
+
+	status.Reason = ClosedReasonHours
+	if _, ok := schedule.Exceptions[local.Format("2006-01-02")]; ok {
+		status.Reason = ClosedReasonException
+	}
+	if opensAt, ok := schedule.NextOpening(t); ok {
+		opensAt = opensAt.In(schedule.Location)
+		status.OpensAt = &opensAt
+	}
+	return status, nil
+}
+
+func (s *hoursService) GetSchedule(branchID uint) (*model.Schedule, error) {
+	branch, err := s.restaurantSvc.GetBranch(branchID, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.schedule(branch)
+}
+
+// schedule loads the weekly hours and the exceptions from yesterday on,
+// which covers shifts still running past midnight
+func (s *hoursService) schedule(branch *model.Branch) (*model.Schedule, error) {
+	weekly, err := s.hoursRepo.GetWeekly(branch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := branch.Location()
+	since := time.Now().In(loc).AddDate(0, 0, -1).Format("2006-01-02")
+	exceptions, err := s.exceptionRepo.Find(database.NewQuery().
+		Eq("branch_id", branch.ID).
+		Where("date", database.OpGte, since))
+	if err != nil {
+		return nil, err
+	}
+
+	return model.NewSchedule(loc, weekly, exceptions), nil
+}
+
+// hours returns the weekly hours and the exceptions from today on
+func (s *hoursService) hours(branch *model.Branch) (*model.BranchHours, error) {
+	weekly, err := s.hoursRepo.GetWeekly(branch.ID)
+	if err != nil {
+		return nil, err
+	}
+	today := time.Now().In(branch.Location()).Format("2006-01-02")
+	exceptions, err := s.exceptionRepo.Find(database.NewQuery().
+		Eq("branch_id", branch.ID).
+		Where("date", database.OpGte, today).
+		OrderBy("date"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.BranchHours{
+		BranchID:   branch.ID,
+		Timezone:   branch.Location().String(),
+		Weekly:     weekly,
+		Exceptions: exceptions,
+	}, nil
+}
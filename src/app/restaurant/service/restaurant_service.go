@@ -0,0 +1,333 @@
+package service
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	"github.com/faisd405/go-restapi-gin/src/app/restaurant/repository"
+	usermodel "github.com/faisd405/go-restapi-gin/src/app/user/model"
+	userrepository "github.com/faisd405/go-restapi-gin/src/app/user/repository"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidSlug       = errors.New("slug may only contain lowercase letters, digits and single dashes")
+	ErrUserInactive      = errors.New("user account is deactivated")
+	ErrStaffRoleRequired = errors.New("user must have the staff, manager or admin role")
+	ErrStaffNotAssigned  = errors.New("user is not assigned to this branch")
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type RestaurantService interface {
+	CreateRestaurant(req model.CreateRestaurantRequest) (*model.Restaurant, error)
+	UpdateRestaurant(id uint, req model.UpdateRestaurantRequest) (*model.Restaurant, error)
+	DeleteRestaurant(id uint) error
+	GetRestaurant(id uint, publicOnly bool) (*model.Restaurant, error)
+	GetAllRestaurants(page, limit int, publicOnly bool) ([]model.Restaurant, int64, error)
+
+	CreateBranch(restaurantID uint, req model.BranchRequest) (*model.Branch, error)
+	UpdateBranch(id uint, req model.BranchRequest) (*model.Branch, error)
+	DeleteBranch(id uint) error
+	GetBranch(id uint, publicOnly bool) (*model.Branch, error)
+	GetBranches(restaurantID uint, publicOnly bool) ([]model.Branch, error)
+
+	AssignStaff(branchID uint, req model.AssignStaffRequest) (*model.StaffMember, error)
+	RemoveStaff(branchID, userID uint) error
+	GetStaff(branchID uint) ([]model.StaffMember, error)
+	GetStaffBranches(userID uint) ([]model.Branch, error)
+	IsBranchStaff(branchID, userID uint) (bool, error)
+}
+
+type restaurantService struct {
+	restaurantRepo repository.RestaurantRepository
+	branchRepo     repository.BranchRepository
+	staffRepo      repository.StaffRepository
+	userRepo       userrepository.UserRepository
+	txManager      database.TxManager
+}
+
+func NewRestaurantService(
+	restaurantRepo repository.RestaurantRepository,
+	branchRepo repository.BranchRepository,
+	staffRepo repository.StaffRepository,
+	userRepo userrepository.UserRepository,
+	txManager database.TxManager,
+) RestaurantService {
+	return &restaurantService{
+		restaurantRepo: restaurantRepo,
+		branchRepo:     branchRepo,
+		staffRepo:      staffRepo,
+		userRepo:       userRepo,
+		txManager:      txManager,
+	}
+}
+
+func (s *restaurantService) CreateRestaurant(req model.CreateRestaurantRequest) (*model.Restaurant, error) {
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !slugPattern.MatchString(slug) {
+		return nil, ErrInvalidSlug
+	}
+
+	status := req.Status
+	if status == "" {
+		status = model.RestaurantStatusActive
+	}
+
+	restaurant := &model.Restaurant{
+		Name:        req.Name,
+		Slug:        slug,
+		Description: req.Description,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		Website:     req.Website,
+		Currency:    strings.ToUpper(req.Currency),
+		Status:      status,
+	}
+
+	if err := s.restaurantRepo.Create(restaurant); err != nil {
+		return nil, err
+	}
+
+	return restaurant, nil
+}
+
+func (s *restaurantService) UpdateRestaurant(id uint, req model.UpdateRestaurantRequest) (*model.Restaurant, error) {
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !slugPattern.MatchString(slug) {
+		return nil, ErrInvalidSlug
+	}
+
+	restaurant, err := s.restaurantRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	restaurant.Name = req.Name
+	restaurant.Slug = slug
+	restaurant.Description = req.Description
+	restaurant.Email = req.Email
+	restaurant.Phone = req.Phone
+	restaurant.Website = req.Website
+	restaurant.Currency = strings.ToUpper(req.Currency)
+	restaurant.Status = req.Status
+
+	if err := s.restaurantRepo.Update(restaurant); err != nil {
+		return nil, err
+	}
+
+	return restaurant, nil
+}
+
+func (s *restaurantService) DeleteRestaurant(id uint) error {
+	if _, err := s.restaurantRepo.GetByID(id); err != nil {
+		return err
+	}
+
+	// Branches go together with their restaurant
+	return s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		branchRepo := s.branchRepo.WithTx(tx)
+
+		branches, err := branchRepo.Find(database.NewQuery().Eq("restaurant_id", id))
+		if err != nil {
+			return err
+		}
+		for _, branch := range branches {
+			if err := branchRepo.Delete(branch.ID); err != nil {
+				return err
+			}
+		}
+
+		return s.restaurantRepo.WithTx(tx).Delete(id)
+	})
+}
+
+func (s *restaurantService) GetRestaurant(id uint, publicOnly bool) (*model.Restaurant, error) {
+	q := database.NewQuery()
+	if publicOnly {
+		q.Eq("status", model.RestaurantStatusActive)
+	}
+
+	restaurant, err := s.restaurantRepo.FindByID(id, q)
+	if err != nil {
+		return nil, err
+	}
+
+	branches, err := s.GetBranches(id, publicOnly)
+	if err != nil {
+		return nil, err
+	}
+	restaurant.Branches = branches
+
+	return restaurant, nil
+}
+
+func (s *restaurantService) GetAllRestaurants(page, limit int, publicOnly bool) ([]model.Restaurant, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	q := database.NewQuery().OrderBy("name").Paginate((page-1)*limit, limit)
+	if publicOnly {
+		q.Eq("status", model.RestaurantStatusActive)
+	}
+
+	return s.restaurantRepo.FindPage(q)
+}
+
+func (s *restaurantService) CreateBranch(restaurantID uint, req model.BranchRequest) (*model.Branch, error) {
+	if _, err := s.restaurantRepo.GetByID(restaurantID); err != nil {
+		return nil, err
+	}
+
+	branch := &model.Branch{RestaurantID: restaurantID}
+	applyBranchRequest(branch, req)
+
+	if err := s.branchRepo.Create(branch); err != nil {
+		return nil, err
+	}
+
+	return branch, nil
+}
+
+func (s *restaurantService) UpdateBranch(id uint, req model.BranchRequest) (*model.Branch, error) {
+	branch, err := s.branchRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyBranchRequest(branch, req)
+
+	if err := s.branchRepo.Update(branch); err != nil {
+		return nil, err
+	}
+
+	return branch, nil
+}
+
+func (s *restaurantService) DeleteBranch(id uint) error {
+	if _, err := s.branchRepo.GetByID(id); err != nil {
+		return err
+	}
+
+	return s.branchRepo.Delete(id)
+}
+
+func (s *restaurantService) GetBranch(id uint, publicOnly bool) (*model.Branch, error) {
+	branch, err := s.branchRepo.FindByID(id, database.NewQuery().Preload("Restaurant"))
+	if err != nil {
+		return nil, err
+	}
+
+	if publicOnly && (branch.Status == model.BranchStatusInactive || branch.Restaurant == nil || branch.Restaurant.Status != model.RestaurantStatusActive) {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return branch, nil
+}
+
+func (s *restaurantService) GetBranches(restaurantID uint, publicOnly bool) ([]model.Branch, error) {
+	q := database.NewQuery().Eq("restaurant_id", restaurantID).OrderBy("name")
+	if publicOnly {
+		q.Where("status", database.OpNotEq, model.BranchStatusInactive)
+	}
+
+	return s.branchRepo.Find(q)
+}
+
+func (s *restaurantService) AssignStaff(branchID uint, req model.AssignStaffRequest) (*model.StaffMember, error) {
+	if _, err := s.branchRepo.GetByID(branchID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+	switch user.Role {
+	case usermodel.RoleStaff, usermodel.RoleManager, usermodel.RoleAdmin:
+	default:
+		return nil, ErrStaffRoleRequired
+	}
+
+	staff := &model.BranchStaff{
+		BranchID: branchID,
+		UserID:   user.ID,
+		Position: req.Position,
+	}
+	if err := s.staffRepo.Upsert(staff, []string{"branch_id", "user_id"}, "position", "updated_at"); err != nil {
+		return nil, err
+	}
+
+	return &model.StaffMember{
+		UserID:   user.ID,
+		Name:     user.Name,
+		Email:    user.Email,
+		Role:     user.Role,
+		Position: staff.Position,
+	}, nil
+}
+
+func (s *restaurantService) RemoveStaff(branchID, userID uint) error {
+	removed, err := s.staffRepo.Remove(branchID, userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrStaffNotAssigned
+	}
+	return nil
+}
+
+func (s *restaurantService) GetStaff(branchID uint) ([]model.StaffMember, error) {
+	if _, err := s.branchRepo.GetByID(branchID); err != nil {
+		return nil, err
+	}
+
+	return s.staffRepo.GetMembers(branchID)
+}
+
+func (s *restaurantService) GetStaffBranches(userID uint) ([]model.Branch, error) {
+	ids, err := s.staffRepo.GetBranchIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []model.Branch{}, nil
+	}
+
+	return s.branchRepo.Find(database.NewQuery().In("id", ids).Preload("Restaurant").OrderBy("name"))
+}
+
+func (s *restaurantService) IsBranchStaff(branchID, userID uint) (bool, error) {
+	return s.staffRepo.IsStaff(branchID, userID)
+}
+
+func applyBranchRequest(branch *model.Branch, req model.BranchRequest) {
+	branch.Name = req.Name
+	branch.AddressLine1 = req.AddressLine1
+	branch.AddressLine2 = req.AddressLine2
+	branch.City = req.City
+	branch.State = req.State
+	branch.PostalCode = req.PostalCode
+	branch.Country = strings.ToUpper(req.Country)
+	branch.Latitude = req.Latitude
+	branch.Longitude = req.Longitude
+	branch.Timezone = req.Timezone
+	branch.Phone = req.Phone
+	branch.Email = req.Email
+	branch.Currency = strings.ToUpper(req.Currency)
+	branch.Status = req.Status
+	if branch.Status == "" {
+		branch.Status = model.BranchStatusActive
+	}
+}
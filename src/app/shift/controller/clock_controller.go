@@ -0,0 +1,250 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/faisd405/go-restapi-gin/src/app/shift/model"
+	"github.com/faisd405/go-restapi-gin/src/app/shift/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type ClockController struct {
+	clockService service.ClockService
+}
+
+func NewClockController(clockService service.ClockService) *ClockController {
+	return &ClockController{clockService: clockService}
+}
+
+// ClockIn godoc
+// @Summary Clock in
+// @Description Start a time entry at a branch, linked to the shift that is about to start or running. Staff clock themselves in; clocking in another staff member by user_id, and any clocking in on a station, takes their PIN. Branches can require the PIN always.
+// @Tags shifts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param clock body model.ClockRequest false "Staff member, PIN and note"
+// @Success 201 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 429 {object} utils.Response
+// @Router /branches/{id}/clock-in [post]
+func (ctrl *ClockController) ClockIn(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.ClockRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+
+	entry, err := ctrl.clockService.ClockIn(actor, id, req)
+	if err != nil {
+		shiftErrorResponse(c, "Clock-in failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Clocked in successfully", entry)
+}
+
+// ClockOut godoc
+// @Summary Clock out
+// @Description Close the open time entry of a staff member at a branch. A break is deducted from the worked time by the branch rules. Takes the same staff member and PIN as clocking in.
+// @Tags shifts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param clock body model.ClockRequest false "Staff member, PIN and note"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /branches/{id}/clock-out [post]
+func (ctrl *ClockController) ClockOut(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.ClockRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ValidationErrorResponse(c, err)
+			return
+		}
+	}
+
+	entry, err := ctrl.clockService.ClockOut(actor, id, req)
+	if err != nil {
+		shiftErrorResponse(c, "Clock-out failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Clocked out successfully", entry)
+}
+
+// GetTimeEntries godoc
+// @Summary Get time entries (Admin/Manager)
+// @Description Get the time entries of a branch, latest first. Dates filter on the local day of clocking in.
+// @Tags shifts
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Param user_id query int false "Staff member"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /branches/{id}/time-entries [get]
+func (ctrl *ClockController) GetTimeEntries(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	filter := model.TimeEntryFilter{From: c.Query("from"), To: c.Query("to")}
+	if value := c.Query("user_id"); value != "" {
+		userID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", err.Error())
+			return
+		}
+		filter.UserID = uint(userID)
+	}
+
+	page, limit := utils.GetPagination(c)
+
+	entries, total, err := ctrl.clockService.GetTimeEntries(actor, id, page, limit, filter)
+	if err != nil {
+		shiftErrorResponse(c, "Failed to retrieve time entries", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Time entries retrieved successfully",
+		utils.PaginatedData("time_entries", entries, page, limit, total))
+}
+
+// UpdateTimeEntry godoc
+// @Summary Correct time entry (Admin/Manager)
+// @Description Correct the clock-in and clock-out times of an entry, e.g. a forgotten clock-out. The break and worked time are recomputed; a null clock_out_at reopens the entry.
+// @Tags shifts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Time entry ID"
+// @Param entry body model.TimeEntryRequest true "Time entry"
+// @Success 200 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /time-entries/{id} [put]
+func (ctrl *ClockController) UpdateTimeEntry(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid time entry ID", err.Error())
+		return
+	}
+
+	var req model.TimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	entry, err := ctrl.clockService.UpdateTimeEntry(actor, id, req)
+	if err != nil {
+		shiftErrorResponse(c, "Time entry update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Time entry updated successfully", entry)
+}
+
+// GetMyTimeEntries godoc
+// @Summary Get my time entries
+// @Description List the time entries of the authenticated staff member, latest first
+// @Tags shifts
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} utils.Response
+// @Router /users/time-entries [get]
+func (ctrl *ClockController) GetMyTimeEntries(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	page, limit := utils.GetPagination(c)
+	entries, total, err := ctrl.clockService.GetMyTimeEntries(actor, page, limit)
+	if err != nil {
+		shiftErrorResponse(c, "Failed to retrieve time entries", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Time entries retrieved successfully",
+		utils.PaginatedData("time_entries", entries, page, limit, total))
+}
+
+// SetPIN godoc
+// @Summary Set clock PIN
+// @Description Set the 4 to 8 digit PIN the authenticated staff member clocks in with on a shared device. Setting it lifts a lock after too many wrong PINs.
+// @Tags shifts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param pin body model.PINRequest true "PIN"
+// @Success 200 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /users/clock-pin [put]
+func (ctrl *ClockController) SetPIN(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	var req model.PINRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	if err := ctrl.clockService.SetPIN(actor, req); err != nil {
+		shiftErrorResponse(c, "Failed to set clock PIN", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Clock PIN set successfully", nil)
+}
@@ -0,0 +1,390 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/app/shift/model"
+	"github.com/faisd405/go-restapi-gin/src/app/shift/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ErrCodeShiftOverlap     = "SHIFT_OVERLAP"
+	ErrCodeAlreadyClockedIn = "ALREADY_CLOCKED_IN"
+	ErrCodeNotClockedIn     = "NOT_CLOCKED_IN"
+	ErrCodePINRequired      = "PIN_REQUIRED"
+	ErrCodePINNotSet        = "PIN_NOT_SET"
+	ErrCodeInvalidPIN       = "INVALID_PIN"
+	ErrCodePINLocked        = "PIN_LOCKED"
+)
+
+type ShiftController struct {
+	shiftService     service.ShiftService
+	timesheetService service.TimesheetService
+}
+
+func NewShiftController(shiftService service.ShiftService, timesheetService service.TimesheetService) *ShiftController {
+	return &ShiftController{shiftService: shiftService, timesheetService: timesheetService}
+}
+
+// GetSettings godoc
+// @Summary Get labour settings (Admin/Manager)
+// @Description Get the pay period, overtime, break and clock-in rules of a branch
+// @Tags shifts
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/labor-settings [get]
+func (ctrl *ShiftController) GetSettings(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	settings, err := ctrl.shiftService.GetSettings(actor, id)
+	if err != nil {
+		shiftErrorResponse(c, "Failed to retrieve labour settings", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Labour settings retrieved successfully", settings)
+}
+
+// UpdateSettings godoc
+// @Summary Update labour settings (Admin/Manager)
+// @Description Set the pay period, overtime, break and clock-in rules of a branch. An overtime limit of 0 turns that overtime off.
+// @Tags shifts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param settings body model.SettingsRequest true "Labour settings"
+// @Success 200 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /branches/{id}/labor-settings [put]
+func (ctrl *ShiftController) UpdateSettings(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.SettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	settings, err := ctrl.shiftService.UpdateSettings(actor, id, req)
+	if err != nil {
+		shiftErrorResponse(c, "Labour settings update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Labour settings updated successfully", settings)
+}
+
+// GetStaff godoc
+// @Summary Get branch staff (Admin/Manager)
+// @Description List the staff assigned to a branch the manager works at, to schedule them
+// @Tags shifts
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/staff [get]
+func (ctrl *ShiftController) GetStaff(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	staff, err := ctrl.shiftService.GetStaff(actor, id)
+	if err != nil {
+		shiftErrorResponse(c, "Failed to retrieve staff", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Staff retrieved successfully", staff)
+}
+
+// GetShifts godoc
+// @Summary Get shifts (Admin/Manager)
+// @Description Get the shifts of a branch starting between two local dates
+// @Tags shifts
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param from query string false "First day (YYYY-MM-DD), default today"
+// @Param to query string false "Last day (YYYY-MM-DD), default six days after from"
+// @Param user_id query int false "Staff member"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /branches/{id}/shifts [get]
+func (ctrl *ShiftController) GetShifts(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	filter := model.ShiftFilter{From: c.Query("from"), To: c.Query("to")}
+	if value := c.Query("user_id"); value != "" {
+		userID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", err.Error())
+			return
+		}
+		filter.UserID = uint(userID)
+	}
+
+	shifts, err := ctrl.shiftService.GetShifts(actor, id, filter)
+	if err != nil {
+		shiftErrorResponse(c, "Failed to retrieve shifts", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shifts retrieved successfully", shifts)
+}
+
+// CreateShift godoc
+// @Summary Create shift (Admin/Manager)
+// @Description Schedule a staff member of the branch. A staff member cannot have two shifts at once, also not at different branches.
+// @Tags shifts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param shift body model.ShiftRequest true "Shift"
+// @Success 201 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Failure 422 {object} utils.Response
+// @Router /branches/{id}/shifts [post]
+func (ctrl *ShiftController) CreateShift(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.ShiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	shift, err := ctrl.shiftService.CreateShift(actor, id, req)
+	if err != nil {
+		shiftErrorResponse(c, "Shift creation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Shift created successfully", shift)
+}
+
+// UpdateShift godoc
+// @Summary Update shift (Admin/Manager)
+// @Description Reschedule a shift or give it to another staff member of the branch
+// @Tags shifts
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Shift ID"
+// @Param shift body model.ShiftRequest true "Shift"
+// @Success 200 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /shifts/{id} [put]
+func (ctrl *ShiftController) UpdateShift(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shift ID", err.Error())
+		return
+	}
+
+	var req model.ShiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	shift, err := ctrl.shiftService.UpdateShift(actor, id, req)
+	if err != nil {
+		shiftErrorResponse(c, "Shift update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shift updated successfully", shift)
+}
+
+// DeleteShift godoc
+// @Summary Delete shift (Admin/Manager)
+// @Description Remove a shift from the schedule
+// @Tags shifts
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Shift ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /shifts/{id} [delete]
+func (ctrl *ShiftController) DeleteShift(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid shift ID", err.Error())
+		return
+	}
+
+	if err := ctrl.shiftService.DeleteShift(actor, id); err != nil {
+		shiftErrorResponse(c, "Shift deletion failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shift deleted successfully", nil)
+}
+
+// GetMyShifts godoc
+// @Summary Get my shifts
+// @Description List the shifts of the authenticated staff member that have not ended yet, soonest first
+// @Tags shifts
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} utils.Response
+// @Router /users/shifts [get]
+func (ctrl *ShiftController) GetMyShifts(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	page, limit := utils.GetPagination(c)
+	shifts, total, err := ctrl.shiftService.GetMyShifts(actor, page, limit)
+	if err != nil {
+		shiftErrorResponse(c, "Failed to retrieve shifts", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Shifts retrieved successfully",
+		utils.PaginatedData("shifts", shifts, page, limit, total))
+}
+
+// GetTimesheet godoc
+// @Summary Get timesheet (Admin/Manager)
+// @Description Total the scheduled, worked, break, regular and overtime minutes of the staff of a branch per day in the pay period containing date. Time entries count towards the day they were clocked in on; open entries are left out. With format=csv the timesheet is downloaded for payroll, in hours.
+// @Tags shifts
+// @Produce json,text/csv
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param date query string false "A day of the pay period (YYYY-MM-DD), default today"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /branches/{id}/timesheets [get]
+func (ctrl *ShiftController) GetTimesheet(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	switch c.DefaultQuery("format", model.FormatJSON) {
+	case model.FormatJSON:
+		sheet, err := ctrl.timesheetService.GetTimesheet(actor, id, c.Query("date"))
+		if err != nil {
+			shiftErrorResponse(c, "Failed to retrieve timesheet", err)
+			return
+		}
+		utils.SuccessResponse(c, http.StatusOK, "Timesheet retrieved successfully", sheet)
+	case model.FormatCSV:
+		rendered, err := ctrl.timesheetService.ExportTimesheet(actor, id, c.Query("date"))
+		if err != nil {
+			shiftErrorResponse(c, "Failed to export timesheet", err)
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", rendered.Filename))
+		c.Data(http.StatusOK, rendered.ContentType, rendered.Body)
+	default:
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to retrieve timesheet", "format must be json or csv")
+	}
+}
+
+func shiftErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrShiftOverlap):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeShiftOverlap, message, err.Error())
+	case errors.Is(err, service.ErrAlreadyClockedIn):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeAlreadyClockedIn, message, err.Error())
+	case errors.Is(err, service.ErrNotClockedIn):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeNotClockedIn, message, err.Error())
+	case errors.Is(err, service.ErrPINRequired):
+		utils.ErrorResponseWithCode(c, http.StatusUnprocessableEntity, ErrCodePINRequired, message, err.Error())
+	case errors.Is(err, service.ErrPINNotSet):
+		utils.ErrorResponseWithCode(c, http.StatusUnprocessableEntity, ErrCodePINNotSet, message, err.Error())
+	case errors.Is(err, service.ErrInvalidPIN):
+		utils.ErrorResponseWithCode(c, http.StatusForbidden, ErrCodeInvalidPIN, message, err.Error())
+	case errors.Is(err, service.ErrPINLocked):
+		utils.ErrorResponseWithCode(c, http.StatusTooManyRequests, ErrCodePINLocked, message, err.Error())
+	case errors.Is(err, model.ErrInvalidShift),
+		errors.Is(err, model.ErrInvalidTimeEntry),
+		errors.Is(err, model.ErrInvalidPayPeriod),
+		errors.Is(err, service.ErrUserRequired),
+		errors.Is(err, restaurantservice.ErrStaffNotAssigned),
+		errors.Is(err, restaurantservice.ErrStaffRoleRequired):
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, message, err.Error())
+	case errors.Is(err, service.ErrInvalidDate),
+		errors.Is(err, service.ErrInvalidRange):
+		utils.ErrorResponse(c, http.StatusBadRequest, message, err.Error())
+	default:
+		utils.DatabaseErrorResponse(c, message, err)
+	}
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Pay periods
+const (
+	PayPeriodWeekly      = "weekly"
+	PayPeriodBiweekly    = "biweekly"
+	PayPeriodSemimonthly = "semimonthly"
+	PayPeriodMonthly     = "monthly"
+)
+
+const dateFormat = "2006-01-02"
+
+var ErrInvalidPayPeriod = errors.New("pay period must be weekly, biweekly, semimonthly or monthly")
+
+// IsValidPayPeriod reports whether period is a known pay period
+func IsValidPayPeriod(period string) bool {
+	switch period {
+	case PayPeriodWeekly, PayPeriodBiweekly, PayPeriodSemimonthly, PayPeriodMonthly:
+		return true
+	}
+	return false
+}
+
+// Settings are the labour rules of a branch. Weekly and biweekly pay
+// periods, and the weeks weekly overtime is counted in, start on
+// PeriodAnchor and every 7 or 14 days from it. Overtime is the work beyond
+// DailyOvertimeMinutes a day, then beyond WeeklyOvertimeMinutes of regular
+// work a week; a limit of 0 turns it off. A stretch of work of at least
+// BreakAfterMinutes has BreakMinutes of unpaid break deducted. Clocking in
+// links the shift that starts within ClockInWindowMinutes.
+type Settings struct {
+	BranchID              uint      `json:"branch_id" gorm:"primaryKey;autoIncrement:false"`
+	PayPeriod             string    `json:"pay_period" gorm:"type:varchar(20);not null;default:'weekly'"`
+	PeriodAnchor          string    `json:"period_anchor" gorm:"type:varchar(10);not null;default:'2024-01-01'"`
+	DailyOvertimeMinutes  int       `json:"daily_overtime_minutes" gorm:"not null;default:480"`
+	WeeklyOvertimeMinutes int       `json:"weekly_overtime_minutes" gorm:"not null;default:2400"`
+	BreakAfterMinutes     int       `json:"break_after_minutes" gorm:"not null;default:360"`
+	BreakMinutes          int       `json:"break_minutes" gorm:"not null;default:30"`
+	ClockInWindowMinutes  int       `json:"clock_in_window_minutes" gorm:"not null;default:30"`
+	RequirePIN            bool      `json:"require_pin" gorm:"not null;default:false"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+func (Settings) TableName() string {
+	return "labor_settings"
+}
+
+// DefaultSettings returns the rules of a branch that has not configured
+// them yet
+func DefaultSettings(branchID uint) *Settings {
+	return &Settings{
+		BranchID:              branchID,
+		PayPeriod:             PayPeriodWeekly,
+		PeriodAnchor:          "2024-01-01",
+		DailyOvertimeMinutes:  480,
+		WeeklyOvertimeMinutes: 2400,
+		BreakAfterMinutes:     360,
+		BreakMinutes:          30,
+		ClockInWindowMinutes:  30,
+	}
+}
+
+// BreakFor returns the break deducted from span minutes of work
+func (s *Settings) BreakFor(span int) int {
+	if s.BreakMinutes <= 0 || span < s.BreakAfterMinutes {
+		return 0
+	}
+	return min(s.BreakMinutes, span)
+}
+
+// Period returns the first day of the pay period containing day and the
+// day after its last one. day is a midnight in the branch time zone.
+func (s *Settings) Period(day time.Time) (time.Time, time.Time) {
+	switch s.PayPeriod {
+	case PayPeriodMonthly:
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+		return start, start.AddDate(0, 1, 0)
+	case PayPeriodSemimonthly:
+		if day.Day() <= 15 {
+			start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+			return start, start.AddDate(0, 0, 15)
+		}
+		start := time.Date(day.Year(), day.Month(), 16, 0, 0, 0, 0, day.Location())
+		return start, time.Date(day.Year(), day.Month()+1, 1, 0, 0, 0, 0, day.Location())
+	case PayPeriodBiweekly:
+		start := s.cycleStart(day, 14)
+		return start, start.AddDate(0, 0, 14)
+	default:
+		start := s.cycleStart(day, 7)
+		return start, start.AddDate(0, 0, 7)
+	}
+}
+
+// WeekStart returns the first day of the overtime week containing day
+func (s *Settings) WeekStart(day time.Time) time.Time {
+	return s.cycleStart(day, 7)
+}
+
+// cycleStart returns the latest day on or before day that lies a multiple
+// of length days from the anchor
+func (s *Settings) cycleStart(day time.Time, length int) time.Time {
+	anchor, err := time.ParseInLocation(dateFormat, s.PeriodAnchor, day.Location())
+	if err != nil {
+		anchor = time.Date(2024, 1, 1, 0, 0, 0, 0, day.Location())
+	}
+	// Count calendar days in UTC so daylight saving changes do not skew
+	// the count
+	from := time.Date(anchor.Year(), anchor.Month(), anchor.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(to.Sub(from).Hours() / 24)
+	offset := days % length
+	if offset < 0 {
+		offset += length
+	}
+	return time.Date(day.Year(), day.Month(), day.Day()-offset, 0, 0, 0, 0, day.Location())
+}
+
+type SettingsRequest struct {
+	PayPeriod             string `json:"pay_period" binding:"required"`
+	PeriodAnchor          string `json:"period_anchor" binding:"required,datetime=2006-01-02"`
+	DailyOvertimeMinutes  int    `json:"daily_overtime_minutes" binding:"min=0,max=1440"`
+	WeeklyOvertimeMinutes int    `json:"weekly_overtime_minutes" binding:"min=0,max=10080"`
+	BreakAfterMinutes     int    `json:"break_after_minutes" binding:"min=0,max=1440"`
+	BreakMinutes          int    `json:"break_minutes" binding:"min=0,max=240"`
+	ClockInWindowMinutes  int    `json:"clock_in_window_minutes" binding:"min=0,max=240"`
+	RequirePIN            *bool  `json:"require_pin"`
+}
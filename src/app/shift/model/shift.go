@@ -0,0 +1,125 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	usermodel "github.com/faisd405/go-restapi-gin/src/app/user/model"
+	"gorm.io/gorm"
+)
+
+// MaxShiftLength is the longest shift or time entry accepted
+const MaxShiftLength = 24 * time.Hour
+
+var (
+	ErrInvalidShift     = errors.New("a shift must end after it starts and last at most 24 hours")
+	ErrInvalidTimeEntry = errors.New("clock-out must be after clock-in and at most 24 hours later")
+)
+
+// Shift is a scheduled shift of a staff member at a branch
+type Shift struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	BranchID    uint            `json:"branch_id" gorm:"not null;index"`
+	UserID      uint            `json:"user_id" gorm:"not null;index"`
+	User        *usermodel.User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Position    string          `json:"position" gorm:"type:varchar(50)"`
+	StartsAt    time.Time       `json:"starts_at" gorm:"not null;index"`
+	EndsAt      time.Time       `json:"ends_at" gorm:"not null"`
+	Note        string          `json:"note" gorm:"type:text"`
+	CreatedByID *uint           `json:"created_by_id"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt  `json:"-" gorm:"index"`
+}
+
+// Minutes is the scheduled length of the shift
+func (s *Shift) Minutes() int {
+	return int(s.EndsAt.Sub(s.StartsAt) / time.Minute)
+}
+
+type ShiftRequest struct {
+	UserID   uint      `json:"user_id" binding:"required"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+	Position string    `json:"position" binding:"max=50"`
+	Note     string    `json:"note" binding:"max=500"`
+}
+
+// ShiftFilter selects shifts by the local dates they start on, formatted
+// as YYYY-MM-DD, and by staff member
+type ShiftFilter struct {
+	From   string
+	To     string
+	UserID uint
+}
+
+// TimeEntry is a stretch of work between clocking in and out. Entries are
+// linked to the shift they were clocked in for, when there was one.
+// BreakMinutes and WorkedMinutes are set at clock-out by the break rules of
+// the branch.
+type TimeEntry struct {
+	ID            uint            `json:"id" gorm:"primaryKey"`
+	BranchID      uint            `json:"branch_id" gorm:"not null;index"`
+	UserID        uint            `json:"user_id" gorm:"not null;index;uniqueIndex:idx_time_entries_open,where:clock_out_at IS NULL"`
+	User          *usermodel.User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	ShiftID       *uint           `json:"shift_id" gorm:"index"`
+	ClockInAt     time.Time       `json:"clock_in_at" gorm:"not null;index"`
+	ClockOutAt    *time.Time      `json:"clock_out_at"`
+	BreakMinutes  int             `json:"break_minutes" gorm:"not null;default:0"`
+	WorkedMinutes int             `json:"worked_minutes" gorm:"not null;default:0"`
+	Note          string          `json:"note" gorm:"type:text"`
+	EditedByID    *uint           `json:"edited_by_id"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// Close sets the clock-out time and applies the break rules of settings
+func (e *TimeEntry) Close(out time.Time, settings *Settings) {
+	e.ClockOutAt = &out
+	span := int(out.Sub(e.ClockInAt) / time.Minute)
+	e.BreakMinutes = settings.BreakFor(span)
+	e.WorkedMinutes = span - e.BreakMinutes
+}
+
+// ClockRequest clocks a staff member in or out. Staff clock themselves in
+// with their own login; a shared device clocks in others by UserID and
+// their PIN.
+type ClockRequest struct {
+	UserID *uint  `json:"user_id"`
+	PIN    string `json:"pin" binding:"omitempty,numeric,min=4,max=8"`
+	Note   string `json:"note" binding:"max=500"`
+}
+
+// TimeEntryRequest corrects a time entry. A nil ClockOutAt leaves the
+// entry open.
+type TimeEntryRequest struct {
+	ClockInAt  time.Time  `json:"clock_in_at" binding:"required"`
+	ClockOutAt *time.Time `json:"clock_out_at"`
+	Note       string     `json:"note" binding:"max=500"`
+}
+
+// TimeEntryFilter selects time entries by the local dates they were
+// clocked in on and by staff member
+type TimeEntryFilter struct {
+	From   string
+	To     string
+	UserID uint
+}
+
+// ClockPIN is the PIN a staff member clocks in with on a shared device.
+// Too many wrong PINs lock it for a while.
+type ClockPIN struct {
+	UserID         uint       `json:"user_id" gorm:"primaryKey;autoIncrement:false"`
+	PINHash        string     `json:"-" gorm:"not null"`
+	FailedAttempts int        `json:"-" gorm:"not null;default:0"`
+	LockedUntil    *time.Time `json:"-"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (ClockPIN) TableName() string {
+	return "staff_clock_pins"
+}
+
+type PINRequest struct {
+	PIN string `json:"pin" binding:"required,numeric,min=4,max=8"`
+}
@@ -0,0 +1,49 @@
+package model
+
+// Timesheet export formats
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// Timesheet is the work of the staff of a branch in one pay period. Time
+// entries count towards the day they were clocked in on, in branch time;
+// entries still open are left out and counted in OpenEntries.
+type Timesheet struct {
+	BranchID    uint             `json:"branch_id"`
+	PayPeriod   string           `json:"pay_period"`
+	From        string           `json:"from"`
+	To          string           `json:"to"`
+	Staff       []StaffTimesheet `json:"staff"`
+	OpenEntries int              `json:"open_entries"`
+}
+
+// StaffTimesheet totals the work of one staff member in a pay period
+type StaffTimesheet struct {
+	UserID           uint           `json:"user_id"`
+	Name             string         `json:"name"`
+	Email            string         `json:"email"`
+	Days             []TimesheetDay `json:"days"`
+	ScheduledMinutes int            `json:"scheduled_minutes"`
+	WorkedMinutes    int            `json:"worked_minutes"`
+	BreakMinutes     int            `json:"break_minutes"`
+	RegularMinutes   int            `json:"regular_minutes"`
+	OvertimeMinutes  int            `json:"overtime_minutes"`
+}
+
+// TimesheetDay is the work of a staff member on one day
+type TimesheetDay struct {
+	Date             string `json:"date"`
+	ScheduledMinutes int    `json:"scheduled_minutes"`
+	WorkedMinutes    int    `json:"worked_minutes"`
+	BreakMinutes     int    `json:"break_minutes"`
+	RegularMinutes   int    `json:"regular_minutes"`
+	OvertimeMinutes  int    `json:"overtime_minutes"`
+}
+
+// Rendered is a timesheet in an export format
+type Rendered struct {
+	ContentType string
+	Filename    string
+	Body        []byte
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/shift/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ShiftRepository interface {
+	Create(shift *model.Shift) error
+	GetByID(id uint) (*model.Shift, error)
+	Update(shift *model.Shift) error
+	Delete(id uint) error
+	First(q *database.Query) (*model.Shift, error)
+	Find(q *database.Query) ([]model.Shift, error)
+	FindPage(q *database.Query) ([]model.Shift, int64, error)
+	// HasOverlap reports whether the user has a shift at any branch between
+	// from and to, other than the shift with ID exclude
+	HasOverlap(userID uint, from, to time.Time, exclude uint) (bool, error)
+}
+
+type shiftRepository struct {
+	database.Repository[model.Shift]
+}
+
+func NewShiftRepository(db *gorm.DB) ShiftRepository {
+	return &shiftRepository{Repository: database.NewRepository[model.Shift](db)}
+}
+
+// Update saves the shift columns only
+func (r *shiftRepository) Update(shift *model.Shift) error {
+	return database.TranslateError(r.DB().Omit("User").Save(shift).Error)
+}
+
+func (r *shiftRepository) HasOverlap(userID uint, from, to time.Time, exclude uint) (bool, error) {
+	return r.Exists(database.NewQuery().
+		Eq("user_id", userID).
+		Where("starts_at", database.OpLt, to).
+		Where("ends_at", database.OpGt, from).
+		Where("id", database.OpNotEq, exclude))
+}
+
+type TimeEntryRepository interface {
+	Create(entry *model.TimeEntry) error
+	GetByID(id uint) (*model.TimeEntry, error)
+	Update(entry *model.TimeEntry) error
+	Find(q *database.Query) ([]model.TimeEntry, error)
+	FindPage(q *database.Query) ([]model.TimeEntry, int64, error)
+	// GetOpen loads the entry the user is clocked in on and locks it until
+	// the surrounding transaction ends
+	GetOpen(userID uint) (*model.TimeEntry, error)
+	WithTx(tx *gorm.DB) TimeEntryRepository
+}
+
+type timeEntryRepository struct {
+	database.Repository[model.TimeEntry]
+}
+
+func NewTimeEntryRepository(db *gorm.DB) TimeEntryRepository {
+	return &timeEntryRepository{Repository: database.NewRepository[model.TimeEntry](db)}
+}
+
+// Update saves the entry columns only
+func (r *timeEntryRepository) Update(entry *model.TimeEntry) error {
+	return database.TranslateError(r.DB().Omit("User").Save(entry).Error)
+}
+
+func (r *timeEntryRepository) GetOpen(userID uint) (*model.TimeEntry, error) {
+	var entry model.TimeEntry
+	err := r.DB().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND clock_out_at IS NULL", userID).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *timeEntryRepository) WithTx(tx *gorm.DB) TimeEntryRepository {
+	return &timeEntryRepository{Repository: r.Repository.WithTx(tx)}
+}
+
+type ClockPINRepository interface {
+	// GetByUser returns the PIN of a user, or gorm.ErrRecordNotFound when
+	// none is set
+	GetByUser(userID uint) (*model.ClockPIN, error)
+	// GetForUpdate returns the PIN of a user like GetByUser and locks it
+	// until the surrounding transaction ends
+	GetForUpdate(userID uint) (*model.ClockPIN, error)
+	Save(pin *model.ClockPIN) error
+	WithTx(tx *gorm.DB) ClockPINRepository
+}
+
+type clockPINRepository struct {
+	database.Repository[model.ClockPIN]
+}
+
+func NewClockPINRepository(db *gorm.DB) ClockPINRepository {
+	return &clockPINRepository{Repository: database.NewRepository[model.ClockPIN](db)}
+}
+
+func (r *clockPINRepository) GetByUser(userID uint) (*model.ClockPIN, error) {
+	return r.First(database.NewQuery().Eq("user_id", userID))
+}
+
+func (r *clockPINRepository) Save(pin *model.ClockPIN) error {
+	return r.Upsert(pin, []string{"user_id"}, "pin_hash", "failed_attempts", "locked_until", "updated_at")
+}
+
+func (r *clockPINRepository) GetForUpdate(userID uint) (*model.ClockPIN, error) {
+	var pin model.ClockPIN
+	if err := r.DB().Clauses(clause.Locking{Strength: "UPDATE"}).First(&pin, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &pin, nil
+}
+
+func (r *clockPINRepository) WithTx(tx *gorm.DB) ClockPINRepository {
+	return &clockPINRepository{Repository: r.Repository.WithTx(tx)}
+}
+
+type SettingsRepository interface {
+	// GetByBranch returns the settings of a branch, or defaults when the
+	// branch has none yet
+	GetByBranch(branchID uint) (*model.Settings, error)
+	Save(settings *model.Settings) error
+}
+
+type settingsRepository struct {
+	database.Repository[model.Settings]
+}
+
+func NewSettingsRepository(db *gorm.DB) SettingsRepository {
+	return &settingsRepository{Repository: database.NewRepository[model.Settings](db)}
+}
+
+func (r *settingsRepository) GetByBranch(branchID uint) (*model.Settings, error) {
+	settings, err := r.First(database.NewQuery().Eq("branch_id", branchID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.DefaultSettings(branchID), nil
+	}
+	return settings, err
+}
+
+func (r *settingsRepository) Save(settings *model.Settings) error {
+	return r.Upsert(settings, []string{"branch_id"},
+		"pay_period", "period_anchor", "daily_overtime_minutes", "weekly_overtime_minutes",
+		"break_after_minutes", "break_minutes", "clock_in_window_minutes", "require_pin", "updated_at")
+}
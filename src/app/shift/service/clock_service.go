@@ -0,0 +1,319 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/app/shift/model"
+	"github.com/faisd405/go-restapi-gin/src/app/shift/repository"
+	usermodel "github.com/faisd405/go-restapi-gin/src/app/user/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+// A clock PIN is locked for pinLockDuration after maxPINAttempts wrong
+// PINs in a row
+const (
+	maxPINAttempts  = 5
+	pinLockDuration = 15 * time.Minute
+)
+
+var (
+	ErrAlreadyClockedIn = errors.New("staff member is already clocked in")
+	ErrNotClockedIn     = errors.New("staff member is not clocked in at this branch")
+	ErrUserRequired     = errors.New("user_id is required to clock in from a station")
+	ErrPINRequired      = errors.New("a PIN is required to clock in or out")
+	ErrPINNotSet        = errors.New("staff member has not set a clock PIN")
+	ErrInvalidPIN       = errors.New("PIN is incorrect")
+	ErrPINLocked        = errors.New("too many wrong PINs, try again later")
+)
+
+type ClockService interface {
+	// ClockIn starts a time entry at a branch, linked to the shift of the
+	// staff member that is about to start or running. Staff clock
+	// themselves in; clocking in someone else, and any clocking in on a
+	// station, takes their PIN. Branches can require the PIN always.
+	ClockIn(actor utils.Actor, branchID uint, req model.ClockRequest) (*model.TimeEntry, error)
+	// ClockOut closes the open time entry of a staff member at a branch and
+	// deducts the break due by the branch rules
+	ClockOut(actor utils.Actor, branchID uint, req model.ClockRequest) (*model.TimeEntry, error)
+	GetTimeEntries(actor utils.Actor, branchID uint, page, limit int, filter model.TimeEntryFilter) ([]model.TimeEntry, int64, error)
+	// UpdateTimeEntry corrects the times of an entry, e.g. a forgotten
+	// clock-out, and recomputes its break and worked time
+	UpdateTimeEntry(actor utils.Actor, id uint, req model.TimeEntryRequest) (*model.TimeEntry, error)
+	GetMyTimeEntries(actor utils.Actor, page, limit int) ([]model.TimeEntry, int64, error)
+	// SetPIN sets the clock PIN of the actor and lifts a lock on it
+	SetPIN(actor utils.Actor, req model.PINRequest) error
+}
+
+type clockService struct {
+	entryRepo     repository.TimeEntryRepository
+	shiftRepo     repository.ShiftRepository
+	pinRepo       repository.ClockPINRepository
+	settingsRepo  repository.SettingsRepository
+	restaurantSvc restaurantservice.RestaurantService
+	txManager     database.TxManager
+	now           func() time.Time
+}
+
+func NewClockService(
+	entryRepo repository.TimeEntryRepository,
+	shiftRepo repository.ShiftRepository,
+	pinRepo repository.ClockPINRepository,
+	settingsRepo repository.SettingsRepository,
+	restaurantSvc restaurantservice.RestaurantService,
+	txManager database.TxManager,
+) ClockService {
+	return &clockService{
+		entryRepo:     entryRepo,
+		shiftRepo:     shiftRepo,
+		pinRepo:       pinRepo,
+		settingsRepo:  settingsRepo,
+		restaurantSvc: restaurantSvc,
+		txManager:     txManager,
+		now:           time.Now,
+	}
+}
+
+func (s *clockService) ClockIn(actor utils.Actor, branchID uint, req model.ClockRequest) (*model.TimeEntry, error) {
+	userID, settings, err := s.clocker(actor, branchID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.entryRepo.GetOpen(userID); err == nil {
+		return nil, ErrAlreadyClockedIn
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	now := s.now()
+	entry := &model.TimeEntry{BranchID: branchID, UserID: userID, ClockInAt: now, Note: req.Note}
+	window := time.Duration(settings.ClockInWindowMinutes) * time.Minute
+	shift, err := s.shiftRepo.First(database.NewQuery().
+		Eq("branch_id", branchID).
+		Eq("user_id", userID).
+		Where("starts_at", database.OpLte, now.Add(window)).
+		Where("ends_at", database.OpGt, now).
+		OrderBy("starts_at"))
+	if err == nil {
+		entry.ShiftID = &shift.ID
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	err = s.entryRepo.Create(entry)
+	if errors.Is(err, database.ErrUniqueViolation) {
+		return nil, ErrAlreadyClockedIn
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *clockService) ClockOut(actor utils.Actor, branchID uint, req model.ClockRequest) (*model.TimeEntry, error) {
+	userID, settings, err := s.clocker(actor, branchID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry *model.TimeEntry
+	err = s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		entryRepo := s.entryRepo.WithTx(tx)
+		entry, err = entryRepo.GetOpen(userID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotClockedIn
+		}
+		if err != nil {
+			return err
+		}
+		if entry.BranchID != branchID {
+			return ErrNotClockedIn
+		}
+
+		entry.Close(s.now(), settings)
+		if req.Note != "" {
+			entry.Note = req.Note
+		}
+		return entryRepo.Update(entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *clockService) GetTimeEntries(actor utils.Actor, branchID uint, page, limit int, filter model.TimeEntryFilter) ([]model.TimeEntry, int64, error) {
+	branch, err := s.restaurantSvc.GetBranch(branchID, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branch.ID); err != nil {
+		return nil, 0, err
+	}
+
+	q := database.NewQuery().Eq("branch_id", branch.ID)
+	if filter.From != "" || filter.To != "" {
+		from, to, err := dateRange(branch, filter.From, filter.To, s.now())
+		if err != nil {
+			return nil, 0, err
+		}
+		q.Where("clock_in_at", database.OpGte, from).Where("clock_in_at", database.OpLt, to)
+	}
+	if filter.UserID != 0 {
+		q.Eq("user_id", filter.UserID)
+	}
+	return s.entryRepo.FindPage(q.Preload("User").OrderByDesc("clock_in_at").Paginate(utils.Offset(page, limit), limit))
+}
+
+func (s *clockService) UpdateTimeEntry(actor utils.Actor, id uint, req model.TimeEntryRequest) (*model.TimeEntry, error) {
+	if req.ClockOutAt != nil && (!req.ClockOutAt.After(req.ClockInAt) || req.ClockOutAt.Sub(req.ClockInAt) > model.MaxShiftLength) {
+		return nil, model.ErrInvalidTimeEntry
+	}
+	entry, err := s.entryRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, entry.BranchID); err != nil {
+		return nil, err
+	}
+	settings, err := s.settingsRepo.GetByBranch(entry.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.ClockInAt = req.ClockInAt
+	entry.Note = req.Note
+	entry.EditedByID = utils.ActorID(actor)
+	if req.ClockOutAt != nil {
+		entry.Close(*req.ClockOutAt, settings)
+	} else {
+		entry.ClockOutAt = nil
+		entry.BreakMinutes = 0
+		entry.WorkedMinutes = 0
+	}
+
+	err = s.entryRepo.Update(entry)
+	if errors.Is(err, database.ErrUniqueViolation) {
+		return nil, ErrAlreadyClockedIn
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *clockService) GetMyTimeEntries(actor utils.Actor, page, limit int) ([]model.TimeEntry, int64, error) {
+	return s.entryRepo.FindPage(database.NewQuery().
+		Eq("user_id", actor.UserID).
+		OrderByDesc("clock_in_at").
+		Paginate(utils.Offset(page, limit), limit))
+}
+
+func (s *clockService) SetPIN(actor utils.Actor, req model.PINRequest) error {
+	switch actor.Role {
+	case usermodel.RoleStaff, usermodel.RoleManager, usermodel.RoleAdmin:
+	default:
+		return restaurantservice.ErrStaffRoleRequired
+	}
+
+	hash, err := utils.HashPassword(req.PIN)
+	if err != nil {
+		return err
+	}
+	return s.pinRepo.Save(&model.ClockPIN{UserID: actor.UserID, PINHash: hash})
+}
+
+// clocker works out who is clocking in or out at a branch: the actor, or
+// the staff member named in the request who then proves it with their PIN.
+// A station acts for whoever opened it, so it always takes a user and PIN.
+func (s *clockService) clocker(actor utils.Actor, branchID uint, req model.ClockRequest) (uint, *model.Settings, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return 0, nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return 0, nil, err
+	}
+	settings, err := s.settingsRepo.GetByBranch(branchID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	userID := actor.UserID
+	onBehalf := actor.IsStation()
+	if req.UserID != nil {
+		onBehalf = onBehalf || *req.UserID != actor.UserID
+		userID = *req.UserID
+	} else if actor.IsStation() {
+		return 0, nil, ErrUserRequired
+	}
+
+	staff, err := s.restaurantSvc.IsBranchStaff(branchID, userID)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !staff {
+		return 0, nil, restaurantservice.ErrStaffNotAssigned
+	}
+	if onBehalf || settings.RequirePIN {
+		if err := s.checkPIN(userID, req.PIN); err != nil {
+			return 0, nil, err
+		}
+	}
+	return userID, settings, nil
+}
+
+// checkPIN verifies the clock PIN of a user and counts wrong attempts
+// towards a lock. The PIN stays locked while it is checked, so concurrent
+// guesses cannot get past the attempt limit.
+func (s *clockService) checkPIN(userID uint, pin string) error {
+	if pin == "" {
+		return ErrPINRequired
+	}
+
+	wrong := false
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		pinRepo := s.pinRepo.WithTx(tx)
+
+		stored, err := pinRepo.GetForUpdate(userID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPINNotSet
+		}
+		if err != nil {
+			return err
+		}
+
+		now := s.now()
+		if stored.LockedUntil != nil && now.Before(*stored.LockedUntil) {
+			return ErrPINLocked
+		}
+		if utils.CheckPassword(pin, stored.PINHash) {
+			if stored.FailedAttempts == 0 && stored.LockedUntil == nil {
+				return nil
+			}
+			stored.FailedAttempts = 0
+			stored.LockedUntil = nil
+			return pinRepo.Save(stored)
+		}
+
+		// The attempt is counted, so the transaction commits
+		wrong = true
+		stored.FailedAttempts++
+		if stored.FailedAttempts >= maxPINAttempts {
+			until := now.Add(pinLockDuration)
+			stored.FailedAttempts = 0
+			stored.LockedUntil = &until
+		}
+		return pinRepo.Save(stored)
+	})
+	if err != nil {
+		return err
+	}
+	if wrong {
+		return ErrInvalidPIN
+	}
+	return nil
+}
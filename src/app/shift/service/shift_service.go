@@ -0,0 +1,254 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	restaurantmodel "github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/app/shift/model"
+	"github.com/faisd405/go-restapi-gin/src/app/shift/repository"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+)
+
+var (
+	ErrShiftOverlap = errors.New("staff member already has a shift at that time")
+	ErrInvalidDate  = errors.New("date must be formatted as YYYY-MM-DD")
+	ErrInvalidRange = errors.New("from must not be after to")
+)
+
+type ShiftService interface {
+	GetSettings(actor utils.Actor, branchID uint) (*model.Settings, error)
+	UpdateSettings(actor utils.Actor, branchID uint, req model.SettingsRequest) (*model.Settings, error)
+
+	// GetStaff lists the staff assigned to a branch the actor manages
+	GetStaff(actor utils.Actor, branchID uint) ([]restaurantmodel.StaffMember, error)
+	GetShifts(actor utils.Actor, branchID uint, filter model.ShiftFilter) ([]model.Shift, error)
+	// CreateShift schedules a staff member of the branch. A staff member
+	// cannot have two shifts at once, also not at different branches.
+	CreateShift(actor utils.Actor, branchID uint, req model.ShiftRequest) (*model.Shift, error)
+	UpdateShift(actor utils.Actor, id uint, req model.ShiftRequest) (*model.Shift, error)
+	DeleteShift(actor utils.Actor, id uint) error
+	// GetMyShifts lists the shifts of the actor that have not ended yet
+	GetMyShifts(actor utils.Actor, page, limit int) ([]model.Shift, int64, error)
+}
+
+type shiftService struct {
+	shiftRepo     repository.ShiftRepository
+	settingsRepo  repository.SettingsRepository
+	restaurantSvc restaurantservice.RestaurantService
+	now           func() time.Time
+}
+
+func NewShiftService(
+	shiftRepo repository.ShiftRepository,
+	settingsRepo repository.SettingsRepository,
+	restaurantSvc restaurantservice.RestaurantService,
+) ShiftService {
+	return &shiftService{
+		shiftRepo:     shiftRepo,
+		settingsRepo:  settingsRepo,
+		restaurantSvc: restaurantSvc,
+		now:           time.Now,
+	}
+}
+
+func (s *shiftService) GetSettings(actor utils.Actor, branchID uint) (*model.Settings, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+	return s.settingsRepo.GetByBranch(branchID)
+}
+
+func (s *shiftService) UpdateSettings(actor utils.Actor, branchID uint, req model.SettingsRequest) (*model.Settings, error) {
+	if !model.IsValidPayPeriod(req.PayPeriod) {
+		return nil, model.ErrInvalidPayPeriod
+	}
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.settingsRepo.GetByBranch(branchID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings.PayPeriod = req.PayPeriod
+	settings.PeriodAnchor = req.PeriodAnchor
+	settings.DailyOvertimeMinutes = req.DailyOvertimeMinutes
+	settings.WeeklyOvertimeMinutes = req.WeeklyOvertimeMinutes
+	settings.BreakAfterMinutes = req.BreakAfterMinutes
+	settings.BreakMinutes = req.BreakMinutes
+	settings.ClockInWindowMinutes = req.ClockInWindowMinutes
+	if req.RequirePIN != nil {
+		settings.RequirePIN = *req.RequirePIN
+	}
+
+	if err := s.settingsRepo.Save(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (s *shiftService) GetStaff(actor utils.Actor, branchID uint) ([]restaurantmodel.StaffMember, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+	return s.restaurantSvc.GetStaff(branchID)
+}
+
+func (s *shiftService) GetShifts(actor utils.Actor, branchID uint, filter model.ShiftFilter) ([]model.Shift, error) {
+	branch, err := s.restaurantSvc.GetBranch(branchID, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branch.ID); err != nil {
+		return nil, err
+	}
+
+	from, to, err := dateRange(branch, filter.From, filter.To, s.now())
+	if err != nil {
+		return nil, err
+	}
+	q := database.NewQuery().
+		Eq("branch_id", branch.ID).
+		Where("starts_at", database.OpGte, from).
+		Where("starts_at", database.OpLt, to)
+	if filter.UserID != 0 {
+		q.Eq("user_id", filter.UserID)
+	}
+	return s.shiftRepo.Find(q.Preload("User").OrderBy("starts_at"))
+}
+
+func (s *shiftService) CreateShift(actor utils.Actor, branchID uint, req model.ShiftRequest) (*model.Shift, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	shift := &model.Shift{BranchID: branchID, CreatedByID: utils.ActorID(actor)}
+	if err := s.apply(shift, req); err != nil {
+		return nil, err
+	}
+	if err := s.shiftRepo.Create(shift); err != nil {
+		return nil, shiftError(err)
+	}
+	return shift, nil
+}
+
+func (s *shiftService) UpdateShift(actor utils.Actor, id uint, req model.ShiftRequest) (*model.Shift, error) {
+	shift, err := s.getAccessible(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.apply(shift, req); err != nil {
+		return nil, err
+	}
+	shift.User = nil
+	if err := s.shiftRepo.Update(shift); err != nil {
+		return nil, shiftError(err)
+	}
+	return shift, nil
+}
+
+func (s *shiftService) DeleteShift(actor utils.Actor, id uint) error {
+	shift, err := s.getAccessible(actor, id)
+	if err != nil {
+		return err
+	}
+	return s.shiftRepo.Delete(shift.ID)
+}
+
+func (s *shiftService) GetMyShifts(actor utils.Actor, page, limit int) ([]model.Shift, int64, error) {
+	return s.shiftRepo.FindPage(database.NewQuery().
+		Eq("user_id", actor.UserID).
+		Where("ends_at", database.OpGt, s.now()).
+		OrderBy("starts_at").
+		Paginate(utils.Offset(page, limit), limit))
+}
+
+// apply checks a shift request and copies it onto shift. The staff member
+// must be assigned to the branch of the shift and free at that time.
+func (s *shiftService) apply(shift *model.Shift, req model.ShiftRequest) error {
+	if !req.EndsAt.After(req.StartsAt) || req.EndsAt.Sub(req.StartsAt) > model.MaxShiftLength {
+		return model.ErrInvalidShift
+	}
+	staff, err := s.restaurantSvc.IsBranchStaff(shift.BranchID, req.UserID)
+	if err != nil {
+		return err
+	}
+	if !staff {
+		return restaurantservice.ErrStaffNotAssigned
+	}
+	overlap, err := s.shiftRepo.HasOverlap(req.UserID, req.StartsAt, req.EndsAt, shift.ID)
+	if err != nil {
+		return err
+	}
+	if overlap {
+		return ErrShiftOverlap
+	}
+
+	shift.UserID = req.UserID
+	shift.StartsAt = req.StartsAt
+	shift.EndsAt = req.EndsAt
+	shift.Position = req.Position
+	shift.Note = req.Note
+	return nil
+}
+
+// getAccessible loads a shift of a branch the actor manages
+func (s *shiftService) getAccessible(actor utils.Actor, id uint) (*model.Shift, error) {
+	shift, err := s.shiftRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, shift.BranchID); err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+// shiftError reports a shift refused by the overlap constraint of the
+// database, which catches two shifts scheduled at the same time
+func shiftError(err error) error {
+	if errors.Is(err, database.ErrExclusionViolation) {
+		return ErrShiftOverlap
+	}
+	return err
+}
+
+// dateRange turns local dates of a branch into the start of from and the
+// end of to. From defaults to today and to to a week after from.
+func dateRange(branch *restaurantmodel.Branch, fromDate, toDate string, now time.Time) (time.Time, time.Time, error) {
+	if fromDate == "" {
+		fromDate = now.In(branch.Location()).Format(dateFormat)
+	}
+	from, err := time.ParseInLocation(dateFormat, fromDate, branch.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidDate
+	}
+	if toDate == "" {
+		return from, from.AddDate(0, 0, 7), nil
+	}
+	to, err := time.ParseInLocation(dateFormat, toDate, branch.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidDate
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, ErrInvalidRange
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"sort"
+	"strconv"
+	"time"
+
+	restaurantmodel "github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	"github.com/faisd405/go-restapi-gin/src/app/shift/model"
+	usermodel "github.com/faisd405/go-restapi-gin/src/app/user/model"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+)
+
+const dateFormat = "2006-01-02"
+
+var csvHeader = []string{"user_id", "name", "email", "date", "scheduled_hours", "worked_hours", "break_hours", "regular_hours", "overtime_hours"}
+
+// staffDays collects the days a staff member worked or was scheduled,
+// including the days of the first week before the period started
+type staffDays struct {
+	sheet *model.StaffTimesheet
+	days  map[string]*model.TimesheetDay
+}
+
+// buildTimesheet totals the shifts and closed time entries of a pay period
+// from start up to end per staff member and day. Each day's work beyond the
+// daily limit is overtime, then the regular work of a week beyond the
+// weekly limit.
+func buildTimesheet(settings *model.Settings, start, end time.Time, staff []restaurantmodel.StaffMember, shifts []model.Shift, entries []model.TimeEntry) *model.Timesheet {
+	loc := start.Location()
+	sheet := &model.Timesheet{
+		BranchID:  settings.BranchID,
+		PayPeriod: settings.PayPeriod,
+		From:      start.Format(dateFormat),
+		To:        end.AddDate(0, 0, -1).Format(dateFormat),
+	}
+
+	byUser := make(map[uint]*staffDays)
+	member := func(userID uint, user *usermodel.User) *staffDays {
+		work, ok := byUser[userID]
+		if !ok {
+			work = &staffDays{
+				sheet: &model.StaffTimesheet{UserID: userID, Days: []model.TimesheetDay{}},
+				days:  make(map[string]*model.TimesheetDay),
+			}
+			byUser[userID] = work
+		}
+		if work.sheet.Name == "" && user != nil {
+			work.sheet.Name = user.Name
+			work.sheet.Email = user.Email
+		}
+		return work
+	}
+	day := func(work *staffDays, at time.Time) *model.TimesheetDay {
+		date := at.In(loc).Format(dateFormat)
+		d, ok := work.days[date]
+		if !ok {
+			d = &model.TimesheetDay{Date: date}
+			work.days[date] = d
+		}
+		return d
+	}
+
+	for _, m := range staff {
+		work := member(m.UserID, nil)
+		work.sheet.Name = m.Name
+		work.sheet.Email = m.Email
+	}
+	for _, shift := range shifts {
+		day(member(shift.UserID, shift.User), shift.StartsAt).ScheduledMinutes += shift.Minutes()
+	}
+	for _, entry := range entries {
+		if entry.ClockOutAt == nil {
+			if !entry.ClockInAt.Before(start) {
+				sheet.OpenEntries++
+			}
+			continue
+		}
+		d := day(member(entry.UserID, entry.User), entry.ClockInAt)
+		d.WorkedMinutes += entry.WorkedMinutes
+		d.BreakMinutes += entry.BreakMinutes
+	}
+
+	first := start.Format(dateFormat)
+	sheet.Staff = make([]model.StaffTimesheet, 0, len(byUser))
+	for _, work := range byUser {
+		dates := make([]string, 0, len(work.days))
+		for date := range work.days {
+			dates = append(dates, date)
+		}
+		sort.Strings(dates)
+
+		weekly := make(map[string]int)
+		for _, date := range dates {
+			d := work.days[date]
+			splitOvertime(settings, d, weekly)
+			if date < first {
+				continue
+			}
+			work.sheet.Days = append(work.sheet.Days, *d)
+			work.sheet.ScheduledMinutes += d.ScheduledMinutes
+			work.sheet.WorkedMinutes += d.WorkedMinutes
+			work.sheet.BreakMinutes += d.BreakMinutes
+			work.sheet.RegularMinutes += d.RegularMinutes
+			work.sheet.OvertimeMinutes += d.OvertimeMinutes
+		}
+		sheet.Staff = append(sheet.Staff, *work.sheet)
+	}
+	sort.Slice(sheet.Staff, func(i, j int) bool {
+		if sheet.Staff[i].Name != sheet.Staff[j].Name {
+			return sheet.Staff[i].Name < sheet.Staff[j].Name
+		}
+		return sheet.Staff[i].UserID < sheet.Staff[j].UserID
+	})
+	return sheet
+}
+
+// splitOvertime splits the work of a day into regular time and overtime.
+// weekly holds the regular minutes of each week so far, by its first day,
+// and days must be passed in order.
+func splitOvertime(settings *model.Settings, d *model.TimesheetDay, weekly map[string]int) {
+	regular := d.WorkedMinutes
+	if settings.DailyOvertimeMinutes > 0 {
+		regular = min(regular, settings.DailyOvertimeMinutes)
+	}
+	if settings.WeeklyOvertimeMinutes > 0 {
+		date, _ := time.Parse(dateFormat, d.Date)
+		week := settings.WeekStart(date).Format(dateFormat)
+		regular = min(regular, max(settings.WeeklyOvertimeMinutes-weekly[week], 0))
+		weekly[week] += regular
+	}
+	d.RegularMinutes = regular
+	d.OvertimeMinutes = d.WorkedMinutes - regular
+}
+
+// renderCSV writes a timesheet as a spreadsheet with a row per staff
+// member and day, then a total row per staff member, in hours
+func renderCSV(sheet *model.Timesheet) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	for _, staff := range sheet.Staff {
+		for _, d := range staff.Days {
+			err := w.Write(csvRow(staff, d.Date, d.ScheduledMinutes, d.WorkedMinutes, d.BreakMinutes, d.RegularMinutes, d.OvertimeMinutes))
+			if err != nil {
+				return nil, err
+			}
+		}
+		err := w.Write(csvRow(staff, "total", staff.ScheduledMinutes, staff.WorkedMinutes, staff.BreakMinutes, staff.RegularMinutes, staff.OvertimeMinutes))
+		if err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func csvRow(staff model.StaffTimesheet, date string, minutes ...int) []string {
+	row := []string{strconv.FormatUint(uint64(staff.UserID), 10), utils.CSVText(staff.Name), utils.CSVText(staff.Email), date}
+	for _, m := range minutes {
+		row = append(row, hours(m))
+	}
+	return row
+}
+
+// hours formats minutes as decimal hours
+func hours(minutes int) string {
+	return strconv.FormatFloat(float64(minutes)/60, 'f', 2, 64)
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/app/shift/model"
+	"github.com/faisd405/go-restapi-gin/src/app/shift/repository"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+)
+
+type TimesheetService interface {
+	// GetTimesheet totals the scheduled and worked time of the staff of a
+	// branch in the pay period containing date, today by default
+	GetTimesheet(actor utils.Actor, branchID uint, date string) (*model.Timesheet, error)
+	// ExportTimesheet renders the timesheet of a pay period as CSV for
+	// payroll
+	ExportTimesheet(actor utils.Actor, branchID uint, date string) (*model.Rendered, error)
+}
+
+type timesheetService struct {
+	entryRepo     repository.TimeEntryRepository
+	shiftRepo     repository.ShiftRepository
+	settingsRepo  repository.SettingsRepository
+	restaurantSvc restaurantservice.RestaurantService
+	now           func() time.Time
+}
+
+func NewTimesheetService(
+	entryRepo repository.TimeEntryRepository,
+	shiftRepo repository.ShiftRepository,
+	settingsRepo repository.SettingsRepository,
+	restaurantSvc restaurantservice.RestaurantService,
+) TimesheetService {
+	return &timesheetService{
+		entryRepo:     entryRepo,
+		shiftRepo:     shiftRepo,
+		settingsRepo:  settingsRepo,
+		restaurantSvc: restaurantSvc,
+		now:           time.Now,
+	}
+}
+
+func (s *timesheetService) GetTimesheet(actor utils.Actor, branchID uint, date string) (*model.Timesheet, error) {
+	branch, err := s.restaurantSvc.GetBranch(branchID, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branch.ID); err != nil {
+		return nil, err
+	}
+	settings, err := s.settingsRepo.GetByBranch(branch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if date == "" {
+		date = s.now().In(branch.Location()).Format(dateFormat)
+	}
+	day, err := time.ParseInLocation(dateFormat, date, branch.Location())
+	if err != nil {
+		return nil, ErrInvalidDate
+	}
+	start, end := settings.Period(day)
+
+	// Weekly overtime counts the whole week, so the days of the first week
+	// before the period starts are loaded too
+	entries, err := s.entryRepo.Find(database.NewQuery().
+		Eq("branch_id", branch.ID).
+		Where("clock_in_at", database.OpGte, settings.WeekStart(start)).
+		Where("clock_in_at", database.OpLt, end).
+		Preload("User").
+		OrderBy("clock_in_at"))
+	if err != nil {
+		return nil, err
+	}
+	shifts, err := s.shiftRepo.Find(database.NewQuery().
+		Eq("branch_id", branch.ID).
+		Where("starts_at", database.OpGte, start).
+		Where("starts_at", database.OpLt, end).
+		Preload("User").
+		OrderBy("starts_at"))
+	if err != nil {
+		return nil, err
+	}
+	staff, err := s.restaurantSvc.GetStaff(branch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildTimesheet(settings, start, end, staff, shifts, entries), nil
+}
+
+func (s *timesheetService) ExportTimesheet(actor utils.Actor, branchID uint, date string) (*model.Rendered, error) {
+	sheet, err := s.GetTimesheet(actor, branchID, date)
+	if err != nil {
+		return nil, err
+	}
+	body, err := renderCSV(sheet)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Rendered{
+		ContentType: "text/csv; charset=utf-8",
+		Filename:    fmt.Sprintf("timesheet-%d-%s-%s.csv", sheet.BranchID, sheet.From, sheet.To),
+		Body:        body,
+	}, nil
+}
@@ -0,0 +1,342 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/faisd405/go-restapi-gin/src/app/table/model"
+	"github.com/faisd405/go-restapi-gin/src/app/table/service"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// Error codes of the tables API
+const (
+	ErrCodeInvalidQRToken   = "INVALID_TABLE_CODE"
+	ErrCodeTableUnavailable = "TABLE_UNAVAILABLE"
+	ErrCodeSessionEnded     = "TABLE_SESSION_ENDED"
+)
+
+type TableController struct {
+	tableService service.TableService
+}
+
+func NewTableController(tableService service.TableService) *TableController {
+	return &TableController{tableService: tableService}
+}
+
+// StartSession godoc
+// @Summary Start table session
+// @Description Open or join the anonymous ordering session of the table a scanned QR token belongs to. The returned token authorizes dine-in orders at that table.
+// @Tags tables
+// @Accept json
+// @Produce json
+// @Param session body model.StartSessionRequest true "Scanned QR token"
+// @Success 201 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /tables/sessions [post]
+func (ctrl *TableController) StartSession(c *gin.Context) {
+	var req model.StartSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	session, err := ctrl.tableService.StartSession(req)
+	if err != nil {
+		tableErrorResponse(c, "Failed to start table session", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Table session started successfully", session)
+}
+
+// GetTables godoc
+// @Summary Get branch tables (Staff)
+// @Description Get the tables of a branch ordered by area and name
+// @Tags tables
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param status query string false "Filter by status"
+// @Param area query string false "Filter by area"
+// @Success 200 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /branches/{id}/tables [get]
+func (ctrl *TableController) GetTables(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	filter := model.TableFilter{Status: c.Query("status"), Area: c.Query("area")}
+	tables, err := ctrl.tableService.GetTables(actor, id, filter)
+	if err != nil {
+		tableErrorResponse(c, "Failed to retrieve tables", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tables retrieved successfully", tables)
+}
+
+// GetTable godoc
+// @Summary Get table (Staff)
+// @Description Get a table by ID
+// @Tags tables
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Table ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tables/{id} [get]
+func (ctrl *TableController) GetTable(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid table ID", err.Error())
+		return
+	}
+
+	table, err := ctrl.tableService.GetTable(actor, id)
+	if err != nil {
+		tableErrorResponse(c, "Failed to retrieve table", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Table retrieved successfully", table)
+}
+
+// CreateTable godoc
+// @Summary Create table (Admin/Manager)
+// @Description Add a dine-in table to a branch
+// @Tags tables
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Branch ID"
+// @Param table body model.TableRequest true "Table data"
+// @Success 201 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /branches/{id}/tables [post]
+func (ctrl *TableController) CreateTable(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid branch ID", err.Error())
+		return
+	}
+
+	var req model.TableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	table, err := ctrl.tableService.CreateTable(actor, id, req)
+	if err != nil {
+		tableErrorResponse(c, "Table creation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Table created successfully", table)
+}
+
+// UpdateTable godoc
+// @Summary Update table (Admin/Manager)
+// @Description Update the name, capacity, area or active flag of a table
+// @Tags tables
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Table ID"
+// @Param table body model.TableRequest true "Table data"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tables/{id} [put]
+func (ctrl *TableController) UpdateTable(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid table ID", err.Error())
+		return
+	}
+
+	var req model.TableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	table, err := ctrl.tableService.UpdateTable(actor, id, req)
+	if err != nil {
+		tableErrorResponse(c, "Table update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Table updated successfully", table)
+}
+
+// DeleteTable godoc
+// @Summary Delete table (Admin/Manager)
+// @Description Delete a table and end its ordering session
+// @Tags tables
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Table ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tables/{id} [delete]
+func (ctrl *TableController) DeleteTable(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid table ID", err.Error())
+		return
+	}
+
+	if err := ctrl.tableService.DeleteTable(actor, id); err != nil {
+		tableErrorResponse(c, "Table deletion failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Table deleted successfully", nil)
+}
+
+// SetStatus godoc
+// @Summary Set table status (Staff)
+// @Description Mark a table free, occupied, reserved or cleaning. Freeing a table or sending it to cleaning ends its ordering session.
+// @Tags tables
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Table ID"
+// @Param status body model.StatusRequest true "Table status"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tables/{id}/status [put]
+func (ctrl *TableController) SetStatus(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid table ID", err.Error())
+		return
+	}
+
+	var req model.StatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	table, err := ctrl.tableService.SetStatus(actor, id, req)
+	if err != nil {
+		tableErrorResponse(c, "Table status update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Table status updated successfully", table)
+}
+
+// GetQRCode godoc
+// @Summary Get table QR code (Staff)
+// @Description Get the signed token, and the URL to print as QR code, of a table
+// @Tags tables
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Table ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tables/{id}/qr [get]
+func (ctrl *TableController) GetQRCode(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid table ID", err.Error())
+		return
+	}
+
+	code, err := ctrl.tableService.GetQRCode(actor, id)
+	if err != nil {
+		tableErrorResponse(c, "Failed to retrieve QR code", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "QR code retrieved successfully", code)
+}
+
+// RotateQRCode godoc
+// @Summary Rotate table QR code (Admin/Manager)
+// @Description Issue a new QR token for a table. Previously printed codes stop working.
+// @Tags tables
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "Table ID"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tables/{id}/qr/rotate [post]
+func (ctrl *TableController) RotateQRCode(c *gin.Context) {
+	actor, ok := utils.RequireActor(c)
+	if !ok {
+		return
+	}
+
+	id, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid table ID", err.Error())
+		return
+	}
+
+	code, err := ctrl.tableService.RotateQRCode(actor, id)
+	if err != nil {
+		tableErrorResponse(c, "QR code rotation failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "QR code rotated successfully", code)
+}
+
+func tableErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidQRToken):
+		utils.ErrorResponseWithCode(c, http.StatusUnauthorized, ErrCodeInvalidQRToken, message, err.Error())
+	case errors.Is(err, service.ErrTableUnavailable):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeTableUnavailable, message, err.Error())
+	case errors.Is(err, service.ErrSessionEnded):
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeSessionEnded, message, err.Error())
+	case errors.Is(err, service.ErrInvalidStatus):
+		utils.ErrorResponse(c, http.StatusUnprocessableEntity, message, err.Error())
+	default:
+		utils.DatabaseErrorResponse(c, message, err)
+	}
+}
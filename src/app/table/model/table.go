@@ -0,0 +1,90 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Table statuses
+const (
+	StatusFree     = "free"
+	StatusOccupied = "occupied"
+	StatusReserved = "reserved"
+	StatusCleaning = "cleaning"
+)
+
+// Table is a dine-in table of a branch. QRVersion is part of the signed QR
+// token; bumping it invalidates codes printed before.
+type Table struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	BranchID  uint           `json:"branch_id" gorm:"not null;uniqueIndex:idx_tables_branch_name,where:deleted_at IS NULL"`
+	Name      string         `json:"name" gorm:"type:varchar(50);not null;uniqueIndex:idx_tables_branch_name,where:deleted_at IS NULL"`
+	Capacity  int            `json:"capacity" gorm:"not null"`
+	Area      string         `json:"area" gorm:"type:varchar(50)"`
+	Status    string         `json:"status" gorm:"type:varchar(20);not null;default:free;index"`
+	QRVersion int            `json:"-" gorm:"not null;default:1"`
+	IsActive  bool           `json:"is_active" gorm:"not null;default:true"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Session is an anonymous ordering session opened by scanning the QR code
+// of a table. Everyone scanning the code while it is active joins it.
+type Session struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	TableID   uint       `json:"table_id" gorm:"not null;index"`
+	BranchID  uint       `json:"branch_id" gorm:"not null;index"`
+	StartedAt time.Time  `json:"started_at" gorm:"not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	EndedAt   *time.Time `json:"ended_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (Session) TableName() string {
+	return "table_sessions"
+}
+
+// IsActive reports whether the session can still be used at t
+func (s *Session) IsActive(t time.Time) bool {
+	return s.EndedAt == nil && t.Before(s.ExpiresAt)
+}
+
+type TableRequest struct {
+	Name     string `json:"name" binding:"required,max=50"`
+	Capacity int    `json:"capacity" binding:"required,min=1,max=100"`
+	Area     string `json:"area" binding:"max=50"`
+	IsActive *bool  `json:"is_active"`
+}
+
+type StatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=free occupied reserved cleaning"`
+}
+
+type StartSessionRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// TableFilter narrows down the tables of a branch
+type TableFilter struct {
+	Status string
+	Area   string
+}
+
+// QRCode is the signed token of a table and the URL to encode in its QR
+// code
+type QRCode struct {
+	TableID uint   `json:"table_id"`
+	Token   string `json:"token"`
+	URL     string `json:"url,omitempty"`
+}
+
+// SessionToken is a started table session together with the bearer token
+// guests order with
+type SessionToken struct {
+	Session Session `json:"session"`
+	Table   Table   `json:"table"`
+	Token   string  `json:"token"`
+}
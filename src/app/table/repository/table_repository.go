@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/app/table/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TableRepository interface {
+	Create(table *model.Table) error
+	GetByID(id uint) (*model.Table, error)
+	GetForUpdate(id uint) (*model.Table, error)
+	Update(table *model.Table) error
+	Delete(id uint) error
+	Find(q *database.Query) ([]model.Table, error)
+	WithTx(tx *gorm.DB) TableRepository
+}
+
+type tableRepository struct {
+	database.Repository[model.Table]
+}
+
+func NewTableRepository(db *gorm.DB) TableRepository {
+	return &tableRepository{Repository: database.NewRepository[model.Table](db)}
+}
+
+// GetForUpdate loads a table and locks its row until the surrounding
+// transaction ends
+func (r *tableRepository) GetForUpdate(id uint) (*model.Table, error) {
+	var table model.Table
+	if err := r.DB().Clauses(clause.Locking{Strength: "UPDATE"}).First(&table, id).Error; err != nil {
+		return nil, err
+	}
+	return &table, nil
+}
+
+func (r *tableRepository) WithTx(tx *gorm.DB) TableRepository {
+	return &tableRepository{Repository: r.Repository.WithTx(tx)}
+}
+
+type SessionRepository interface {
+	Create(session *model.Session) error
+	GetByID(id uint) (*model.Session, error)
+	Update(session *model.Session) error
+	// GetActive returns the unexpired, not ended session of a table
+	GetActive(tableID uint, now time.Time) (*model.Session, error)
+	// EndActive ends all open sessions of a table
+	EndActive(tableID uint, now time.Time) error
+	WithTx(tx *gorm.DB) SessionRepository
+}
+
+type sessionRepository struct {
+	database.Repository[model.Session]
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{Repository: database.NewRepository[model.Session](db)}
+}
+
+func (r *sessionRepository) GetActive(tableID uint, now time.Time) (*model.Session, error) {
+	return r.First(database.NewQuery().
+		Eq("table_id", tableID).
+		Where("ended_at", database.OpIsNull, nil).
+		Where("expires_at", database.OpGt, now).
+		OrderByDesc("id"))
+}
+
+func (r *sessionRepository) EndActive(tableID uint, now time.Time) error {
+	err := r.DB().Model(&model.Session{}).
+		Where("table_id = ? AND ended_at IS NULL", tableID).
+		Update("ended_at", now).Error
+	return database.TranslateError(err)
+}
+
+func (r *sessionRepository) WithTx(tx *gorm.DB) SessionRepository {
+	return &sessionRepository{Repository: r.Repository.WithTx(tx)}
+}
@@ -0,0 +1,374 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	restaurantmodel "github.com/faisd405/go-restapi-gin/src/app/restaurant/model"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	"github.com/faisd405/go-restapi-gin/src/app/table/model"
+	"github.com/faisd405/go-restapi-gin/src/app/table/repository"
+	usermodel "github.com/faisd405/go-restapi-gin/src/app/user/model"
+	"github.com/faisd405/go-restapi-gin/src/config"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidQRToken   = errors.New("table code is invalid or no longer in use")
+	ErrTableUnavailable = errors.New("table is not available for ordering")
+	ErrSessionEnded     = errors.New("table session has ended")
+	ErrInvalidStatus    = errors.New("unknown table status")
+)
+
+type TableService interface {
+	GetTables(actor utils.Actor, branchID uint, filter model.TableFilter) ([]model.Table, error)
+	GetTable(actor utils.Actor, id uint) (*model.Table, error)
+	CreateTable(actor utils.Actor, branchID uint, req model.TableRequest) (*model.Table, error)
+	UpdateTable(actor utils.Actor, id uint, req model.TableRequest) (*model.Table, error)
+	DeleteTable(actor utils.Actor, id uint) error
+	SetStatus(actor utils.Actor, id uint, req model.StatusRequest) (*model.Table, error)
+
+	GetQRCode(actor utils.Actor, id uint) (*model.QRCode, error)
+	RotateQRCode(actor utils.Actor, id uint) (*model.QRCode, error)
+
+	// StartSession opens or joins the ordering session of the table a QR
+	// token was issued for
+	StartSession(req model.StartSessionRequest) (*model.SessionToken, error)
+	// GetActiveSession returns a session that can still place orders
+	GetActiveSession(id uint) (*model.Session, error)
+	// GetBookableTables returns the active tables of a branch, smallest
+	// first
+	GetBookableTables(branchID uint) ([]model.Table, error)
+}
+
+type tableService struct {
+	tableRepo     repository.TableRepository
+	sessionRepo   repository.SessionRepository
+	restaurantSvc restaurantservice.RestaurantService
+	txManager     database.TxManager
+	cfg           config.TableConfig
+}
+
+func NewTableService(
+	tableRepo repository.TableRepository,
+	sessionRepo repository.SessionRepository,
+	restaurantSvc restaurantservice.RestaurantService,
+	txManager database.TxManager,
+	cfg config.TableConfig,
+) TableService {
+	return &tableService{
+		tableRepo:     tableRepo,
+		sessionRepo:   sessionRepo,
+		restaurantSvc: restaurantSvc,
+		txManager:     txManager,
+		cfg:           cfg,
+	}
+}
+
+func (s *tableService) GetTables(actor utils.Actor, branchID uint, filter model.TableFilter) ([]model.Table, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	q := database.NewQuery().Eq("branch_id", branchID)
+	if filter.Status != "" {
+		if !isValidStatus(filter.Status) {
+			return nil, ErrInvalidStatus
+		}
+		q.Eq("status", filter.Status)
+	}
+	if filter.Area != "" {
+		q.Eq("area", filter.Area)
+	}
+
+	return s.tableRepo.Find(q.OrderBy("area").OrderBy("name"))
+}
+
+func (s *tableService) GetTable(actor utils.Actor, id uint) (*model.Table, error) {
+	return s.getAccessibleTable(actor, id)
+}
+
+func (s *tableService) CreateTable(actor utils.Actor, branchID uint, req model.TableRequest) (*model.Table, error) {
+	if _, err := s.restaurantSvc.GetBranch(branchID, false); err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, branchID); err != nil {
+		return nil, err
+	}
+
+	table := &model.Table{
+		BranchID:  branchID,
+		Status:    model.StatusFree,
+		QRVersion: 1,
+		IsActive:  true,
+	}
+	applyTableRequest(table, req)
+
+	if err := s.tableRepo.Create(table); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+func (s *tableService) UpdateTable(actor utils.Actor, id uint, req model.TableRequest) (*model.Table, error) {
+	table, err := s.getAccessibleTable(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	applyTableRequest(table, req)
+	if err := s.tableRepo.Update(table); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+func (s *tableService) DeleteTable(actor utils.Actor, id uint) error {
+	table, err := s.getAccessibleTable(actor, id)
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		if err := s.sessionRepo.WithTx(tx).EndActive(table.ID, time.Now()); err != nil {
+			return err
+		}
+		return s.tableRepo.WithTx(tx).Delete(table.ID)
+	})
+}
+
+// SetStatus changes the status of a table. Freeing a table or sending it
+// to cleaning ends its ordering session so the next guests start afresh.
+func (s *tableService) SetStatus(actor utils.Actor, id uint, req model.StatusRequest) (*model.Table, error) {
+	if !isValidStatus(req.Status) {
+		return nil, ErrInvalidStatus
+	}
+	if _, err := s.getAccessibleTable(actor, id); err != nil {
+		return nil, err
+	}
+
+	var table *model.Table
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		tableRepo := s.tableRepo.WithTx(tx)
+
+		var err error
+		table, err = tableRepo.GetForUpdate(id)
+		if err != nil {
+			return err
+		}
+
+		if req.Status == model.StatusFree || req.Status == model.StatusCleaning {
+			if err := s.sessionRepo.WithTx(tx).EndActive(table.ID, time.Now()); err != nil {
+				return err
+			}
+		}
+
+		table.Status = req.Status
+		return tableRepo.Update(table)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
+func (s *tableService) GetQRCode(actor utils.Actor, id uint) (*model.QRCode, error) {
+	table, err := s.getAccessibleTable(actor, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.qrCode(table), nil
+}
+
+// RotateQRCode issues a new token for a table. Codes printed before stop
+// working; sessions already started are not affected.
+func (s *tableService) RotateQRCode(actor utils.Actor, id uint) (*model.QRCode, error) {
+	table, err := s.getAccessibleTable(actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	table.QRVersion++
+	if err := s.tableRepo.Update(table); err != nil {
+		return nil, err
+	}
+	return s.qrCode(table), nil
+}
+
+func (s *tableService) StartSession(req model.StartSessionRequest) (*model.SessionToken, error) {
+	tableID, version, ok := s.parseToken(req.Token)
+	if !ok {
+		return nil, ErrInvalidQRToken
+	}
+
+	var result *model.SessionToken
+	err := s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		tableRepo := s.tableRepo.WithTx(tx)
+		sessionRepo := s.sessionRepo.WithTx(tx)
+
+		// The row lock makes guests scanning at the same time join the
+		// same session
+		table, err := tableRepo.GetForUpdate(tableID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidQRToken
+		}
+		if err != nil {
+			return err
+		}
+		if table.QRVersion != version {
+			return ErrInvalidQRToken
+		}
+		if !table.IsActive || table.Status == model.StatusCleaning {
+			return ErrTableUnavailable
+		}
+
+		branch, err := s.restaurantSvc.GetBranch(table.BranchID, true)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTableUnavailable
+		}
+		if err != nil {
+			return err
+		}
+		if branch.Status != restaurantmodel.BranchStatusActive {
+			return ErrTableUnavailable
+		}
+
+		now := time.Now()
+		session, err := sessionRepo.GetActive(table.ID, now)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			session = &model.Session{
+				TableID:   table.ID,
+				BranchID:  table.BranchID,
+				StartedAt: now,
+				ExpiresAt: now.Add(s.cfg.SessionTTL),
+			}
+			if err := sessionRepo.Create(session); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			session.ExpiresAt = now.Add(s.cfg.SessionTTL)
+			if err := sessionRepo.Update(session); err != nil {
+				return err
+			}
+		}
+
+		if table.Status != model.StatusOccupied {
+			table.Status = model.StatusOccupied
+			if err := tableRepo.Update(table); err != nil {
+				return err
+			}
+		}
+
+		token, err := utils.GenerateTableSessionJWT(session.ID, table.ID, table.BranchID, usermodel.RoleGuest, session.ExpiresAt)
+		if err != nil {
+			return err
+		}
+
+		result = &model.SessionToken{Session: *session, Table: *table, Token: token}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *tableService) GetActiveSession(id uint) (*model.Session, error) {
+	session, err := s.sessionRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsActive(time.Now()) {
+		return nil, ErrSessionEnded
+	}
+	return session, nil
+}
+
+func (s *tableService) GetBookableTables(branchID uint) ([]model.Table, error) {
+	return s.tableRepo.Find(database.NewQuery().
+		Eq("branch_id", branchID).
+		Eq("is_active", true).
+		OrderBy("capacity").
+		OrderBy("name"))
+}
+
+// getAccessibleTable loads a table the actor works at
+func (s *tableService) getAccessibleTable(actor utils.Actor, id uint) (*model.Table, error) {
+	table, err := s.tableRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.CheckBranchAccess(s.restaurantSvc, actor, table.BranchID); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// qrCode builds the token "<table id>.<version>.<signature>" of a table
+func (s *tableService) qrCode(table *model.Table) *model.QRCode {
+	message := qrMessage(table.ID, table.QRVersion)
+	token := fmt.Sprintf("%d.%d.%s", table.ID, table.QRVersion, utils.Sign(s.cfg.QRSecret, message))
+
+	code := &model.QRCode{TableID: table.ID, Token: token}
+	if s.cfg.QRBaseURL != "" {
+		code.URL = s.cfg.QRBaseURL + token
+	}
+	return code
+}
+
+// parseToken verifies the signature of a QR token and returns the table
+// and version it was issued for
+func (s *tableService) parseToken(token string) (uint, int, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, 0, false
+	}
+
+	tableID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	version, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, false
+	}
+	if !utils.VerifySignature(s.cfg.QRSecret, qrMessage(uint(tableID), version), parts[2]) {
+		return 0, 0, false
+	}
+
+	return uint(tableID), version, true
+}
+
+func qrMessage(tableID uint, version int) string {
+	return fmt.Sprintf("table:%d:%d", tableID, version)
+}
+
+func applyTableRequest(table *model.Table, req model.TableRequest) {
+	table.Name = strings.TrimSpace(req.Name)
+	table.Capacity = req.Capacity
+	table.Area = strings.TrimSpace(req.Area)
+	if req.IsActive != nil {
+		table.IsActive = *req.IsActive
+	}
+}
+
+func isValidStatus(status string) bool {
+	switch status {
+	case model.StatusFree, model.StatusOccupied, model.StatusReserved, model.StatusCleaning:
+		return true
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -10,6 +11,9 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// ErrCodeEmailAlreadyExists is returned with 409 when registering a taken email
+const ErrCodeEmailAlreadyExists = "EMAIL_ALREADY_EXISTS"
+
 type UserController struct {
 	userService service.UserService
 }
@@ -27,6 +31,7 @@ func NewUserController(userService service.UserService) *UserController {
 // @Param user body model.RegisterRequest true "User registration data"
 // @Success 201 {object} utils.Response
 // @Failure 400 {object} utils.Response
+// @Failure 409 {object} utils.Response
 // @Router /auth/register [post]
 func (ctrl *UserController) Register(c *gin.Context) {
 	var req model.RegisterRequest
@@ -36,6 +41,10 @@ func (ctrl *UserController) Register(c *gin.Context) {
 	}
 
 	user, err := ctrl.userService.Register(req)
+	if errors.Is(err, service.ErrEmailAlreadyExists) {
+		utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeEmailAlreadyExists, "Registration failed", err.Error())
+		return
+	}
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, "Registration failed", err.Error())
 		return
@@ -230,3 +239,37 @@ func (ctrl *UserController) DeleteUser(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, "User deleted successfully", nil)
 }
+
+// UpdateUserRole godoc
+// @Summary Update user role (Admin only)
+// @Description Change the role of a user, e.g. to grant staff or manager access
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path int true "User ID"
+// @Param role body model.UpdateRoleRequest true "New role"
+// @Success 200 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/users/{id}/role [put]
+func (ctrl *UserController) UpdateUserRole(c *gin.Context) {
+	userID, err := utils.ParseID(c, "id")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	var req model.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	user, err := ctrl.userService.UpdateRole(userID, req)
+	if err != nil {
+		utils.DatabaseErrorResponse(c, "Role update failed", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User role updated successfully", user)
+}
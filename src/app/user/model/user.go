@@ -1,15 +1,29 @@
 package model
 
 import (
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// User roles. Staff and managers are linked to the branches they work at
+// through the restaurant module. Guest is the role of anonymous table
+// sessions and Station the role of kitchen station screens; neither is
+// ever stored on a user.
+const (
+	RoleUser    = "user"
+	RoleStaff   = "staff"
+	RoleManager = "manager"
+	RoleAdmin   = "admin"
+	RoleGuest   = "guest"
+	RoleStation = "station"
+)
+
 type User struct {
 	ID        uint           `json:"id" gorm:"primaryKey"`
 	Name      string         `json:"name" gorm:"not null" binding:"required"`
-	Email     string         `json:"email" gorm:"uniqueIndex;not null" binding:"required,email"`
+	Email     string         `json:"email" gorm:"uniqueIndex;uniqueIndex:idx_users_email_lower,expression:lower(email);not null" binding:"required,email"`
 	Password  string         `json:"-" gorm:"not null"`
 	Role      string         `json:"role" gorm:"default:user"`
 	IsActive  bool           `json:"is_active" gorm:"default:true"`
@@ -18,6 +32,11 @@ type User struct {
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
+// NormalizeEmail returns the canonical form emails are stored and looked up in
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
@@ -33,6 +52,10 @@ type UpdateUserRequest struct {
 	Name string `json:"name" binding:"required"`
 }
 
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user staff manager admin"`
+}
+
 type ChangePasswordRequest struct {
 	CurrentPassword string `json:"current_password" binding:"required"`
 	NewPassword     string `json:"new_password" binding:"required,min=6"`
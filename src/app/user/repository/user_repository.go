@@ -2,6 +2,7 @@ package repository
 
 import (
 	"github.com/faisd405/go-restapi-gin/src/app/user/model"
+	"github.com/faisd405/go-restapi-gin/src/database"
 	"gorm.io/gorm"
 )
 
@@ -13,59 +14,31 @@ type UserRepository interface {
 	Delete(id uint) error
 	GetAll(offset, limit int) ([]model.User, int64, error)
 	UpdatePassword(userID uint, hashedPassword string) error
+	WithTx(tx *gorm.DB) UserRepository
 }
 
 type userRepository struct {
-	db *gorm.DB
+	database.Repository[model.User]
 }
 
 func NewUserRepository(db *gorm.DB) UserRepository {
-	return &userRepository{db: db}
-}
-
-func (r *userRepository) Create(user *model.User) error {
-	return r.db.Create(user).Error
-}
-
-func (r *userRepository) GetByID(id uint) (*model.User, error) {
-	var user model.User
-	err := r.db.First(&user, id).Error
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
+	return &userRepository{Repository: database.NewRepository[model.User](db)}
 }
 
 func (r *userRepository) GetByEmail(email string) (*model.User, error) {
 	var user model.User
-	err := r.db.Where("email = ?", email).First(&user).Error
+	err := r.DB().Where("LOWER(email) = ?", model.NormalizeEmail(email)).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userRepository) Update(user *model.User) error {
-	return r.db.Save(user).Error
-}
-
-func (r *userRepository) Delete(id uint) error {
-	return r.db.Delete(&model.User{}, id).Error
-}
-
-func (r *userRepository) GetAll(offset, limit int) ([]model.User, int64, error) {
-	var users []model.User
-	var count int64
-	
-	err := r.db.Model(&model.User{}).Count(&count).Error
-	if err != nil {
-		return nil, 0, err
-	}
-	
-	err = r.db.Offset(offset).Limit(limit).Find(&users).Error
-	return users, count, err
+func (r *userRepository) UpdatePassword(userID uint, hashedPassword string) error {
+	err := r.DB().Model(&model.User{}).Where("id = ?", userID).Update("password", hashedPassword).Error
+	return database.TranslateError(err)
 }
 
-func (r *userRepository) UpdatePassword(userID uint, hashedPassword string) error {
-	return r.db.Model(&model.User{}).Where("id = ?", userID).Update("password", hashedPassword).Error
+func (r *userRepository) WithTx(tx *gorm.DB) UserRepository {
+	return &userRepository{Repository: r.Repository.WithTx(tx)}
 }
@@ -1,14 +1,20 @@
 package service
 
 import (
+	"database/sql"
 	"errors"
 
 	"github.com/faisd405/go-restapi-gin/src/app/user/model"
 	"github.com/faisd405/go-restapi-gin/src/app/user/repository"
+	"github.com/faisd405/go-restapi-gin/src/database"
 	"github.com/faisd405/go-restapi-gin/src/utils"
 	"gorm.io/gorm"
 )
 
+// ErrEmailAlreadyExists is returned when registering an email that is
+// already taken, compared case-insensitively
+var ErrEmailAlreadyExists = errors.New("user already exists with this email")
+
 type UserService interface {
 	Register(req model.RegisterRequest) (*model.User, error)
 	Login(req model.LoginRequest) (*model.LoginResponse, error)
@@ -17,42 +23,52 @@ type UserService interface {
 	ChangePassword(userID uint, req model.ChangePasswordRequest) error
 	GetAllUsers(page, limit int) ([]model.UserResponse, int64, error)
 	DeleteUser(userID uint) error
+	UpdateRole(userID uint, req model.UpdateRoleRequest) (*model.UserResponse, error)
 }
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo  repository.UserRepository
+	txManager database.TxManager
 }
 
-func NewUserService(userRepo repository.UserRepository) UserService {
-	return &userService{userRepo: userRepo}
+func NewUserService(userRepo repository.UserRepository, txManager database.TxManager) UserService {
+	return &userService{userRepo: userRepo, txManager: txManager}
 }
 
 func (s *userService) Register(req model.RegisterRequest) (*model.User, error) {
-	// Check if user already exists
-	existingUser, err := s.userRepo.GetByEmail(req.Email)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, err
-	}
-	if existingUser != nil {
-		return nil, errors.New("user already exists with this email")
-	}
-
 	// Hash password
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create user
 	user := &model.User{
 		Name:     req.Name,
-		Email:    req.Email,
+		Email:    model.NormalizeEmail(req.Email),
 		Password: hashedPassword,
-		Role:     "user",
+		Role:     model.RoleUser,
 		IsActive: true,
 	}
 
-	err = s.userRepo.Create(user)
+	// Check and insert in one serializable transaction so concurrent
+	// registrations for the same email cannot both pass the check. The
+	// unique index on lower(email) remains the final guard.
+	err = s.txManager.WithTransaction(func(tx *gorm.DB) error {
+		userRepo := s.userRepo.WithTx(tx)
+
+		existingUser, err := userRepo.GetByEmail(user.Email)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if existingUser != nil {
+			return ErrEmailAlreadyExists
+		}
+
+		return userRepo.Create(user)
+	}, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	if errors.Is(err, database.ErrUniqueViolation) {
+		return nil, ErrEmailAlreadyExists
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -169,3 +185,19 @@ func (s *userService) DeleteUser(userID uint) error {
 
 	return s.userRepo.Delete(userID)
 }
+
+func (s *userService) UpdateRole(userID uint, req model.UpdateRoleRequest) (*model.UserResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Role = req.Role
+	err = s.userRepo.Update(user)
+	if err != nil {
+		return nil, err
+	}
+
+	userResponse := user.ToResponse()
+	return &userResponse, nil
+}
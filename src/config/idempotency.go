@@ -0,0 +1,35 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// IdempotencyConfig holds the Idempotency-Key middleware settings
+type IdempotencyConfig struct {
+	Store   string
+	TTL     time.Duration
+	LockTTL time.Duration
+}
+
+// GetIdempotencyConfig reads the Idempotency-Key settings from the environment
+func GetIdempotencyConfig() IdempotencyConfig {
+	cfg := IdempotencyConfig{
+		Store:   os.Getenv("IDEMPOTENCY_STORE"),
+		TTL:     24 * time.Hour,
+		LockTTL: time.Minute,
+	}
+
+	if cfg.Store == "" {
+		cfg.Store = "postgres"
+	}
+	if hours, err := strconv.Atoi(os.Getenv("IDEMPOTENCY_TTL_HOURS")); err == nil && hours > 0 {
+		cfg.TTL = time.Duration(hours) * time.Hour
+	}
+	if seconds, err := strconv.Atoi(os.Getenv("IDEMPOTENCY_LOCK_SECONDS")); err == nil && seconds > 0 {
+		cfg.LockTTL = time.Duration(seconds) * time.Second
+	}
+
+	return cfg
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// KitchenConfig holds the kitchen station screen settings
+type KitchenConfig struct {
+	StationTokenTTL time.Duration
+	StreamTicketTTL time.Duration
+}
+
+// GetKitchenConfig reads the kitchen station screen settings from the
+// environment
+func GetKitchenConfig() KitchenConfig {
+	cfg := KitchenConfig{StationTokenTTL: 12 * time.Hour, StreamTicketTTL: 30 * time.Second}
+
+	if hours, err := strconv.Atoi(os.Getenv("KITCHEN_STATION_TOKEN_HOURS")); err == nil && hours > 0 {
+		cfg.StationTokenTTL = time.Duration(hours) * time.Hour
+	}
+	// Stream tickets travel in URLs, so they must not outlive a minute
+	if seconds, err := strconv.Atoi(os.Getenv("KITCHEN_STREAM_TICKET_SECONDS")); err == nil && seconds > 0 && seconds < 60 {
+		cfg.StreamTicketTTL = time.Duration(seconds) * time.Second
+	}
+
+	return cfg
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaymentConfig holds the payment provider settings
+type PaymentConfig struct {
+	Provider         string
+	WebhookSecret    []byte
+	WebhookTolerance time.Duration
+	PendingTimeout   time.Duration
+}
+
+// GetPaymentConfig reads the payment provider settings from the
+// environment. PAYMENT_PROVIDER only defaults to the fake provider in gin's
+// debug mode, so a release build never takes fake payments by accident.
+// PAYMENT_WEBHOOK_SECRET is always required.
+func GetPaymentConfig() PaymentConfig {
+	cfg := PaymentConfig{
+		Provider:         os.Getenv("PAYMENT_PROVIDER"),
+		WebhookTolerance: 5 * time.Minute,
+		PendingTimeout:   15 * time.Minute,
+	}
+
+	if cfg.Provider == "" {
+		if gin.Mode() != gin.DebugMode {
+			log.Fatal("PAYMENT_PROVIDER is required outside debug mode")
+		}
+		cfg.Provider = "fake"
+	}
+
+	secret := os.Getenv("PAYMENT_WEBHOOK_SECRET")
+	if secret == "" {
+		log.Fatal("PAYMENT_WEBHOOK_SECRET is required")
+	}
+	cfg.WebhookSecret = []byte(secret)
+
+	if seconds, err := strconv.Atoi(os.Getenv("PAYMENT_WEBHOOK_TOLERANCE_SECONDS")); err == nil && seconds > 0 {
+		cfg.WebhookTolerance = time.Duration(seconds) * time.Second
+	}
+	if minutes, err := strconv.Atoi(os.Getenv("PAYMENT_PENDING_TIMEOUT_MINUTES")); err == nil && minutes > 0 {
+		cfg.PendingTimeout = time.Duration(minutes) * time.Minute
+	}
+
+	return cfg
+}
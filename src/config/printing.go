@@ -0,0 +1,46 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// PrintingConfig holds the print queue settings
+type PrintingConfig struct {
+	SpoolDir     string
+	TCPTimeout   time.Duration
+	MaxAttempts  int
+	RetryDelay   time.Duration
+	PollInterval time.Duration
+}
+
+// GetPrintingConfig reads the print queue settings from the environment.
+// Failed jobs are retried after RetryDelay, doubling with every attempt.
+func GetPrintingConfig() PrintingConfig {
+	cfg := PrintingConfig{
+		SpoolDir:     os.Getenv("PRINT_SPOOL_DIR"),
+		TCPTimeout:   5 * time.Second,
+		MaxAttempts:  5,
+		RetryDelay:   10 * time.Second,
+		PollInterval: 2 * time.Second,
+	}
+
+	if cfg.SpoolDir == "" {
+		cfg.SpoolDir = "spool"
+	}
+	if seconds, err := strconv.Atoi(os.Getenv("PRINT_TCP_TIMEOUT_SECONDS")); err == nil && seconds > 0 {
+		cfg.TCPTimeout = time.Duration(seconds) * time.Second
+	}
+	if attempts, err := strconv.Atoi(os.Getenv("PRINT_MAX_ATTEMPTS")); err == nil && attempts > 0 {
+		cfg.MaxAttempts = attempts
+	}
+	if seconds, err := strconv.Atoi(os.Getenv("PRINT_RETRY_SECONDS")); err == nil && seconds > 0 {
+		cfg.RetryDelay = time.Duration(seconds) * time.Second
+	}
+	if seconds, err := strconv.Atoi(os.Getenv("PRINT_POLL_SECONDS")); err == nil && seconds > 0 {
+		cfg.PollInterval = time.Duration(seconds) * time.Second
+	}
+
+	return cfg
+}
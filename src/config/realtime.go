@@ -0,0 +1,34 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// RealtimeConfig holds the settings of the live event streams
+type RealtimeConfig struct {
+	ReplaySize int
+	BufferSize int
+	Heartbeat  time.Duration
+	Retry      time.Duration
+}
+
+// GetRealtimeConfig reads the live event stream settings from the environment
+func GetRealtimeConfig() RealtimeConfig {
+	cfg := RealtimeConfig{
+		ReplaySize: 500,
+		BufferSize: 64,
+		Heartbeat:  15 * time.Second,
+		Retry:      3 * time.Second,
+	}
+
+	if size, err := strconv.Atoi(os.Getenv("REALTIME_REPLAY_SIZE")); err == nil && size > 0 {
+		cfg.ReplaySize = size
+	}
+	if seconds, err := strconv.Atoi(os.Getenv("REALTIME_HEARTBEAT_SECONDS")); err == nil && seconds > 0 {
+		cfg.Heartbeat = time.Duration(seconds) * time.Second
+	}
+
+	return cfg
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// TableConfig holds the dine-in QR code settings
+type TableConfig struct {
+	QRSecret   []byte
+	QRBaseURL  string
+	SessionTTL time.Duration
+}
+
+// GetTableConfig reads the dine-in QR code settings from the environment.
+// TABLE_QR_SECRET is required; it is kept apart from JWT_SECRET so a leaked
+// QR secret cannot sign login tokens and the other way round.
+func GetTableConfig() TableConfig {
+	cfg := TableConfig{
+		QRBaseURL:  os.Getenv("TABLE_QR_BASE_URL"),
+		SessionTTL: 4 * time.Hour,
+	}
+
+	secret := os.Getenv("TABLE_QR_SECRET")
+	if secret == "" {
+		log.Fatal("TABLE_QR_SECRET is required")
+	}
+	cfg.QRSecret = []byte(secret)
+
+	if hours, err := strconv.Atoi(os.Getenv("TABLE_SESSION_HOURS")); err == nil && hours > 0 {
+		cfg.SessionTTL = time.Duration(hours) * time.Hour
+	}
+
+	return cfg
+}
@@ -0,0 +1,85 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Constraint violation kinds returned by TranslateError. Use errors.Is to
+// check for them; errors.As with *ConstraintError exposes the details.
+var (
+	ErrUniqueViolation     = errors.New("unique constraint violation")
+	ErrForeignKeyViolation = errors.New("foreign key constraint violation")
+	ErrCheckViolation      = errors.New("check constraint violation")
+	ErrNotNullViolation    = errors.New("not null constraint violation")
+	ErrExclusionViolation  = errors.New("exclusion constraint violation")
+)
+
+// Postgres SQLSTATE codes of the integrity constraint violation class
+const (
+	sqlStateNotNullViolation    = "23502"
+	sqlStateForeignKeyViolation = "23503"
+	sqlStateUniqueViolation     = "23505"
+	sqlStateCheckViolation      = "23514"
+	sqlStateExclusionViolation  = "23P01"
+)
+
+// ConstraintError describes a violated database constraint without leaking
+// the raw driver message to API clients
+type ConstraintError struct {
+	Kind       error
+	Table      string
+	Column     string
+	Constraint string
+	Err        error
+}
+
+func (e *ConstraintError) Error() string {
+	switch {
+	case e.Constraint != "":
+		return fmt.Sprintf("%s: %s", e.Kind, e.Constraint)
+	case e.Column != "":
+		return fmt.Sprintf("%s: %s.%s", e.Kind, e.Table, e.Column)
+	default:
+		return e.Kind.Error()
+	}
+}
+
+func (e *ConstraintError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// TranslateError converts Postgres integrity constraint violations into a
+// *ConstraintError. Other errors, including nil, are returned unchanged.
+func TranslateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	var kind error
+	switch pgErr.Code {
+	case sqlStateUniqueViolation:
+		kind = ErrUniqueViolation
+	case sqlStateForeignKeyViolation:
+		kind = ErrForeignKeyViolation
+	case sqlStateCheckViolation:
+		kind = ErrCheckViolation
+	case sqlStateNotNullViolation:
+		kind = ErrNotNullViolation
+	case sqlStateExclusionViolation:
+		kind = ErrExclusionViolation
+	default:
+		return err
+	}
+
+	return &ConstraintError{
+		Kind:       kind,
+		Table:      pgErr.TableName,
+		Column:     pgErr.ColumnName,
+		Constraint: pgErr.ConstraintName,
+		Err:        err,
+	}
+}
@@ -0,0 +1,190 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidField is returned when a filter or sort references a column name
+// that is not a plain identifier
+var ErrInvalidField = errors.New("invalid field name")
+
+var fieldPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)?$`)
+
+// Operator is the comparison applied by a Filter
+type Operator string
+
+const (
+	OpEq      Operator = "="
+	OpNotEq   Operator = "<>"
+	OpGt      Operator = ">"
+	OpGte     Operator = ">="
+	OpLt      Operator = "<"
+	OpLte     Operator = "<="
+	OpLike    Operator = "LIKE"
+	OpILike   Operator = "ILIKE"
+	OpIn      Operator = "IN"
+	OpNotIn   Operator = "NOT IN"
+	OpIsNull  Operator = "IS NULL"
+	OpNotNull Operator = "IS NOT NULL"
+)
+
+// Filter restricts a query to rows where Field compares to Value using Op
+type Filter struct {
+	Field string
+	Op    Operator
+	Value interface{}
+}
+
+// Sort orders a query by Field
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Query is a composable specification of filters, sorting, preloads and
+// pagination understood by Repository. A nil *Query matches every row.
+type Query struct {
+	Filters  []Filter
+	Sorts    []Sort
+	Preloads []string
+	Unscoped bool
+	Offset   int
+	Limit    int
+}
+
+// NewQuery returns an empty query
+func NewQuery() *Query {
+	return &Query{}
+}
+
+// Where adds a filter to the query
+func (q *Query) Where(field string, op Operator, value interface{}) *Query {
+	q.Filters = append(q.Filters, Filter{Field: field, Op: op, Value: value})
+	return q
+}
+
+// Eq adds an equality filter to the query
+func (q *Query) Eq(field string, value interface{}) *Query {
+	return q.Where(field, OpEq, value)
+}
+
+// In adds a membership filter to the query
+func (q *Query) In(field string, values interface{}) *Query {
+	return q.Where(field, OpIn, values)
+}
+
+// OrderBy sorts the query ascending by field
+func (q *Query) OrderBy(field string) *Query {
+	q.Sorts = append(q.Sorts, Sort{Field: field})
+	return q
+}
+
+// OrderByDesc sorts the query descending by field
+func (q *Query) OrderByDesc(field string) *Query {
+	q.Sorts = append(q.Sorts, Sort{Field: field, Desc: true})
+	return q
+}
+
+// Preload eager loads the given associations
+func (q *Query) Preload(associations ...string) *Query {
+	q.Preloads = append(q.Preloads, associations...)
+	return q
+}
+
+// WithDeleted includes soft deleted rows in the results
+func (q *Query) WithDeleted() *Query {
+	q.Unscoped = true
+	return q
+}
+
+// Paginate limits the query to a window of rows
+func (q *Query) Paginate(offset, limit int) *Query {
+	q.Offset = offset
+	q.Limit = limit
+	return q
+}
+
+// Merge appends the filters, sorts and preloads of other to q. Pagination
+// and Unscoped from other override q when set.
+func (q *Query) Merge(other *Query) *Query {
+	if other == nil {
+		return q
+	}
+	q.Filters = append(q.Filters, other.Filters...)
+	q.Sorts = append(q.Sorts, other.Sorts...)
+	q.Preloads = append(q.Preloads, other.Preloads...)
+	if other.Unscoped {
+		q.Unscoped = true
+	}
+	if other.Limit > 0 {
+		q.Offset = other.Offset
+		q.Limit = other.Limit
+	}
+	return q
+}
+
+// scope applies the soft delete mode and filters only, so the same scope can
+// be used for counting and for fetching rows
+func (q *Query) scope(db *gorm.DB) (*gorm.DB, error) {
+	if q == nil {
+		return db, nil
+	}
+
+	if q.Unscoped {
+		db = db.Unscoped()
+	}
+
+	for _, f := range q.Filters {
+		if !fieldPattern.MatchString(f.Field) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidField, f.Field)
+		}
+
+		switch f.Op {
+		case OpIsNull, OpNotNull:
+			db = db.Where(fmt.Sprintf("%s %s", f.Field, f.Op))
+		case OpIn, OpNotIn:
+			db = db.Where(fmt.Sprintf("%s %s (?)", f.Field, f.Op), f.Value)
+		case OpEq, OpNotEq, OpGt, OpGte, OpLt, OpLte, OpLike, OpILike:
+			db = db.Where(fmt.Sprintf("%s %s ?", f.Field, f.Op), f.Value)
+		default:
+			return nil, fmt.Errorf("unsupported operator %q", f.Op)
+		}
+	}
+
+	return db, nil
+}
+
+func (q *Query) apply(db *gorm.DB) (*gorm.DB, error) {
+	db, err := q.scope(db)
+	if err != nil || q == nil {
+		return db, err
+	}
+
+	for _, association := range q.Preloads {
+		db = db.Preload(association)
+	}
+
+	for _, s := range q.Sorts {
+		if !fieldPattern.MatchString(s.Field) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidField, s.Field)
+		}
+		if s.Desc {
+			db = db.Order(s.Field + " DESC")
+		} else {
+			db = db.Order(s.Field)
+		}
+	}
+
+	if q.Offset > 0 {
+		db = db.Offset(q.Offset)
+	}
+	if q.Limit > 0 {
+		db = db.Limit(q.Limit)
+	}
+
+	return db, nil
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository implements the common CRUD operations for a GORM model T.
+// Domain repositories embed it and only add their custom queries. Write
+// errors are passed through TranslateError.
+type Repository[T any] struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a Repository for model T
+func NewRepository[T any](db *gorm.DB) Repository[T] {
+	return Repository[T]{db: db}
+}
+
+// DB returns the underlying connection for custom queries
+func (r *Repository[T]) DB() *gorm.DB {
+	return r.db
+}
+
+// WithTx returns a copy of the repository that runs its queries in tx
+func (r *Repository[T]) WithTx(tx *gorm.DB) Repository[T] {
+	return Repository[T]{db: tx}
+}
+
+func (r *Repository[T]) Create(entity *T) error {
+	return TranslateError(r.db.Create(entity).Error)
+}
+
+func (r *Repository[T]) GetByID(id uint) (*T, error) {
+	return r.FindByID(id, nil)
+}
+
+// FindByID loads a single row by primary key, honoring the preloads and
+// soft delete mode of q
+func (r *Repository[T]) FindByID(id uint, q *Query) (*T, error) {
+	db, err := q.apply(r.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var entity T
+	if err := db.First(&entity, id).Error; err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+func (r *Repository[T]) Update(entity *T) error {
+	return TranslateError(r.db.Save(entity).Error)
+}
+
+// Delete soft deletes the row when T has a gorm.DeletedAt field
+func (r *Repository[T]) Delete(id uint) error {
+	var entity T
+	return TranslateError(r.db.Delete(&entity, id).Error)
+}
+
+// ForceDelete permanently removes the row, bypassing soft delete
+func (r *Repository[T]) ForceDelete(id uint) error {
+	var entity T
+	return TranslateError(r.db.Unscoped().Delete(&entity, id).Error)
+}
+
+func (r *Repository[T]) GetAll(offset, limit int) ([]T, int64, error) {
+	return r.FindPage(NewQuery().OrderBy("id").Paginate(offset, limit))
+}
+
+// First returns the first row matching q
+func (r *Repository[T]) First(q *Query) (*T, error) {
+	db, err := q.apply(r.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var entity T
+	if err := db.First(&entity).Error; err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// Find returns every row matching q
+func (r *Repository[T]) Find(q *Query) ([]T, error) {
+	db, err := q.apply(r.db)
+	if err != nil {
+		return nil, err
+	}
+
+	var entities []T
+	err = db.Find(&entities).Error
+	return entities, err
+}
+
+// FindPage returns the rows in the window of q together with the total
+// number of rows matching its filters
+func (r *Repository[T]) FindPage(q *Query) ([]T, int64, error) {
+	count, err := r.Count(q)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entities, err := r.Find(q)
+	return entities, count, err
+}
+
+// Count returns the number of rows matching the filters of q
+func (r *Repository[T]) Count(q *Query) (int64, error) {
+	var entity T
+	db, err := q.scope(r.db.Model(&entity))
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = db.Count(&count).Error
+	return count, err
+}
+
+// Exists reports whether at least one row matches the filters of q
+func (r *Repository[T]) Exists(q *Query) (bool, error) {
+	var entity T
+	db, err := q.scope(r.db.Model(&entity))
+	if err != nil {
+		return false, err
+	}
+
+	var found int
+	err = db.Select("1").Limit(1).Scan(&found).Error
+	return found == 1, err
+}
+
+// Upsert inserts entity or, when it conflicts on conflictColumns, updates
+// updateColumns of the existing row. With no updateColumns every column
+// is updated.
+func (r *Repository[T]) Upsert(entity *T, conflictColumns []string, updateColumns ...string) error {
+	onConflict := clause.OnConflict{}
+	for _, column := range conflictColumns {
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: column})
+	}
+
+	if len(updateColumns) > 0 {
+		onConflict.DoUpdates = clause.AssignmentColumns(updateColumns)
+	} else {
+		onConflict.UpdateAll = true
+	}
+
+	return TranslateError(r.db.Clauses(onConflict).Create(entity).Error)
+}
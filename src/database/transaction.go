@@ -0,0 +1,92 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultMaxRetries = 3
+	retryBaseDelay    = 20 * time.Millisecond
+)
+
+// Postgres SQLSTATE codes that mean the transaction can safely be retried
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// TxManager runs a unit of work in a single database transaction. Every
+// repository taking part in the work is rebound to the same tx with its
+// WithTx method.
+type TxManager interface {
+	// WithTransaction runs fn inside a transaction. The transaction is rolled
+	// back when fn returns an error or panics and committed otherwise. When
+	// the manager is already bound to an open transaction a savepoint is used
+	// instead, so units of work can be nested.
+	WithTransaction(fn func(tx *gorm.DB) error, opts ...*sql.TxOptions) error
+	// WithTx returns a manager bound to tx, for nesting units of work
+	WithTx(tx *gorm.DB) TxManager
+}
+
+type txManager struct {
+	db         *gorm.DB
+	maxRetries int
+}
+
+func NewTxManager(db *gorm.DB) TxManager {
+	return &txManager{db: db, maxRetries: defaultMaxRetries}
+}
+
+func (m *txManager) WithTx(tx *gorm.DB) TxManager {
+	return &txManager{db: tx, maxRetries: m.maxRetries}
+}
+
+func (m *txManager) WithTransaction(fn func(tx *gorm.DB) error, opts ...*sql.TxOptions) error {
+	// Inside an open transaction gorm uses a savepoint. A serialization
+	// failure aborts the whole outer transaction, so only the outermost
+	// call retries.
+	if InTransaction(m.db) {
+		return m.db.Transaction(fn)
+	}
+
+	var err error
+	for attempt := 0; attempt <= m.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(retryDelay(attempt))
+		}
+
+		err = m.db.Transaction(fn, opts...)
+		if !IsRetryable(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// InTransaction reports whether db is bound to an open transaction
+func InTransaction(db *gorm.DB) bool {
+	committer, ok := db.Statement.ConnPool.(gorm.TxCommitter)
+	return ok && committer != nil
+}
+
+// IsRetryable reports whether err is a serialization failure or deadlock
+// that is expected to succeed when the transaction is run again
+func IsRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == sqlStateSerializationFailure || pgErr.Code == sqlStateDeadlockDetected
+}
+
+func retryDelay(attempt int) time.Duration {
+	backoff := retryBaseDelay * time.Duration(1<<(attempt-1))
+	return backoff + time.Duration(rand.Int63n(int64(retryBaseDelay)))
+}
@@ -0,0 +1,127 @@
+// Package escpos builds the byte streams thermal receipt printers take.
+// It covers the commands common to ESC/POS printers: text styles,
+// alignment, character size, feeding and cutting. Text is encoded as code
+// page 1252, so characters outside Western European scripts are replaced
+// with "?".
+package escpos
+
+import (
+	"bytes"
+)
+
+const (
+	esc = 0x1B
+	gs  = 0x1D
+)
+
+// Alignment of the lines that follow
+type Align byte
+
+const (
+	AlignLeft   Align = 0
+	AlignCenter Align = 1
+	AlignRight  Align = 2
+)
+
+// codePage1252 selects Windows-1252 on Epson compatible printers
+const codePage1252 = 16
+
+// Builder collects the commands of a print job
+type Builder struct {
+	buf bytes.Buffer
+}
+
+// New starts a job by resetting the printer and selecting code page 1252
+func New() *Builder {
+	b := &Builder{}
+	b.buf.Write([]byte{esc, '@', esc, 't', codePage1252})
+	return b
+}
+
+// Bold switches emphasized printing on or off
+func (b *Builder) Bold(on bool) *Builder {
+	b.buf.Write([]byte{esc, 'E', flag(on)})
+	return b
+}
+
+// Align sets the alignment of the following lines
+func (b *Builder) Align(align Align) *Builder {
+	b.buf.Write([]byte{esc, 'a', byte(align)})
+	return b
+}
+
+// Size sets the character width and height multipliers, 1 to 8. A line
+// holds proportionally fewer characters at larger widths.
+func (b *Builder) Size(width, height int) *Builder {
+	b.buf.Write([]byte{gs, '!', byte((clamp(width)-1)<<4 | (clamp(height) - 1))})
+	return b
+}
+
+// Text writes text without ending the line
+func (b *Builder) Text(text string) *Builder {
+	b.buf.Write(encode(text))
+	return b
+}
+
+// Line writes text and ends the line
+func (b *Builder) Line(text string) *Builder {
+	b.buf.Write(encode(text))
+	b.buf.WriteByte('\n')
+	return b
+}
+
+// Feed advances the paper by n lines
+func (b *Builder) Feed(n int) *Builder {
+	b.buf.Write([]byte{esc, 'd', byte(max(0, min(n, 255)))})
+	return b
+}
+
+// Cut feeds the paper past the cutter and cuts it, leaving a small hinge
+// on printers without a full cut
+func (b *Builder) Cut() *Builder {
+	b.buf.Write([]byte{gs, 'V', 66, 0})
+	return b
+}
+
+// Bytes returns the commands written so far
+func (b *Builder) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+func flag(on bool) byte {
+	if on {
+		return 1
+	}
+	return 0
+}
+
+func clamp(n int) int {
+	return max(1, min(n, 8))
+}
+
+// cp1252 maps the characters Windows-1252 places in 0x80-0x9F
+var cp1252 = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encode converts text to code page 1252. Control characters are dropped
+// so text cannot smuggle in printer commands.
+func encode(text string) []byte {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		switch {
+		case r < 0x20 || r == 0x7F:
+			continue
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		case cp1252[r] != 0:
+			out = append(out, cp1252[r])
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
@@ -0,0 +1,149 @@
+package gateway
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FakeTokenDeclined is the card token the fake provider declines. Every
+// other token is accepted.
+const FakeTokenDeclined = "tok_declined"
+
+type fakePayment struct {
+	authorized int64
+	captured   int64
+	refunded   int64
+	voided     bool
+}
+
+type fakeProvider struct {
+	mu       sync.Mutex
+	seq      int
+	payments map[string]*fakePayment
+	auths    map[string]*Result
+	refunds  map[string]*Result
+	now      func() time.Time
+}
+
+// NewFakeProvider creates a PaymentProvider kept in process memory that
+// approves every token but FakeTokenDeclined. It is meant for development
+// and tests; payments are lost on restart.
+func NewFakeProvider() PaymentProvider {
+	return &fakeProvider{
+		payments: map[string]*fakePayment{},
+		auths:    map[string]*Result{},
+		refunds:  map[string]*Result{},
+		now:      time.Now,
+	}
+}
+
+func (p *fakeProvider) Name() string {
+	return "fake"
+}
+
+// Authorize reserves an amount. Repeating a request reference returns the
+// first result instead of authorizing twice.
+func (p *fakeProvider) Authorize(req AuthorizeRequest) (*Result, error) {
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if req.Token == FakeTokenDeclined {
+		return nil, ErrDeclined
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if result, ok := p.auths[req.Reference]; ok {
+		return result, nil
+	}
+
+	p.seq++
+	reference := fmt.Sprintf("fake_pay_%d", p.seq)
+	p.payments[reference] = &fakePayment{authorized: req.Amount}
+	result := &Result{Reference: reference, Amount: req.Amount, ProcessedAt: p.now()}
+	p.auths[req.Reference] = result
+	return result, nil
+}
+
+func (p *fakeProvider) Capture(reference string, amount int64) (*Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[reference]
+	if !ok {
+		return nil, ErrUnknownReference
+	}
+	if payment.voided || payment.captured > 0 {
+		return nil, ErrInvalidState
+	}
+	if amount <= 0 || amount > payment.authorized {
+		return nil, ErrInvalidAmount
+	}
+
+	payment.captured = amount
+	return &Result{Reference: reference, Amount: amount, ProcessedAt: p.now()}, nil
+}
+
+func (p *fakeProvider) Void(reference string) (*Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payment, ok := p.payments[reference]
+	if !ok {
+		return nil, ErrUnknownReference
+	}
+	if payment.captured > 0 {
+		return nil, ErrInvalidState
+	}
+
+	payment.voided = true
+	return &Result{Reference: reference, Amount: payment.authorized, ProcessedAt: p.now()}, nil
+}
+
+// Refund returns amount of a captured payment. Repeating a refund
+// reference returns the first result instead of refunding twice.
+func (p *fakeProvider) Refund(reference string, amount int64, refundReference string) (*Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if result, ok := p.refunds[refundReference]; ok {
+		return result, nil
+	}
+
+	payment, ok := p.payments[reference]
+	if !ok {
+		return nil, ErrUnknownReference
+	}
+	if payment.captured == 0 {
+		return nil, ErrInvalidState
+	}
+	if amount <= 0 || amount > payment.captured-payment.refunded {
+		return nil, ErrInvalidAmount
+	}
+
+	payment.refunded += amount
+	p.seq++
+	result := &Result{Reference: fmt.Sprintf("fake_ref_%d", p.seq), Amount: amount, ProcessedAt: p.now()}
+	p.refunds[refundReference] = result
+	return result, nil
+}
+
+func (p *fakeProvider) Lookup(requestReference string) (*Authorization, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result, ok := p.auths[requestReference]
+	if !ok {
+		return nil, ErrUnknownReference
+	}
+
+	payment := p.payments[result.Reference]
+	return &Authorization{
+		Reference:  result.Reference,
+		Authorized: payment.authorized,
+		Captured:   payment.captured,
+		Voided:     payment.voided,
+	}, nil
+}
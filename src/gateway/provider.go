@@ -0,0 +1,56 @@
+package gateway
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrDeclined         = errors.New("payment was declined")
+	ErrUnknownReference = errors.New("unknown payment reference")
+	ErrInvalidAmount    = errors.New("amount exceeds what the payment allows")
+	ErrInvalidState     = errors.New("payment cannot be changed in its current state")
+)
+
+// AuthorizeRequest asks a provider to reserve an amount on a payment
+// method. Reference is our own ID of the payment and lets providers drop
+// duplicate requests.
+type AuthorizeRequest struct {
+	Amount    int64
+	Currency  string
+	Token     string
+	Reference string
+}
+
+// Result is the outcome of a provider call. Reference identifies the
+// payment at the provider; refunds get a reference of their own.
+type Result struct {
+	Reference   string
+	Amount      int64
+	ProcessedAt time.Time
+}
+
+// Authorization is what a provider holds for an authorize request: the
+// amounts authorized and captured, tip included, and whether it was
+// voided
+type Authorization struct {
+	Reference  string
+	Authorized int64
+	Captured   int64
+	Voided     bool
+}
+
+// PaymentProvider is a card payment gateway. Amounts are in the minor unit
+// of the currency. Calls fail with ErrDeclined when the issuer refuses the
+// payment and with ErrInvalidAmount or ErrInvalidState when the request
+// does not fit the payment. Lookup finds the authorization made for a
+// request reference, or fails with ErrUnknownReference when the provider
+// never authorized it.
+type PaymentProvider interface {
+	Name() string
+	Authorize(req AuthorizeRequest) (*Result, error)
+	Capture(reference string, amount int64) (*Result, error)
+	Void(reference string) (*Result, error)
+	Refund(reference string, amount int64, refundReference string) (*Result, error)
+	Lookup(requestReference string) (*Authorization, error)
+}
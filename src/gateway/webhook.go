@@ -0,0 +1,55 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/utils"
+)
+
+// ErrInvalidSignature is returned for webhooks whose signature is missing,
+// wrong or too old
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// SignWebhook returns the signature header "t=<unix time>,v1=<signature>"
+// of a webhook body sent at t. The signature is the HMAC of "<unix
+// time>.<body>" so old deliveries cannot be replayed with a new timestamp.
+func SignWebhook(secret []byte, body []byte, t time.Time) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, utils.Sign(secret, timestamp+"."+string(body)))
+}
+
+// VerifyWebhook checks a signature header made by SignWebhook. Deliveries
+// signed more than tolerance before or after now are rejected.
+func VerifyWebhook(secret []byte, header string, body []byte, tolerance time.Duration, now time.Time) error {
+	var timestamp, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature = value
+		}
+	}
+	if timestamp == "" || signature == "" {
+		return ErrInvalidSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	age := now.Sub(time.Unix(unix, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrInvalidSignature
+	}
+
+	if !utils.VerifySignature(secret, timestamp+"."+string(body), signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
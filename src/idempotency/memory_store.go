@@ -0,0 +1,80 @@
+package idempotency
+
+import (
+	"sync"
+	"time"
+)
+
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]*Record
+	now     func() time.Time
+}
+
+// NewMemoryStore creates a Store kept in process memory. It is meant for
+// development and single instance deployments.
+func NewMemoryStore() Store {
+	return &memoryStore{records: map[string]*Record{}, now: time.Now}
+}
+
+func (s *memoryStore) Reserve(key, fingerprint string, lockTTL, ttl time.Duration) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.evictExpired(now)
+
+	if existing, ok := s.records[key]; ok && existing.Active(now) {
+		found := *existing
+		return &found, nil
+	}
+
+	s.records[key] = &Record{
+		Key:         key,
+		Fingerprint: fingerprint,
+		Status:      StatusInProgress,
+		LockedUntil: now.Add(lockTTL),
+		ExpiresAt:   now.Add(ttl),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	return nil, nil
+}
+
+func (s *memoryStore) Complete(key string, status int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[key]
+	if !ok || record.Status != StatusInProgress {
+		return ErrNotReserved
+	}
+
+	record.Status = StatusCompleted
+	record.ResponseStatus = status
+	record.ResponseType = contentType
+	record.ResponseBody = append([]byte(nil), body...)
+	record.UpdatedAt = s.now()
+	return nil
+}
+
+func (s *memoryStore) Release(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[key]
+	if !ok || record.Status != StatusInProgress {
+		return ErrNotReserved
+	}
+
+	delete(s.records, key)
+	return nil
+}
+
+func (s *memoryStore) evictExpired(now time.Time) {
+	for key, record := range s.records {
+		if !now.Before(record.ExpiresAt) {
+			delete(s.records, key)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package idempotency
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresStore struct {
+	db *gorm.DB
+}
+
+// NewPostgresStore creates a Store backed by the idempotency_keys table,
+// shared by every instance of the API
+func NewPostgresStore(db *gorm.DB) Store {
+	return &postgresStore{db: db}
+}
+
+func (s *postgresStore) Reserve(key, fingerprint string, lockTTL, ttl time.Duration) (*Record, error) {
+	now := time.Now()
+	record := Record{
+		Key:         key,
+		Fingerprint: fingerprint,
+		Status:      StatusInProgress,
+		LockedUntil: now.Add(lockTTL),
+		ExpiresAt:   now.Add(ttl),
+	}
+
+	result := s.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil, nil
+	}
+
+	var existing Record
+	if err := s.db.Where("key = ?", key).First(&existing).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Released between the insert and the read; let the client retry
+			return &Record{Key: key, Fingerprint: fingerprint, Status: StatusInProgress}, nil
+		}
+		return nil, err
+	}
+	if existing.Active(now) {
+		return &existing, nil
+	}
+
+	// Take over an expired or abandoned record. Matching on updated_at makes
+	// sure only one of several concurrent takeovers wins.
+	result = s.db.Model(&Record{}).
+		Where("key = ? AND updated_at = ?", key, existing.UpdatedAt).
+		Updates(map[string]interface{}{
+			"fingerprint":     fingerprint,
+			"status":          StatusInProgress,
+			"response_status": 0,
+			"response_type":   "",
+			"response_body":   nil,
+			"locked_until":    record.LockedUntil,
+			"expires_at":      record.ExpiresAt,
+			"updated_at":      now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return &Record{Key: key, Fingerprint: fingerprint, Status: StatusInProgress}, nil
+	}
+
+	return nil, nil
+}
+
+func (s *postgresStore) Complete(key string, status int, contentType string, body []byte) error {
+	result := s.db.Model(&Record{}).
+		Where("key = ? AND status = ?", key, StatusInProgress).
+		Updates(map[string]interface{}{
+			"status":          StatusCompleted,
+			"response_status": status,
+			"response_type":   contentType,
+			"response_body":   body,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotReserved
+	}
+	return nil
+}
+
+func (s *postgresStore) Release(key string) error {
+	result := s.db.Where("key = ? AND status = ?", key, StatusInProgress).Delete(&Record{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotReserved
+	}
+	return nil
+}
+
+// StartCleanup periodically deletes records past their TTL from the
+// idempotency_keys table
+func StartCleanup(db *gorm.DB, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			result := db.Where("expires_at <= ?", time.Now()).Delete(&Record{})
+			if result.Error != nil {
+				log.Println("Failed to delete expired idempotency keys:", result.Error)
+			}
+		}
+	}()
+}
@@ -0,0 +1,58 @@
+package idempotency
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotReserved is returned when completing or releasing a key that is not
+// held by an in-flight request
+var ErrNotReserved = errors.New("idempotency key is not reserved")
+
+// Record status values
+const (
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+)
+
+// Record is the stored state of an idempotency key
+type Record struct {
+	Key            string    `json:"key" gorm:"primaryKey;type:varchar(255)"`
+	Fingerprint    string    `json:"fingerprint" gorm:"type:varchar(64);not null"`
+	Status         string    `json:"status" gorm:"type:varchar(20);not null"`
+	ResponseStatus int       `json:"response_status"`
+	ResponseType   string    `json:"response_type" gorm:"type:varchar(255)"`
+	ResponseBody   []byte    `json:"response_body"`
+	LockedUntil    time.Time `json:"locked_until"`
+	ExpiresAt      time.Time `json:"expires_at" gorm:"index;not null"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func (Record) TableName() string {
+	return "idempotency_keys"
+}
+
+// Active reports whether the record still applies at now. Expired records
+// and in-progress records whose lock has lapsed, because the request that
+// held them crashed, may be taken over by a new request.
+func (r *Record) Active(now time.Time) bool {
+	if !now.Before(r.ExpiresAt) {
+		return false
+	}
+	if r.Status == StatusInProgress && !now.Before(r.LockedUntil) {
+		return false
+	}
+	return true
+}
+
+// Store persists idempotency records
+type Store interface {
+	// Reserve atomically claims key for an in-flight request. It returns nil
+	// when the key was claimed, or the active record already holding it.
+	Reserve(key, fingerprint string, lockTTL, ttl time.Duration) (*Record, error)
+	// Complete stores the response of the request holding key
+	Complete(key string, status int, contentType string, body []byte) error
+	// Release drops the reservation on key so the request can be retried
+	Release(key string) error
+}
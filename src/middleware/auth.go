@@ -8,8 +8,21 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware validates JWT tokens
+// AuthMiddleware validates JWT tokens. Station screen tokens are rejected;
+// they only reach the routes behind StationAuthMiddleware.
 func AuthMiddleware() gin.HandlerFunc {
+	return authMiddleware(false)
+}
+
+// StationAuthMiddleware authenticates like AuthMiddleware but also accepts
+// station screen tokens, for the routes station screens work on
+func StationAuthMiddleware() gin.HandlerFunc {
+	return authMiddleware(true)
+}
+
+// authMiddleware validates JWT tokens, accepting station screen tokens
+// when stations is set
+func authMiddleware(stations bool) gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -34,10 +47,83 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// Set user info in context
-		c.Set("userID", claims.UserID)
-		c.Set("userEmail", claims.Email)
-		c.Set("userRole", claims.Role)
+		// Stream tickets only open their event stream
+		if claims.Stream != "" {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid token", "stream tickets only open event streams")
+			c.Abort()
+			return
+		}
+
+		// Station screens carry the user who opened them but must not act
+		// as that user outside the station routes
+		if claims.StationID != 0 && !stations {
+			utils.ErrorResponse(c, http.StatusForbidden, "Access denied", "station tokens only reach station routes")
+			c.Abort()
+			return
+		}
+
+		setClaims(c, claims)
+		c.Next()
+	})
+}
+
+// setClaims sets the user info of claims in context
+func setClaims(c *gin.Context, claims *utils.Claims) {
+	c.Set("userID", claims.UserID)
+	c.Set("userEmail", claims.Email)
+	c.Set("userRole", claims.Role)
+	if claims.TableSessionID != 0 {
+		c.Set("tableSessionID", claims.TableSessionID)
+		c.Set("tableID", claims.TableID)
+	}
+	if claims.StationID != 0 {
+		c.Set("stationID", claims.StationID)
+		c.Set("branchID", claims.BranchID)
+	}
+}
+
+// OptionalAuthMiddleware sets the user info like AuthMiddleware when a
+// token is sent and lets anonymous requests through. Invalid tokens are
+// still rejected so clients notice expired logins.
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	auth := AuthMiddleware()
+	return gin.HandlerFunc(func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
+			return
+		}
+		auth(c)
+	})
+}
+
+// StreamAuthMiddleware authenticates like StationAuthMiddleware but also accepts
+// a stream ticket in the ticket query, since browser EventSource clients
+// cannot send an Authorization header. A ticket only opens the stream of
+// kind whose ID is in the id path parameter; login tokens are never
+// accepted in the URL.
+func StreamAuthMiddleware(kind string) gin.HandlerFunc {
+	auth := StationAuthMiddleware()
+	return gin.HandlerFunc(func(c *gin.Context) {
+		ticket := c.Query("ticket")
+		if c.GetHeader("Authorization") != "" || ticket == "" {
+			auth(c)
+			return
+		}
+
+		claims, err := utils.ValidateJWT(ticket)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid stream ticket", err.Error())
+			c.Abort()
+			return
+		}
+		id, err := utils.ParseID(c, "id")
+		if err != nil || claims.Stream != utils.StreamScope(kind, id) {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid stream ticket", "ticket is for another stream")
+			c.Abort()
+			return
+		}
+
+		setClaims(c, claims)
 		c.Next()
 	})
 }
@@ -61,3 +147,25 @@ func AdminMiddleware() gin.HandlerFunc {
 		c.Next()
 	})
 }
+
+// RoleMiddleware ensures user has one of the given roles
+func RoleMiddleware(roles ...string) gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		userRole, exists := c.Get("userRole")
+		if !exists {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "User role not found", "authentication required")
+			c.Abort()
+			return
+		}
+
+		for _, role := range roles {
+			if userRole == role {
+				c.Next()
+				return
+			}
+		}
+
+		utils.ErrorResponse(c, http.StatusForbidden, "Access denied", "insufficient permissions")
+		c.Abort()
+	})
+}
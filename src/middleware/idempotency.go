@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/faisd405/go-restapi-gin/src/idempotency"
+	"github.com/faisd405/go-restapi-gin/src/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	ErrCodeIdempotencyKeyUsed = "IDEMPOTENCY_KEY_REUSED"
+	ErrCodeRequestInProgress  = "IDEMPOTENCY_REQUEST_IN_PROGRESS"
+)
+
+// IdempotencyConfig controls how long keys and in-flight locks are kept
+type IdempotencyConfig struct {
+	// TTL is how long a completed response is replayed for
+	TTL time.Duration
+	// LockTTL bounds how long an in-flight request holds its key, so a
+	// crashed request does not block retries forever
+	LockTTL time.Duration
+}
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware replays the stored response of POST and PUT requests
+// repeated with the same Idempotency-Key header. Keys are scoped to the
+// caller's Authorization header and the route, reusing a key with a
+// different payload is rejected and concurrent duplicates get 409 until the
+// first request finishes. Requests without the header pass through.
+func IdempotencyMiddleware(store idempotency.Store, cfg IdempotencyConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		method := c.Request.Method
+		if method != http.MethodPost && method != http.MethodPut {
+			c.Next()
+			return
+		}
+
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid idempotency key", "key must be at most 255 characters")
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read request body", err.Error())
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		storageKey := hashParts(c.GetHeader("Authorization"), method, c.FullPath(), key)
+		fingerprint := hashParts(method, c.Request.URL.RequestURI(), string(body))
+
+		existing, err := store.Reserve(storageKey, fingerprint, cfg.LockTTL, cfg.TTL)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Idempotency check failed", err.Error())
+			c.Abort()
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.Fingerprint != fingerprint:
+				utils.ErrorResponseWithCode(c, http.StatusUnprocessableEntity, ErrCodeIdempotencyKeyUsed,
+					"Idempotency key reused", "the key was already used with a different request payload")
+			case existing.Status == idempotency.StatusInProgress:
+				c.Header("Retry-After", "1")
+				utils.ErrorResponseWithCode(c, http.StatusConflict, ErrCodeRequestInProgress,
+					"Request in progress", "a request with this idempotency key is still being processed")
+			default:
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(existing.ResponseStatus, existing.ResponseType, existing.ResponseBody)
+			}
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		defer func() {
+			// Server errors and panics are not stored so the client can retry
+			if recovered := recover(); recovered != nil {
+				releaseKey(store, storageKey)
+				panic(recovered)
+			}
+		}()
+
+		c.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError {
+			releaseKey(store, storageKey)
+			return
+		}
+
+		if err := store.Complete(storageKey, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+			log.Println("Failed to store idempotent response:", err)
+		}
+	}
+}
+
+func releaseKey(store idempotency.Store, key string) {
+	if err := store.Release(key); err != nil {
+		log.Println("Failed to release idempotency key:", err)
+	}
+}
+
+func hashParts(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
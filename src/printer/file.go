@@ -0,0 +1,63 @@
+package printer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync/atomic"
+	"time"
+)
+
+// spoolName limits file printer addresses to a single directory name
+var spoolName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+type fileTransport struct {
+	dir string
+	seq atomic.Uint64
+}
+
+// NewFileTransport writes every job to a file of its own in a spool
+// directory named after the printer address, below dir. A print server
+// can pick the files up, and it lets the printing be tried out without a
+// printer.
+func NewFileTransport(dir string) Transport {
+	return &fileTransport{dir: dir}
+}
+
+func (t *fileTransport) Name() string {
+	return TransportFile
+}
+
+func (t *fileTransport) Send(ctx context.Context, address string, data []byte) error {
+	if err := CheckSpoolName(address); err != nil {
+		return err
+	}
+
+	dir := filepath.Join(t.dir, address)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	// Files are written under a temporary name and renamed so readers of
+	// the spool never see a partial job
+	name := fmt.Sprintf("%s-%06d.bin", time.Now().UTC().Format("20060102T150405.000000000"), t.seq.Add(1))
+	tmp := filepath.Join(dir, "."+name)
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, name)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// CheckSpoolName checks the address of a file printer
+func CheckSpoolName(address string) error {
+	if !spoolName.MatchString(address) {
+		return ErrInvalidAddress
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package printer
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+)
+
+// DefaultTCPPort is the raw printing port, also known as JetDirect or
+// AppSocket
+const DefaultTCPPort = 9100
+
+type tcpTransport struct {
+	timeout time.Duration
+}
+
+// NewTCPTransport sends jobs over a raw TCP connection, the way network
+// receipt printers take them. Addresses without a port use port 9100.
+func NewTCPTransport(timeout time.Duration) Transport {
+	return &tcpTransport{timeout: timeout}
+}
+
+func (t *tcpTransport) Name() string {
+	return TransportTCP
+}
+
+func (t *tcpTransport) Send(ctx context.Context, address string, data []byte) error {
+	address, err := TCPAddress(address)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetWriteDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	if _, err := conn.Write(data); err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// TCPAddress checks a printer address and adds the default port when it
+// has none
+func TCPAddress(address string) (string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		host, port = address, strconv.Itoa(DefaultTCPPort)
+	}
+	if host == "" {
+		return "", ErrInvalidAddress
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", ErrInvalidAddress
+	}
+	return net.JoinHostPort(host, port), nil
+}
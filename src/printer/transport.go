@@ -0,0 +1,26 @@
+// Package printer delivers raw print jobs to printers. Jobs are byte
+// streams in the printer's own language, such as ESC/POS; transports only
+// move them.
+package printer
+
+import (
+	"context"
+	"errors"
+)
+
+// Transport names
+const (
+	TransportTCP  = "tcp"
+	TransportFile = "file"
+)
+
+var ErrInvalidAddress = errors.New("invalid printer address")
+
+// Transport sends a job to the printer at address. What an address is
+// depends on the transport: host:port for raw TCP, a spool name for files.
+// Errors are temporary from the caller's point of view; the job is tried
+// again later.
+type Transport interface {
+	Name() string
+	Send(ctx context.Context, address string, data []byte) error
+}
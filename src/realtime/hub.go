@@ -0,0 +1,54 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Event is a message published on a topic. IDs increase across all topics
+// of a hub so a client can resume a stream of several topics from the last
+// ID it has seen.
+type Event struct {
+	ID        uint64          `json:"id"`
+	Topic     string          `json:"topic"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// Subscription is a live feed of events. Replay holds the retained events
+// published after the last event ID given to Subscribe; Missed is set when
+// some of them are no longer retained and the client has to reload its
+// state. Events is closed when the subscription context ends or when the
+// subscriber falls too far behind, after which the client reconnects with
+// the ID of the last event it received.
+type Subscription struct {
+	Replay []Event
+	Missed bool
+	Events <-chan Event
+}
+
+// Publisher publishes events to the subscribers of a topic
+type Publisher interface {
+	Publish(topic, eventType string, data interface{}) error
+}
+
+// Hub is a publish/subscribe broker. The in-memory hub serves a single
+// instance; deployments with several instances plug in a shared backend
+// behind the same interface.
+type Hub interface {
+	Publisher
+	Subscribe(ctx context.Context, lastEventID uint64, topics ...string) (*Subscription, error)
+}
+
+// BranchTopic is the topic of the order events of a branch
+func BranchTopic(branchID uint) string {
+	return fmt.Sprintf("branch:%d", branchID)
+}
+
+// StationTopic is the topic of the ticket events of a kitchen station
+func StationTopic(stationID uint) string {
+	return fmt.Sprintf("station:%d", stationID)
+}
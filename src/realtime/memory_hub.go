@@ -0,0 +1,144 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+type memoryTopic struct {
+	events      []Event
+	evictedUpTo uint64
+	subscribers map[*memorySubscriber]struct{}
+}
+
+type memorySubscriber struct {
+	ch     chan Event
+	topics []string
+	closed bool
+}
+
+type memoryHub struct {
+	mu         sync.Mutex
+	lastID     uint64
+	firstID    uint64
+	replaySize int
+	bufferSize int
+	topics     map[string]*memoryTopic
+	now        func() time.Time
+}
+
+// NewMemoryHub creates a Hub kept in process memory. Each topic retains its
+// last replaySize events for reconnecting clients, and subscribers that
+// have more than bufferSize events pending are dropped.
+//
+// Event IDs start at the current time in microseconds so IDs handed out
+// before a restart are recognised as missed rather than replayed wrongly.
+func NewMemoryHub(replaySize, bufferSize int) Hub {
+	start := uint64(time.Now().UnixMicro())
+	return &memoryHub{
+		lastID:     start,
+		firstID:    start,
+		replaySize: replaySize,
+		bufferSize: bufferSize,
+		topics:     map[string]*memoryTopic{},
+		now:        time.Now,
+	}
+}
+
+func (h *memoryHub) Publish(topic, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	event := Event{ID: h.lastID, Topic: topic, Type: eventType, Data: payload, CreatedAt: h.now()}
+
+	t := h.topic(topic)
+	t.events = append(t.events, event)
+	if len(t.events) > h.replaySize {
+		evicted := len(t.events) - h.replaySize
+		t.evictedUpTo = t.events[evicted-1].ID
+		t.events = append([]Event(nil), t.events[evicted:]...)
+	}
+
+	for sub := range t.subscribers {
+		select {
+		case sub.ch <- event:
+		default:
+			// The subscriber is too slow; it resumes from its last event ID
+			h.unsubscribe(sub)
+		}
+	}
+
+	return nil
+}
+
+func (h *memoryHub) Subscribe(ctx context.Context, lastEventID uint64, topics ...string) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := &memorySubscriber{ch: make(chan Event, h.bufferSize), topics: topics}
+	result := &Subscription{Events: sub.ch}
+
+	if lastEventID != 0 {
+		if lastEventID < h.firstID || lastEventID > h.lastID {
+			result.Missed = true
+		}
+		for _, name := range topics {
+			t := h.topic(name)
+			if lastEventID < t.evictedUpTo {
+				result.Missed = true
+			}
+			for _, event := range t.events {
+				if event.ID > lastEventID {
+					result.Replay = append(result.Replay, event)
+				}
+			}
+		}
+		sort.Slice(result.Replay, func(i, j int) bool { return result.Replay[i].ID < result.Replay[j].ID })
+	}
+
+	for _, name := range topics {
+		h.topic(name).subscribers[sub] = struct{}{}
+	}
+
+	go func() {
+		<-ctx.Done()
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.unsubscribe(sub)
+	}()
+
+	return result, nil
+}
+
+func (h *memoryHub) topic(name string) *memoryTopic {
+	t, ok := h.topics[name]
+	if !ok {
+		t = &memoryTopic{subscribers: map[*memorySubscriber]struct{}{}}
+		h.topics[name] = t
+	}
+	return t
+}
+
+// unsubscribe removes sub from its topics and closes its channel. The
+// caller holds h.mu.
+func (h *memoryHub) unsubscribe(sub *memorySubscriber) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	for _, name := range sub.topics {
+		if t, ok := h.topics[name]; ok {
+			delete(t.subscribers, sub)
+		}
+	}
+	close(sub.ch)
+}
@@ -1,12 +1,65 @@
 package router
 
 import (
+	"log"
+	"time"
+
 	examplecontroller "github.com/faisd405/go-restapi-gin/src/app/example/controller"
+	inventorycontroller "github.com/faisd405/go-restapi-gin/src/app/inventory/controller"
+	inventoryrepository "github.com/faisd405/go-restapi-gin/src/app/inventory/repository"
+	inventoryservice "github.com/faisd405/go-restapi-gin/src/app/inventory/service"
+	kitchencontroller "github.com/faisd405/go-restapi-gin/src/app/kitchen/controller"
+	kitchenrepository "github.com/faisd405/go-restapi-gin/src/app/kitchen/repository"
+	kitchenservice "github.com/faisd405/go-restapi-gin/src/app/kitchen/service"
+	loyaltycontroller "github.com/faisd405/go-restapi-gin/src/app/loyalty/controller"
+	loyaltyrepository "github.com/faisd405/go-restapi-gin/src/app/loyalty/repository"
+	loyaltyservice "github.com/faisd405/go-restapi-gin/src/app/loyalty/service"
+	menucontroller "github.com/faisd405/go-restapi-gin/src/app/menu/controller"
+	menurepository "github.com/faisd405/go-restapi-gin/src/app/menu/repository"
+	menuservice "github.com/faisd405/go-restapi-gin/src/app/menu/service"
+	ordercontroller "github.com/faisd405/go-restapi-gin/src/app/order/controller"
+	ordermodel "github.com/faisd405/go-restapi-gin/src/app/order/model"
+	orderrepository "github.com/faisd405/go-restapi-gin/src/app/order/repository"
+	orderservice "github.com/faisd405/go-restapi-gin/src/app/order/service"
+	paymentcontroller "github.com/faisd405/go-restapi-gin/src/app/payment/controller"
+	paymentrepository "github.com/faisd405/go-restapi-gin/src/app/payment/repository"
+	paymentservice "github.com/faisd405/go-restapi-gin/src/app/payment/service"
+	pricingcontroller "github.com/faisd405/go-restapi-gin/src/app/pricing/controller"
+	pricingrepository "github.com/faisd405/go-restapi-gin/src/app/pricing/repository"
+	pricingservice "github.com/faisd405/go-restapi-gin/src/app/pricing/service"
+	printingcontroller "github.com/faisd405/go-restapi-gin/src/app/printing/controller"
+	printingrepository "github.com/faisd405/go-restapi-gin/src/app/printing/repository"
+	printingservice "github.com/faisd405/go-restapi-gin/src/app/printing/service"
+	purchasingcontroller "github.com/faisd405/go-restapi-gin/src/app/purchasing/controller"
+	purchasingrepository "github.com/faisd405/go-restapi-gin/src/app/purchasing/repository"
+	purchasingservice "github.com/faisd405/go-restapi-gin/src/app/purchasing/service"
+	receiptcontroller "github.com/faisd405/go-restapi-gin/src/app/receipt/controller"
+	receiptrepository "github.com/faisd405/go-restapi-gin/src/app/receipt/repository"
+	receiptservice "github.com/faisd405/go-restapi-gin/src/app/receipt/service"
+	reservationcontroller "github.com/faisd405/go-restapi-gin/src/app/reservation/controller"
+	reservationrepository "github.com/faisd405/go-restapi-gin/src/app/reservation/repository"
+	reservationservice "github.com/faisd405/go-restapi-gin/src/app/reservation/service"
+	restaurantcontroller "github.com/faisd405/go-restapi-gin/src/app/restaurant/controller"
+	restaurantrepository "github.com/faisd405/go-restapi-gin/src/app/restaurant/repository"
+	restaurantservice "github.com/faisd405/go-restapi-gin/src/app/restaurant/service"
+	shiftcontroller "github.com/faisd405/go-restapi-gin/src/app/shift/controller"
+	shiftrepository "github.com/faisd405/go-restapi-gin/src/app/shift/repository"
+	shiftservice "github.com/faisd405/go-restapi-gin/src/app/shift/service"
+	tablecontroller "github.com/faisd405/go-restapi-gin/src/app/table/controller"
+	tablerepository "github.com/faisd405/go-restapi-gin/src/app/table/repository"
+	tableservice "github.com/faisd405/go-restapi-gin/src/app/table/service"
 	usercontroller "github.com/faisd405/go-restapi-gin/src/app/user/controller"
+	usermodel "github.com/faisd405/go-restapi-gin/src/app/user/model"
 	userrepository "github.com/faisd405/go-restapi-gin/src/app/user/repository"
 	userservice "github.com/faisd405/go-restapi-gin/src/app/user/service"
 	"github.com/faisd405/go-restapi-gin/src/config"
+	"github.com/faisd405/go-restapi-gin/src/database"
+	"github.com/faisd405/go-restapi-gin/src/gateway"
+	"github.com/faisd405/go-restapi-gin/src/idempotency"
 	"github.com/faisd405/go-restapi-gin/src/middleware"
+	"github.com/faisd405/go-restapi-gin/src/printer"
+	"github.com/faisd405/go-restapi-gin/src/realtime"
+	"github.com/faisd405/go-restapi-gin/src/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,13 +71,178 @@ func Routes() *gin.Engine {
 	r.Use(middleware.CORSMiddleware())
 	r.Use(middleware.LoggerMiddleware())
 
+	// Shared transaction manager for services spanning several repositories
+	txManager := database.NewTxManager(config.GetDB())
+
+	// In-process event hub for the live kitchen feeds
+	realtimeCfg := config.GetRealtimeConfig()
+	hub := realtime.NewMemoryHub(realtimeCfg.ReplaySize, realtimeCfg.BufferSize)
+
 	// Initialize user dependencies
 	userRepo := userrepository.NewUserRepository(config.GetDB())
-	userSvc := userservice.NewUserService(userRepo)
+	userSvc := userservice.NewUserService(userRepo, txManager)
 	userCtrl := usercontroller.NewUserController(userSvc)
 
+	// Initialize restaurant dependencies
+	restaurantRepo := restaurantrepository.NewRestaurantRepository(config.GetDB())
+	branchRepo := restaurantrepository.NewBranchRepository(config.GetDB())
+	staffRepo := restaurantrepository.NewStaffRepository(config.GetDB())
+	restaurantSvc := restaurantservice.NewRestaurantService(restaurantRepo, branchRepo, staffRepo, userRepo, txManager)
+	restaurantCtrl := restaurantcontroller.NewRestaurantController(restaurantSvc)
+	branchCtrl := restaurantcontroller.NewBranchController(restaurantSvc)
+	hoursRepo := restaurantrepository.NewHoursRepository(config.GetDB())
+	hoursExceptionRepo := restaurantrepository.NewHoursExceptionRepository(config.GetDB())
+	hoursSvc := restaurantservice.NewHoursService(hoursRepo, hoursExceptionRepo, restaurantSvc, txManager)
+	hoursCtrl := restaurantcontroller.NewHoursController(hoursSvc)
+
+	// Initialize menu dependencies
+	categoryRepo := menurepository.NewCategoryRepository(config.GetDB())
+	menuItemRepo := menurepository.NewItemRepository(config.GetDB())
+	modifierGroupRepo := menurepository.NewModifierGroupRepository(config.GetDB())
+	ingredientRepo := menurepository.NewIngredientRepository(config.GetDB())
+	menuSvc := menuservice.NewMenuService(categoryRepo, menuItemRepo, modifierGroupRepo, ingredientRepo, restaurantSvc, txManager)
+	menuCtrl := menucontroller.NewMenuController(menuSvc)
+
+	// Initialize pricing dependencies
+	pricingSettingsRepo := pricingrepository.NewSettingsRepository(config.GetDB())
+	taxRateRepo := pricingrepository.NewTaxRateRepository(config.GetDB())
+	discountRepo := pricingrepository.NewDiscountRepository(config.GetDB())
+	pricingSvc := pricingservice.NewPricingService(pricingSettingsRepo, taxRateRepo, discountRepo, restaurantSvc)
+	pricingCtrl := pricingcontroller.NewPricingController(pricingSvc)
+
+	// Initialize loyalty dependencies
+	loyaltyAccountRepo := loyaltyrepository.NewAccountRepository(config.GetDB())
+	loyaltyEntryRepo := loyaltyrepository.NewEntryRepository(config.GetDB())
+	loyaltyTierRepo := loyaltyrepository.NewTierRepository(config.GetDB())
+	loyaltySettingsRepo := loyaltyrepository.NewSettingsRepository(config.GetDB())
+	loyaltySvc := loyaltyservice.NewLoyaltyService(loyaltyAccountRepo, loyaltyEntryRepo, loyaltyTierRepo, loyaltySettingsRepo, restaurantSvc, userSvc, txManager)
+	loyaltyCtrl := loyaltycontroller.NewLoyaltyController(loyaltySvc)
+
+	// Initialize table dependencies
+	tableRepo := tablerepository.NewTableRepository(config.GetDB())
+	tableSessionRepo := tablerepository.NewSessionRepository(config.GetDB())
+	tableSvc := tableservice.NewTableService(tableRepo, tableSessionRepo, restaurantSvc, txManager, config.GetTableConfig())
+	tableCtrl := tablecontroller.NewTableController(tableSvc)
+
+	// Initialize reservation dependencies
+	reservationRepo := reservationrepository.NewReservationRepository(config.GetDB())
+	reservationSettingsRepo := reservationrepository.NewSettingsRepository(config.GetDB())
+	reservationSvc := reservationservice.NewReservationService(reservationRepo, reservationSettingsRepo, tableSvc, restaurantSvc, hoursSvc, userSvc, txManager)
+	reservationCtrl := reservationcontroller.NewReservationController(reservationSvc)
+
+	// Initialize kitchen station dependencies
+	stationRepo := kitchenrepository.NewStationRepository(config.GetDB())
+	ticketRepo := kitchenrepository.NewTicketRepository(config.GetDB())
+	kitchenCfg := config.GetKitchenConfig()
+	stationSvc := kitchenservice.NewStationService(stationRepo, menuSvc, restaurantSvc, txManager, kitchenCfg)
+	stationCtrl := kitchencontroller.NewStationController(stationSvc)
+
+	// Print queues are filled when the kitchen receives an order
+	printerRepo := printingrepository.NewPrinterRepository(config.GetDB())
+	printJobRepo := printingrepository.NewJobRepository(config.GetDB())
+
+	// Stock is taken when the kitchen receives an order
+	stockRepo := inventoryrepository.NewStockRepository(config.GetDB())
+	stockMovementRepo := inventoryrepository.NewMovementRepository(config.GetDB())
+	inventorySvc := inventoryservice.NewInventoryService(stockRepo, stockMovementRepo, menuSvc, restaurantSvc, txManager)
+	inventoryCtrl := inventorycontroller.NewInventoryController(inventorySvc)
+
+	// Initialize order dependencies
+	// Loyalty points are earned on what was not refunded
+	paymentRepo := paymentrepository.NewPaymentRepository(config.GetDB())
+	orderRepo := orderrepository.NewOrderRepository(config.GetDB())
+	orderHistoryRepo := orderrepository.NewHistoryRepository(config.GetDB())
+	// Status hooks of other modules run, in this order, within the
+	// transaction that moves an order
+	orderHooks := []ordermodel.StatusHook{
+		kitchenservice.NewRoutingHook(stationRepo, ticketRepo, hub, printingservice.NewTicketPrintHook(printJobRepo)),
+		inventoryservice.NewStockHook(stockRepo),
+		loyaltyservice.NewOrderHook(loyaltyAccountRepo, loyaltyTierRepo, loyaltySettingsRepo, paymentservice.Refunded(paymentRepo)),
+	}
+	orderSvc := orderservice.NewOrderService(orderRepo, orderHistoryRepo, menuSvc, pricingSvc, loyaltySvc, restaurantSvc, hoursSvc, tableSvc, hub, txManager, orderHooks...)
+	orderCtrl := ordercontroller.NewOrderController(orderSvc)
+
+	// Initialize kitchen ticket and feed dependencies
+	ticketSvc := kitchenservice.NewTicketService(ticketRepo, stationRepo, orderSvc, restaurantSvc, hub, txManager)
+	ticketCtrl := kitchencontroller.NewTicketController(ticketSvc)
+	feedSvc := kitchenservice.NewFeedService(hub, stationRepo, restaurantSvc, kitchenCfg)
+	feedCtrl := kitchencontroller.NewFeedController(feedSvc, realtimeCfg)
+
+	// Initialize payment dependencies
+	paymentCfg := config.GetPaymentConfig()
+	var paymentProvider gateway.PaymentProvider
+	if paymentCfg.Provider == "fake" {
+		paymentProvider = gateway.NewFakeProvider()
+	} else {
+		log.Fatalf("Unknown payment provider: %s", paymentCfg.Provider)
+	}
+	webhookEventRepo := paymentrepository.NewWebhookEventRepository(config.GetDB())
+	billRepo := paymentrepository.NewBillRepository(config.GetDB())
+	paymentSvc := paymentservice.NewPaymentService(paymentRepo, webhookEventRepo, billRepo, orderSvc, restaurantSvc, paymentProvider, txManager, paymentCfg)
+	paymentCtrl := paymentcontroller.NewPaymentController(paymentSvc)
+	billSvc := paymentservice.NewBillService(billRepo, paymentRepo, orderSvc, txManager)
+	billCtrl := paymentcontroller.NewBillController(billSvc)
+	refundRepo := paymentrepository.NewRefundRepository(config.GetDB())
+	refundGroupRepo := paymentrepository.NewRefundGroupRepository(config.GetDB())
+	refundSvc := paymentservice.NewRefundService(paymentRepo, refundRepo, refundGroupRepo, orderSvc, restaurantSvc, loyaltySvc, paymentProvider, txManager, paymentCfg)
+	refundCtrl := paymentcontroller.NewRefundController(refundSvc)
+	paymentservice.StartReaper(paymentSvc, refundSvc, time.Minute)
+	reportSvc := paymentservice.NewReportService(paymentRepo, refundRepo, restaurantSvc)
+	reportCtrl := paymentcontroller.NewReportController(reportSvc)
+
+	// Initialize receipt dependencies
+	templateRepo := receiptrepository.NewTemplateRepository(config.GetDB())
+	invoiceRepo := receiptrepository.NewInvoiceRepository(config.GetDB())
+	receiptSvc := receiptservice.NewReceiptService(templateRepo, invoiceRepo, orderSvc, paymentSvc, restaurantSvc, txManager)
+	receiptCtrl := receiptcontroller.NewReceiptController(receiptSvc)
+
+	// Initialize printing dependencies
+	printingCfg := config.GetPrintingConfig()
+	printTransports := []printer.Transport{
+		printer.NewTCPTransport(printingCfg.TCPTimeout),
+		printer.NewFileTransport(printingCfg.SpoolDir),
+	}
+	printerSvc := printingservice.NewPrinterService(printerRepo, printJobRepo, stationRepo, restaurantSvc, txManager)
+	printerCtrl := printingcontroller.NewPrinterController(printerSvc)
+	printSvc := printingservice.NewPrintService(printerRepo, printJobRepo, ticketRepo, stationRepo, tableRepo, ticketSvc, orderSvc, receiptSvc, restaurantSvc, printTransports, txManager, printingCfg)
+	printCtrl := printingcontroller.NewPrintController(printSvc)
+	printingservice.StartDispatcher(printSvc, printingCfg.PollInterval)
+
+	// Initialize purchasing dependencies
+	supplierRepo := purchasingrepository.NewSupplierRepository(config.GetDB())
+	purchaseOrderRepo := purchasingrepository.NewPurchaseOrderRepository(config.GetDB())
+	supplierSvc := purchasingservice.NewSupplierService(supplierRepo, menuSvc, restaurantSvc, txManager)
+	supplierCtrl := purchasingcontroller.NewSupplierController(supplierSvc)
+	purchaseOrderSvc := purchasingservice.NewPurchaseOrderService(purchaseOrderRepo, supplierRepo, stockRepo, inventorySvc, menuSvc, restaurantSvc, txManager)
+	purchaseOrderCtrl := purchasingcontroller.NewPurchaseOrderController(purchaseOrderSvc)
+
+	// Initialize shift dependencies
+	shiftRepo := shiftrepository.NewShiftRepository(config.GetDB())
+	timeEntryRepo := shiftrepository.NewTimeEntryRepository(config.GetDB())
+	clockPINRepo := shiftrepository.NewClockPINRepository(config.GetDB())
+	laborSettingsRepo := shiftrepository.NewSettingsRepository(config.GetDB())
+	shiftSvc := shiftservice.NewShiftService(shiftRepo, laborSettingsRepo, restaurantSvc)
+	clockSvc := shiftservice.NewClockService(timeEntryRepo, shiftRepo, clockPINRepo, laborSettingsRepo, restaurantSvc, txManager)
+	timesheetSvc := shiftservice.NewTimesheetService(timeEntryRepo, shiftRepo, laborSettingsRepo, restaurantSvc)
+	shiftCtrl := shiftcontroller.NewShiftController(shiftSvc, timesheetSvc)
+	clockCtrl := shiftcontroller.NewClockController(clockSvc)
+
+	// Idempotency-Key support for POST/PUT requests
+	idempotencyCfg := config.GetIdempotencyConfig()
+	var idempotencyStore idempotency.Store
+	if idempotencyCfg.Store == "memory" {
+		idempotencyStore = idempotency.NewMemoryStore()
+	} else {
+		idempotencyStore = idempotency.NewPostgresStore(config.GetDB())
+		idempotency.StartCleanup(config.GetDB(), time.Hour)
+	}
+
 	// API v1 routes
 	v1 := r.Group("/api/v1")
+	v1.Use(middleware.IdempotencyMiddleware(idempotencyStore, middleware.IdempotencyConfig{
+		TTL:     idempotencyCfg.TTL,
+		LockTTL: idempotencyCfg.LockTTL,
+	}))
 	{
 		// Auth routes (public)
 		auth := v1.Group("/auth")
@@ -40,6 +258,333 @@ func Routes() *gin.Engine {
 			users.GET("/profile", userCtrl.GetProfile)
 			users.PUT("/profile", userCtrl.UpdateProfile)
 			users.PUT("/change-password", userCtrl.ChangePassword)
+			users.GET("/branches", branchCtrl.GetMyBranches)
+			users.GET("/shifts", shiftCtrl.GetMyShifts)
+			users.GET("/time-entries", clockCtrl.GetMyTimeEntries)
+			users.PUT("/clock-pin", clockCtrl.SetPIN)
+			users.GET("/loyalty", loyaltyCtrl.GetMyLoyalty)
+			users.GET("/loyalty/history", loyaltyCtrl.GetMyHistory)
+		}
+
+		// Restaurant routes (public)
+		restaurants := v1.Group("/restaurants")
+		{
+			restaurants.GET("", restaurantCtrl.GetRestaurants)
+			restaurants.GET("/:id", restaurantCtrl.GetRestaurant)
+			restaurants.GET("/:id/branches", branchCtrl.GetBranches)
+		}
+
+		// Branch routes (public)
+		branches := v1.Group("/branches")
+		{
+			branches.GET("/:id", branchCtrl.GetBranch)
+			branches.GET("/:id/menu", menuCtrl.GetMenu)
+			branches.GET("/:id/hours", hoursCtrl.GetHours)
+			branches.GET("/:id/open-status", hoursCtrl.GetOpenStatus)
+			branches.GET("/:id/availability", reservationCtrl.GetAvailability)
+		}
+
+		// Menu routes (public)
+		menu := v1.Group("/menu")
+		{
+			menu.GET("/items/:id", menuCtrl.GetItem)
+			menu.GET("/dietary-labels", menuCtrl.GetDietaryLabels)
+		}
+
+		// Menu management routes (protected + admin/manager)
+		menuAdmin := v1.Group("")
+		menuAdmin.Use(middleware.AuthMiddleware())
+		menuAdmin.Use(middleware.RoleMiddleware(usermodel.RoleAdmin, usermodel.RoleManager))
+		{
+			menuAdmin.POST("/branches/:id/menu/categories", menuCtrl.CreateCategory)
+			menuAdmin.PUT("/branches/:id/menu/categories/order", menuCtrl.ReorderCategories)
+			menuAdmin.PUT("/menu/categories/:id", menuCtrl.UpdateCategory)
+			menuAdmin.DELETE("/menu/categories/:id", menuCtrl.DeleteCategory)
+			menuAdmin.POST("/menu/items", menuCtrl.CreateItem)
+			menuAdmin.PUT("/menu/items/:id", menuCtrl.UpdateItem)
+			menuAdmin.PUT("/menu/items/:id/availability", menuCtrl.SetAvailability)
+			menuAdmin.DELETE("/menu/items/:id", menuCtrl.DeleteItem)
+			menuAdmin.PUT("/menu/items/:id/modifier-groups", menuCtrl.SetItemModifierGroups)
+			menuAdmin.GET("/branches/:id/menu/modifier-groups", menuCtrl.GetModifierGroups)
+			menuAdmin.POST("/branches/:id/menu/modifier-groups", menuCtrl.CreateModifierGroup)
+			menuAdmin.PUT("/menu/modifier-groups/:id", menuCtrl.UpdateModifierGroup)
+			menuAdmin.DELETE("/menu/modifier-groups/:id", menuCtrl.DeleteModifierGroup)
+			menuAdmin.PUT("/menu/items/:id/ingredients", menuCtrl.SetItemIngredients)
+			menuAdmin.GET("/menu/items/:id/recipe", menuCtrl.GetRecipe)
+			menuAdmin.PUT("/menu/items/:id/recipe", menuCtrl.SetRecipe)
+			menuAdmin.GET("/branches/:id/menu/ingredients", menuCtrl.GetIngredients)
+			menuAdmin.POST("/branches/:id/menu/ingredients", menuCtrl.CreateIngredient)
+			menuAdmin.PUT("/menu/ingredients/:id", menuCtrl.UpdateIngredient)
+			menuAdmin.DELETE("/menu/ingredients/:id", menuCtrl.DeleteIngredient)
+		}
+
+		// Pricing management routes (protected + admin/manager)
+		pricingAdmin := v1.Group("")
+		pricingAdmin.Use(middleware.AuthMiddleware())
+		pricingAdmin.Use(middleware.RoleMiddleware(usermodel.RoleAdmin, usermodel.RoleManager))
+		{
+			pricingAdmin.GET("/branches/:id/pricing", pricingCtrl.GetConfig)
+			pricingAdmin.PUT("/branches/:id/pricing", pricingCtrl.UpdateSettings)
+			pricingAdmin.POST("/branches/:id/tax-rates", pricingCtrl.CreateTaxRate)
+			pricingAdmin.PUT("/tax-rates/:id", pricingCtrl.UpdateTaxRate)
+			pricingAdmin.DELETE("/tax-rates/:id", pricingCtrl.DeleteTaxRate)
+			pricingAdmin.GET("/branches/:id/discounts", pricingCtrl.GetDiscounts)
+			pricingAdmin.POST("/branches/:id/discounts", pricingCtrl.CreateDiscount)
+			pricingAdmin.PUT("/discounts/:id", pricingCtrl.UpdateDiscount)
+			pricingAdmin.DELETE("/discounts/:id", pricingCtrl.DeleteDiscount)
+		}
+
+		// Table routes (public). Scanning a table QR code starts an
+		// anonymous session whose token can be used on the order routes.
+		tables := v1.Group("/tables")
+		{
+			tables.POST("/sessions", tableCtrl.StartSession)
+		}
+
+		// Table management routes (protected + admin/manager)
+		tableAdmin := v1.Group("")
+		tableAdmin.Use(middleware.AuthMiddleware())
+		tableAdmin.Use(middleware.RoleMiddleware(usermodel.RoleAdmin, usermodel.RoleManager))
+		{
+			tableAdmin.POST("/branches/:id/tables", tableCtrl.CreateTable)
+			tableAdmin.PUT("/tables/:id", tableCtrl.UpdateTable)
+			tableAdmin.DELETE("/tables/:id", tableCtrl.DeleteTable)
+			tableAdmin.POST("/tables/:id/qr/rotate", tableCtrl.RotateQRCode)
+		}
+
+		// Opening hours management routes (protected + admin/manager)
+		hoursAdmin := v1.Group("")
+		hoursAdmin.Use(middleware.AuthMiddleware())
+		hoursAdmin.Use(middleware.RoleMiddleware(usermodel.RoleAdmin, usermodel.RoleManager))
+		{
+			hoursAdmin.PUT("/branches/:id/hours", hoursCtrl.SetWeeklyHours)
+			hoursAdmin.POST("/branches/:id/hours/exceptions", hoursCtrl.SaveException)
+			hoursAdmin.DELETE("/hours-exceptions/:id", hoursCtrl.DeleteException)
+		}
+
+		// Kitchen station management routes (protected + admin/manager)
+		stationAdmin := v1.Group("")
+		stationAdmin.Use(middleware.AuthMiddleware())
+		stationAdmin.Use(middleware.RoleMiddleware(usermodel.RoleAdmin, usermodel.RoleManager))
+		{
+			stationAdmin.POST("/branches/:id/stations", stationCtrl.CreateStation)
+			stationAdmin.PUT("/stations/:id", stationCtrl.UpdateStation)
+			stationAdmin.DELETE("/stations/:id", stationCtrl.DeleteStation)
+			stationAdmin.PUT("/stations/:id/items", stationCtrl.SetStationItems)
+		}
+
+		// Inventory routes (protected + admin/manager)
+		inventoryAdmin := v1.Group("")
+		inventoryAdmin.Use(middleware.AuthMiddleware())
+		inventoryAdmin.Use(middleware.RoleMiddleware(usermodel.RoleAdmin, usermodel.RoleManager))
+		{
+			inventoryAdmin.GET("/branches/:id/stock", inventoryCtrl.GetStock)
+			inventoryAdmin.GET("/branches/:id/stock/movements", inventoryCtrl.GetMovements)
+			inventoryAdmin.POST("/branches/:id/stock/movements", inventoryCtrl.RecordMovement)
+			inventoryAdmin.PUT("/branches/:id/stock/reorder-levels", inventoryCtrl.SetReorderLevel)
+		}
+
+		// Purchasing routes (protected + admin/manager)
+		purchasingAdmin := v1.Group("")
+		purchasingAdmin.Use(middleware.AuthMiddleware())
+		purchasingAdmin.Use(middleware.RoleMiddleware(usermodel.RoleAdmin, usermodel.RoleManager))
+		{
+			purchasingAdmin.GET("/branches/:id/suppliers", supplierCtrl.GetSuppliers)
+			purchasingAdmin.POST("/branches/:id/suppliers", supplierCtrl.CreateSupplier)
+			purchasingAdmin.GET("/suppliers/:id", supplierCtrl.GetSupplier)
+			purchasingAdmin.PUT("/suppliers/:id", supplierCtrl.UpdateSupplier)
+			purchasingAdmin.DELETE("/suppliers/:id", supplierCtrl.DeleteSupplier)
+			purchasingAdmin.GET("/branches/:id/purchase-orders", purchaseOrderCtrl.GetPurchaseOrders)
+			purchasingAdmin.POST("/branches/:id/purchase-orders", purchaseOrderCtrl.CreatePurchaseOrder)
+			purchasingAdmin.GET("/branches/:id/purchase-orders/suggestions", purchaseOrderCtrl.GetSuggestions)
+			purchasingAdmin.POST("/branches/:id/purchase-orders/suggestions", purchaseOrderCtrl.CreateSuggested)
+			purchasingAdmin.GET("/purchase-orders/:id", purchaseOrderCtrl.GetPurchaseOrder)
+			purchasingAdmin.PUT("/purchase-orders/:id", purchaseOrderCtrl.UpdatePurchaseOrder)
+			purchasingAdmin.DELETE("/purchase-orders/:id", purchaseOrderCtrl.DeletePurchaseOrder)
+			purchasingAdmin.POST("/purchase-orders/:id/send", purchaseOrderCtrl.SendPurchaseOrder)
+			purchasingAdmin.POST("/purchase-orders/:id/receive", purchaseOrderCtrl.ReceivePurchaseOrder)
+			purchasingAdmin.GET("/purchase-orders/:id/export", purchaseOrderCtrl.ExportPurchaseOrder)
+		}
+
+		// Shift scheduling routes (protected + admin/manager). Managers only
+		// reach the staff and shifts of the branches they work at.
+		shiftAdmin := v1.Group("")
+		shiftAdmin.Use(middleware.AuthMiddleware())
+		shiftAdmin.Use(middleware.RoleMiddleware(usermodel.RoleAdmin, usermodel.RoleManager))
+		{
+			shiftAdmin.GET("/branches/:id/labor-settings", shiftCtrl.GetSettings)
+			shiftAdmin.PUT("/branches/:id/labor-settings", shiftCtrl.UpdateSettings)
+			shiftAdmin.GET("/branches/:id/staff", shiftCtrl.GetStaff)
+			shiftAdmin.GET("/branches/:id/shifts", shiftCtrl.GetShifts)
+			shiftAdmin.POST("/branches/:id/shifts", shiftCtrl.CreateShift)
+			shiftAdmin.PUT("/shifts/:id", shiftCtrl.UpdateShift)
+			shiftAdmin.DELETE("/shifts/:id", shiftCtrl.DeleteShift)
+			shiftAdmin.GET("/branches/:id/time-entries", clockCtrl.GetTimeEntries)
+			shiftAdmin.PUT("/time-entries/:id", clockCtrl.UpdateTimeEntry)
+			shiftAdmin.GET("/branches/:id/timesheets", shiftCtrl.GetTimesheet)
+		}
+
+		// Time clock routes (protected + staff/manager/admin/station).
+		// Clocking in someone else, or on a station, takes their PIN.
+		clock := v1.Group("")
+		clock.Use(middleware.StationAuthMiddleware())
+		clock.Use(middleware.RoleMiddleware(usermodel.RoleStaff, usermodel.RoleManager, usermodel.RoleAdmin, usermodel.RoleStation))
+		{
+			clock.POST("/branches/:id/clock-in", clockCtrl.ClockIn)
+			clock.POST("/branches/:id/clock-out", clockCtrl.ClockOut)
+		}
+
+		// Loyalty program routes (protected + admin/manager)
+		loyaltyAdmin := v1.Group("")
+		loyaltyAdmin.Use(middleware.AuthMiddleware())
+		loyaltyAdmin.Use(middleware.RoleMiddleware(usermodel.RoleAdmin, usermodel.RoleManager))
+		{
+			loyaltyAdmin.GET("/branches/:id/loyalty-settings", loyaltyCtrl.GetSettings)
+			loyaltyAdmin.PUT("/branches/:id/loyalty-settings", loyaltyCtrl.UpdateSettings)
+		}
+
+		// Printer management routes (protected + admin/manager)
+		printerAdmin := v1.Group("")
+		printerAdmin.Use(middleware.AuthMiddleware())
+		printerAdmin.Use(middleware.RoleMiddleware(usermodel.RoleAdmin, usermodel.RoleManager))
+		{
+			printerAdmin.GET("/branches/:id/printers", printerCtrl.GetPrinters)
+			printerAdmin.POST("/branches/:id/printers", printerCtrl.CreatePrinter)
+			printerAdmin.GET("/printers/:id", printerCtrl.GetPrinter)
+			printerAdmin.PUT("/printers/:id", printerCtrl.UpdatePrinter)
+			printerAdmin.DELETE("/printers/:id", printerCtrl.DeletePrinter)
+			printerAdmin.POST("/printers/:id/test", printCtrl.PrintTest)
+		}
+
+		// Reservation booking routes (public, login optional). Logged-in
+		// users book on their account, anonymous guests with contact details.
+		bookings := v1.Group("/reservations")
+		bookings.Use(middleware.OptionalAuthMiddleware())
+		{
+			bookings.POST("", reservationCtrl.CreateReservation)
+			bookings.POST("/cancel", reservationCtrl.CancelAsGuest)
+		}
+
+		// Reservation routes (protected). Guests and branch staff share
+		// these routes; the reservation lifecycle decides who may do what.
+		reservations := v1.Group("/reservations")
+		reservations.Use(middleware.AuthMiddleware())
+		{
+			reservations.GET("", reservationCtrl.GetMyReservations)
+			reservations.GET("/:id", reservationCtrl.GetReservation)
+			reservations.POST("/:id/status", reservationCtrl.Transition)
+		}
+
+		// Reservation settings routes (protected + admin/manager)
+		reservationAdmin := v1.Group("")
+		reservationAdmin.Use(middleware.AuthMiddleware())
+		reservationAdmin.Use(middleware.RoleMiddleware(usermodel.RoleAdmin, usermodel.RoleManager))
+		{
+			reservationAdmin.GET("/branches/:id/reservation-settings", reservationCtrl.GetSettings)
+			reservationAdmin.PUT("/branches/:id/reservation-settings", reservationCtrl.UpdateSettings)
+		}
+
+		// Refund and report routes (protected + admin/manager)
+		paymentAdmin := v1.Group("")
+		paymentAdmin.Use(middleware.AuthMiddleware())
+		paymentAdmin.Use(middleware.RoleMiddleware(usermodel.RoleAdmin, usermodel.RoleManager))
+		{
+			paymentAdmin.POST("/orders/:id/refunds", refundCtrl.Refund)
+			paymentAdmin.GET("/branches/:id/reports/sales", reportCtrl.GetSalesReport)
+			paymentAdmin.GET("/branches/:id/reports/tips", reportCtrl.GetTipReport)
+		}
+
+		// Receipt template routes (protected + admin/manager)
+		receiptAdmin := v1.Group("")
+		receiptAdmin.Use(middleware.AuthMiddleware())
+		receiptAdmin.Use(middleware.RoleMiddleware(usermodel.RoleAdmin, usermodel.RoleManager))
+		{
+			receiptAdmin.GET("/restaurants/:id/receipt-template", receiptCtrl.GetTemplate)
+			receiptAdmin.PUT("/restaurants/:id/receipt-template", receiptCtrl.SaveTemplate)
+		}
+
+		// Payment webhook routes (public, signed by the provider)
+		payments := v1.Group("/payments")
+		{
+			payments.POST("/webhooks/:provider", paymentCtrl.Webhook)
+		}
+
+		// Order routes (protected). Customers and branch staff share these
+		// routes; the order state machine decides who may do what.
+		orders := v1.Group("/orders")
+		orders.Use(middleware.AuthMiddleware())
+		{
+			orders.GET("", orderCtrl.GetMyOrders)
+			orders.POST("", orderCtrl.CreateOrder)
+			orders.POST("/quote", orderCtrl.Quote)
+			orders.GET("/:id", orderCtrl.GetOrder)
+			orders.PUT("/:id/lines", orderCtrl.UpdateLines)
+			orders.POST("/:id/status", orderCtrl.Transition)
+			orders.GET("/:id/history", orderCtrl.GetHistory)
+			orders.GET("/:id/payments", paymentCtrl.GetPayments)
+			orders.POST("/:id/payments", paymentCtrl.Pay)
+			orders.GET("/:id/bills", billCtrl.GetBills)
+			orders.POST("/:id/bills", billCtrl.Split)
+			orders.GET("/:id/receipt", receiptCtrl.GetReceipt)
+			orders.GET("/:id/invoice", receiptCtrl.GetInvoice)
+			orders.POST("/:id/invoice", receiptCtrl.IssueInvoice)
+		}
+
+		// Branch staff routes (protected + staff/manager/admin)
+		staff := v1.Group("")
+		staff.Use(middleware.AuthMiddleware())
+		staff.Use(middleware.RoleMiddleware(usermodel.RoleStaff, usermodel.RoleManager, usermodel.RoleAdmin))
+		{
+			staff.GET("/branches/:id/orders", orderCtrl.GetBranchOrders)
+			staff.GET("/branches/:id/tables", tableCtrl.GetTables)
+			staff.GET("/tables/:id", tableCtrl.GetTable)
+			staff.PUT("/tables/:id/status", tableCtrl.SetStatus)
+			staff.GET("/tables/:id/qr", tableCtrl.GetQRCode)
+			staff.GET("/branches/:id/reservations", reservationCtrl.GetBranchReservations)
+			staff.POST("/branches/:id/reservations", reservationCtrl.CreateStaffReservation)
+			staff.PUT("/reservations/:id", reservationCtrl.Reschedule)
+			staff.GET("/branches/:id/stations", stationCtrl.GetStations)
+			staff.POST("/stations/:id/sessions", stationCtrl.StartSession)
+			staff.POST("/branches/:id/kitchen/stream/tickets", feedCtrl.IssueBranchTicket)
+			staff.POST("/orders/:id/payments/manual", paymentCtrl.RecordManual)
+			staff.POST("/payments/:id/capture", paymentCtrl.Capture)
+			staff.POST("/payments/:id/void", paymentCtrl.Void)
+			staff.GET("/orders/:id/refunds", refundCtrl.GetRefunds)
+			staff.POST("/orders/:id/receipt/print", printCtrl.PrintReceipt)
+			staff.GET("/printers/:id/jobs", printCtrl.GetJobs)
+			staff.GET("/print-jobs/:id", printCtrl.GetJob)
+			staff.POST("/print-jobs/:id/retry", printCtrl.Retry)
+		}
+
+		// Kitchen ticket routes (protected + staff/manager/admin/station).
+		// Station screens only reach the tickets of their own station.
+		tickets := v1.Group("")
+		tickets.Use(middleware.StationAuthMiddleware())
+		tickets.Use(middleware.RoleMiddleware(usermodel.RoleStaff, usermodel.RoleManager, usermodel.RoleAdmin, usermodel.RoleStation))
+		{
+			tickets.GET("/stations/:id", stationCtrl.GetStation)
+			tickets.GET("/stations/:id/tickets", ticketCtrl.GetTickets)
+			tickets.GET("/tickets/:id", ticketCtrl.GetTicket)
+			tickets.POST("/tickets/:id/status", ticketCtrl.Transition)
+			tickets.POST("/tickets/:id/print", printCtrl.PrintTicket)
+			tickets.POST("/stations/:id/stream/tickets", feedCtrl.IssueStationTicket)
+		}
+
+		// Kitchen feed routes (protected + staff/manager/admin). EventSource
+		// clients pass a stream ticket in the ticket query instead.
+		kitchen := v1.Group("")
+		kitchen.Use(middleware.StreamAuthMiddleware(utils.StreamBranch))
+		kitchen.Use(middleware.RoleMiddleware(usermodel.RoleStaff, usermodel.RoleManager, usermodel.RoleAdmin))
+		{
+			kitchen.GET("/branches/:id/kitchen/stream", feedCtrl.StreamBranch)
+		}
+
+		// Station feed routes (protected + staff/manager/admin/station)
+		stationFeed := v1.Group("")
+		stationFeed.Use(middleware.StreamAuthMiddleware(utils.StreamStation))
+		stationFeed.Use(middleware.RoleMiddleware(usermodel.RoleStaff, usermodel.RoleManager, usermodel.RoleAdmin, usermodel.RoleStation))
+		{
+			stationFeed.GET("/stations/:id/stream", feedCtrl.StreamStation)
 		}
 
 		// Admin routes (protected + admin only)
@@ -49,6 +594,29 @@ func Routes() *gin.Engine {
 		{
 			admin.GET("/users", userCtrl.GetAllUsers)
 			admin.DELETE("/users/:id", userCtrl.DeleteUser)
+			admin.PUT("/users/:id/role", userCtrl.UpdateUserRole)
+
+			admin.GET("/restaurants", restaurantCtrl.AdminGetRestaurants)
+			admin.GET("/restaurants/:id", restaurantCtrl.AdminGetRestaurant)
+			admin.POST("/restaurants", restaurantCtrl.CreateRestaurant)
+			admin.PUT("/restaurants/:id", restaurantCtrl.UpdateRestaurant)
+			admin.DELETE("/restaurants/:id", restaurantCtrl.DeleteRestaurant)
+			admin.POST("/restaurants/:id/branches", branchCtrl.CreateBranch)
+
+			admin.GET("/branches/:id", branchCtrl.AdminGetBranch)
+			admin.PUT("/branches/:id", branchCtrl.UpdateBranch)
+			admin.DELETE("/branches/:id", branchCtrl.DeleteBranch)
+			admin.GET("/branches/:id/staff", branchCtrl.GetStaff)
+			admin.POST("/branches/:id/staff", branchCtrl.AssignStaff)
+			admin.DELETE("/branches/:id/staff/:userId", branchCtrl.RemoveStaff)
+
+			admin.GET("/loyalty/tiers", loyaltyCtrl.GetTiers)
+			admin.POST("/loyalty/tiers", loyaltyCtrl.CreateTier)
+			admin.PUT("/loyalty/tiers/:id", loyaltyCtrl.UpdateTier)
+			admin.DELETE("/loyalty/tiers/:id", loyaltyCtrl.DeleteTier)
+			admin.GET("/users/:id/loyalty", loyaltyCtrl.GetUserLoyalty)
+			admin.GET("/users/:id/loyalty/history", loyaltyCtrl.GetUserHistory)
+			admin.POST("/users/:id/loyalty/adjustments", loyaltyCtrl.Adjust)
 		}
 
 		// Example routes (for backward compatibility)
@@ -60,6 +628,8 @@ func Routes() *gin.Engine {
 			examples.PUT("/:id", examplecontroller.Update)
 			examples.DELETE("/:id", examplecontroller.Delete)
 		}
+
+		// Scaffolded routes are inserted above this line
 	}
 
 	// Health check route
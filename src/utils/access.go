@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrBranchAccessDenied is returned when a staff member acts on a branch
+// they are not assigned to
+var ErrBranchAccessDenied = errors.New("you do not have access to this branch")
+
+// Actor is the authenticated user performing a request. Guests of an
+// anonymous table session have no UserID but a TableSessionID. Kitchen
+// station screens act for the user who opened them but are limited to the
+// StationID and BranchID of their token.
+type Actor struct {
+	UserID         uint
+	Email          string
+	Role           string
+	TableSessionID uint
+	TableID        uint
+	StationID      uint
+	BranchID       uint
+}
+
+// IsAdmin reports whether the actor has the admin role
+func (a Actor) IsAdmin() bool {
+	return a.Role == "admin"
+}
+
+// IsGuest reports whether the actor is an anonymous table session
+func (a Actor) IsGuest() bool {
+	return a.TableSessionID != 0
+}
+
+// IsStation reports whether the actor is a kitchen station screen
+func (a Actor) IsStation() bool {
+	return a.StationID != 0
+}
+
+// GetActor returns the user set on the context by AuthMiddleware
+func GetActor(c *gin.Context) (Actor, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		return Actor{}, false
+	}
+
+	actor := Actor{UserID: userID.(uint)}
+	actor.Email = c.GetString("userEmail")
+	actor.Role = c.GetString("userRole")
+	actor.TableSessionID = c.GetUint("tableSessionID")
+	actor.TableID = c.GetUint("tableID")
+	actor.StationID = c.GetUint("stationID")
+	actor.BranchID = c.GetUint("branchID")
+	return actor, true
+}
+
+// RequireActor returns the user set on the context by AuthMiddleware and
+// responds with 401 when there is none
+func RequireActor(c *gin.Context) (Actor, bool) {
+	actor, ok := GetActor(c)
+	if !ok {
+		ErrorResponse(c, http.StatusUnauthorized, "User not authenticated", "user ID not found")
+	}
+	return actor, ok
+}
+
+// ActorID returns the user ID of the actor to record who made a change, or
+// nil for guests and others acting without a user
+func ActorID(actor Actor) *uint {
+	if actor.UserID == 0 {
+		return nil
+	}
+	id := actor.UserID
+	return &id
+}
+
+// BranchAccessChecker reports whether a user is assigned to a branch
+type BranchAccessChecker interface {
+	IsBranchStaff(branchID, userID uint) (bool, error)
+}
+
+// CheckBranchAccess allows admins everywhere and other users only on the
+// branches they are assigned to. Station screens are limited to the branch
+// of their token.
+func CheckBranchAccess(checker BranchAccessChecker, actor Actor, branchID uint) error {
+	if actor.IsAdmin() {
+		return nil
+	}
+	if actor.IsStation() {
+		if actor.BranchID != branchID {
+			return ErrBranchAccessDenied
+		}
+		return nil
+	}
+
+	ok, err := checker.IsBranchStaff(branchID, actor.UserID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrBranchAccessDenied
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"strconv"
 	"time"
@@ -10,10 +11,20 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Claims are the JWT claims of users, of anonymous table sessions and of
+// kitchen station screens. Table session tokens carry no user but the
+// session, table and branch IDs; station tokens carry the user who opened
+// the screen and the station and branch IDs. Stream tickets copy the claims
+// of their holder and name the one event stream they open.
 type Claims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID         uint   `json:"user_id"`
+	Email          string `json:"email"`
+	Role           string `json:"role"`
+	TableSessionID uint   `json:"table_session_id,omitempty"`
+	TableID        uint   `json:"table_id,omitempty"`
+	StationID      uint   `json:"station_id,omitempty"`
+	BranchID       uint   `json:"branch_id,omitempty"`
+	Stream         string `json:"stream,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -63,6 +74,90 @@ func GenerateJWT(userID uint, email, role string) (string, error) {
 	return token.SignedString([]byte(secret))
 }
 
+// GenerateTableSessionJWT generates the token of an anonymous table
+// session. It expires together with the session.
+func GenerateTableSessionJWT(sessionID, tableID, branchID uint, role string, expiresAt time.Time) (string, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		secret = "default-secret-change-this"
+	}
+
+	claims := &Claims{
+		Role:           role,
+		TableSessionID: sessionID,
+		TableID:        tableID,
+		BranchID:       branchID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
+// GenerateStationJWT generates the token of a kitchen station screen. It
+// only grants access to the tickets of the station.
+func GenerateStationJWT(userID uint, email string, stationID, branchID uint, role string, expiresAt time.Time) (string, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		secret = "default-secret-change-this"
+	}
+
+	claims := &Claims{
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		StationID: stationID,
+		BranchID:  branchID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
+// Kinds of event streams a stream ticket can open
+const (
+	StreamBranch  = "branch"
+	StreamStation = "station"
+)
+
+// StreamScope names the event stream a stream ticket opens, e.g. "branch:1"
+func StreamScope(kind string, id uint) string {
+	return fmt.Sprintf("%s:%d", kind, id)
+}
+
+// GenerateStreamTicket generates a short-lived ticket that opens a single
+// event stream on behalf of actor. Browsers pass it in the URL, which ends
+// up in logs, so it is useless for anything but that stream.
+func GenerateStreamTicket(actor Actor, stream string, expiresAt time.Time) (string, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		secret = "default-secret-change-this"
+	}
+
+	claims := &Claims{
+		UserID:    actor.UserID,
+		Email:     actor.Email,
+		Role:      actor.Role,
+		StationID: actor.StationID,
+		BranchID:  actor.BranchID,
+		Stream:    stream,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
 // ValidateJWT validates a JWT token and returns claims
 func ValidateJWT(tokenString string) (*Claims, error) {
 	secret := os.Getenv("JWT_SECRET")
@@ -0,0 +1,13 @@
+package utils
+
+import "strings"
+
+// CSVText makes user-entered text safe for a CSV cell. Spreadsheets run
+// cells starting with =, +, - or @ (or a tab or carriage return before
+// one) as formulas, so those get a leading ' and are shown as text.
+func CSVText(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
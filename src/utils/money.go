@@ -0,0 +1,33 @@
+package utils
+
+import "fmt"
+
+// currencyDecimals lists the currencies whose minor unit is not a hundredth
+var currencyDecimals = map[string]int{
+	"BHD": 3, "JOD": 3, "KWD": 3, "OMR": 3, "TND": 3,
+	"CLP": 0, "ISK": 0, "JPY": 0, "KRW": 0, "UGX": 0, "VND": 0,
+}
+
+// FormatMoney formats an amount in minor units with the decimals of
+// currency, e.g. 1250 EUR as "12.50"
+func FormatMoney(amount int64, currency string) string {
+	decimals, ok := currencyDecimals[currency]
+	if !ok {
+		decimals = 2
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	if decimals == 0 {
+		return fmt.Sprintf("%s%d", sign, amount)
+	}
+
+	unit := int64(1)
+	for i := 0; i < decimals; i++ {
+		unit *= 10
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/unit, decimals, amount%unit)
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPagination reads the page and limit query parameters, defaulting to
+// page 1 with 10 items
+func GetPagination(c *gin.Context) (int, int) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		limit = 10
+	}
+
+	return page, limit
+}
+
+// Offset returns the number of rows to skip for page
+func Offset(page, limit int) int {
+	return (page - 1) * limit
+}
+
+// PaginatedData wraps a page of items with its pagination details
+func PaginatedData(key string, items interface{}, page, limit int, total int64) map[string]interface{} {
+	return map[string]interface{}{
+		key: items,
+		"pagination": map[string]interface{}{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	}
+}
+
+// ParseID reads a numeric path parameter
+func ParseID(c *gin.Context, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint(id), nil
+}
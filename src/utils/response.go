@@ -1,9 +1,23 @@
 package utils
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/faisd405/go-restapi-gin/src/database"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Stable error codes clients can match on instead of parsing messages
+const (
+	ErrCodeNotFound            = "NOT_FOUND"
+	ErrCodeUniqueViolation     = "UNIQUE_VIOLATION"
+	ErrCodeForeignKeyViolation = "FOREIGN_KEY_VIOLATION"
+	ErrCodeCheckViolation      = "CHECK_VIOLATION"
+	ErrCodeNotNullViolation    = "NOT_NULL_VIOLATION"
+	ErrCodeExclusionViolation  = "EXCLUSION_VIOLATION"
+	ErrCodeBranchAccessDenied  = "BRANCH_ACCESS_DENIED"
 )
 
 type Response struct {
@@ -11,6 +25,7 @@ type Response struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	Code    string      `json:"code,omitempty"`
 }
 
 // SuccessResponse sends a success response
@@ -31,6 +46,41 @@ func ErrorResponse(c *gin.Context, statusCode int, message string, err string) {
 	})
 }
 
+// ErrorResponseWithCode sends an error response carrying a stable error code
+func ErrorResponseWithCode(c *gin.Context, statusCode int, code string, message string, err string) {
+	c.JSON(statusCode, Response{
+		Success: false,
+		Message: message,
+		Error:   err,
+		Code:    code,
+	})
+}
+
+// DatabaseErrorResponse sends an error response whose status and code
+// match a repository error: 404 for missing records, 409 for unique,
+// foreign key and exclusion violations, 422 for check and not null
+// violations, 403 for branch access denials and 400 for anything else
+func DatabaseErrorResponse(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		ErrorResponseWithCode(c, http.StatusNotFound, ErrCodeNotFound, message, err.Error())
+	case errors.Is(err, database.ErrUniqueViolation):
+		ErrorResponseWithCode(c, http.StatusConflict, ErrCodeUniqueViolation, message, err.Error())
+	case errors.Is(err, database.ErrForeignKeyViolation):
+		ErrorResponseWithCode(c, http.StatusConflict, ErrCodeForeignKeyViolation, message, err.Error())
+	case errors.Is(err, database.ErrExclusionViolation):
+		ErrorResponseWithCode(c, http.StatusConflict, ErrCodeExclusionViolation, message, err.Error())
+	case errors.Is(err, database.ErrCheckViolation):
+		ErrorResponseWithCode(c, http.StatusUnprocessableEntity, ErrCodeCheckViolation, message, err.Error())
+	case errors.Is(err, database.ErrNotNullViolation):
+		ErrorResponseWithCode(c, http.StatusUnprocessableEntity, ErrCodeNotNullViolation, message, err.Error())
+	case errors.Is(err, ErrBranchAccessDenied):
+		ErrorResponseWithCode(c, http.StatusForbidden, ErrCodeBranchAccessDenied, message, err.Error())
+	default:
+		ErrorResponse(c, http.StatusBadRequest, message, err.Error())
+	}
+}
+
 // ValidationErrorResponse sends a validation error response
 func ValidationErrorResponse(c *gin.Context, err error) {
 	ErrorResponse(c, http.StatusBadRequest, "Validation failed", err.Error())